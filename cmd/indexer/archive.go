@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/usecases"
+)
+
+const (
+	exportUsage = "usage: export [-o file] [-api url] [-api-key key] owner/name"
+	importUsage = "usage: import file | -"
+)
+
+// runExport runs the export subcommand, args are the arguments after "export".
+// The archive is written to a file as the database logs to standard output.
+func runExport(c *cli, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	remote := remoteFlags(flags)
+	output := flags.String("o", "", "file to write the archive to, owner_name.ndjson.gz by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New(exportUsage)
+	}
+
+	repoName := flags.Arg(0)
+	if *output == "" {
+		*output = strings.ReplaceAll(repoName, "/", "_") + ".ndjson.gz"
+	}
+
+	b, err := c.backend(remote)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+
+	summary, err := b.Export(c.ctx, repoName, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(*output)
+		return err
+	}
+
+	if summary == nil {
+		fmt.Fprintf(c.out, "Exported %s to %s\n", repoName, *output)
+		return nil
+	}
+	fmt.Fprintf(c.out, "Exported %s to %s: %d authors, %d commits\n", summary.Repository, *output, summary.Authors, summary.Commits)
+	return nil
+}
+
+// runImport runs the import subcommand, args are the arguments after "import".
+// It reads the archive from standard input when the file is "-".
+func runImport(ctx context.Context, uc usecases.ArchiveUsecase, args []string, in io.Reader, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New(importUsage)
+	}
+
+	r := in
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	summary, err := uc.Import(ctx, r, func(s domain.ArchiveSummary) {
+		fmt.Fprintf(out, "%s: %d commits read, %d new\n", s.Repository, s.Commits, s.Imported)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Imported %s: %d authors, %d commits, %d new\n", summary.Repository, summary.Authors, summary.Commits, summary.Imported)
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+)
+
+const authorsUsage = "usage: authors top [-n count] [-since date] [-until date] [-rank-by commits|lines] [-exclude patterns] [-group name] [owner/name]"
+
+// runAuthors runs the authors subcommand, args are the arguments after
+// "authors". Without a repository the authors are ranked across every
+// repository, or the repositories of -group.
+func runAuthors(c *cli, args []string) error {
+	if len(args) == 0 || args[0] != "top" {
+		return errors.New(authorsUsage)
+	}
+
+	flags := flag.NewFlagSet("authors top", flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	remote := remoteFlags(flags)
+	n := flags.Int("n", 10, "number of authors to rank")
+	since := flags.String("since", "", "rank commits from this date, RFC 3339 or YYYY-MM-DD")
+	until := flags.String("until", "", "rank commits before this date, RFC 3339 or YYYY-MM-DD")
+	rankBy := flags.String("rank-by", string(domain.RankByCommits), "measure to rank by: commits or lines")
+	exclude := flags.String("exclude", "", "comma separated name or email patterns to skip, * matches any characters")
+	group := flags.String("group", "", "rank across the repositories of a configured group")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 || (flags.NArg() == 1 && *group != "") {
+		return errors.New(authorsUsage)
+	}
+	if *n <= 0 {
+		return fmt.Errorf("invalid -n %d, expected a positive number", *n)
+	}
+	if !domain.AuthorRanking(*rankBy).Valid() {
+		return fmt.Errorf("invalid -rank-by %q, expected commits or lines", *rankBy)
+	}
+
+	query := domain.TopAuthorsQuery{
+		Limit:   min(*n, dtos.MaxLimit),
+		RankBy:  domain.AuthorRanking(*rankBy),
+		Exclude: splitList(*exclude),
+	}
+	var err error
+	if query.Since, err = parseDate("since", *since); err != nil {
+		return err
+	}
+	if query.Until, err = parseDate("until", *until); err != nil {
+		return err
+	}
+
+	b, err := c.backend(remote)
+	if err != nil {
+		return err
+	}
+
+	var authors []domain.Author
+	if flags.NArg() == 1 {
+		authors, err = b.TopAuthors(c.ctx, flags.Arg(0), query)
+	} else {
+		authors, err = b.Leaderboard(c.ctx, *group, query)
+	}
+	if err != nil {
+		return err
+	}
+	return writeAuthors(c.out, authors, flags.NArg() == 0)
+}
+
+// writeAuthors writes a ranking, across adds the number of repositories each
+// author committed to.
+func writeAuthors(out io.Writer, authors []domain.Author, across bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if across {
+		fmt.Fprintln(w, "RANK\tNAME\tEMAIL\tCOMMITS\tADDITIONS\tDELETIONS\tREPOSITORIES")
+	} else {
+		fmt.Fprintln(w, "RANK\tNAME\tEMAIL\tCOMMITS\tADDITIONS\tDELETIONS")
+	}
+	for i, a := range authors {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%d", i+1, a.Name, a.Email, a.CommitCount, a.Additions, a.Deletions)
+		if across {
+			fmt.Fprintf(w, "\t%d", a.RepositoryCount)
+		}
+		fmt.Fprintln(w)
+	}
+	return w.Flush()
+}
+
+// parseDate parses the value of a date flag as RFC 3339 or as a UTC date.
+func parseDate(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid -%s %q, expected RFC 3339 or YYYY-MM-DD", name, value)
+	}
+	return t, nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/client"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/config"
+	"github.com/just-nibble/git-service/pkg/env"
+	"github.com/just-nibble/git-service/pkg/git"
+	"github.com/just-nibble/git-service/pkg/log"
+	"gorm.io/gorm"
+)
+
+// requestTimeout bounds each call to the REST API.
+const requestTimeout = time.Minute
+
+// backend runs the commands against the database or the REST API of a
+// running server.
+type backend interface {
+	AddRepository(ctx context.Context, name string) (*domain.RepositoryMeta, error)
+	Repository(ctx context.Context, name string) (*domain.RepositoryMeta, error)
+	Repositories(ctx context.Context) ([]domain.RepositoryMeta, error)
+	RemoveRepository(ctx context.Context, name string) error
+	ReindexRepository(ctx context.Context, name string) (*domain.RepositoryMeta, error)
+	Commits(ctx context.Context, repoName string, query domain.APIPaging, filter domain.CommitFilter) ([]domain.Commit, domain.PagingInfo, error)
+	TopAuthors(ctx context.Context, repoName string, query domain.TopAuthorsQuery) ([]domain.Author, error)
+	Leaderboard(ctx context.Context, group string, query domain.TopAuthorsQuery) ([]domain.Author, error)
+	// Export writes the archive of a repository to w, the summary is nil when
+	// the backend does not report one
+	Export(ctx context.Context, repoName string, w io.Writer) (*domain.ArchiveSummary, error)
+}
+
+// remote holds the flags selecting the REST API, the database is used when
+// url is empty.
+type remote struct {
+	url    string
+	apiKey string
+}
+
+// remoteFlags adds the -api and -api-key flags to flags.
+func remoteFlags(flags *flag.FlagSet) *remote {
+	r := &remote{}
+	flags.StringVar(&r.url, "api", env.Getenv("GIT_SERVICE_API", ""), "base URL of a running server, e.g. http://localhost:8080, the database is used when empty")
+	flags.StringVar(&r.apiKey, "api-key", env.Getenv("GIT_SERVICE_API_KEY", ""), "API key sent to the server")
+	return r
+}
+
+type databaseBackend struct {
+	repoMetaUsecase usecases.RepoMetaUsecase
+	commitUsecase   usecases.GitCommitUsecase
+	authorUsecase   usecases.AuthorUseCase
+	archiveUsecase  usecases.ArchiveUsecase
+}
+
+func newDatabaseBackend(db *gorm.DB, config *config.Config, log log.Log) *databaseBackend {
+	githubClient := git.NewGitHubClient(config.GitClientBaseURL, config.GitClientToken, config.MonitorInterval)
+
+	repoRepository := repository.NewGormRepositoryMetaRepository(db)
+	authorRepository := repository.NewGormAuthorRepository(db)
+	commitRepository := repository.NewGormCommitRepository(db)
+	tagRepository := repository.NewGormTagRepository(db)
+	pullRequestRepository := repository.NewGormPullRequestRepository(db)
+
+	return &databaseBackend{
+		repoMetaUsecase: usecases.NewrepoMetaUsecase(repoRepository, commitRepository, authorRepository, tagRepository, pullRequestRepository, githubClient, *config, log),
+		commitUsecase:   usecases.NewGitCommitUsecase(commitRepository, repoRepository),
+		authorUsecase:   usecases.NewAuthorUseCase(authorRepository, config.RepositoryGroups, log),
+		archiveUsecase:  usecases.NewArchiveUsecase(repoRepository, commitRepository),
+	}
+}
+
+// AddRepository saves the repository without indexing it, the indexing jobs
+// belong to the server, which indexes it once it starts.
+func (b *databaseBackend) AddRepository(ctx context.Context, name string) (*domain.RepositoryMeta, error) {
+	return b.repoMetaUsecase.RegisterRepository(ctx, dtos.RepositoryInput{Name: name})
+}
+
+func (b *databaseBackend) Repository(ctx context.Context, name string) (*domain.RepositoryMeta, error) {
+	return b.repoMetaUsecase.FindRepoByName(ctx, name)
+}
+
+func (b *databaseBackend) Repositories(ctx context.Context) ([]domain.RepositoryMeta, error) {
+	return b.repoMetaUsecase.RetrieveAllRepos(ctx)
+}
+
+// RemoveRepository deletes the repository, the jobs of a running server stop
+// once they fail to save commits for it.
+func (b *databaseBackend) RemoveRepository(ctx context.Context, name string) error {
+	return b.repoMetaUsecase.RemoveRepository(ctx, name)
+}
+
+// ReindexRepository discards the stored commits of the repository without
+// indexing it, the server indexes it again once it starts.
+func (b *databaseBackend) ReindexRepository(ctx context.Context, name string) (*domain.RepositoryMeta, error) {
+	return b.repoMetaUsecase.ResetRepository(ctx, name)
+}
+
+func (b *databaseBackend) Commits(ctx context.Context, repoName string, query domain.APIPaging, filter domain.CommitFilter) ([]domain.Commit, domain.PagingInfo, error) {
+	return b.commitUsecase.GetAllCommitsByRepository(ctx, repoName, query, filter)
+}
+
+func (b *databaseBackend) TopAuthors(ctx context.Context, repoName string, query domain.TopAuthorsQuery) ([]domain.Author, error) {
+	return b.authorUsecase.GetTopAuthors(ctx, repoName, query)
+}
+
+func (b *databaseBackend) Leaderboard(ctx context.Context, group string, query domain.TopAuthorsQuery) ([]domain.Author, error) {
+	return b.authorUsecase.GetTopAuthorsAcross(ctx, group, query)
+}
+
+func (b *databaseBackend) Export(ctx context.Context, repoName string, w io.Writer) (*domain.ArchiveSummary, error) {
+	summary, err := b.archiveUsecase.Export(ctx, repoName, w)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// apiBackend converts the responses of the REST API to the domain types the
+// commands print.
+type apiBackend struct {
+	client *client.Client
+}
+
+func newAPIBackend(r *remote) *apiBackend {
+	return &apiBackend{client: client.New(r.url, r.apiKey, &http.Client{Timeout: requestTimeout})}
+}
+
+func (b *apiBackend) AddRepository(ctx context.Context, name string) (*domain.RepositoryMeta, error) {
+	repo, err := b.client.AddRepository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return toRepository(*repo), nil
+}
+
+func (b *apiBackend) Repository(ctx context.Context, name string) (*domain.RepositoryMeta, error) {
+	repo, err := b.client.Repository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return toRepository(*repo), nil
+}
+
+func (b *apiBackend) Repositories(ctx context.Context) ([]domain.RepositoryMeta, error) {
+	repos, err := b.client.Repositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.RepositoryMeta, 0, len(repos))
+	for _, repo := range repos {
+		res = append(res, *toRepository(repo))
+	}
+	return res, nil
+}
+
+func (b *apiBackend) RemoveRepository(ctx context.Context, name string) error {
+	return b.client.RemoveRepository(ctx, name)
+}
+
+func (b *apiBackend) ReindexRepository(ctx context.Context, name string) (*domain.RepositoryMeta, error) {
+	repo, err := b.client.ReindexRepository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return toRepository(*repo), nil
+}
+
+func (b *apiBackend) Commits(ctx context.Context, repoName string, query domain.APIPaging, filter domain.CommitFilter) ([]domain.Commit, domain.PagingInfo, error) {
+	commits, paging, err := b.client.Commits(ctx, repoName, query, filter)
+	if err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	res := make([]domain.Commit, 0, len(commits))
+	for _, c := range commits {
+		res = append(res, toCommit(c))
+	}
+	return res, domain.PagingInfo{
+		TotalCount:  paging.TotalCount,
+		Page:        paging.Page,
+		HasNextPage: paging.HasNextPage,
+		Count:       paging.Count,
+	}, nil
+}
+
+func (b *apiBackend) TopAuthors(ctx context.Context, repoName string, query domain.TopAuthorsQuery) ([]domain.Author, error) {
+	authors, err := b.client.TopAuthors(ctx, repoName, query)
+	if err != nil {
+		return nil, err
+	}
+	return toAuthors(authors), nil
+}
+
+func (b *apiBackend) Leaderboard(ctx context.Context, group string, query domain.TopAuthorsQuery) ([]domain.Author, error) {
+	authors, err := b.client.Leaderboard(ctx, group, query)
+	if err != nil {
+		return nil, err
+	}
+	return toAuthors(authors), nil
+}
+
+func (b *apiBackend) Export(ctx context.Context, repoName string, w io.Writer) (*domain.ArchiveSummary, error) {
+	return nil, b.client.Export(ctx, repoName, w)
+}
+
+func toRepository(r dtos.RepositoryMeta) *domain.RepositoryMeta {
+	return &domain.RepositoryMeta{
+		OwnerName:       r.Owner.Login,
+		Name:            r.Name,
+		Description:     r.Description,
+		Language:        r.Language,
+		URL:             r.URL,
+		ForksCount:      r.ForksCount,
+		StarsCount:      r.StarsCount,
+		OpenIssuesCount: r.OpenIssuesCount,
+		WatchersCount:   r.WatchersCount,
+		Retention:       domain.RetentionPolicy{Days: r.Retention.Days, Commits: r.Retention.Commits},
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+}
+
+func toCommit(c dtos.CommitReponse) domain.Commit {
+	return domain.Commit{
+		ID:       c.ID,
+		Hash:     c.Hash,
+		Message:  c.Message,
+		Date:     c.Date,
+		AuthorID: c.Author.ID,
+		Author: domain.Author{
+			ID:    c.Author.ID,
+			Name:  c.Author.Name,
+			Email: c.Author.Email,
+		},
+		Conventional: domain.ConventionalCommit{
+			Type:        c.Type,
+			Scope:       c.Scope,
+			Breaking:    c.Breaking,
+			Description: c.Description,
+		},
+		RevertedBy: c.RevertedBy,
+	}
+}
+
+func toAuthors(authors []dtos.Author) []domain.Author {
+	res := make([]domain.Author, 0, len(authors))
+	for _, a := range authors {
+		author := domain.Author{
+			ID:              a.ID,
+			Name:            a.Name,
+			Email:           a.Email,
+			CommitCount:     a.CommitCount,
+			RepositoryCount: a.RepositoryCount,
+			Additions:       a.Additions,
+			Deletions:       a.Deletions,
+		}
+		if a.FirstCommitAt != nil {
+			author.FirstCommitAt = *a.FirstCommitAt
+		}
+		if a.LastCommitAt != nil {
+			author.LastCommitAt = *a.LastCommitAt
+		}
+		res = append(res, author)
+	}
+	return res
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+)
+
+const commitsUsage = "usage: commits ls [-limit n] [-page n] [-sort column] [-direction asc|desc] [-type types] [-scope scope] [-breaking true|false] owner/name"
+
+// runCommits runs the commits subcommand, args are the arguments after
+// "commits". The paging of the listing is logged to standard error so the
+// table can be piped.
+func runCommits(c *cli, args []string) error {
+	if len(args) == 0 || args[0] != "ls" {
+		return errors.New(commitsUsage)
+	}
+
+	flags := flag.NewFlagSet("commits ls", flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	remote := remoteFlags(flags)
+	limit := flags.Int("limit", 0, "number of commits per page, 10 by default")
+	page := flags.Int("page", 0, "page to list, 1 by default")
+	sort := flags.String("sort", "", "column to sort by: created_at, date or commit_hash")
+	direction := flags.String("direction", "", "sort direction: asc or desc")
+	types := flags.String("type", "", "comma separated conventional commit types to keep")
+	scope := flags.String("scope", "", "conventional commit scope to keep")
+	breaking := flags.String("breaking", "", "keep only breaking (true) or non-breaking (false) commits")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New(commitsUsage)
+	}
+	if *limit < 0 || *page < 0 {
+		return errors.New("invalid -limit or -page, expected a positive number")
+	}
+
+	query := domain.APIPaging{
+		Limit:     min(*limit, dtos.MaxLimit),
+		Page:      *page,
+		Sort:      *sort,
+		Direction: *direction,
+	}
+	filter := domain.CommitFilter{
+		Types: splitList(*types),
+		Scope: *scope,
+	}
+	if *breaking != "" {
+		b, err := strconv.ParseBool(*breaking)
+		if err != nil {
+			return fmt.Errorf("invalid -breaking %q, expected true or false", *breaking)
+		}
+		filter.Breaking = &b
+	}
+
+	b, err := c.backend(remote)
+	if err != nil {
+		return err
+	}
+
+	commits, paging, err := b.Commits(c.ctx, flags.Arg(0), query, filter)
+	if err != nil {
+		return err
+	}
+	if err := writeCommits(c.out, commits); err != nil {
+		return err
+	}
+
+	c.log.Info.Printf("Page %d: %d of %d commits", paging.Page, paging.Count, paging.TotalCount)
+	if paging.HasNextPage {
+		c.log.Info.Printf("More commits on -page %d", paging.Page+1)
+	}
+	return nil
+}
+
+func writeCommits(out io.Writer, commits []domain.Commit) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "HASH\tDATE\tAUTHOR\tMESSAGE")
+	for _, c := range commits {
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", shortHash(c.Hash), formatTime(c.Date), c.Author.Name, subject)
+	}
+	return w.Flush()
+}
+
+// splitList splits a comma separated flag, dropping empty values.
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -2,109 +2,169 @@ package main
 
 import (
 	"context"
-	"net/http"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
-	"github.com/just-nibble/git-service/internal/http/dtos"
-	"github.com/just-nibble/git-service/internal/http/handlers"
-	"github.com/just-nibble/git-service/internal/http/routes"
-	"github.com/just-nibble/git-service/internal/repository"
-	"github.com/just-nibble/git-service/internal/usecases"
 	"github.com/just-nibble/git-service/pkg/config"
 	"github.com/just-nibble/git-service/pkg/database"
-	"github.com/just-nibble/git-service/pkg/errcodes"
-	"github.com/just-nibble/git-service/pkg/git"
 	"github.com/just-nibble/git-service/pkg/log"
+	"gorm.io/gorm"
 )
 
+const usage = `usage: main [command] [arguments]
+
+Commands:
+  serve                                     run the server, the default command
+  repo add|list|remove|reindex|status       manage the tracked repositories
+  commits ls [flags] owner/name             list the commits of a repository
+  authors top [flags] [owner/name]          rank the authors of a repository or of all of them
+  export [-o file] owner/name               write the archive of a repository
+  import file | -                           import the archive of a repository
+  migrate up | down [-steps n] | status | partition
+
+repo, commits, authors and export use the database unless -api or
+GIT_SERVICE_API names a running server. Run a command with -h for its flags.`
+
 func main() {
-	ctx, cancelFunc := context.WithCancel(context.Background())
-	defer cancelFunc()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	log := log.NewLogger()
-	config, err := config.LoadConfig(*log)
-	if err != nil {
-		log.Error.Printf("failed to load config %s", err.Error())
-	}
 
-	// Create a new PostgresDatabase instance
-	dbClient := database.NewPostgresDatabase(config.DSN, 10, 5, 3*time.Hour)
-	err = dbClient.ConnectDB(ctx)
-	if err != nil {
-		log.Error.Printf("failed to establish postgres database connection: %s", err.Error())
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
 	}
 
-	// Run database migrations
-	if err := dbClient.Migrate(ctx); err != nil {
-		log.Error.Printf("failed to run database migrations: %s", err.Error())
+	if command == "serve" {
+		serve(ctx, stop, log)
+		return
 	}
 
-	githubClient := git.NewGitHubClient(config.GitClientBaseURL, config.GitClientToken, config.MonitorInterval)
+	// the output of the other commands is read by scripts, logs go to stderr
+	log.Info.SetOutput(os.Stderr)
 
-	dB := dbClient.GetDB()
+	c := &cli{ctx: ctx, log: log, in: os.Stdin, out: os.Stdout}
+	err := c.run(command, args)
+	c.close()
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		log.Error.Fatalf("%s: %s", command, err.Error())
+	}
+}
 
-	repoRepository := repository.NewGormRepositoryMetaRepository(dB)
-	authorRepository := repository.NewGormAuthorRepository(dB)
-	commitRepository := repository.NewGormCommitRepository(dB)
+// cli runs the commands other than serve. The config is loaded and the
+// database connected only by the commands that use them, so the commands sent
+// to the REST API also run where neither is available.
+type cli struct {
+	ctx context.Context
+	log *log.Log
+	in  io.Reader
+	out io.Writer
+
+	config   *config.Config
+	dbClient database.Database
+}
 
-	commitUsecase := usecases.NewGitCommitUsecase(commitRepository, repoRepository)
-	gitRepoUsecase := usecases.NewrepoMetaUsecase(repoRepository, commitRepository, authorRepository, githubClient, *config, *log)
-	authorUsecase := usecases.NewAuthorUseCase(authorRepository)
+func (c *cli) run(command string, args []string) error {
+	switch command {
+	case "repo":
+		return runRepo(c, args)
+	case "commits":
+		return runCommits(c, args)
+	case "authors":
+		return runAuthors(c, args)
+	case "export":
+		return runExport(c, args)
+	case "import":
+		b, err := c.databaseBackend()
+		if err != nil {
+			return err
+		}
+		return runImport(c.ctx, b.archiveUsecase, args, c.in, c.out)
+	case "migrate":
+		db, err := c.db()
+		if err != nil {
+			return err
+		}
+		return runMigrate(c.ctx, db, args, c.out)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprintln(c.out, usage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", command, usage)
+	}
+}
 
-	repoHandler := handlers.NewRepositoryHandler(gitRepoUsecase)
-	authorHandler := handlers.NewAuthorHandler(authorUsecase)
-	commitHandler := handlers.NewCommitHandler(commitUsecase)
+func (c *cli) loadConfig() (*config.Config, error) {
+	if c.config != nil {
+		return c.config, nil
+	}
 
-	// Set up HTTP routes
-	mux := http.NewServeMux()
-	routes.NewAuthorRouter(mux, *authorHandler)
-	routes.NewCommitRouter(mux, *commitHandler)
-	routes.NewRepositoryRouter(mux, *repoHandler)
+	conf, err := config.LoadConfig(*c.log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	c.config = conf
+	return conf, nil
+}
 
-	err = seedDefaultRepository(config, gitRepoUsecase, *log)
-	if err != nil && err != errcodes.ErrRepoAlreadyAdded {
-		log.Error.Fatalf("failed to seed default repository: %s,", err.Error())
+func (c *cli) db() (*gorm.DB, error) {
+	if c.dbClient != nil {
+		return c.dbClient.GetDB(), nil
 	}
 
-	go gitRepoUsecase.ResumeIndexing(ctx)
-
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				log.Info.Println("Program is shutting down...")
-				// Call method to set isFetching to false in DB
-				if err := gitRepoUsecase.ModifyRepoStatus(context.Background(), false); err != nil {
-					log.Error.Printf("Error updating index to false: %s", err.Error())
-				}
-				os.Exit(0)
-			default:
-				time.Sleep(5 * time.Second)
-			}
-		}
-	}()
+	conf, err := c.loadConfig()
+	if err != nil {
+		return nil, err
+	}
 
-	// Start the HTTP server
-	log.Info.Println("Server is running on port 8080")
-	if err := http.ListenAndServe(":8080", mux); err != nil {
-		log.Error.Fatalf("Could not start server: %v", err)
+	var dbClient database.Database
+	if conf.DBDriver == "sqlite" {
+		dbClient = database.NewSQLiteDatabase(conf.SQLitePath)
+	} else {
+		dbClient = database.NewPostgresDatabase(conf.DSN, 2, 1, time.Hour)
+	}
+	if err := dbClient.ConnectDB(c.ctx); err != nil {
+		return nil, fmt.Errorf("failed to establish %s database connection: %w", conf.DBDriver, err)
 	}
+	c.dbClient = dbClient
+	return dbClient.GetDB(), nil
 }
 
-// seedDefaultRepository seeds a default repository to database
-func seedDefaultRepository(config *config.Config, repositoryUsecase usecases.RepoMetaUsecase, log log.Log) error {
-	defaultRepo := dtos.RepositoryInput{
-		Name: config.DefaultRepository,
+// backend returns the backend selected by the flags of r.
+func (c *cli) backend(r *remote) (backend, error) {
+	if r.url != "" {
+		return newAPIBackend(r), nil
+	}
+
+	b, err := c.databaseBackend()
+	if err != nil {
+		return nil, err
 	}
+	return b, nil
+}
 
-	repo, err := repositoryUsecase.InitiateIndexing(context.Background(), defaultRepo)
-	if err != nil && err != errcodes.ErrNoRecordFound {
-		return err
+func (c *cli) databaseBackend() (*databaseBackend, error) {
+	db, err := c.db()
+	if err != nil {
+		return nil, err
 	}
 
-	if repo != nil {
-		log.Info.Printf("Successfully seeded default repository: %s", repo.Name)
+	return newDatabaseBackend(db, c.config, *c.log), nil
+}
+
+// close closes the database.
+func (c *cli) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if c.dbClient != nil {
+		c.dbClient.CloseDb(ctx)
 	}
-	return err
 }
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+)
+
+const repoUsage = "usage: repo add | list | remove | reindex | status [-api url] [-api-key key] [owner/name]"
+
+// runRepo runs the repo subcommand, args are the arguments after "repo".
+// Through the database the repositories are only saved, reset or deleted, the
+// server indexes them once it starts or restarts. Pass -api to have a running
+// server index them right away.
+func runRepo(c *cli, args []string) error {
+	if len(args) == 0 {
+		return errors.New(repoUsage)
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("repo "+action, flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	remote := remoteFlags(flags)
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch action {
+	case "list":
+		if flags.NArg() != 0 {
+			return errors.New(repoUsage)
+		}
+	case "add", "remove", "reindex", "status":
+		if flags.NArg() != 1 {
+			return errors.New(repoUsage)
+		}
+	default:
+		return errors.New(repoUsage)
+	}
+
+	b, err := c.backend(remote)
+	if err != nil {
+		return err
+	}
+	repoName := flags.Arg(0)
+
+	switch action {
+	case "add":
+		repo, err := b.AddRepository(c.ctx, repoName)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "Added %s\n", repo.Name)
+
+	case "list":
+		repos, err := b.Repositories(c.ctx)
+		if err != nil {
+			return err
+		}
+		return writeRepositories(c.out, repos)
+
+	case "remove":
+		if err := b.RemoveRepository(c.ctx, repoName); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "Removed %s\n", repoName)
+
+	case "reindex":
+		repo, err := b.ReindexRepository(c.ctx, repoName)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "Re-indexing %s\n", repo.Name)
+
+	case "status":
+		repo, err := b.Repository(c.ctx, repoName)
+		if err != nil {
+			return err
+		}
+		latest, paging, err := b.Commits(c.ctx, repoName, domain.APIPaging{Limit: 1, Sort: "date", Direction: "desc"}, domain.CommitFilter{})
+		if err != nil {
+			return err
+		}
+		return writeRepositoryStatus(c.out, *repo, latest, paging.TotalCount, remote.url == "")
+	}
+	return nil
+}
+
+func writeRepositories(out io.Writer, repos []domain.RepositoryMeta) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tLANGUAGE\tSTARS\tFORKS\tADDED")
+	for _, r := range repos {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", r.Name, r.Language, r.StarsCount, r.ForksCount, formatTime(r.CreatedAt))
+	}
+	return w.Flush()
+}
+
+// writeRepositoryStatus writes a repository with its number of commits and
+// the latest of them, and its index flag and cursor when cursor is set, the
+// REST API does not return them.
+func writeRepositoryStatus(out io.Writer, repo domain.RepositoryMeta, latest []domain.Commit, commits int64, cursor bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", repo.Name)
+	fmt.Fprintf(w, "URL:\t%s\n", repo.URL)
+	fmt.Fprintf(w, "Language:\t%s\n", repo.Language)
+	fmt.Fprintf(w, "Added:\t%s\n", formatTime(repo.CreatedAt))
+	fmt.Fprintf(w, "Retention:\t%s\n", formatRetention(repo.Retention))
+	fmt.Fprintf(w, "Commits:\t%d\n", commits)
+	if len(latest) > 0 {
+		fmt.Fprintf(w, "Latest commit:\t%s %s\n", shortHash(latest[0].Hash), formatTime(latest[0].Date))
+	}
+	if cursor {
+		fmt.Fprintf(w, "Index flag:\t%t\n", repo.Index)
+		fmt.Fprintf(w, "Cursor:\tpage %d, commit %s\n", repo.LastPage, formatHash(repo.LastFetchedCommit))
+	}
+	return w.Flush()
+}
+
+func formatRetention(p domain.RetentionPolicy) string {
+	switch {
+	case p.Days > 0 && p.Commits > 0:
+		return fmt.Sprintf("%d days, %d commits", p.Days, p.Commits)
+	case p.Days > 0:
+		return fmt.Sprintf("%d days", p.Days)
+	case p.Commits > 0:
+		return fmt.Sprintf("%d commits", p.Commits)
+	default:
+		return "keep every commit"
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatHash(hash string) string {
+	if hash == "" {
+		return "-"
+	}
+	return shortHash(hash)
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/graph"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/http/handlers"
+	"github.com/just-nibble/git-service/internal/http/middleware"
+	"github.com/just-nibble/git-service/internal/http/openapi"
+	"github.com/just-nibble/git-service/internal/http/routes"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/internal/rpc"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/config"
+	"github.com/just-nibble/git-service/pkg/database"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/git"
+	"github.com/just-nibble/git-service/pkg/log"
+	"github.com/just-nibble/git-service/pkg/ratelimit"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+// apiPrefix is the prefix of the current API version.
+const apiPrefix = "/v1"
+
+// serve runs the server until ctx is done. stop restores the default handling
+// of the signals so a second interrupt ends a slow shutdown.
+func serve(ctx context.Context, stop context.CancelFunc, log *log.Log) {
+	config, err := config.LoadConfig(*log)
+	if err != nil {
+		log.Error.Printf("failed to load config %s", err.Error())
+	}
+
+	var dbClient database.Database
+	if config.DBDriver == "sqlite" {
+		dbClient = database.NewSQLiteDatabase(config.SQLitePath)
+	} else {
+		dbClient = database.NewPostgresDatabase(config.DSN, 10, 5, 3*time.Hour)
+	}
+	err = dbClient.ConnectDB(ctx)
+	if err != nil {
+		log.Error.Printf("failed to establish %s database connection: %s", config.DBDriver, err.Error())
+	}
+
+	if config.MigrateOnStart {
+		if err := dbClient.Migrate(ctx); err != nil {
+			log.Error.Fatalf("failed to run database migrations: %s", err.Error())
+		}
+	}
+
+	// Replicas must not serve a schema older than their code
+	migrator, err := database.NewMigrator(dbClient.GetDB())
+	if err != nil {
+		log.Error.Fatalf("failed to load database migrations: %s", err.Error())
+	}
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		log.Error.Fatalf("failed to read database migrations: %s", err.Error())
+	}
+	if pending > 0 {
+		log.Error.Fatalf("database schema is %d migrations behind, run `main migrate up` or set MIGRATE_ON_START=true", pending)
+	}
+
+	githubClient := git.NewGitHubClient(config.GitClientBaseURL, config.GitClientToken, config.MonitorInterval)
+
+	dB := dbClient.GetDB()
+
+	repoRepository := repository.NewGormRepositoryMetaRepository(dB)
+	authorRepository := repository.NewGormAuthorRepository(dB)
+	commitRepository := repository.NewGormCommitRepository(dB)
+	apiKeyRepository := repository.NewGormAPIKeyRepository(dB)
+	statsRepository := repository.NewGormStatsRepository(dB)
+	tagRepository := repository.NewGormTagRepository(dB)
+	pullRequestRepository := repository.NewGormPullRequestRepository(dB)
+
+	commitUsecase := usecases.NewGitCommitUsecase(commitRepository, repoRepository)
+	gitRepoUsecase := usecases.NewrepoMetaUsecase(repoRepository, commitRepository, authorRepository, tagRepository, pullRequestRepository, githubClient, *config, *log)
+	authorUsecase := usecases.NewAuthorUseCase(authorRepository, config.RepositoryGroups, *log)
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(apiKeyRepository, *log)
+	statsUsecase := usecases.NewStatsUsecase(statsRepository, repoRepository, domain.DeliveryQuery{
+		Source:         domain.DeploymentSource(config.DeliverySource),
+		Branch:         config.DeliveryBranch,
+		FailurePattern: domain.LikePattern(config.DeliveryHotfixes),
+	})
+	changelogUsecase := usecases.NewChangelogUsecase(commitRepository, repoRepository, tagRepository, githubClient)
+	releaseUsecase := usecases.NewReleaseUsecase(tagRepository, commitRepository, repoRepository, githubClient)
+	pullRequestUsecase := usecases.NewPullRequestUsecase(pullRequestRepository, repoRepository)
+	ownershipUsecase := usecases.NewOwnershipUsecase(statsRepository, repoRepository, config.OwnershipMonths)
+	retentionUsecase := usecases.NewRetentionUsecase(repoRepository, commitRepository, domain.RetentionPolicy{
+		Days:    config.RetentionDays,
+		Commits: config.RetentionCommits,
+	}, *log)
+	archiveUsecase := usecases.NewArchiveUsecase(repoRepository, commitRepository)
+
+	if config.AdminAPIKey != "" {
+		if err := apiKeyUsecase.EnsureBootstrapKey(ctx, config.AdminAPIKey); err != nil {
+			log.Error.Fatalf("failed to store bootstrap admin key: %s", err.Error())
+		}
+	}
+
+	repoHandler := handlers.NewRepositoryHandler(gitRepoUsecase)
+	authorHandler := handlers.NewAuthorHandler(authorUsecase)
+	commitHandler := handlers.NewCommitHandler(commitUsecase)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyUsecase)
+	statsHandler := handlers.NewStatsHandler(statsUsecase)
+	changelogHandler := handlers.NewChangelogHandler(changelogUsecase)
+	releaseHandler := handlers.NewReleaseHandler(releaseUsecase)
+	pullRequestHandler := handlers.NewPullRequestHandler(pullRequestUsecase)
+	ownershipHandler := handlers.NewOwnershipHandler(ownershipUsecase)
+	retentionHandler := handlers.NewRetentionHandler(retentionUsecase)
+	archiveHandler := handlers.NewArchiveHandler(archiveUsecase)
+
+	graphQLServer, err := graph.NewServer(gitRepoUsecase, commitUsecase, authorUsecase, graph.Limits{
+		MaxDepth:      config.GraphQLMaxDepth,
+		MaxComplexity: config.GraphQLMaxComplexity,
+		MaxFanout:     config.GraphQLMaxFanout,
+	})
+	if err != nil {
+		log.Error.Fatalf("failed to build graphql schema: %s", err.Error())
+	}
+	graphQLHandler := handlers.NewGraphQLHandler(graphQLServer)
+	openAPIHandler := handlers.NewOpenAPIHandler(openapi.JSON())
+
+	// Set up HTTP routes
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", handlers.NotFound)
+	routes.NewAuthorRouter(mux, *authorHandler)
+	routes.NewCommitRouter(mux, *commitHandler)
+	routes.NewRepositoryRouter(mux, *repoHandler)
+	routes.NewStatsRouter(mux, *statsHandler)
+	routes.NewChangelogRouter(mux, *changelogHandler)
+	routes.NewReleaseRouter(mux, *releaseHandler)
+	routes.NewPullRequestRouter(mux, *pullRequestHandler)
+	routes.NewOwnershipRouter(mux, *ownershipHandler)
+	routes.NewRetentionRouter(mux, *retentionHandler)
+	routes.NewArchiveRouter(mux, *archiveHandler)
+	routes.NewGraphQLRouter(mux, *graphQLHandler)
+	routes.NewAPIKeyRouter(mux, *apiKeyHandler)
+	routes.NewOpenAPIRouter(mux, *openAPIHandler)
+
+	var handler http.Handler = mux
+	if config.OpenAPIValidation {
+		spec, err := openapi.Load()
+		if err != nil {
+			log.Error.Fatalf("failed to load openapi specification: %s", err.Error())
+		}
+		handler = openapi.Validate(spec, handler)
+	}
+
+	// the limiters are shared with the gRPC server so clients cannot double
+	// their limits by using both APIs
+	var limiters map[string]*ratelimit.Limiter
+	if config.RateLimitEnabled {
+		limiters = make(map[string]*ratelimit.Limiter, len(config.RateLimits))
+		for group, limit := range config.RateLimits {
+			limiters[group] = ratelimit.New(limit)
+		}
+		handler = middleware.RateLimit(limiters, handler)
+	}
+
+	rpcOptions := rpc.Options{Limiters: limiters, PollInterval: config.GRPCPollInterval}
+	if config.AuthEnabled {
+		handler = middleware.Authenticate(apiKeyUsecase, handler)
+		if limiter, ok := limiters[middleware.AuthRateLimitGroup]; ok {
+			handler = middleware.LimitAuthFailures(limiter, handler)
+		}
+		rpcOptions.APIKeys = apiKeyUsecase
+	} else {
+		log.Info.Println("API key authentication is disabled")
+	}
+
+	root := http.NewServeMux()
+	root.Handle(apiPrefix+"/", http.StripPrefix(apiPrefix, handler))
+	if config.LegacyRoutes {
+		// The unversioned routes are kept for clients written before /v1
+		root.Handle("/", response.Legacy(apiPrefix, config.LegacyRoutesSunset, handler))
+	}
+
+	err = seedDefaultRepository(config, gitRepoUsecase, *log)
+	if err != nil && err != errcodes.ErrRepoAlreadyAdded {
+		log.Error.Fatalf("failed to seed default repository: %s,", err.Error())
+	}
+
+	go gitRepoUsecase.ResumeIndexing(ctx)
+
+	go func() {
+		parsed, err := commitUsecase.ParseStoredMessages(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Error.Printf("Error parsing stored commit messages: %s", err.Error())
+		}
+		if parsed > 0 {
+			log.Info.Printf("Parsed %d stored commit messages", parsed)
+		}
+
+		linked, err := commitUsecase.LinkStoredCommits(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Error.Printf("Error linking stored reverts and fixups: %s", err.Error())
+		}
+		if linked > 0 {
+			log.Info.Printf("Linked %d stored reverts and fixups", linked)
+		}
+	}()
+
+	if config.StatsRepairInterval > 0 {
+		go authorUsecase.RepairStats(ctx, config.StatsRepairInterval)
+	}
+
+	if config.RetentionInterval > 0 {
+		go retentionUsecase.Prune(ctx, config.RetentionInterval)
+	}
+
+	if config.DBDriver != "sqlite" {
+		go database.MaintainCommitPartitions(ctx, dB, 24*time.Hour, *log)
+	}
+
+	server := &http.Server{
+		Addr:    ":" + config.ServerPort,
+		Handler: root,
+	}
+
+	// Start the HTTP server
+	go func() {
+		log.Info.Printf("Server is running on port %s", config.ServerPort)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error.Fatalf("Could not start server: %v", err)
+		}
+	}()
+
+	var rpcServer *rpc.Server
+	if config.GRPCEnabled {
+		lis, err := net.Listen("tcp", ":"+config.GRPCPort)
+		if err != nil {
+			log.Error.Fatalf("Could not listen on gRPC port %s: %v", config.GRPCPort, err)
+		}
+
+		rpcServer = rpc.NewServer(gitRepoUsecase, commitUsecase, authorUsecase, rpcOptions)
+		go func() {
+			log.Info.Printf("gRPC server is running on port %s", config.GRPCPort)
+			if err := rpcServer.Serve(lis); err != nil {
+				log.Error.Fatalf("Could not start gRPC server: %v", err)
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	stop()
+	log.Info.Println("Program is shutting down...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+
+	// Stop accepting requests first so no new indexing jobs are started
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Error.Printf("Error shutting down http server: %s", err.Error())
+	}
+
+	if rpcServer != nil {
+		if err := rpcServer.Shutdown(shutdownCtx); err != nil {
+			log.Error.Printf("Error shutting down gRPC server: %s", err.Error())
+		}
+	}
+
+	if err := gitRepoUsecase.Shutdown(shutdownCtx); err != nil {
+		log.Error.Printf("Error stopping indexing jobs: %s", err.Error())
+	}
+
+	if err := dbClient.CloseDb(shutdownCtx); err != nil {
+		log.Error.Printf("Error closing database connection: %s", err.Error())
+	}
+
+	log.Info.Println("Shutdown complete")
+}
+
+// seedDefaultRepository seeds a default repository to database
+func seedDefaultRepository(config *config.Config, repositoryUsecase usecases.RepoMetaUsecase, log log.Log) error {
+	defaultRepo := dtos.RepositoryInput{
+		Name: config.DefaultRepository,
+	}
+
+	repo, err := repositoryUsecase.InitiateIndexing(context.Background(), defaultRepo)
+	if err != nil && err != errcodes.ErrNoRecordFound {
+		return err
+	}
+
+	if repo != nil {
+		log.Info.Printf("Successfully seeded default repository: %s", repo.Name)
+	}
+	return err
+}
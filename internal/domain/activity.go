@@ -0,0 +1,84 @@
+package domain
+
+import "time"
+
+// ActivityInterval is the size of the buckets commits are counted in.
+type ActivityInterval string
+
+const (
+	IntervalDay   ActivityInterval = "day"
+	IntervalWeek  ActivityInterval = "week"
+	IntervalMonth ActivityInterval = "month"
+)
+
+// Valid reports whether i is a supported interval.
+func (i ActivityInterval) Valid() bool {
+	return i == IntervalDay || i == IntervalWeek || i == IntervalMonth
+}
+
+// Truncate returns the start of the bucket t falls in, in t's location. Weeks
+// start on Monday.
+func (i ActivityInterval) Truncate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	switch i {
+	case IntervalWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, t.Location())
+	case IntervalMonth:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// Next returns the start of the bucket following the one starting at t.
+func (i ActivityInterval) Next(t time.Time) time.Time {
+	switch i {
+	case IntervalWeek:
+		return t.AddDate(0, 0, 7)
+	case IntervalMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// ActivityQuery selects the commits of a repository in [Since, Until).
+type ActivityQuery struct {
+	RepoID   uint
+	Interval ActivityInterval
+	Since    time.Time
+	Until    time.Time
+	Location *time.Location
+	// Authors is the number of most active authors to break down
+	Authors int
+}
+
+type ActivityBucket struct {
+	Start time.Time
+	Count int
+}
+
+// PunchCardCell counts the commits made on a weekday (0 is Sunday) at an hour.
+type PunchCardCell struct {
+	Weekday int
+	Hour    int
+	Count   int
+}
+
+type AuthorActivity struct {
+	Author  Author
+	Count   int
+	Buckets []ActivityBucket
+}
+
+type Activity struct {
+	Interval  ActivityInterval
+	Location  *time.Location
+	Since     time.Time
+	Until     time.Time
+	Total     int
+	Buckets   []ActivityBucket
+	PunchCard []PunchCardCell
+	Authors   []AuthorActivity
+}
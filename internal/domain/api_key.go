@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Role is the access level granted to an API key.
+type Role string
+
+const (
+	RoleReader Role = "reader"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	RoleReader: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Allows reports whether r grants at least the access of required.
+func (r Role) Allows(required Role) bool {
+	return r.Valid() && roleRanks[r] >= roleRanks[required]
+}
+
+type APIKey struct {
+	ID        uint
+	Name      string
+	Prefix    string
+	Role      Role
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
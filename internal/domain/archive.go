@@ -0,0 +1,100 @@
+package domain
+
+import "github.com/just-nibble/git-service/internal/http/dtos"
+
+// ArchiveSummary counts the records of an archive that were exported or
+// imported. Imported counts the commits that were not stored yet.
+type ArchiveSummary struct {
+	Repository string
+	Authors    int
+	Commits    int
+	Imported   int
+}
+
+func (r RepositoryMeta) ToArchive() dtos.ArchiveRepository {
+	return dtos.ArchiveRepository{
+		Name:              r.Name,
+		Owner:             r.OwnerName,
+		Description:       r.Description,
+		Language:          r.Language,
+		URL:               r.URL,
+		ForksCount:        r.ForksCount,
+		StarsCount:        r.StarsCount,
+		OpenIssuesCount:   r.OpenIssuesCount,
+		WatchersCount:     r.WatchersCount,
+		LastPage:          r.LastPage,
+		LastFetchedCommit: r.LastFetchedCommit,
+		Index:             r.Index,
+		Retention:         dtos.RetentionPolicy{Days: r.Retention.Days, Commits: r.Retention.Commits},
+		CreatedAt:         r.CreatedAt,
+	}
+}
+
+// RepositoryFromArchive returns the repository of an archive record.
+func RepositoryFromArchive(a dtos.ArchiveRepository) RepositoryMeta {
+	return RepositoryMeta{
+		Name:              a.Name,
+		OwnerName:         a.Owner,
+		Description:       a.Description,
+		Language:          a.Language,
+		URL:               a.URL,
+		ForksCount:        a.ForksCount,
+		StarsCount:        a.StarsCount,
+		OpenIssuesCount:   a.OpenIssuesCount,
+		WatchersCount:     a.WatchersCount,
+		LastPage:          a.LastPage,
+		LastFetchedCommit: a.LastFetchedCommit,
+		Index:             a.Index,
+		Retention:         RetentionPolicy{Days: a.Retention.Days, Commits: a.Retention.Commits},
+		CreatedAt:         a.CreatedAt,
+	}
+}
+
+func (c Commit) ToArchive() dtos.ArchiveCommit {
+	commit := dtos.ArchiveCommit{
+		Hash:         c.Hash,
+		Message:      c.Message,
+		Date:         c.Date,
+		AuthorID:     c.AuthorID,
+		Additions:    c.Additions,
+		Deletions:    c.Deletions,
+		Type:         c.Conventional.Type,
+		Scope:        c.Conventional.Scope,
+		Breaking:     c.Conventional.Breaking,
+		Description:  c.Conventional.Description,
+		FilesFetched: c.FilesFetched,
+	}
+	for _, f := range c.Files {
+		commit.Files = append(commit.Files, dtos.ArchiveFile{Path: f.Path, Additions: f.Additions, Deletions: f.Deletions})
+	}
+	if c.Link != nil {
+		commit.Link = &dtos.ArchiveLink{Kind: string(c.Link.Kind), Target: c.Link.Target}
+	}
+	return commit
+}
+
+// CommitFromArchive returns the commit of an archive record made by author.
+func CommitFromArchive(a dtos.ArchiveCommit, author Author) Commit {
+	commit := Commit{
+		Hash:      a.Hash,
+		Message:   a.Message,
+		Date:      a.Date,
+		Author:    author,
+		Additions: a.Additions,
+		Deletions: a.Deletions,
+		Conventional: ConventionalCommit{
+			Type:        a.Type,
+			Scope:       a.Scope,
+			Breaking:    a.Breaking,
+			Description: a.Description,
+		},
+		FilesFetched: a.FilesFetched,
+	}
+	for _, f := range a.Files {
+		commit.Files = append(commit.Files, CommitFile{Path: f.Path, Additions: f.Additions, Deletions: f.Deletions})
+	}
+	if a.Link != nil {
+		commit.Link = &CommitLink{Kind: CommitLinkKind(a.Link.Kind), Target: a.Link.Target}
+	}
+	return commit
+}
@@ -1,8 +1,68 @@
 package domain
 
+import (
+	"strings"
+	"time"
+)
+
 type Author struct {
 	ID          uint
 	Name        string
 	Email       string
 	CommitCount int
+	// RepositoryCount is the number of repositories in a cross-repository ranking
+	// the author committed to
+	RepositoryCount int
+	Additions       int
+	Deletions       int
+	FirstCommitAt   time.Time
+	LastCommitAt    time.Time
+}
+
+// RepositoryContribution summarizes the commits of an author to a repository.
+type RepositoryContribution struct {
+	RepositoryID   uint
+	RepositoryName string
+	CommitCount    int
+	FirstCommitAt  time.Time
+	LastCommitAt   time.Time
+}
+
+// AuthorProfile is an author with their contributions to every tracked
+// repository, Author holds the totals.
+type AuthorProfile struct {
+	Author
+	Repositories []RepositoryContribution
+}
+
+// AuthorRanking is the measure top authors are ranked by.
+type AuthorRanking string
+
+const (
+	RankByCommits AuthorRanking = "commits"
+	RankByLines   AuthorRanking = "lines"
+)
+
+// Valid reports whether r is a supported ranking.
+func (r AuthorRanking) Valid() bool {
+	return r == RankByCommits || r == RankByLines
+}
+
+// TopAuthorsQuery selects the authors of commits in [Since, Until), a zero time
+// leaves that side of the window open.
+type TopAuthorsQuery struct {
+	Limit  int
+	Since  time.Time
+	Until  time.Time
+	RankBy AuthorRanking
+	// Exclude holds case-insensitive patterns matched against the name and email,
+	// `*` matches any run of characters and everything else is literal
+	Exclude []string
+}
+
+// LikePattern converts an exclude pattern to a SQL LIKE pattern using `\` as
+// the escape character.
+func LikePattern(pattern string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "*", "%")
+	return replacer.Replace(strings.ToLower(pattern))
 }
@@ -10,4 +10,28 @@ type Commit struct {
 	Author   Author
 	AuthorID uint
 	RepoID   uint
+	// Additions and Deletions are the lines changed, 0 until file stats are indexed
+	Additions    int
+	Deletions    int
+	Conventional ConventionalCommit
+	// FilesFetched is set once the changed files and line counts are stored
+	FilesFetched bool
+	// Files are the changed files, only set when they are fetched
+	Files []CommitFile
+	// Link is the commit this commit reverts or amends, nil for other commits
+	Link *CommitLink
+	// RevertedBy are the hashes of the indexed commits reverting this commit
+	RevertedBy []string
+}
+
+// CommitFilter restricts commit listings by their parsed message and date, zero
+// values match every commit.
+type CommitFilter struct {
+	Types    []string
+	Scope    string
+	Breaking *bool
+	// After excludes commits dated at or before it
+	After time.Time
+	// Until excludes commits dated after it
+	Until time.Time
 }
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ConventionalCommit is a commit message parsed according to the Conventional
+// Commits specification. Type is empty for messages that do not follow it.
+type ConventionalCommit struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+var (
+	conventionalHeader = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()\r\n]*)\))?(!)?: +(\S.*)$`)
+	breakingFooter     = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+)
+
+// ParseConventionalCommit parses the header and footers of a commit message.
+func ParseConventionalCommit(message string) ConventionalCommit {
+	header, body, _ := strings.Cut(message, "\n")
+
+	m := conventionalHeader.FindStringSubmatch(strings.TrimSpace(header))
+	if m == nil {
+		return ConventionalCommit{}
+	}
+
+	return ConventionalCommit{
+		Type:        strings.ToLower(m[1]),
+		Scope:       strings.TrimSpace(m[2]),
+		Breaking:    m[3] == "!" || breakingFooter.MatchString(body),
+		Description: strings.TrimSpace(m[4]),
+	}
+}
+
+// ChangelogSection is a group of commits of the same type in release notes.
+type ChangelogSection struct {
+	Type    string
+	Title   string
+	Commits []Commit
+}
+
+// ChangelogTypes are the commit types with their own section in release notes,
+// in the order they are listed. Commits of other types go to "Other Changes".
+var ChangelogTypes = []struct {
+	Type  string
+	Title string
+}{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance Improvements"},
+	{"revert", "Reverts"},
+	{"refactor", "Code Refactoring"},
+	{"docs", "Documentation"},
+	{"test", "Tests"},
+	{"build", "Build System"},
+	{"ci", "Continuous Integration"},
+	{"style", "Styles"},
+	{"chore", "Chores"},
+}
+
+// Changelog holds the release notes for the commits after From up to and
+// including To.
+type Changelog struct {
+	Repository RepositoryMeta
+	From       *Commit
+	To         Commit
+	Breaking   []Commit
+	Sections   []ChangelogSection
+}
+
+// NewChangelog groups commits, newest first, into release note sections.
+func NewChangelog(repo RepositoryMeta, from *Commit, to Commit, commits []Commit) *Changelog {
+	changelog := &Changelog{Repository: repo, From: from, To: to, Breaking: []Commit{}, Sections: []ChangelogSection{}}
+
+	byType := make(map[string][]Commit)
+	for _, c := range commits {
+		if c.Conventional.Breaking {
+			changelog.Breaking = append(changelog.Breaking, c)
+		}
+		byType[c.Conventional.Type] = append(byType[c.Conventional.Type], c)
+	}
+
+	for _, t := range ChangelogTypes {
+		if len(byType[t.Type]) > 0 {
+			changelog.Sections = append(changelog.Sections, ChangelogSection{Type: t.Type, Title: t.Title, Commits: byType[t.Type]})
+		}
+		delete(byType, t.Type)
+	}
+
+	var other []Commit
+	for _, c := range commits {
+		if _, ok := byType[c.Conventional.Type]; ok {
+			other = append(other, c)
+		}
+	}
+	if len(other) > 0 {
+		changelog.Sections = append(changelog.Sections, ChangelogSection{Title: "Other Changes", Commits: other})
+	}
+
+	return changelog
+}
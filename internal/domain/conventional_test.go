@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected ConventionalCommit
+	}{
+		{"feat: add changelog", ConventionalCommit{Type: "feat", Description: "add changelog"}},
+		{"fix(parser): handle empty scope\n\nbody", ConventionalCommit{Type: "fix", Scope: "parser", Description: "handle empty scope"}},
+		{"Refactor!: drop Go 1.21", ConventionalCommit{Type: "refactor", Breaking: true, Description: "drop Go 1.21"}},
+		{"feat(api): new auth\n\nBREAKING CHANGE: tokens are required", ConventionalCommit{Type: "feat", Scope: "api", Breaking: true, Description: "new auth"}},
+		{"chore: bump deps\n\nBREAKING-CHANGE: requires Go 1.22", ConventionalCommit{Type: "chore", Breaking: true, Description: "bump deps"}},
+		{"Merge pull request #12 from org/branch", ConventionalCommit{}},
+		{"feat:missing space", ConventionalCommit{}},
+		{"fix(a)(b): nested scope", ConventionalCommit{}},
+		{"", ConventionalCommit{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseConventionalCommit(tt.message))
+		})
+	}
+}
+
+func TestNewChangelog(t *testing.T) {
+	commits := []Commit{
+		{Hash: "1", Conventional: ConventionalCommit{Type: "fix", Description: "a"}},
+		{Hash: "2", Conventional: ConventionalCommit{Type: "feat", Breaking: true, Description: "b"}},
+		{Hash: "3", Message: "Update README"},
+		{Hash: "4", Conventional: ConventionalCommit{Type: "wip", Description: "c"}},
+	}
+
+	changelog := NewChangelog(RepositoryMeta{Name: "org/repo"}, nil, Commit{Hash: "4"}, commits)
+
+	assert.Equal(t, []Commit{commits[1]}, changelog.Breaking)
+	if assert.Len(t, changelog.Sections, 3) {
+		assert.Equal(t, "Features", changelog.Sections[0].Title)
+		assert.Equal(t, "Bug Fixes", changelog.Sections[1].Title)
+		assert.Equal(t, "Other Changes", changelog.Sections[2].Title)
+		assert.Equal(t, []Commit{commits[2], commits[3]}, changelog.Sections[2].Commits)
+	}
+}
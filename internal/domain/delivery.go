@@ -0,0 +1,98 @@
+package domain
+
+import "time"
+
+// DeploymentSource is what marks a deployment of a repository.
+type DeploymentSource string
+
+const (
+	// DeploymentTags treats every tag as a deployment of its commit
+	DeploymentTags DeploymentSource = "tags"
+	// DeploymentReleases treats every published release that is not a
+	// prerelease as a deployment of its tag
+	DeploymentReleases DeploymentSource = "releases"
+	// DeploymentBranch treats every pull request merged into a branch as a
+	// deployment
+	DeploymentBranch DeploymentSource = "branch"
+)
+
+// Valid reports whether s is a supported source.
+func (s DeploymentSource) Valid() bool {
+	return s == DeploymentTags || s == DeploymentReleases || s == DeploymentBranch
+}
+
+// DeliveryQuery selects the deployments of a repository in [Since, Until).
+type DeliveryQuery struct {
+	RepoID   uint
+	Source   DeploymentSource
+	Branch   string
+	Since    time.Time
+	Until    time.Time
+	Location *time.Location
+	// FailurePattern is a LikePattern matched against lowercased commit messages
+	// to recognize hotfixes, reverts are always failures
+	FailurePattern string
+}
+
+// Deployment shipped the commits dated up to and including Cutoff that earlier
+// deployments did not ship, at time At.
+type Deployment struct {
+	At     time.Time
+	Cutoff time.Time
+}
+
+// DeliveredCommit is a commit as seen by the delivery metrics, Failure is set
+// for commits that revert or hotfix an earlier change.
+type DeliveredCommit struct {
+	Date    time.Time
+	Failure bool
+}
+
+// DeliveryBucket holds the metrics of the deployments made in the week
+// starting at Start. LeadTime is the median time from commit to deployment.
+type DeliveryBucket struct {
+	Start             time.Time
+	Deployments       int
+	FailedDeployments int
+	Commits           int
+	LeadTime          time.Duration
+}
+
+type DeliveryMetrics struct {
+	Source            DeploymentSource
+	Branch            string
+	Location          *time.Location
+	Since             time.Time
+	Until             time.Time
+	Deployments       int
+	FailedDeployments int
+	Commits           int
+	LeadTime          time.Duration
+	Buckets           []DeliveryBucket
+}
+
+// DeploymentsPerWeek is the average number of deployments per week.
+func (m DeliveryMetrics) DeploymentsPerWeek() float64 {
+	weeks := m.Until.Sub(m.Since).Hours() / (7 * 24)
+	if weeks <= 0 {
+		return 0
+	}
+	return float64(m.Deployments) / weeks
+}
+
+// ChangeFailureRate is the share of deployments that shipped a failure fix.
+func (b DeliveryBucket) ChangeFailureRate() float64 {
+	return changeFailureRate(b.FailedDeployments, b.Deployments)
+}
+
+// ChangeFailureRate is the share of deployments that shipped a failure fix.
+func (m DeliveryMetrics) ChangeFailureRate() float64 {
+	return changeFailureRate(m.FailedDeployments, m.Deployments)
+}
+
+func changeFailureRate(failed, deployments int) float64 {
+	if deployments == 0 {
+		return 0
+	}
+	return float64(failed) / float64(deployments)
+}
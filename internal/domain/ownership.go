@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// CommitFile is a file changed by a commit.
+type CommitFile struct {
+	Path      string
+	Additions int
+	Deletions int
+}
+
+// OwnershipQuery selects the file changes under Path of the commits of a
+// repository made since Since.
+type OwnershipQuery struct {
+	RepoID uint
+	// Path is a directory prefix as returned by PathPrefix, empty for the
+	// whole repository
+	Path  string
+	Since time.Time
+	// Limit bounds the owners, directories and hotspots returned
+	Limit int
+}
+
+// PathPrefix normalizes a path to a directory prefix ending in a slash, the
+// root of the repository is the empty prefix.
+func PathPrefix(path string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return ""
+	}
+	return path + "/"
+}
+
+// DirectoryChurn is the churn an author caused in the files directly in a
+// directory. Changes counts changed files, Churn counts added and deleted lines.
+type DirectoryChurn struct {
+	Directory string
+	Author    Author
+	Changes   int
+	Churn     int
+}
+
+// PathOwner is an author of the files under a path, Share is their part of the
+// churn.
+type PathOwner struct {
+	Author  Author
+	Changes int
+	Churn   int
+	Share   float64
+}
+
+type DirectoryOwnership struct {
+	Path      string
+	Changes   int
+	Churn     int
+	BusFactor int
+	Owners    []PathOwner
+}
+
+// Hotspot is a file ranked by its churn.
+type Hotspot struct {
+	Path    string
+	Commits int
+	Churn   int
+}
+
+type Ownership struct {
+	Path        string
+	Since       time.Time
+	Changes     int
+	Churn       int
+	BusFactor   int
+	Owners      []PathOwner
+	Directories []DirectoryOwnership
+	Hotspots    []Hotspot
+}
+
+// Owners ranks the authors in churn by their churn and returns the bus factor:
+// the fewest authors that together caused at least half of the churn.
+func Owners(churn []DirectoryChurn) ([]PathOwner, int) {
+	byAuthor := make(map[uint]*PathOwner)
+	total := 0
+	for _, c := range churn {
+		owner, ok := byAuthor[c.Author.ID]
+		if !ok {
+			owner = &PathOwner{Author: c.Author}
+			byAuthor[c.Author.ID] = owner
+		}
+		owner.Changes += c.Changes
+		owner.Churn += c.Churn
+		total += c.Churn
+	}
+
+	owners := make([]PathOwner, 0, len(byAuthor))
+	for _, o := range byAuthor {
+		if total > 0 {
+			o.Share = float64(o.Churn) / float64(total)
+		}
+		owners = append(owners, *o)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if owners[i].Churn != owners[j].Churn {
+			return owners[i].Churn > owners[j].Churn
+		}
+		return owners[i].Author.ID < owners[j].Author.ID
+	})
+
+	busFactor, covered := 0, 0
+	for _, o := range owners {
+		if total == 0 || 2*covered >= total {
+			break
+		}
+		covered += o.Churn
+		busFactor++
+	}
+
+	return owners, busFactor
+}
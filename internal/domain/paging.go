@@ -2,8 +2,11 @@ package domain
 
 type (
 	APIPaging struct {
-		Limit     int
-		Page      int
+		Limit int
+		Page  int
+		// Offset skips the first items instead of whole pages, it replaces Page
+		// when set
+		Offset    int
 		Sort      string
 		Direction string
 	}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// PullRequestState is the state of a pull request, merged pull requests are
+// not closed.
+type PullRequestState string
+
+const (
+	PullRequestOpen   PullRequestState = "open"
+	PullRequestClosed PullRequestState = "closed"
+	PullRequestMerged PullRequestState = "merged"
+)
+
+// Valid reports whether s is a known state.
+func (s PullRequestState) Valid() bool {
+	switch s {
+	case PullRequestOpen, PullRequestClosed, PullRequestMerged:
+		return true
+	}
+	return false
+}
+
+// PullRequest is a pull request of a repository. UpdatedAt is the time it was
+// last updated on the git host and Commits are the hashes of its commits.
+type PullRequest struct {
+	ID              uint
+	RepoID          uint
+	Number          int
+	Title           string
+	Author          string
+	State           PullRequestState
+	BaseBranch      string
+	HeadBranch      string
+	MergeCommitHash string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	MergedAt        *time.Time
+	ClosedAt        *time.Time
+	Commits         []string
+}
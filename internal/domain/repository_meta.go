@@ -21,12 +21,13 @@ type RepositoryMeta struct {
 	LastFetchedCommit string
 	Index             bool
 	Since             time.Time
+	Retention         RetentionPolicy
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
 
 func (r RepositoryMeta) ToDto() dtos.RepositoryMeta {
-	return dtos.RepositoryMeta{
+	repo := dtos.RepositoryMeta{
 		Name:            r.Name,
 		Description:     r.Description,
 		URL:             r.URL,
@@ -37,5 +38,8 @@ func (r RepositoryMeta) ToDto() dtos.RepositoryMeta {
 		WatchersCount:   r.WatchersCount,
 		CreatedAt:       r.CreatedAt,
 		UpdatedAt:       r.UpdatedAt,
+		Retention:       dtos.RetentionPolicy{Days: r.Retention.Days, Commits: r.Retention.Commits},
 	}
+	repo.Owner.Login = r.OwnerName
+	return repo
 }
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// RetentionPolicy bounds the commits kept of a repository. Commits older than
+// Days days or beyond the newest Commits commits expire, zero values keep every
+// commit.
+type RetentionPolicy struct {
+	Days    int
+	Commits int
+}
+
+// IsZero reports whether the policy keeps every commit.
+func (p RetentionPolicy) IsZero() bool {
+	return p.Days == 0 && p.Commits == 0
+}
+
+// Valid reports whether the limits of the policy are not negative.
+func (p RetentionPolicy) Valid() bool {
+	return p.Days >= 0 && p.Commits >= 0
+}
+
+// Cutoff returns the time commits dated before expire, zero when the policy
+// does not limit the age of commits.
+func (p RetentionPolicy) Cutoff(now time.Time) time.Time {
+	if p.Days == 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -p.Days)
+}
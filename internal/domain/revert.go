@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CommitLinkKind is how a commit undoes or amends the commit it links to.
+type CommitLinkKind string
+
+const (
+	LinkRevert CommitLinkKind = "revert"
+	LinkFixup  CommitLinkKind = "fixup"
+	LinkSquash CommitLinkKind = "squash"
+)
+
+// CommitLink links a commit to the commit it reverts or amends. Target is the
+// hash named by a revert, possibly abbreviated, or the subject named by a
+// fixup! or squash! prefix. Hash is the full hash of the target once it is
+// indexed.
+type CommitLink struct {
+	Kind   CommitLinkKind
+	Target string
+	Hash   string
+}
+
+var (
+	revertBody     = regexp.MustCompile(`(?m)^This reverts commit ([0-9a-fA-F]{7,40})\b`)
+	autosquashHead = regexp.MustCompile(`^(fixup|squash)! +(\S.*)$`)
+)
+
+// ParseCommitLink returns the commit a message reverts, as written by git
+// revert, or amends, as written by git commit --fixup and --squash. It returns
+// nil for other messages.
+func ParseCommitLink(message string) *CommitLink {
+	if m := revertBody.FindStringSubmatch(message); m != nil {
+		return &CommitLink{Kind: LinkRevert, Target: strings.ToLower(m[1])}
+	}
+
+	header, _, _ := strings.Cut(message, "\n")
+	m := autosquashHead.FindStringSubmatch(strings.TrimSpace(header))
+	if m == nil {
+		return nil
+	}
+
+	// fixups of fixups target the subject of the first commit
+	target := m[2]
+	for {
+		next := autosquashHead.FindStringSubmatch(target)
+		if next == nil {
+			break
+		}
+		target = next[2]
+	}
+
+	return &CommitLink{Kind: CommitLinkKind(m[1]), Target: target}
+}
+
+// Revert is a commit reverting Reverted, which is nil until the reverted commit
+// is indexed.
+type Revert struct {
+	Commit   Commit
+	Reverted *Commit
+}
+
+// RevertActivity counts the reverts of a repository per interval in [Since, Until).
+type RevertActivity struct {
+	Interval ActivityInterval
+	Location *time.Location
+	Since    time.Time
+	Until    time.Time
+	Total    int
+	Buckets  []ActivityBucket
+	// Reverts are the most recent reverts, newest first
+	Reverts []Revert
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommitLink(t *testing.T) {
+	hash := "0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		message  string
+		expected *CommitLink
+	}{
+		{"Revert \"feat: add changelog\"\n\nThis reverts commit " + hash + ".", &CommitLink{Kind: LinkRevert, Target: hash}},
+		{"revert: drop cache\n\nThis reverts commit ABCDEF1, it broke the build.", &CommitLink{Kind: LinkRevert, Target: "abcdef1"}},
+		{"fixup! feat: add changelog", &CommitLink{Kind: LinkFixup, Target: "feat: add changelog"}},
+		{"squash! fixup! fix(parser): handle empty scope\n\nbody", &CommitLink{Kind: LinkSquash, Target: "fix(parser): handle empty scope"}},
+		{"Revert \"feat: add changelog\"", nil},
+		{"docs: explain that This reverts commit abc is written by git", nil},
+		{"fixup!missing space", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseCommitLink(tt.message))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// Tag is a git tag, Date is the date of the commit it points to.
+type Tag struct {
+	ID     uint
+	RepoID uint
+	Name   string
+	Hash   string
+	Date   time.Time
+}
+
+// Release is a release published on the git host for a tag.
+type Release struct {
+	ID          uint
+	RepoID      uint
+	TagName     string
+	Name        string
+	Body        string
+	Prerelease  bool
+	PublishedAt time.Time
+}
+
+// Comparison is the commits after From up to and including To.
+type Comparison struct {
+	From    Commit
+	To      Commit
+	Commits []Commit
+}
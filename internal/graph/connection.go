@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+)
+
+// cursorPrefix marks the offset in a cursor, cursors are opaque to clients
+const cursorPrefix = "offset:"
+
+// commitConnection is a page of commits starting at offset
+type commitConnection struct {
+	commits []domain.Commit
+	offset  int
+	paging  domain.PagingInfo
+}
+
+type commitEdge struct {
+	Cursor string
+	Node   domain.Commit
+}
+
+func (c commitConnection) edges() []commitEdge {
+	edges := make([]commitEdge, 0, len(c.commits))
+	for i, commit := range c.commits {
+		edges = append(edges, commitEdge{Cursor: encodeCursor(c.offset + i), Node: commit})
+	}
+	return edges
+}
+
+// endCursor returns the cursor of the last edge, or nil for an empty page.
+func (c commitConnection) endCursor() interface{} {
+	if len(c.commits) == 0 {
+		return nil
+	}
+	return encodeCursor(c.offset + len(c.commits) - 1)
+}
+
+func encodeCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// decodeCursor returns the offset of the edge a cursor belongs to.
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errcodes.ErrInvalidCursor
+	}
+
+	value, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return 0, errcodes.ErrInvalidCursor
+	}
+
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, errcodes.ErrInvalidCursor
+	}
+	return offset, nil
+}
@@ -0,0 +1,191 @@
+package graph
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+)
+
+// Limits bound the queries a client can send, a zero limit is not checked.
+type Limits struct {
+	// MaxDepth is the deepest nesting of fields
+	MaxDepth int
+	// MaxComplexity is the largest cost of a query, see measure
+	MaxComplexity int
+	// MaxFanout is the most lists a query reads for each of the items of
+	// another list, such as the commits of every repository
+	MaxFanout int
+}
+
+// fanoutFields are the fields read with a query of their own for each of
+// their parents.
+var fanoutFields = map[string]bool{
+	"Repository.commits":    true,
+	"Repository.topAuthors": true,
+}
+
+// check returns an error when the operation of a valid document exceeds the
+// limits.
+func (l Limits) check(schema graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) error {
+	depth, complexity, fanout := measure(schema, doc, operationName, variables)
+
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return errcodes.ErrQueryTooDeep.WithDetails(map[string]interface{}{
+			"depth":     depth,
+			"max_depth": l.MaxDepth,
+		})
+	}
+	if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+		return errcodes.ErrQueryTooComplex.WithDetails(map[string]interface{}{
+			"complexity":     complexity,
+			"max_complexity": l.MaxComplexity,
+		})
+	}
+	if l.MaxFanout > 0 && fanout > l.MaxFanout {
+		return errcodes.ErrQueryFansOut.WithDetails(map[string]interface{}{
+			"fanout":     fanout,
+			"max_fanout": l.MaxFanout,
+		})
+	}
+	return nil
+}
+
+// measure returns the depth, complexity and fanout of the operation to execute.
+// Every field costs 1, a field with a first argument multiplies the cost of its
+// selection by the number of items asked for. The fanout counts the
+// fanoutFields read, once for each item of the lists they are nested in.
+// Introspection is free.
+func measure(schema graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) (depth, complexity, fanout int) {
+	m := measurer{
+		schema:    schema,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+		visited:   make(map[string]bool),
+	}
+
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch d := definition.(type) {
+		case *ast.OperationDefinition:
+			if operation == nil && (operationName == "" || d.Name != nil && d.Name.Value == operationName) {
+				operation = d
+			}
+		case *ast.FragmentDefinition:
+			m.fragments[d.Name.Value] = d
+		}
+	}
+	if operation == nil {
+		return 0, 0, 0
+	}
+
+	return m.selectionSet(schema.QueryType(), operation.SelectionSet)
+}
+
+type measurer struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	// visited are the fragments spread on the current path, validation rejects
+	// cycles but they must not hang the walk either
+	visited map[string]bool
+}
+
+func (m *measurer) selectionSet(parent *graphql.Object, set *ast.SelectionSet) (depth, complexity, fanout int) {
+	if set == nil {
+		return 0, 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c, f int
+		switch s := selection.(type) {
+		case *ast.Field:
+			d, c, f = m.field(parent, s)
+		case *ast.InlineFragment:
+			d, c, f = m.selectionSet(m.typeCondition(parent, s.TypeCondition), s.SelectionSet)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			fragment, ok := m.fragments[name]
+			if !ok || m.visited[name] {
+				continue
+			}
+			m.visited[name] = true
+			d, c, f = m.selectionSet(m.typeCondition(parent, fragment.TypeCondition), fragment.SelectionSet)
+			delete(m.visited, name)
+		}
+
+		depth = max(depth, d)
+		complexity += c
+		fanout += f
+	}
+	return depth, complexity, fanout
+}
+
+func (m *measurer) field(parent *graphql.Object, field *ast.Field) (depth, complexity, fanout int) {
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return 0, 0, 0
+	}
+	if parent == nil {
+		return 1, 1, 0
+	}
+
+	definition, ok := parent.Fields()[field.Name.Value]
+	if !ok {
+		return 1, 1, 0
+	}
+
+	child, _ := graphql.GetNamed(definition.Type).(*graphql.Object)
+	depth, complexity, fanout = m.selectionSet(child, field.SelectionSet)
+	items := m.items(definition, field)
+	fanout *= items
+	if fanoutFields[parent.Name()+"."+definition.Name] {
+		fanout++
+	}
+	return depth + 1, 1 + complexity*items, fanout
+}
+
+// items returns the number of items a field asks for with its first argument,
+// 1 for fields without one.
+func (m *measurer) items(definition *graphql.FieldDefinition, field *ast.Field) int {
+	var first *graphql.Argument
+	for _, arg := range definition.Args {
+		if arg.Name() == "first" {
+			first = arg
+		}
+	}
+	if first == nil {
+		return 1
+	}
+
+	items, _ := first.DefaultValue.(int)
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		switch value := arg.Value.(type) {
+		case *ast.IntValue:
+			items, _ = strconv.Atoi(value.Value)
+		case *ast.Variable:
+			switch n := m.variables[value.Name.Value].(type) {
+			case float64:
+				// variables decoded from JSON
+				items = int(n)
+			case int:
+				items = n
+			}
+		}
+	}
+
+	return min(max(items, 1), dtos.MaxLimit)
+}
+
+func (m *measurer) typeCondition(parent *graphql.Object, condition *ast.Named) *graphql.Object {
+	if condition == nil {
+		return parent
+	}
+	object, _ := m.schema.Type(condition.Name.Value).(*graphql.Object)
+	return object
+}
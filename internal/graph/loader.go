@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/just-nibble/git-service/internal/domain"
+)
+
+// authorLoader batches the author profiles asked for while a level of a query
+// is resolved. Resolvers queue the authors they need and return a thunk, the
+// executor calls the thunks once the level is done and the first one reads
+// every queued author at once.
+type authorLoader struct {
+	fetch func(ctx context.Context, authorIDs []uint) ([]domain.AuthorProfile, error)
+
+	mu       sync.Mutex
+	queued   []uint
+	loaded   map[uint]bool
+	profiles map[uint]*domain.AuthorProfile
+	errs     map[uint]error
+}
+
+func newAuthorLoader(fetch func(ctx context.Context, authorIDs []uint) ([]domain.AuthorProfile, error)) *authorLoader {
+	return &authorLoader{
+		fetch:    fetch,
+		loaded:   make(map[uint]bool),
+		profiles: make(map[uint]*domain.AuthorProfile),
+		errs:     make(map[uint]error),
+	}
+}
+
+// Load queues an author and returns a thunk of its profile, the profile is nil
+// when the author is unknown.
+func (l *authorLoader) Load(ctx context.Context, authorID uint) func() (*domain.AuthorProfile, error) {
+	l.mu.Lock()
+	if !l.loaded[authorID] {
+		l.loaded[authorID] = true
+		l.queued = append(l.queued, authorID)
+	}
+	l.mu.Unlock()
+
+	return func() (*domain.AuthorProfile, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.flush(ctx)
+		return l.profiles[authorID], l.errs[authorID]
+	}
+}
+
+// flush reads the queued authors, l.mu must be held.
+func (l *authorLoader) flush(ctx context.Context) {
+	if len(l.queued) == 0 {
+		return
+	}
+	authorIDs := l.queued
+	l.queued = nil
+
+	profiles, err := l.fetch(ctx, authorIDs)
+	if err != nil {
+		for _, id := range authorIDs {
+			l.errs[id] = err
+		}
+		return
+	}
+
+	for i := range profiles {
+		l.profiles[profiles[i].ID] = &profiles[i]
+	}
+}
+
+type loaderKey struct{}
+
+func withAuthorLoader(ctx context.Context, loader *authorLoader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, loader)
+}
+
+func authorLoaderFrom(ctx context.Context) *authorLoader {
+	return ctx.Value(loaderKey{}).(*authorLoader)
+}
@@ -0,0 +1,199 @@
+package graph
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+)
+
+// resolver resolves the root fields and the fields with arguments through the
+// usecases of the REST API.
+type resolver struct {
+	repoMetaUsecase usecases.RepoMetaUsecase
+	commitUsecase   usecases.GitCommitUsecase
+	authorUsecase   usecases.AuthorUseCase
+}
+
+func (r *resolver) repository(p graphql.ResolveParams) (interface{}, error) {
+	repo, err := r.repoMetaUsecase.FindRepoByName(p.Context, p.Args["name"].(string))
+	if err == errcodes.ErrNoRecordFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return *repo, nil
+}
+
+func (r *resolver) repositories(p graphql.ResolveParams) (interface{}, error) {
+	first, err := firstArg(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := r.repoMetaUsecase.RetrieveAllRepos(p.Context)
+	if err != nil {
+		return nil, err
+	}
+	return firstItems(repos, first), nil
+}
+
+// author returns null for unknown authors, the profile read for it is reused
+// by the fields of the author.
+func (r *resolver) author(p graphql.ResolveParams) (interface{}, error) {
+	id, err := strconv.ParseUint(p.Args["id"].(string), 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+
+	load := authorLoaderFrom(p.Context).Load(p.Context, uint(id))
+	return func() (interface{}, error) {
+		profile, err := load()
+		if err != nil || profile == nil {
+			return nil, err
+		}
+		return profile.Author, nil
+	}, nil
+}
+
+func (r *resolver) topAuthors(p graphql.ResolveParams) (interface{}, error) {
+	query, err := topAuthorsQuery(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	group, _ := p.Args["group"].(string)
+	return r.authorUsecase.GetTopAuthorsAcross(p.Context, group, query)
+}
+
+func (r *resolver) repositoryTopAuthors(p graphql.ResolveParams) (interface{}, error) {
+	query, err := topAuthorsQuery(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.authorUsecase.GetTopAuthors(p.Context, p.Source.(domain.RepositoryMeta).Name, query)
+}
+
+func (r *resolver) commits(p graphql.ResolveParams) (interface{}, error) {
+	first, err := firstArg(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	var offset int
+	if after, ok := p.Args["after"].(string); ok {
+		if offset, err = decodeCursor(after); err != nil {
+			return nil, err
+		}
+		offset++
+	}
+
+	filter := domain.CommitFilter{
+		Types: stringList(p.Args["types"]),
+	}
+	filter.Scope, _ = p.Args["scope"].(string)
+	if breaking, ok := p.Args["breaking"].(bool); ok {
+		filter.Breaking = &breaking
+	}
+
+	query := domain.APIPaging{
+		Limit:     first,
+		Offset:    offset,
+		Sort:      "date",
+		Direction: p.Args["direction"].(string),
+	}
+
+	repo := p.Source.(domain.RepositoryMeta)
+	commits, paging, err := r.commitUsecase.GetAllCommitsByRepository(p.Context, repo.Name, query, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return commitConnection{commits: commits, offset: offset, paging: paging}, nil
+}
+
+// profileField resolves a field of an author from its profile, the profiles
+// of every author of a level of the query are read at once.
+func profileField(get func(profile *domain.AuthorProfile) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		load := authorLoaderFrom(p.Context).Load(p.Context, p.Source.(domain.Author).ID)
+		return func() (interface{}, error) {
+			profile, err := load()
+			if err != nil {
+				return nil, err
+			}
+			if profile == nil {
+				return nil, errcodes.ErrNoRecordFound
+			}
+			return get(profile), nil
+		}, nil
+	}
+}
+
+func topAuthorsQuery(args map[string]interface{}) (domain.TopAuthorsQuery, error) {
+	first, err := firstArg(args)
+	if err != nil {
+		return domain.TopAuthorsQuery{}, err
+	}
+
+	query := domain.TopAuthorsQuery{
+		Limit:   first,
+		RankBy:  args["rankBy"].(domain.AuthorRanking),
+		Exclude: stringList(args["exclude"]),
+	}
+	query.Since, _ = args["since"].(time.Time)
+	query.Until, _ = args["until"].(time.Time)
+
+	return query, nil
+}
+
+// firstArg returns the number of items asked for, capped like the page limit
+// of the REST API.
+func firstArg(args map[string]interface{}) (int, error) {
+	first, _ := args["first"].(int)
+	if first < 1 {
+		return 0, errcodes.ErrInvalidFirst
+	}
+	if first > dtos.MaxLimit {
+		first = dtos.MaxLimit
+	}
+	return first, nil
+}
+
+// firstItems returns the first items of a list that is not paged.
+func firstItems[T any](items []T, first int) []T {
+	if len(items) > first {
+		return items[:first]
+	}
+	return items
+}
+
+func stringList(arg interface{}) []string {
+	values, _ := arg.([]interface{})
+
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		list = append(list, v.(string))
+	}
+	return list
+}
+
+func optionalString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func optionalTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,360 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+)
+
+// defaultFirst is the number of items of a page when first is not given, the
+// lists that are not paged return up to dtos.MaxLimit items
+const defaultFirst = 10
+
+// newSchema builds the read-only schema over repositories, commits and authors.
+func newSchema(r *resolver) (graphql.Schema, error) {
+	rankingEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name:        "AuthorRanking",
+		Description: "The measure authors are ranked by",
+		Values: graphql.EnumValueConfigMap{
+			"COMMITS": {Value: domain.RankByCommits, Description: "Number of commits"},
+			"LINES":   {Value: domain.RankByLines, Description: "Lines added and deleted"},
+		},
+	})
+
+	directionEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name: "SortDirection",
+		Values: graphql.EnumValueConfigMap{
+			"ASC":  {Value: "asc", Description: "Oldest first"},
+			"DESC": {Value: "desc", Description: "Newest first"},
+		},
+	})
+
+	linkKindEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name: "CommitLinkKind",
+		Values: graphql.EnumValueConfigMap{
+			"REVERT": {Value: domain.LinkRevert},
+			"FIXUP":  {Value: domain.LinkFixup},
+			"SQUASH": {Value: domain.LinkSquash},
+		},
+	})
+
+	contributionType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "RepositoryContribution",
+		Description: "The commits of an author to a repository",
+		Fields: graphql.Fields{
+			"repositoryName": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"commitCount":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"firstCommitAt":  &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"lastCommitAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+
+	authorType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Author",
+		Description: "An author with the totals of their commits to every tracked repository",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(domain.Author).ID, nil },
+			},
+			"name":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"email": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"commitCount": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: profileField(func(profile *domain.AuthorProfile) interface{} {
+					return profile.CommitCount
+				}),
+			},
+			"repositoryCount": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: profileField(func(profile *domain.AuthorProfile) interface{} {
+					return profile.RepositoryCount
+				}),
+			},
+			"firstCommitAt": &graphql.Field{
+				Type: graphql.DateTime,
+				Resolve: profileField(func(profile *domain.AuthorProfile) interface{} {
+					return optionalTime(profile.FirstCommitAt)
+				}),
+			},
+			"lastCommitAt": &graphql.Field{
+				Type: graphql.DateTime,
+				Resolve: profileField(func(profile *domain.AuthorProfile) interface{} {
+					return optionalTime(profile.LastCommitAt)
+				}),
+			},
+			"repositories": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(contributionType))),
+				Args: graphql.FieldConfigArgument{
+					"first": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: dtos.MaxLimit},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					first, err := firstArg(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					return profileField(func(profile *domain.AuthorProfile) interface{} {
+						return firstItems(profile.Repositories, first)
+					})(p)
+				},
+			},
+		},
+	})
+
+	rankedAuthorType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "RankedAuthor",
+		Description: "An author with the commits counted for a ranking",
+		Fields: graphql.Fields{
+			"author": &graphql.Field{
+				Type:    graphql.NewNonNull(authorType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source, nil },
+			},
+			"commitCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"repositoryCount": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "Repositories committed to, only counted in rankings across repositories",
+			},
+			"additions": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"deletions": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"firstCommitAt": &graphql.Field{
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return optionalTime(p.Source.(domain.Author).FirstCommitAt), nil
+				},
+			},
+			"lastCommitAt": &graphql.Field{
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return optionalTime(p.Source.(domain.Author).LastCommitAt), nil
+				},
+			},
+		},
+	})
+
+	linkType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "CommitLink",
+		Description: "The commit a commit reverts or amends",
+		Fields: graphql.Fields{
+			"kind":   &graphql.Field{Type: graphql.NewNonNull(linkKindEnum)},
+			"target": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The hash or subject as written in the message"},
+			"hash": &graphql.Field{
+				Type:        graphql.String,
+				Description: "The hash of the target, null when it is not indexed",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return optionalString(p.Source.(*domain.CommitLink).Hash), nil
+				},
+			},
+		},
+	})
+
+	commitType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Commit",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(domain.Commit).ID, nil },
+			},
+			"hash":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"message":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"date":      &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"additions": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"deletions": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"type": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return optionalString(p.Source.(domain.Commit).Conventional.Type), nil
+				},
+			},
+			"scope": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return optionalString(p.Source.(domain.Commit).Conventional.Scope), nil
+				},
+			},
+			"breaking": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(domain.Commit).Conventional.Breaking, nil
+				},
+			},
+			"description": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return optionalString(p.Source.(domain.Commit).Conventional.Description), nil
+				},
+			},
+			"link": &graphql.Field{
+				Type: linkType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if link := p.Source.(domain.Commit).Link; link != nil {
+						return link, nil
+					}
+					return nil, nil
+				},
+			},
+			"revertedBy": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if hashes := p.Source.(domain.Commit).RevertedBy; hashes != nil {
+						return hashes, nil
+					}
+					return []string{}, nil
+				},
+			},
+			"author": &graphql.Field{
+				Type: graphql.NewNonNull(authorType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					c := p.Source.(domain.Commit)
+					return domain.Author{ID: c.AuthorID, Name: c.Author.Name, Email: c.Author.Email}, nil
+				},
+			},
+		},
+	})
+
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(commitConnection).paging.HasNextPage, nil
+				},
+			},
+			"endCursor": &graphql.Field{
+				Type:        graphql.String,
+				Description: "The cursor of the last edge, pass it as after to read the next page",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(commitConnection).endCursor(), nil
+				},
+			},
+		},
+	})
+
+	edgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CommitEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: graphql.NewNonNull(commitType)},
+		},
+	})
+
+	connectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CommitConnection",
+		Fields: graphql.Fields{
+			"edges": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(commitConnection).edges(), nil
+				},
+			},
+			"nodes": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(commitType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(commitConnection).commits, nil
+				},
+			},
+			"pageInfo": &graphql.Field{
+				Type:    graphql.NewNonNull(pageInfoType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source, nil },
+			},
+			"totalCount": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(commitConnection).paging.TotalCount, nil
+				},
+			},
+		},
+	})
+
+	rankingArgs := func() graphql.FieldConfigArgument {
+		return graphql.FieldConfigArgument{
+			"first":   &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultFirst},
+			"since":   &graphql.ArgumentConfig{Type: graphql.DateTime, Description: "Only count commits at or after this time"},
+			"until":   &graphql.ArgumentConfig{Type: graphql.DateTime, Description: "Only count commits before this time"},
+			"rankBy":  &graphql.ArgumentConfig{Type: rankingEnum, DefaultValue: domain.RankByCommits},
+			"exclude": &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Name or email patterns of authors to leave out, * matches any run of characters"},
+		}
+	}
+
+	repositoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Repository",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(domain.RepositoryMeta).ID, nil },
+			},
+			"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"owner": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(domain.RepositoryMeta).OwnerName, nil
+				},
+			},
+			"description":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"language":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"url":             &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"forksCount":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"starsCount":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"openIssuesCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"watchersCount":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"createdAt":       &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"updatedAt":       &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"commits": &graphql.Field{
+				Type:        graphql.NewNonNull(connectionType),
+				Description: "The indexed commits ordered by date",
+				Args: graphql.FieldConfigArgument{
+					"first":     &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultFirst},
+					"after":     &graphql.ArgumentConfig{Type: graphql.String, Description: "The cursor of the edge to start after"},
+					"direction": &graphql.ArgumentConfig{Type: directionEnum, DefaultValue: "desc"},
+					"types":     &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Conventional commit types to keep"},
+					"scope":     &graphql.ArgumentConfig{Type: graphql.String},
+					"breaking":  &graphql.ArgumentConfig{Type: graphql.Boolean},
+				},
+				Resolve: r.commits,
+			},
+			"topAuthors": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(rankedAuthorType))),
+				Args:    rankingArgs(),
+				Resolve: r.repositoryTopAuthors,
+			},
+		},
+	})
+
+	topAuthorsArgs := rankingArgs()
+	topAuthorsArgs["group"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "A configured repository group, every repository when not given"}
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"repository": &graphql.Field{
+				Type: repositoryType,
+				Args: graphql.FieldConfigArgument{
+					"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String), Description: "The repository as owner/name"},
+				},
+				Resolve: r.repository,
+			},
+			"repositories": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(repositoryType))),
+				Args: graphql.FieldConfigArgument{
+					"first": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: dtos.MaxLimit},
+				},
+				Resolve: r.repositories,
+			},
+			"author": &graphql.Field{
+				Type: authorType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: r.author,
+			},
+			"topAuthors": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(rankedAuthorType))),
+				Description: "Authors ranked across repositories",
+				Args:        topAuthorsArgs,
+				Resolve:     r.topAuthors,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
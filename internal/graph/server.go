@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+)
+
+// Server executes GraphQL queries over repositories, commits and authors.
+type Server struct {
+	schema        graphql.Schema
+	authorUsecase usecases.AuthorUseCase
+	limits        Limits
+}
+
+func NewServer(repoMetaUsecase usecases.RepoMetaUsecase, commitUsecase usecases.GitCommitUsecase, authorUsecase usecases.AuthorUseCase, limits Limits) (*Server, error) {
+	schema, err := newSchema(&resolver{
+		repoMetaUsecase: repoMetaUsecase,
+		commitUsecase:   commitUsecase,
+		authorUsecase:   authorUsecase,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{schema: schema, authorUsecase: authorUsecase, limits: limits}, nil
+}
+
+// Execute runs a query. Errors are reported in the result with the code of the
+// error in their extensions, as the REST API reports them.
+func (s *Server) Execute(ctx context.Context, req dtos.GraphQLRequest) *graphql.Result {
+	doc, err := parseQuery(req.Query)
+	if err != nil {
+		return &graphql.Result{Errors: withCodes(gqlerrors.FormatErrors(err), errcodes.CodeInvalidArgument)}
+	}
+
+	validation := graphql.ValidateDocument(&s.schema, doc, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: withCodes(validation.Errors, errcodes.CodeInvalidArgument)}
+	}
+
+	if err := s.limits.check(s.schema, doc, req.OperationName, req.Variables); err != nil {
+		return &graphql.Result{Errors: withCodes(gqlerrors.FormatErrors(err), errcodes.CodeInvalidArgument)}
+	}
+
+	// the loader caches profiles, it must not outlive the request
+	ctx = withAuthorLoader(ctx, newAuthorLoader(s.authorUsecase.GetAuthorProfiles))
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+	// without data the variables or operation name were invalid
+	fallback := errcodes.CodeInternal
+	if result.Data == nil {
+		fallback = errcodes.CodeInvalidArgument
+	}
+	result.Errors = withCodes(result.Errors, fallback)
+	return result
+}
+
+func parseQuery(query string) (*ast.Document, error) {
+	return parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(query),
+		Name: "GraphQL request",
+	})})
+}
+
+// withCodes adds the code and details of errcodes errors to the extensions of
+// the errors, errors of the query itself get the code fallback.
+func withCodes(errs []gqlerrors.FormattedError, fallback errcodes.Code) []gqlerrors.FormattedError {
+	for i, formatted := range errs {
+		original := formatted.OriginalError()
+		var located *gqlerrors.Error
+		if errors.As(original, &located) {
+			original = located.OriginalError
+		}
+
+		code := fallback
+		if original != nil {
+			code = errcodes.CodeOf(original)
+		}
+
+		extensions := map[string]interface{}{"code": code}
+		var e *errcodes.Error
+		if errors.As(original, &e) && e.Details != nil {
+			extensions["details"] = e.Details
+		}
+		errs[i].Extensions = extensions
+	}
+	return errs
+}
@@ -0,0 +1,281 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/config"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingAuthorRepository counts the batches of author profiles read
+type countingAuthorRepository struct {
+	repository.AuthorRepository
+	batches [][]uint
+}
+
+func (r *countingAuthorRepository) AuthorProfiles(ctx context.Context, authorIDs []uint) ([]domain.AuthorProfile, error) {
+	r.batches = append(r.batches, authorIDs)
+	return r.AuthorRepository.AuthorProfiles(ctx, authorIDs)
+}
+
+// newTestServer returns a server over a repository with commits of two authors
+func newTestServer(t *testing.T, limits Limits) (*Server, *countingAuthorRepository) {
+	t.Helper()
+	ctx := context.TODO()
+
+	store := repository.NewMemoryStore()
+	repoMetaRepository := repository.NewMemoryRepositoryMetaRepository(store)
+	commitRepository := repository.NewMemoryCommitRepository(store)
+	authorRepository := &countingAuthorRepository{AuthorRepository: repository.NewMemoryAuthorRepository(store)}
+
+	repo, err := repoMetaRepository.SaveRepoMetadata(ctx, domain.RepositoryMeta{Name: "org/repo", OwnerName: "org"})
+	require.NoError(t, err)
+
+	jane := domain.Author{Name: "Jane", Email: "jane@example.com"}
+	john := domain.Author{Name: "John", Email: "john@example.com"}
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commits := []domain.Commit{
+		{Hash: "c1", Message: "feat: one", Date: date, Author: jane},
+		{Hash: "c2", Message: "fix: two", Date: date.Add(time.Hour), Author: john},
+		{Hash: "c3", Message: "feat: three", Date: date.Add(2 * time.Hour), Author: jane},
+	}
+	for i := range commits {
+		commits[i].Conventional = domain.ParseConventionalCommit(commits[i].Message)
+	}
+	_, err = commitRepository.SaveCommits(ctx, *repo, commits)
+	require.NoError(t, err)
+
+	logger := *log.NewLogger()
+	server, err := NewServer(
+		usecases.NewrepoMetaUsecase(repoMetaRepository, commitRepository, authorRepository, nil, nil, nil, config.Config{}, logger),
+		usecases.NewGitCommitUsecase(commitRepository, repoMetaRepository),
+		usecases.NewAuthorUseCase(authorRepository, nil, logger),
+		limits,
+	)
+	require.NoError(t, err)
+
+	return server, authorRepository
+}
+
+// execute runs a query and decodes its result as JSON clients see it
+func execute(t *testing.T, server *Server, query string, variables map[string]interface{}) map[string]interface{} {
+	t.Helper()
+
+	body, err := json.Marshal(server.Execute(context.TODO(), dtos.GraphQLRequest{Query: query, Variables: variables}))
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &result))
+	return result
+}
+
+// errorCode returns the code of the first error of a result
+func errorCode(t *testing.T, result map[string]interface{}) interface{} {
+	t.Helper()
+
+	errs, ok := result["errors"].([]interface{})
+	require.True(t, ok, "result has no errors: %v", result)
+	return errs[0].(map[string]interface{})["extensions"].(map[string]interface{})["code"]
+}
+
+// TestServer_NestedQuery tests that the authors of a page of commits and their
+// profiles are read with a single batch
+func TestServer_NestedQuery(t *testing.T) {
+	// Arrange
+	server, authors := newTestServer(t, Limits{})
+
+	// Act
+	result := execute(t, server, `{
+		repository(name: "org/repo") {
+			name
+			commits(first: 3) {
+				totalCount
+				nodes {
+					hash
+					type
+					author { name commitCount repositories { repositoryName commitCount } }
+				}
+			}
+		}
+	}`, nil)
+
+	// Assert
+	require.Nil(t, result["errors"])
+	commits := result["data"].(map[string]interface{})["repository"].(map[string]interface{})["commits"].(map[string]interface{})
+	assert.Equal(t, float64(3), commits["totalCount"])
+
+	nodes := commits["nodes"].([]interface{})
+	require.Len(t, nodes, 3)
+	newest := nodes[0].(map[string]interface{})
+	assert.Equal(t, "c3", newest["hash"])
+	assert.Equal(t, "feat", newest["type"])
+	author := newest["author"].(map[string]interface{})
+	assert.Equal(t, "Jane", author["name"])
+	assert.Equal(t, float64(2), author["commitCount"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"repositoryName": "org/repo", "commitCount": float64(2)}}, author["repositories"])
+
+	require.Len(t, authors.batches, 1)
+	assert.Len(t, authors.batches[0], 2, "each author is read once")
+}
+
+// TestServer_CommitsPaging tests that the end cursor of a page starts the next
+func TestServer_CommitsPaging(t *testing.T) {
+	// Arrange
+	server, _ := newTestServer(t, Limits{})
+	query := `query($after: String) {
+		repository(name: "org/repo") {
+			commits(first: 2, after: $after, direction: ASC) {
+				edges { cursor node { hash } }
+				pageInfo { hasNextPage endCursor }
+			}
+		}
+	}`
+	page := func(result map[string]interface{}) (hashes []string, pageInfo map[string]interface{}) {
+		require.Nil(t, result["errors"])
+		commits := result["data"].(map[string]interface{})["repository"].(map[string]interface{})["commits"].(map[string]interface{})
+		for _, edge := range commits["edges"].([]interface{}) {
+			hashes = append(hashes, edge.(map[string]interface{})["node"].(map[string]interface{})["hash"].(string))
+		}
+		return hashes, commits["pageInfo"].(map[string]interface{})
+	}
+
+	// Act
+	first, firstInfo := page(execute(t, server, query, nil))
+	second, secondInfo := page(execute(t, server, query, map[string]interface{}{"after": firstInfo["endCursor"]}))
+	invalid := execute(t, server, query, map[string]interface{}{"after": "not a cursor"})
+
+	// Assert
+	assert.Equal(t, []string{"c1", "c2"}, first)
+	assert.Equal(t, true, firstInfo["hasNextPage"])
+	assert.Equal(t, []string{"c3"}, second)
+	assert.Equal(t, false, secondInfo["hasNextPage"])
+	assert.Equal(t, string(errcodes.CodeInvalidArgument), errorCode(t, invalid))
+}
+
+// TestServer_TopAuthors tests rankings and the authors looked up by id
+func TestServer_TopAuthors(t *testing.T) {
+	// Arrange
+	server, authors := newTestServer(t, Limits{})
+
+	// Act
+	result := execute(t, server, `{
+		topAuthors(first: 1) { commitCount author { id name repositoryCount } }
+		repository(name: "org/repo") { topAuthors(rankBy: COMMITS) { author { email lastCommitAt } } }
+		missing: repository(name: "org/missing") { name }
+	}`, nil)
+
+	// Assert
+	require.Nil(t, result["errors"])
+	data := result["data"].(map[string]interface{})
+
+	top := data["topAuthors"].([]interface{})
+	require.Len(t, top, 1)
+	ranked := top[0].(map[string]interface{})
+	assert.Equal(t, float64(2), ranked["commitCount"])
+	author := ranked["author"].(map[string]interface{})
+	assert.Equal(t, "Jane", author["name"])
+	assert.Equal(t, float64(1), author["repositoryCount"])
+
+	assert.Len(t, data["repository"].(map[string]interface{})["topAuthors"], 2)
+	assert.Nil(t, data["missing"])
+	assert.Len(t, authors.batches, 1)
+
+	byID := execute(t, server, `query($id: ID!) { author(id: $id) { name } unknown: author(id: "999") { name } }`,
+		map[string]interface{}{"id": author["id"]})
+	require.Nil(t, byID["errors"])
+	assert.Equal(t, map[string]interface{}{"name": "Jane"}, byID["data"].(map[string]interface{})["author"])
+	assert.Nil(t, byID["data"].(map[string]interface{})["unknown"])
+}
+
+// TestServer_Limits tests that deep and expensive queries are rejected before
+// they are executed
+func TestServer_Limits(t *testing.T) {
+	// Arrange
+	server, authors := newTestServer(t, Limits{MaxDepth: 4, MaxComplexity: 50})
+
+	// Act
+	deep := execute(t, server, `{ repository(name: "org/repo") { commits { nodes { author { name } } } } }`, nil)
+	complex := execute(t, server, `query($n: Int) {
+		repository(name: "org/repo") { commits(first: $n) { nodes { ...fields } } }
+	}
+	fragment fields on Commit { hash message date }`, map[string]interface{}{"n": float64(20)})
+	allowed := execute(t, server, `{ repository(name: "org/repo") { commits(first: 5) { nodes { hash } } } }`, nil)
+	invalid := execute(t, server, `{ repository(name: "org/repo") { unknown } }`, nil)
+
+	// Assert
+	assert.Equal(t, string(errcodes.CodeInvalidArgument), errorCode(t, deep))
+	assert.Contains(t, deep["errors"].([]interface{})[0].(map[string]interface{})["message"], "nested too deeply")
+	assert.Nil(t, deep["data"])
+	assert.Contains(t, complex["errors"].([]interface{})[0].(map[string]interface{})["message"], "too complex")
+	assert.Nil(t, allowed["errors"])
+	assert.Equal(t, string(errcodes.CodeInvalidArgument), errorCode(t, invalid))
+	assert.Empty(t, authors.batches)
+}
+
+// TestServer_ListLimits tests that lists without first count as many items as
+// they can return and that reading a list for each repository is bounded
+func TestServer_ListLimits(t *testing.T) {
+	// Arrange
+	server, authors := newTestServer(t, Limits{MaxComplexity: 2000, MaxFanout: 10})
+
+	// Act
+	nested := execute(t, server, `{ repositories { commits { nodes { author { repositories { repositoryName } } } } } }`, nil)
+	fanout := execute(t, server, `{ repositories(first: 20) { topAuthors(first: 1) { commitCount } } }`, nil)
+	allowed := execute(t, server, `{ repositories(first: 5) { name commits(first: 2) { totalCount } } }`, nil)
+
+	// Assert
+	assert.Contains(t, nested["errors"].([]interface{})[0].(map[string]interface{})["message"], "too complex")
+	assert.Nil(t, nested["data"])
+	assert.Contains(t, fanout["errors"].([]interface{})[0].(map[string]interface{})["message"], "too many nested lists")
+	assert.Equal(t, string(errcodes.CodeInvalidArgument), errorCode(t, fanout))
+	assert.Empty(t, authors.batches)
+
+	require.Nil(t, allowed["errors"])
+	repos := allowed["data"].(map[string]interface{})["repositories"].([]interface{})
+	require.Len(t, repos, 1)
+	assert.Equal(t, "org/repo", repos[0].(map[string]interface{})["name"])
+}
+
+// TestMeasure tests the depth, complexity and fanout of queries
+func TestMeasure(t *testing.T) {
+	server, _ := newTestServer(t, Limits{})
+
+	tests := map[string]struct {
+		query      string
+		depth      int
+		complexity int
+		fanout     int
+	}{
+		"flat":                {`{ repositories { name } }`, 2, 101, 0},
+		"first":               {`{ repository(name: "a") { commits(first: 20) { totalCount } } }`, 3, 22, 1},
+		"default first":       {`{ repository(name: "a") { commits { totalCount nodes { hash } } } }`, 4, 32, 1},
+		"capped":              {`{ repository(name: "a") { commits(first: 1000) { totalCount } } }`, 3, 102, 1},
+		"fragment":            {`{ ...q } fragment q on Query { repositories { ... on Repository { name } } }`, 2, 101, 0},
+		"fanout":              {`{ repositories(first: 5) { commits(first: 2) { totalCount } topAuthors { commitCount } } }`, 3, 71, 10},
+		"author repositories": {`{ author(id: "1") { repositories { commitCount } } }`, 3, 102, 0},
+		"introspection":       {`{ __schema { types { name fields { name } } } }`, 0, 0, 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc, err := parseQuery(tt.query)
+			require.NoError(t, err)
+
+			depth, complexity, fanout := measure(server.schema, doc, "", nil)
+
+			assert.Equal(t, tt.depth, depth)
+			assert.Equal(t, tt.complexity, complexity)
+			assert.Equal(t, tt.fanout, fanout)
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+// apiPrefix is the prefix of the API version the client speaks.
+const apiPrefix = "/v1"
+
+// Client calls the REST API of a running server. Errors reported by the API
+// are returned as *errcodes.Error with the code and details of the response.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// New returns a client of the server at baseURL, e.g. http://localhost:8080.
+// The key is sent as a bearer token when set.
+func New(baseURL, apiKey string, httpClient *http.Client) *Client {
+	baseURL = strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), apiPrefix)
+	return &Client{baseURL: baseURL + apiPrefix, apiKey: apiKey, httpClient: httpClient}
+}
+
+func (c *Client) AddRepository(ctx context.Context, name string) (*dtos.RepositoryMeta, error) {
+	body, err := json.Marshal(dtos.RepositoryInput{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	var repo dtos.RepositoryMeta
+	if err := c.do(ctx, http.MethodPost, "/repositories", nil, body, &repo, nil); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+func (c *Client) Repository(ctx context.Context, name string) (*dtos.RepositoryMeta, error) {
+	path, err := repositoryPath("/repositories", name)
+	if err != nil {
+		return nil, err
+	}
+
+	var repo dtos.RepositoryMeta
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &repo, nil); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+func (c *Client) Repositories(ctx context.Context) ([]dtos.RepositoryMeta, error) {
+	var repos []dtos.RepositoryMeta
+	if err := c.do(ctx, http.MethodGet, "/repositories", nil, nil, &repos, nil); err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+func (c *Client) RemoveRepository(ctx context.Context, name string) error {
+	path, err := repositoryPath("/repositories", name)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodDelete, path, nil, nil, nil, nil)
+}
+
+func (c *Client) ReindexRepository(ctx context.Context, name string) (*dtos.RepositoryMeta, error) {
+	path, err := repositoryPath("/repositories", name)
+	if err != nil {
+		return nil, err
+	}
+
+	var repo dtos.RepositoryMeta
+	if err := c.do(ctx, http.MethodPost, path+"/reindex", nil, nil, &repo, nil); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// Commits returns a page of the commits of a repository.
+func (c *Client) Commits(ctx context.Context, repoName string, query domain.APIPaging, filter domain.CommitFilter) ([]dtos.CommitReponse, dtos.PagingInfo, error) {
+	path, err := repositoryPath("/commits", repoName)
+	if err != nil {
+		return nil, dtos.PagingInfo{}, err
+	}
+
+	params := url.Values{}
+	setInt(params, "page", query.Page)
+	setInt(params, "limit", query.Limit)
+	setString(params, "sort", query.Sort)
+	setString(params, "direction", query.Direction)
+	setString(params, "type", strings.Join(filter.Types, ","))
+	setString(params, "scope", filter.Scope)
+	if filter.Breaking != nil {
+		params.Set("breaking", strconv.FormatBool(*filter.Breaking))
+	}
+
+	var commits []dtos.CommitReponse
+	var paging dtos.PagingInfo
+	if err := c.do(ctx, http.MethodGet, path, params, nil, &commits, &paging); err != nil {
+		return nil, dtos.PagingInfo{}, err
+	}
+	return commits, paging, nil
+}
+
+// TopAuthors ranks the authors of a repository.
+func (c *Client) TopAuthors(ctx context.Context, repoName string, query domain.TopAuthorsQuery) ([]dtos.Author, error) {
+	path, err := repositoryPath("/authors", repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	var authors []dtos.Author
+	if err := c.do(ctx, http.MethodGet, path+"/top", rankingParams(query), nil, &authors, nil); err != nil {
+		return nil, err
+	}
+	return authors, nil
+}
+
+// Leaderboard ranks the authors across every repository, or the repositories
+// of a configured group when group is set.
+func (c *Client) Leaderboard(ctx context.Context, group string, query domain.TopAuthorsQuery) ([]dtos.Author, error) {
+	params := rankingParams(query)
+	setString(params, "group", group)
+
+	var authors []dtos.Author
+	if err := c.do(ctx, http.MethodGet, "/authors/top", params, nil, &authors, nil); err != nil {
+		return nil, err
+	}
+	return authors, nil
+}
+
+// Export writes the archive of a repository to w.
+func (c *Client) Export(ctx context.Context, repoName string, w io.Writer) error {
+	path, err := repositoryPath("/repositories", repoName)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, path+"/export", nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+func rankingParams(query domain.TopAuthorsQuery) url.Values {
+	params := url.Values{}
+	setInt(params, "n", query.Limit)
+	setString(params, "rank_by", string(query.RankBy))
+	setString(params, "exclude", strings.Join(query.Exclude, ","))
+	if !query.Since.IsZero() {
+		params.Set("since", query.Since.Format(time.RFC3339))
+	}
+	if !query.Until.IsZero() {
+		params.Set("until", query.Until.Format(time.RFC3339))
+	}
+	return params
+}
+
+// repositoryPath returns the path of a repository named owner/name under
+// prefix.
+func repositoryPath(prefix, name string) (string, error) {
+	owner, repo, ok := strings.Cut(name, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", errcodes.ErrInvalidRepositoryName
+	}
+	return prefix + "/" + url.PathEscape(owner) + "/" + url.PathEscape(repo), nil
+}
+
+// do sends a request and decodes the data and meta of the response envelope.
+func (c *Client) do(ctx context.Context, method, path string, params url.Values, body []byte, data, meta interface{}) error {
+	resp, err := c.send(ctx, method, path, params, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+	if resp.StatusCode == http.StatusNoContent || data == nil {
+		return nil
+	}
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+		Meta json.RawMessage `json:"meta"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("decoding response of %s %s: %w", method, path, err)
+	}
+	if err := json.Unmarshal(envelope.Data, data); err != nil {
+		return fmt.Errorf("decoding response of %s %s: %w", method, path, err)
+	}
+	if meta != nil && len(envelope.Meta) > 0 {
+		if err := json.Unmarshal(envelope.Meta, meta); err != nil {
+			return fmt.Errorf("decoding response of %s %s: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) send(ctx context.Context, method, path string, params url.Values, body []byte) (*http.Response, error) {
+	target := c.baseURL + path
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// decodeError returns the error of an error response, responses without an
+// error body, e.g. from a proxy, get the code of their status.
+func decodeError(resp *http.Response) error {
+	var envelope struct {
+		Error *response.ErrorBody `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil || envelope.Error == nil {
+		return errcodes.New(response.CodeOfStatus(resp.StatusCode), "unexpected response "+resp.Status)
+	}
+	return &errcodes.Error{Code: envelope.Error.Code, Message: envelope.Error.Message, Details: envelope.Error.Details}
+}
+
+func setString(params url.Values, key, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
+
+func setInt(params url.Values, key string, value int) {
+	if value != 0 {
+		params.Set(key, strconv.Itoa(value))
+	}
+}
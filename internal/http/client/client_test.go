@@ -0,0 +1,202 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/handlers"
+	"github.com/just-nibble/git-service/internal/http/middleware"
+	"github.com/just-nibble/git-service/internal/http/routes"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/config"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAPIKeys authenticates the keys it holds by their secret
+type stubAPIKeys struct {
+	usecases.APIKeyUsecase
+	keys map[string]*domain.APIKey
+}
+
+func (s stubAPIKeys) Authenticate(ctx context.Context, secret string) (*domain.APIKey, error) {
+	key, ok := s.keys[secret]
+	if !ok {
+		return nil, errcodes.ErrUnauthorized
+	}
+	return key, nil
+}
+
+// newTestServer serves the REST API over a repository with commits of two
+// authors, the reader and editor keys are accepted
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	ctx := context.TODO()
+
+	store := repository.NewMemoryStore()
+	repoMetaRepository := repository.NewMemoryRepositoryMetaRepository(store)
+	commitRepository := repository.NewMemoryCommitRepository(store)
+	authorRepository := repository.NewMemoryAuthorRepository(store)
+
+	repo, err := repoMetaRepository.SaveRepoMetadata(ctx, domain.RepositoryMeta{Name: "org/repo", OwnerName: "org"})
+	require.NoError(t, err)
+
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commits := []domain.Commit{
+		{Hash: "c1", Message: "feat: one", Date: date, Author: domain.Author{Name: "Jane", Email: "jane@example.com"}},
+		{Hash: "c2", Message: "fix(api): two", Date: date.Add(time.Hour), Author: domain.Author{Name: "John", Email: "john@example.com"}},
+		{Hash: "c3", Message: "feat!: three", Date: date.Add(2 * time.Hour), Author: domain.Author{Name: "Jane", Email: "jane@example.com"}},
+	}
+	for i := range commits {
+		commits[i].Conventional = domain.ParseConventionalCommit(commits[i].Message)
+	}
+	_, err = commitRepository.SaveCommits(ctx, *repo, commits)
+	require.NoError(t, err)
+
+	logger := *log.NewLogger()
+	repoMetaUsecase := usecases.NewrepoMetaUsecase(repoMetaRepository, commitRepository, authorRepository, nil, nil, nil, config.Config{}, logger)
+	t.Cleanup(func() { repoMetaUsecase.Shutdown(context.TODO()) })
+
+	mux := http.NewServeMux()
+	routes.NewRepositoryRouter(mux, *handlers.NewRepositoryHandler(repoMetaUsecase))
+	routes.NewCommitRouter(mux, *handlers.NewCommitHandler(usecases.NewGitCommitUsecase(commitRepository, repoMetaRepository)))
+	routes.NewAuthorRouter(mux, *handlers.NewAuthorHandler(usecases.NewAuthorUseCase(authorRepository, nil, logger)))
+	routes.NewArchiveRouter(mux, *handlers.NewArchiveHandler(usecases.NewArchiveUsecase(repoMetaRepository, commitRepository)))
+
+	handler := middleware.Authenticate(stubAPIKeys{keys: map[string]*domain.APIKey{
+		"reader": {ID: 1, Role: domain.RoleReader},
+		"editor": {ID: 2, Role: domain.RoleEditor},
+	}}, mux)
+
+	root := http.NewServeMux()
+	root.Handle(apiPrefix+"/", http.StripPrefix(apiPrefix, handler))
+
+	server := httptest.NewServer(root)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Repositories(t *testing.T) {
+	// Arrange
+	server := newTestServer(t)
+	c := New(server.URL+"/", "reader", server.Client())
+
+	// Act
+	repos, err := c.Repositories(context.TODO())
+	repo, repoErr := c.Repository(context.TODO(), "org/repo")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+	assert.Equal(t, "org/repo", repos[0].Name)
+	require.NoError(t, repoErr)
+	assert.Equal(t, "org", repo.Owner.Login)
+}
+
+func TestClient_Commits(t *testing.T) {
+	// Arrange
+	server := newTestServer(t)
+	c := New(server.URL+apiPrefix, "reader", server.Client())
+	breaking := false
+
+	// Act
+	commits, paging, err := c.Commits(context.TODO(), "org/repo",
+		domain.APIPaging{Limit: 1, Sort: "date", Direction: "desc"},
+		domain.CommitFilter{Types: []string{"feat", "fix"}, Breaking: &breaking})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "c2", commits[0].Hash)
+	assert.Equal(t, "fix", commits[0].Type)
+	assert.Equal(t, "api", commits[0].Scope)
+	assert.Equal(t, int64(2), paging.TotalCount)
+	assert.True(t, paging.HasNextPage)
+}
+
+func TestClient_TopAuthors(t *testing.T) {
+	// Arrange
+	server := newTestServer(t)
+	c := New(server.URL, "reader", server.Client())
+	query := domain.TopAuthorsQuery{Limit: 1, Exclude: []string{"john*"}}
+
+	// Act
+	authors, err := c.TopAuthors(context.TODO(), "org/repo", query)
+	leaderboard, leaderboardErr := c.Leaderboard(context.TODO(), "", domain.TopAuthorsQuery{Limit: 5})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, authors, 1)
+	assert.Equal(t, "Jane", authors[0].Name)
+	assert.Equal(t, 2, authors[0].CommitCount)
+	require.NoError(t, leaderboardErr)
+	assert.Len(t, leaderboard, 2)
+}
+
+func TestClient_Export(t *testing.T) {
+	// Arrange
+	server := newTestServer(t)
+	var archive bytes.Buffer
+
+	// Act
+	readerErr := New(server.URL, "reader", server.Client()).Export(context.TODO(), "org/repo", &archive)
+	err := New(server.URL, "editor", server.Client()).Export(context.TODO(), "org/repo", &archive)
+
+	// Assert
+	assert.Equal(t, errcodes.CodePermissionDenied, errcodes.CodeOf(readerErr))
+	require.NoError(t, err)
+	assert.NotZero(t, archive.Len())
+}
+
+func TestClient_RemoveRepository(t *testing.T) {
+	// Arrange
+	server := newTestServer(t)
+	c := New(server.URL, "editor", server.Client())
+
+	// Act
+	err := c.RemoveRepository(context.TODO(), "org/repo")
+	_, findErr := c.Repository(context.TODO(), "org/repo")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, errcodes.CodeNotFound, errcodes.CodeOf(findErr))
+}
+
+func TestClient_Errors(t *testing.T) {
+	server := newTestServer(t)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	t.Cleanup(proxy.Close)
+
+	tests := []struct {
+		name   string
+		client *Client
+		repo   string
+		code   errcodes.Code
+	}{
+		{name: "missing key", client: New(server.URL, "", server.Client()), repo: "org/repo", code: errcodes.CodeUnauthenticated},
+		{name: "unknown repository", client: New(server.URL, "reader", server.Client()), repo: "org/missing", code: errcodes.CodeNotFound},
+		{name: "invalid name", client: New(server.URL, "reader", server.Client()), repo: "org", code: errcodes.CodeInvalidArgument},
+		{name: "response without envelope", client: New(proxy.URL, "reader", proxy.Client()), repo: "org/repo", code: errcodes.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := tt.client.Repository(context.TODO(), tt.repo)
+
+			// Assert
+			require.Error(t, err)
+			assert.Equal(t, tt.code, errcodes.CodeOf(err))
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package dtos
+
+import "time"
+
+type APIKeyInput struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type APIKeyResponse struct {
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Role      string     `json:"role"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	// Key is only returned once, when the key is created
+	Key string `json:"key,omitempty"`
+}
@@ -0,0 +1,62 @@
+package dtos
+
+import "time"
+
+// ArchiveRepository is the repository record of an archive, it includes the
+// indexing cursor so monitoring resumes where it stopped.
+type ArchiveRepository struct {
+	Name              string          `json:"name"`
+	Owner             string          `json:"owner"`
+	Description       string          `json:"description"`
+	Language          string          `json:"language"`
+	URL               string          `json:"url"`
+	ForksCount        int             `json:"forks_count"`
+	StarsCount        int             `json:"stars_count"`
+	OpenIssuesCount   int             `json:"open_issues_count"`
+	WatchersCount     int             `json:"watchers_count"`
+	LastPage          int             `json:"last_page"`
+	LastFetchedCommit string          `json:"last_fetched_commit"`
+	Index             bool            `json:"index"`
+	Retention         RetentionPolicy `json:"retention"`
+	CreatedAt         time.Time       `json:"created_at"`
+}
+
+// ArchiveAuthor is an author record of an archive, ID is only meaningful within
+// the archive.
+type ArchiveAuthor struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// ArchiveCommit is a commit record of an archive, its author record comes
+// before it.
+type ArchiveCommit struct {
+	Hash        string    `json:"hash"`
+	Message     string    `json:"message"`
+	Date        time.Time `json:"date"`
+	AuthorID    uint      `json:"author_id"`
+	Additions   int       `json:"additions"`
+	Deletions   int       `json:"deletions"`
+	Type        string    `json:"type"`
+	Scope       string    `json:"scope"`
+	Breaking    bool      `json:"breaking"`
+	Description string    `json:"description"`
+	// FilesFetched is set when Files holds every changed file
+	FilesFetched bool          `json:"files_fetched"`
+	Files        []ArchiveFile `json:"files,omitempty"`
+	Link         *ArchiveLink  `json:"link,omitempty"`
+}
+
+type ArchiveFile struct {
+	Path      string `json:"path"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// ArchiveLink is the commit a commit reverts or amends, as written in its
+// message.
+type ArchiveLink struct {
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+}
@@ -2,9 +2,34 @@ package dtos
 
 import "time"
 
-type Author struct {
-	Name        string    `json:"name"`
-	Email       string    `json:"email"`
-	Date        time.Time `json:"date"`
-	CommitCount int       `json:"commit_count"`
-}
+type (
+	Author struct {
+		ID              uint       `json:"id,omitempty"`
+		Name            string     `json:"name"`
+		Email           string     `json:"email"`
+		Date            time.Time  `json:"date"`
+		CommitCount     int        `json:"commit_count"`
+		RepositoryCount int        `json:"repository_count,omitempty"`
+		Additions       int        `json:"additions,omitempty"`
+		Deletions       int        `json:"deletions,omitempty"`
+		FirstCommitAt   *time.Time `json:"first_commit_at,omitempty"`
+		LastCommitAt    *time.Time `json:"last_commit_at,omitempty"`
+	}
+
+	RepositoryContribution struct {
+		Repository    string    `json:"repository"`
+		CommitCount   int       `json:"commit_count"`
+		FirstCommitAt time.Time `json:"first_commit_at"`
+		LastCommitAt  time.Time `json:"last_commit_at"`
+	}
+
+	AuthorProfile struct {
+		ID            uint                     `json:"id"`
+		Name          string                   `json:"name"`
+		Email         string                   `json:"email"`
+		CommitCount   int                      `json:"commit_count"`
+		FirstCommitAt *time.Time               `json:"first_commit_at"`
+		LastCommitAt  *time.Time               `json:"last_commit_at"`
+		Repositories  []RepositoryContribution `json:"repositories"`
+	}
+)
@@ -0,0 +1,34 @@
+package dtos
+
+import "time"
+
+type (
+	ChangelogRef struct {
+		Ref  string    `json:"ref"`
+		Hash string    `json:"hash"`
+		Date time.Time `json:"date"`
+	}
+
+	ChangelogCommit struct {
+		Hash        string `json:"hash"`
+		Scope       string `json:"scope,omitempty"`
+		Description string `json:"description"`
+		Breaking    bool   `json:"breaking"`
+		Author      string `json:"author"`
+		URL         string `json:"url,omitempty"`
+	}
+
+	ChangelogSection struct {
+		Type    string            `json:"type,omitempty"`
+		Title   string            `json:"title"`
+		Commits []ChangelogCommit `json:"commits"`
+	}
+
+	ChangelogResponse struct {
+		Repository string             `json:"repository"`
+		From       *ChangelogRef      `json:"from,omitempty"`
+		To         ChangelogRef       `json:"to"`
+		Breaking   []ChangelogCommit  `json:"breaking"`
+		Sections   []ChangelogSection `json:"sections"`
+	}
+)
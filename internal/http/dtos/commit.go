@@ -13,11 +13,18 @@ type Commit struct {
 }
 
 type CommitReponse struct {
-	ID      uint      `json:"id"`
-	Hash    string    `json:"hash"`
-	Message string    `json:"message"`
-	Date    time.Time `json:"date"`
-	Author  Author    `json:"author"`
+	ID          uint      `json:"id"`
+	Hash        string    `json:"hash"`
+	Message     string    `json:"message"`
+	Date        time.Time `json:"date"`
+	Type        string    `json:"type,omitempty"`
+	Scope       string    `json:"scope,omitempty"`
+	Breaking    bool      `json:"breaking"`
+	Description string    `json:"description,omitempty"`
+	Author      Author    `json:"author"`
+	Reverts     string    `json:"reverts,omitempty"`
+	RevertedBy  []string  `json:"reverted_by,omitempty"`
+	FixupOf     string    `json:"fixup_of,omitempty"`
 }
 
 type MultiCommitsResponse struct {
@@ -0,0 +1,39 @@
+package dtos
+
+import "time"
+
+type (
+	PathOwner struct {
+		ID      uint    `json:"id"`
+		Name    string  `json:"name"`
+		Email   string  `json:"email"`
+		Changes int     `json:"changes"`
+		Churn   int     `json:"churn"`
+		Share   float64 `json:"share"`
+	}
+
+	DirectoryOwnership struct {
+		Path      string      `json:"path"`
+		Changes   int         `json:"changes"`
+		Churn     int         `json:"churn"`
+		BusFactor int         `json:"bus_factor"`
+		Owners    []PathOwner `json:"owners"`
+	}
+
+	Hotspot struct {
+		Path    string `json:"path"`
+		Commits int    `json:"commits"`
+		Churn   int    `json:"churn"`
+	}
+
+	OwnershipResponse struct {
+		Path        string               `json:"path"`
+		Since       time.Time            `json:"since"`
+		Changes     int                  `json:"changes"`
+		Churn       int                  `json:"churn"`
+		BusFactor   int                  `json:"bus_factor"`
+		Owners      []PathOwner          `json:"owners"`
+		Directories []DirectoryOwnership `json:"directories"`
+		Hotspots    []Hotspot            `json:"hotspots"`
+	}
+)
@@ -1,5 +1,8 @@
 package dtos
 
+// MaxLimit is the largest page a client can request, larger limits are capped.
+const MaxLimit = 100
+
 type (
 	APIPagingDto struct {
 		Limit     int    `json:"limit,omitempty"`
@@ -15,3 +18,11 @@ type (
 		Count       int   `json:"count"`
 	}
 )
+
+// Capped returns the paging with its limit capped to MaxLimit.
+func (p APIPagingDto) Capped() APIPagingDto {
+	if p.Limit > MaxLimit {
+		p.Limit = MaxLimit
+	}
+	return p
+}
@@ -0,0 +1,16 @@
+package dtos
+
+import "time"
+
+type PullRequest struct {
+	Number          int        `json:"number"`
+	Title           string     `json:"title"`
+	Author          string     `json:"author"`
+	State           string     `json:"state"`
+	BaseBranch      string     `json:"base_branch"`
+	HeadBranch      string     `json:"head_branch"`
+	MergeCommitHash string     `json:"merge_commit_sha,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	MergedAt        *time.Time `json:"merged_at,omitempty"`
+	ClosedAt        *time.Time `json:"closed_at,omitempty"`
+}
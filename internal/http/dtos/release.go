@@ -0,0 +1,25 @@
+package dtos
+
+import "time"
+
+type (
+	Tag struct {
+		Name string    `json:"name"`
+		Hash string    `json:"hash"`
+		Date time.Time `json:"date"`
+	}
+
+	Release struct {
+		TagName     string    `json:"tag_name"`
+		Name        string    `json:"name"`
+		Body        string    `json:"body"`
+		Prerelease  bool      `json:"prerelease"`
+		PublishedAt time.Time `json:"published_at"`
+	}
+
+	CompareResponse struct {
+		From    ChangelogRef    `json:"from"`
+		To      ChangelogRef    `json:"to"`
+		Commits []CommitReponse `json:"commits"`
+	}
+)
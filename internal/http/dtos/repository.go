@@ -21,4 +21,12 @@ type RepositoryMeta struct {
 	WatchersCount   int       `json:"watchers_count"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
+	// Retention is not part of the GitHub payload
+	Retention RetentionPolicy `json:"retention"`
+}
+
+// RetentionPolicy bounds the commits kept of a repository, zero keeps every commit
+type RetentionPolicy struct {
+	Days    int `json:"days"`
+	Commits int `json:"commits"`
 }
@@ -0,0 +1,75 @@
+package dtos
+
+import "time"
+
+type (
+	ActivityBucket struct {
+		Start time.Time `json:"start"`
+		Count int       `json:"count"`
+	}
+
+	PunchCardCell struct {
+		Weekday int `json:"weekday"`
+		Hour    int `json:"hour"`
+		Count   int `json:"count"`
+	}
+
+	AuthorActivity struct {
+		ID      uint             `json:"id"`
+		Name    string           `json:"name"`
+		Email   string           `json:"email"`
+		Count   int              `json:"count"`
+		Buckets []ActivityBucket `json:"buckets"`
+	}
+
+	ActivityResponse struct {
+		Interval  string           `json:"interval"`
+		Timezone  string           `json:"timezone"`
+		Since     time.Time        `json:"since"`
+		Until     time.Time        `json:"until"`
+		Total     int              `json:"total"`
+		Buckets   []ActivityBucket `json:"buckets"`
+		PunchCard []PunchCardCell  `json:"punch_card"`
+		Authors   []AuthorActivity `json:"authors"`
+	}
+
+	DeliveryBucket struct {
+		Start                 time.Time `json:"start"`
+		Deployments           int       `json:"deployments"`
+		FailedDeployments     int       `json:"failed_deployments"`
+		ChangeFailureRate     float64   `json:"change_failure_rate"`
+		Commits               int       `json:"commits"`
+		MedianLeadTimeSeconds int64     `json:"median_lead_time_seconds"`
+	}
+
+	DeliveryResponse struct {
+		Source                string           `json:"source"`
+		Branch                string           `json:"branch,omitempty"`
+		Timezone              string           `json:"timezone"`
+		Since                 time.Time        `json:"since"`
+		Until                 time.Time        `json:"until"`
+		Deployments           int              `json:"deployments"`
+		DeploymentsPerWeek    float64          `json:"deployments_per_week"`
+		FailedDeployments     int              `json:"failed_deployments"`
+		ChangeFailureRate     float64          `json:"change_failure_rate"`
+		Commits               int              `json:"commits"`
+		MedianLeadTimeSeconds int64            `json:"median_lead_time_seconds"`
+		Buckets               []DeliveryBucket `json:"buckets"`
+	}
+
+	Revert struct {
+		Commit              CommitReponse  `json:"commit"`
+		Reverted            *CommitReponse `json:"reverted"`
+		TimeToRevertSeconds int64          `json:"time_to_revert_seconds,omitempty"`
+	}
+
+	RevertsResponse struct {
+		Interval string           `json:"interval"`
+		Timezone string           `json:"timezone"`
+		Since    time.Time        `json:"since"`
+		Until    time.Time        `json:"until"`
+		Total    int              `json:"total"`
+		Buckets  []ActivityBucket `json:"buckets"`
+		Reverts  []Revert         `json:"reverts"`
+	}
+)
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+type APIKeyHandler struct {
+	apiKeyUsecase usecases.APIKeyUsecase
+}
+
+func NewAPIKeyHandler(apiKeyUsecase usecases.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyUsecase: apiKeyUsecase}
+}
+
+func toAPIKeyResponse(k domain.APIKey) dtos.APIKeyResponse {
+	return dtos.APIKeyResponse{
+		ID:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Role:      string(k.Role),
+		CreatedAt: k.CreatedAt,
+		RevokedAt: k.RevokedAt,
+	}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req dtos.APIKeyInput
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Name == "" {
+		response.ErrorResponse(w, http.StatusBadRequest, "Key name is required")
+		return
+	}
+
+	key, secret, err := h.apiKeyUsecase.CreateKey(r.Context(), req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	keyResponse := toAPIKeyResponse(*key)
+	keyResponse.Key = secret
+
+	response.SuccessResponse(w, http.StatusCreated, keyResponse)
+}
+
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.apiKeyUsecase.ListKeys(r.Context())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	keysResponse := make([]dtos.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		keysResponse = append(keysResponse, toAPIKeyResponse(k))
+	}
+
+	response.SuccessResponse(w, http.StatusOK, keysResponse)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid key id")
+		return
+	}
+
+	if err := h.apiKeyUsecase.RevokeKey(r.Context(), uint(id)); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+type ArchiveHandler struct {
+	archiveUsecase usecases.ArchiveUsecase
+}
+
+func NewArchiveHandler(archiveUsecase usecases.ArchiveUsecase) *ArchiveHandler {
+	return &ArchiveHandler{archiveUsecase: archiveUsecase}
+}
+
+// Export streams the archive of a repository. Errors found after the archive
+// started can no longer be reported, the archive then lacks its trailer and is
+// rejected on import.
+func (h *ArchiveHandler) Export(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	aw := &archiveWriter{w: w, filename: strings.ReplaceAll(repoName, "/", "_") + ".ndjson.gz"}
+	if _, err := h.archiveUsecase.Export(r.Context(), repoName, aw); err != nil && !aw.started {
+		response.Error(w, err)
+	}
+}
+
+// archiveWriter sends the archive headers before the first byte of an archive,
+// so errors found before it are sent as JSON.
+type archiveWriter struct {
+	w        http.ResponseWriter
+	filename string
+	started  bool
+}
+
+func (a *archiveWriter) Write(p []byte) (int, error) {
+	if !a.started {
+		a.started = true
+		a.w.Header().Set("Content-Type", "application/gzip")
+		a.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.filename))
+		a.w.WriteHeader(http.StatusOK)
+	}
+	return a.w.Write(p)
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/just-nibble/git-service/internal/domain"
 	"github.com/just-nibble/git-service/internal/http/dtos"
 	"github.com/just-nibble/git-service/internal/usecases"
 	"github.com/just-nibble/git-service/pkg/response"
@@ -18,46 +20,191 @@ func NewAuthorHandler(authorUsecase usecases.AuthorUseCase) *AuthorHandler {
 	return &AuthorHandler{authorUsecase: authorUsecase}
 }
 
+// listParam reads a query parameter that can be repeated or hold comma
+// separated values.
+func listParam(r *http.Request, name string) []string {
+	var values []string
+	for _, value := range r.URL.Query()[name] {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// topAuthorsQuery reads the ranking query parameters, it writes an error
+// response and returns false if one is invalid.
+func topAuthorsQuery(w http.ResponseWriter, r *http.Request) (domain.TopAuthorsQuery, bool) {
+	params := r.URL.Query()
+
+	n, err := strconv.Atoi(params.Get("n"))
+	if err != nil || n <= 0 {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid number of authors")
+		return domain.TopAuthorsQuery{}, false
+	}
+	if n > dtos.MaxLimit {
+		n = dtos.MaxLimit
+	}
+
+	since, err := parseTime(params.Get("since"), time.UTC)
+	if err != nil {
+		response.Error(w, err)
+		return domain.TopAuthorsQuery{}, false
+	}
+
+	until, err := parseTime(params.Get("until"), time.UTC)
+	if err != nil {
+		response.Error(w, err)
+		return domain.TopAuthorsQuery{}, false
+	}
+
+	return domain.TopAuthorsQuery{
+		Limit:   n,
+		Since:   since,
+		Until:   until,
+		RankBy:  domain.AuthorRanking(params.Get("rank_by")),
+		Exclude: listParam(r, "exclude"),
+	}, true
+}
+
+func toAuthorDtos(authors []domain.Author) []dtos.Author {
+	authorResponse := make([]dtos.Author, 0, len(authors))
+
+	for _, v := range authors {
+		firstCommitAt, lastCommitAt := v.FirstCommitAt, v.LastCommitAt
+		author := dtos.Author{
+			ID:              v.ID,
+			Name:            v.Name,
+			Email:           v.Email,
+			CommitCount:     v.CommitCount,
+			RepositoryCount: v.RepositoryCount,
+			Additions:       v.Additions,
+			Deletions:       v.Deletions,
+			FirstCommitAt:   &firstCommitAt,
+			LastCommitAt:    &lastCommitAt,
+		}
+		authorResponse = append(authorResponse, author)
+	}
+
+	return authorResponse
+}
+
+// writeAuthors writes a ranking as JSON, or as CSV or NDJSON rows when the
+// client asks for them.
+func writeAuthors(w http.ResponseWriter, r *http.Request, authors []domain.Author) {
+	format, ok := response.Negotiate(r)
+	if !ok {
+		response.SuccessResponse(w, http.StatusOK, toAuthorDtos(authors))
+		return
+	}
+
+	stream := response.NewStream(w, format, authorCSVHeader)
+	for _, author := range toAuthorDtos(authors) {
+		if err := stream.Write(author, authorCSVRecord(author)); err != nil {
+			return
+		}
+	}
+	stream.Close()
+}
+
+// authorCSVHeader names the columns of authorCSVRecord.
+var authorCSVHeader = []string{
+	"id", "name", "email", "commit_count", "repository_count", "additions", "deletions", "first_commit_at", "last_commit_at",
+}
+
+func authorCSVRecord(a dtos.Author) []string {
+	record := []string{
+		strconv.FormatUint(uint64(a.ID), 10),
+		a.Name,
+		a.Email,
+		strconv.Itoa(a.CommitCount),
+		strconv.Itoa(a.RepositoryCount),
+		strconv.Itoa(a.Additions),
+		strconv.Itoa(a.Deletions),
+		"",
+		"",
+	}
+	if a.FirstCommitAt != nil && !a.FirstCommitAt.IsZero() {
+		record[7] = a.FirstCommitAt.Format(time.RFC3339)
+	}
+	if a.LastCommitAt != nil && !a.LastCommitAt.IsZero() {
+		record[8] = a.LastCommitAt.Format(time.RFC3339)
+	}
+	return record
+}
+
 func (h *AuthorHandler) GetTopAuthors(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
 
-	owner := r.PathValue("owner")
-	if owner == "" {
-		response.ErrorResponse(w, http.StatusBadRequest, "Repository owner is required")
+	query, ok := topAuthorsQuery(w, r)
+	if !ok {
 		return
 	}
 
-	name := r.PathValue("name")
-	if name == "" {
-		response.ErrorResponse(w, http.StatusBadRequest, "Repository name is required")
+	authors, err := h.authorUsecase.GetTopAuthors(r.Context(), repoName, query)
+	if err != nil {
+		response.Error(w, err)
 		return
 	}
 
-	repoName := fmt.Sprintf("%s/%s", owner, name)
+	writeAuthors(w, r, authors)
+}
 
-	nStr := r.URL.Query().Get("n")
-	n, err := strconv.Atoi(nStr)
-	if err != nil || n <= 0 {
-		http.Error(w, "Invalid number of authors", http.StatusBadRequest)
+// GetLeaderboard ranks authors across every repository or a configured group.
+func (h *AuthorHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	query, ok := topAuthorsQuery(w, r)
+	if !ok {
 		return
 	}
 
-	authors, err := h.authorUsecase.GetTopAuthors(ctx, repoName, n)
+	authors, err := h.authorUsecase.GetTopAuthorsAcross(r.Context(), r.URL.Query().Get("group"), query)
 	if err != nil {
-		response.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		response.Error(w, err)
 		return
 	}
 
-	var authorResponse []dtos.Author
+	writeAuthors(w, r, authors)
+}
 
-	for _, v := range authors {
-		author := dtos.Author{
-			Name:        v.Name,
-			Email:       v.Email,
-			CommitCount: v.CommitCount,
-		}
-		authorResponse = append(authorResponse, author)
+func (h *AuthorHandler) GetAuthor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid author id")
+		return
+	}
+
+	profile, err := h.authorUsecase.GetAuthorProfile(r.Context(), uint(id))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	res := dtos.AuthorProfile{
+		ID:           profile.ID,
+		Name:         profile.Name,
+		Email:        profile.Email,
+		CommitCount:  profile.CommitCount,
+		Repositories: make([]dtos.RepositoryContribution, 0, len(profile.Repositories)),
+	}
+
+	// an author without commits has no activity dates
+	if profile.CommitCount > 0 {
+		res.FirstCommitAt, res.LastCommitAt = &profile.FirstCommitAt, &profile.LastCommitAt
+	}
+
+	for _, c := range profile.Repositories {
+		res.Repositories = append(res.Repositories, dtos.RepositoryContribution{
+			Repository:    c.RepositoryName,
+			CommitCount:   c.CommitCount,
+			FirstCommitAt: c.FirstCommitAt,
+			LastCommitAt:  c.LastCommitAt,
+		})
 	}
 
-	response.SuccessResponse(w, http.StatusOK, authorResponse)
+	response.SuccessResponse(w, http.StatusOK, res)
 }
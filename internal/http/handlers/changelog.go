@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+// shortHashLen is the length of commit hashes in rendered release notes
+const shortHashLen = 7
+
+type ChangelogHandler struct {
+	changelogUsecase usecases.ChangelogUsecase
+}
+
+func NewChangelogHandler(changelogUsecase usecases.ChangelogUsecase) *ChangelogHandler {
+	return &ChangelogHandler{changelogUsecase: changelogUsecase}
+}
+
+func (h *ChangelogHandler) GetChangelog(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	params := r.URL.Query()
+
+	format := params.Get("format")
+	if format != "" && format != "json" && format != "markdown" {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid format, expected one of: json, markdown")
+		return
+	}
+
+	from, to := params.Get("from"), params.Get("to")
+
+	changelog, err := h.changelogUsecase.GetChangelog(r.Context(), repoName, from, to)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	res := toChangelogDto(changelog, from, to)
+
+	if format == "markdown" {
+		response.Content(w, http.StatusOK, "text/markdown; charset=utf-8", []byte(renderChangelog(res)))
+		return
+	}
+
+	response.SuccessResponse(w, http.StatusOK, res)
+}
+
+func toChangelogDto(changelog *domain.Changelog, from, to string) dtos.ChangelogResponse {
+	commitURL := func(hash string) string {
+		if changelog.Repository.URL == "" {
+			return ""
+		}
+		return changelog.Repository.URL + "/commit/" + hash
+	}
+
+	toCommitDtos := func(commits []domain.Commit) []dtos.ChangelogCommit {
+		res := make([]dtos.ChangelogCommit, 0, len(commits))
+		for _, c := range commits {
+			description := c.Conventional.Description
+			if c.Conventional.Type == "" {
+				description, _, _ = strings.Cut(c.Message, "\n")
+			}
+			res = append(res, dtos.ChangelogCommit{
+				Hash:        c.Hash,
+				Scope:       c.Conventional.Scope,
+				Description: description,
+				Breaking:    c.Conventional.Breaking,
+				Author:      c.Author.Name,
+				URL:         commitURL(c.Hash),
+			})
+		}
+		return res
+	}
+
+	if to == "" {
+		to = changelog.To.Hash
+	}
+
+	res := dtos.ChangelogResponse{
+		Repository: changelog.Repository.Name,
+		To:         dtos.ChangelogRef{Ref: to, Hash: changelog.To.Hash, Date: changelog.To.Date},
+		Breaking:   toCommitDtos(changelog.Breaking),
+		Sections:   make([]dtos.ChangelogSection, 0, len(changelog.Sections)),
+	}
+
+	if changelog.From != nil {
+		res.From = &dtos.ChangelogRef{Ref: from, Hash: changelog.From.Hash, Date: changelog.From.Date}
+	}
+
+	for _, s := range changelog.Sections {
+		res.Sections = append(res.Sections, dtos.ChangelogSection{
+			Type:    s.Type,
+			Title:   s.Title,
+			Commits: toCommitDtos(s.Commits),
+		})
+	}
+
+	return res
+}
+
+// renderChangelog renders release notes as Markdown in the layout of
+// conventional-changelog.
+func renderChangelog(c dtos.ChangelogResponse) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s (%s)\n\n", c.To.Ref, c.To.Date.Format("2006-01-02"))
+	if c.From != nil {
+		fmt.Fprintf(&b, "Changes to %s since %s.\n", c.Repository, c.From.Ref)
+	} else {
+		fmt.Fprintf(&b, "Changes to %s since the first commit.\n", c.Repository)
+	}
+
+	writeSection := func(title string, commits []dtos.ChangelogCommit) {
+		if len(commits) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", title)
+		for _, commit := range commits {
+			b.WriteString("* ")
+			if commit.Scope != "" {
+				fmt.Fprintf(&b, "**%s:** ", commit.Scope)
+			}
+			b.WriteString(commit.Description)
+
+			hash := commit.Hash
+			if len(hash) > shortHashLen {
+				hash = hash[:shortHashLen]
+			}
+			if commit.URL != "" {
+				fmt.Fprintf(&b, " ([%s](%s))\n", hash, commit.URL)
+			} else {
+				fmt.Fprintf(&b, " (%s)\n", hash)
+			}
+		}
+	}
+
+	writeSection("⚠ BREAKING CHANGES", c.Breaking)
+	for _, s := range c.Sections {
+		writeSection(s.Title, s.Commits)
+	}
+
+	return b.String()
+}
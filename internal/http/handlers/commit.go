@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/just-nibble/git-service/internal/domain"
 	"github.com/just-nibble/git-service/internal/http/dtos"
@@ -32,7 +33,16 @@ func getPagingInfo(r *http.Request) dtos.APIPagingDto {
 	paging.Sort = sort
 	paging.Direction = direction
 
-	return paging
+	return paging.Capped()
+}
+
+func toPagingInfoDto(p domain.PagingInfo) dtos.PagingInfo {
+	return dtos.PagingInfo{
+		TotalCount:  p.TotalCount,
+		Page:        p.Page,
+		HasNextPage: p.HasNextPage,
+		Count:       p.Count,
+	}
 }
 
 func (h *CommitHandler) GetCommitsByRepoName(w http.ResponseWriter, r *http.Request) {
@@ -59,28 +69,109 @@ func (h *CommitHandler) GetCommitsByRepoName(w http.ResponseWriter, r *http.Requ
 		Direction: query.Direction,
 	}
 
+	filter := domain.CommitFilter{
+		Types: listParam(r, "type"),
+		Scope: r.URL.Query().Get("scope"),
+	}
+
+	if b := r.URL.Query().Get("breaking"); b != "" {
+		breaking, err := strconv.ParseBool(b)
+		if err != nil {
+			response.ErrorResponse(w, http.StatusBadRequest, "Invalid breaking, expected true or false")
+			return
+		}
+		filter.Breaking = &breaking
+	}
+
+	if format, ok := response.Negotiate(r); ok {
+		h.streamCommits(w, r, format, repoName, domainQuery, filter)
+		return
+	}
+
 	// Fetch commits from the dbbase
-	commits, err := h.gitCommitUseCase.GetAllCommitsByRepository(r.Context(), repoName, domainQuery)
+	commits, pagingInfo, err := h.gitCommitUseCase.GetAllCommitsByRepository(r.Context(), repoName, domainQuery, filter)
 	if err != nil {
-		http.Error(w, "Failed to retrieve commits", http.StatusInternalServerError)
+		response.Error(w, err)
 		return
 	}
 
-	var commitsResponse []dtos.CommitReponse
+	response.PagedResponse(w, http.StatusOK, toCommitDtos(commits), toPagingInfoDto(pagingInfo))
+}
 
+// streamCommits writes every matching commit as a CSV or NDJSON row, ignoring
+// the page and limit.
+func (h *CommitHandler) streamCommits(w http.ResponseWriter, r *http.Request, format response.StreamFormat, repoName string, query domain.APIPaging, filter domain.CommitFilter) {
+	stream := response.NewStream(w, format, commitCSVHeader)
+	err := h.gitCommitUseCase.StreamCommits(r.Context(), repoName, query, filter, func(c domain.Commit) error {
+		commit := toCommitDto(c)
+		return stream.Write(commit, commitCSVRecord(commit))
+	})
+	if err == nil {
+		err = stream.Close()
+	}
+	if err != nil && !stream.Started() {
+		response.Error(w, err)
+	}
+}
+
+// commitCSVHeader names the columns of commitCSVRecord.
+var commitCSVHeader = []string{
+	"id", "hash", "date", "author_name", "author_email", "type", "scope", "breaking", "description", "reverts", "fixup_of", "message",
+}
+
+func commitCSVRecord(c dtos.CommitReponse) []string {
+	return []string{
+		strconv.FormatUint(uint64(c.ID), 10),
+		c.Hash,
+		c.Date.Format(time.RFC3339),
+		c.Author.Name,
+		c.Author.Email,
+		c.Type,
+		c.Scope,
+		strconv.FormatBool(c.Breaking),
+		c.Description,
+		c.Reverts,
+		c.FixupOf,
+		c.Message,
+	}
+}
+
+func toCommitDtos(commits []domain.Commit) []dtos.CommitReponse {
+	res := make([]dtos.CommitReponse, 0, len(commits))
 	for _, v := range commits {
-		commit := dtos.CommitReponse{
-			ID:      v.ID,
-			Hash:    v.Hash,
-			Message: v.Message,
-			Date:    v.Date,
-			Author: dtos.Author{
-				Name:  v.Author.Name,
-				Email: v.Author.Email,
-			},
-		}
-		commitsResponse = append(commitsResponse, commit)
+		res = append(res, toCommitDto(v))
+	}
+	return res
+}
+
+func toCommitDto(v domain.Commit) dtos.CommitReponse {
+	res := dtos.CommitReponse{
+		ID:          v.ID,
+		Hash:        v.Hash,
+		Message:     v.Message,
+		Date:        v.Date,
+		Type:        v.Conventional.Type,
+		Scope:       v.Conventional.Scope,
+		Breaking:    v.Conventional.Breaking,
+		Description: v.Conventional.Description,
+		Author: dtos.Author{
+			ID:    v.AuthorID,
+			Name:  v.Author.Name,
+			Email: v.Author.Email,
+		},
+		RevertedBy: v.RevertedBy,
 	}
 
-	response.SuccessResponse(w, http.StatusOK, commitsResponse)
+	if v.Link != nil {
+		switch {
+		case v.Link.Kind == domain.LinkRevert && v.Link.Hash != "":
+			res.Reverts = v.Link.Hash
+		case v.Link.Kind == domain.LinkRevert:
+			// the reverted commit is not indexed, report the hash as written
+			res.Reverts = v.Link.Target
+		default:
+			res.FixupOf = v.Link.Hash
+		}
+	}
+	return res
 }
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/graph"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+type GraphQLHandler struct {
+	server *graph.Server
+}
+
+func NewGraphQLHandler(server *graph.Server) *GraphQLHandler {
+	return &GraphQLHandler{server: server}
+}
+
+// Query executes a GraphQL query. Errors of the query are part of the GraphQL
+// response, only a body that is not a query is an error of the API.
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) {
+	var req dtos.GraphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Query == "" {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	body, err := json.Marshal(h.server.Execute(r.Context(), req))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Content(w, http.StatusOK, "application/json", body)
+}
@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+// NotFound answers requests that match no route with the API error format.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	response.Error(w, errcodes.New(errcodes.CodeNotFound, "no route for "+r.Method+" "+r.URL.Path))
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http"
+)
+
+type OpenAPIHandler struct {
+	spec []byte
+}
+
+func NewOpenAPIHandler(spec []byte) *OpenAPIHandler {
+	return &OpenAPIHandler{spec: spec}
+}
+
+func (h *OpenAPIHandler) GetSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(h.spec)
+}
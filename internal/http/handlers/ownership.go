@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+type OwnershipHandler struct {
+	ownershipUsecase usecases.OwnershipUsecase
+}
+
+func NewOwnershipHandler(ownershipUsecase usecases.OwnershipUsecase) *OwnershipHandler {
+	return &OwnershipHandler{ownershipUsecase: ownershipUsecase}
+}
+
+func (h *OwnershipHandler) GetOwnership(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	params := r.URL.Query()
+	months, _ := strconv.Atoi(params.Get("months"))
+	limit, _ := strconv.Atoi(params.Get("limit"))
+
+	ownership, err := h.ownershipUsecase.GetOwnership(r.Context(), repoName, params.Get("path"), months, limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	res := dtos.OwnershipResponse{
+		Path:        ownership.Path,
+		Since:       ownership.Since,
+		Changes:     ownership.Changes,
+		Churn:       ownership.Churn,
+		BusFactor:   ownership.BusFactor,
+		Owners:      toPathOwnerDtos(ownership.Owners),
+		Directories: make([]dtos.DirectoryOwnership, 0, len(ownership.Directories)),
+		Hotspots:    make([]dtos.Hotspot, 0, len(ownership.Hotspots)),
+	}
+
+	for _, d := range ownership.Directories {
+		res.Directories = append(res.Directories, dtos.DirectoryOwnership{
+			Path:      d.Path,
+			Changes:   d.Changes,
+			Churn:     d.Churn,
+			BusFactor: d.BusFactor,
+			Owners:    toPathOwnerDtos(d.Owners),
+		})
+	}
+
+	for _, hs := range ownership.Hotspots {
+		res.Hotspots = append(res.Hotspots, dtos.Hotspot{Path: hs.Path, Commits: hs.Commits, Churn: hs.Churn})
+	}
+
+	response.SuccessResponse(w, http.StatusOK, res)
+}
+
+func toPathOwnerDtos(owners []domain.PathOwner) []dtos.PathOwner {
+	res := make([]dtos.PathOwner, 0, len(owners))
+	for _, o := range owners {
+		res = append(res, dtos.PathOwner{
+			ID:      o.Author.ID,
+			Name:    o.Author.Name,
+			Email:   o.Author.Email,
+			Changes: o.Changes,
+			Churn:   o.Churn,
+			Share:   o.Share,
+		})
+	}
+	return res
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+type PullRequestHandler struct {
+	pullRequestUsecase usecases.PullRequestUsecase
+}
+
+func NewPullRequestHandler(pullRequestUsecase usecases.PullRequestUsecase) *PullRequestHandler {
+	return &PullRequestHandler{pullRequestUsecase: pullRequestUsecase}
+}
+
+func (h *PullRequestHandler) GetPullRequests(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	state := domain.PullRequestState(r.URL.Query().Get("state"))
+	if state == "all" {
+		state = ""
+	}
+
+	prs, pagingInfo, err := h.pullRequestUsecase.GetPullRequests(r.Context(), repoName, state, toDomainPaging(getPagingInfo(r)))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.PagedResponse(w, http.StatusOK, toPullRequestDtos(prs), toPagingInfoDto(pagingInfo))
+}
+
+func (h *PullRequestHandler) GetCommitPullRequests(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	prs, err := h.pullRequestUsecase.PullRequestsForCommit(r.Context(), repoName, r.PathValue("hash"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.SuccessResponse(w, http.StatusOK, toPullRequestDtos(prs))
+}
+
+func toPullRequestDtos(prs []domain.PullRequest) []dtos.PullRequest {
+	res := make([]dtos.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		res = append(res, dtos.PullRequest{
+			Number:          pr.Number,
+			Title:           pr.Title,
+			Author:          pr.Author,
+			State:           string(pr.State),
+			BaseBranch:      pr.BaseBranch,
+			HeadBranch:      pr.HeadBranch,
+			MergeCommitHash: pr.MergeCommitHash,
+			CreatedAt:       pr.CreatedAt,
+			MergedAt:        pr.MergedAt,
+			ClosedAt:        pr.ClosedAt,
+		})
+	}
+	return res
+}
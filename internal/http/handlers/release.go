@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+type ReleaseHandler struct {
+	releaseUsecase usecases.ReleaseUsecase
+}
+
+func NewReleaseHandler(releaseUsecase usecases.ReleaseUsecase) *ReleaseHandler {
+	return &ReleaseHandler{releaseUsecase: releaseUsecase}
+}
+
+func toDomainPaging(query dtos.APIPagingDto) domain.APIPaging {
+	return domain.APIPaging{
+		Limit:     query.Limit,
+		Page:      query.Page,
+		Sort:      query.Sort,
+		Direction: query.Direction,
+	}
+}
+
+func (h *ReleaseHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	tags, pagingInfo, err := h.releaseUsecase.GetTags(r.Context(), repoName, toDomainPaging(getPagingInfo(r)))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	res := make([]dtos.Tag, 0, len(tags))
+	for _, t := range tags {
+		res = append(res, dtos.Tag{Name: t.Name, Hash: t.Hash, Date: t.Date})
+	}
+
+	response.PagedResponse(w, http.StatusOK, res, toPagingInfoDto(pagingInfo))
+}
+
+func (h *ReleaseHandler) GetReleases(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	releases, pagingInfo, err := h.releaseUsecase.GetReleases(r.Context(), repoName, toDomainPaging(getPagingInfo(r)))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	res := make([]dtos.Release, 0, len(releases))
+	for _, rel := range releases {
+		res = append(res, dtos.Release{
+			TagName:     rel.TagName,
+			Name:        rel.Name,
+			Body:        rel.Body,
+			Prerelease:  rel.Prerelease,
+			PublishedAt: rel.PublishedAt,
+		})
+	}
+
+	response.PagedResponse(w, http.StatusOK, res, toPagingInfoDto(pagingInfo))
+}
+
+func (h *ReleaseHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+	if from == "" || to == "" {
+		response.ErrorResponse(w, http.StatusBadRequest, "from and to are required")
+		return
+	}
+
+	comparison, pagingInfo, err := h.releaseUsecase.Compare(r.Context(), repoName, from, to, toDomainPaging(getPagingInfo(r)))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	res := dtos.CompareResponse{
+		From:    dtos.ChangelogRef{Ref: from, Hash: comparison.From.Hash, Date: comparison.From.Date},
+		To:      dtos.ChangelogRef{Ref: to, Hash: comparison.To.Hash, Date: comparison.To.Date},
+		Commits: toCommitDtos(comparison.Commits),
+	}
+
+	response.PagedResponse(w, http.StatusOK, res, toPagingInfoDto(pagingInfo))
+}
@@ -1,14 +1,12 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
 	"github.com/just-nibble/git-service/internal/http/dtos"
 	"github.com/just-nibble/git-service/internal/usecases"
-	"github.com/just-nibble/git-service/pkg/errcodes"
 	"github.com/just-nibble/git-service/pkg/response"
 )
 
@@ -22,6 +20,24 @@ func NewRepositoryHandler(gitRepositoryUsecase usecases.RepoMetaUsecase) *Reposi
 	}
 }
 
+// repoNameFromPath builds the full repository name from the owner and name
+// path values, it writes an error response and returns false if one is missing.
+func repoNameFromPath(w http.ResponseWriter, r *http.Request) (string, bool) {
+	owner := r.PathValue("owner")
+	if owner == "" {
+		response.ErrorResponse(w, http.StatusBadRequest, "Repository owner is required")
+		return "", false
+	}
+
+	name := r.PathValue("name")
+	if name == "" {
+		response.ErrorResponse(w, http.StatusBadRequest, "Repository name is required")
+		return "", false
+	}
+
+	return fmt.Sprintf("%s/%s", owner, name), true
+}
+
 func (rh RepositoryHandler) AddRepository(w http.ResponseWriter, r *http.Request) {
 	var req dtos.RepositoryInput
 
@@ -35,102 +51,71 @@ func (rh RepositoryHandler) AddRepository(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	ctx := context.Background()
-
-	_, err := rh.gitRepositoryUsecase.InitiateIndexing(ctx, req)
+	repo, err := rh.gitRepositoryUsecase.InitiateIndexing(r.Context(), req)
 	if err != nil {
-		if err == errcodes.ErrRepoAlreadyAdded {
-			response.ErrorResponse(w, http.StatusBadRequest, err.Error())
-			return
-		}
-		response.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		response.Error(w, err)
 		return
 	}
 
-	response.SuccessResponse(w, http.StatusCreated, "Repository successfully indexed, its commits are being fetched...")
+	response.SuccessResponse(w, http.StatusCreated, repo.ToDto())
 }
 
 func (rh RepositoryHandler) FetchAllRepositories(w http.ResponseWriter, r *http.Request) {
 	repos, err := rh.gitRepositoryUsecase.RetrieveAllRepos(r.Context())
 	if err != nil {
-		response.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		response.Error(w, err)
 		return
 	}
 
-	if len(repos) == 0 {
-		response.SuccessResponse(w, http.StatusOK, "no repository indexed yet")
-		return
-	}
-
-	var repoResponse []dtos.RepositoryMeta
+	repoResponse := make([]dtos.RepositoryMeta, 0, len(repos))
 
 	for _, v := range repos {
-		repo := dtos.RepositoryMeta{
-			Name:        v.Name,
-			URL:         v.URL,
-			Description: v.Description,
-			Language:    v.Language,
-			Owner: struct {
-				Login string "json:\"login\""
-			}{
-				Login: v.OwnerName,
-			},
-			ForksCount:      v.ForksCount,
-			StarsCount:      v.StarsCount,
-			OpenIssuesCount: v.OpenIssuesCount,
-			WatchersCount:   v.WatchersCount,
-			CreatedAt:       v.CreatedAt,
-			UpdatedAt:       v.UpdatedAt,
-		}
-		repoResponse = append(repoResponse, repo)
+		repoResponse = append(repoResponse, v.ToDto())
 	}
 
 	response.SuccessResponse(w, http.StatusOK, repoResponse)
 }
 
 func (rh RepositoryHandler) FetchRepository(w http.ResponseWriter, r *http.Request) {
-	owner := r.PathValue("owner")
-	if owner == "" {
-		response.ErrorResponse(w, http.StatusBadRequest, "Repository owner is required")
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
 		return
 	}
 
-	name := r.PathValue("name")
-	if name == "" {
-		response.ErrorResponse(w, http.StatusBadRequest, "Repository name is required")
+	repo, err := rh.gitRepositoryUsecase.FindRepoByName(r.Context(), repoName)
+	if err != nil {
+		response.Error(w, err)
 		return
 	}
 
-	repoName := fmt.Sprintf("%s/%s", owner, name)
+	response.SuccessResponse(w, http.StatusOK, repo.ToDto())
+}
 
-	ctx := r.Context()
+func (rh RepositoryHandler) RemoveRepository(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
 
-	repo, err := rh.gitRepositoryUsecase.FindRepoByName(ctx, repoName)
-	if err != nil {
-		if err == errcodes.ErrNoRecordFound {
-			response.ErrorResponse(w, http.StatusBadRequest, "no repository found")
-			return
-		}
-		response.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+	if err := rh.gitRepositoryUsecase.RemoveRepository(r.Context(), repoName); err != nil {
+		response.Error(w, err)
 		return
 	}
 
-	repoReponse := dtos.RepositoryMeta{
-		Name:        repo.Name,
-		URL:         repo.URL,
-		Description: repo.Description,
-		Language:    repo.Language,
-		Owner: struct {
-			Login string "json:\"login\""
-		}{
-			Login: repo.OwnerName,
-		},
-		ForksCount:      repo.ForksCount,
-		StarsCount:      repo.StarsCount,
-		OpenIssuesCount: repo.OpenIssuesCount,
-		WatchersCount:   repo.WatchersCount,
-		CreatedAt:       repo.CreatedAt,
-		UpdatedAt:       repo.UpdatedAt,
+	response.NoContent(w)
+}
+
+func (rh RepositoryHandler) ReindexRepository(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	repo, err := rh.gitRepositoryUsecase.ReindexRepository(r.Context(), repoName)
+	if err != nil {
+		response.Error(w, err)
+		return
 	}
-	response.SuccessResponse(w, http.StatusOK, repoReponse)
+
+	response.SuccessResponse(w, http.StatusAccepted, repo.ToDto())
 }
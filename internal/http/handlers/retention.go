@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+type RetentionHandler struct {
+	retentionUsecase usecases.RetentionUsecase
+}
+
+func NewRetentionHandler(retentionUsecase usecases.RetentionUsecase) *RetentionHandler {
+	return &RetentionHandler{retentionUsecase: retentionUsecase}
+}
+
+func (h *RetentionHandler) SetRetention(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.RetentionPolicy
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	repo, err := h.retentionUsecase.SetRetention(r.Context(), repoName, domain.RetentionPolicy{Days: req.Days, Commits: req.Commits})
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.SuccessResponse(w, http.StatusOK, repo.ToDto())
+}
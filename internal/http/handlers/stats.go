@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+type StatsHandler struct {
+	statsUsecase usecases.StatsUsecase
+}
+
+func NewStatsHandler(statsUsecase usecases.StatsUsecase) *StatsHandler {
+	return &StatsHandler{statsUsecase: statsUsecase}
+}
+
+// parseTime accepts an RFC 3339 timestamp or a date, which is read as midnight
+// in loc. An empty value is the zero time.
+func parseTime(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, loc)
+	if err != nil {
+		return time.Time{}, errcodes.ErrInvalidDate
+	}
+	return t, nil
+}
+
+// timeRange reads the tz, since and until parameters, dates are read in tz.
+func timeRange(params url.Values) (*time.Location, time.Time, time.Time, error) {
+	loc := time.UTC
+	if tz := params.Get("tz"); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, time.Time{}, time.Time{}, errcodes.ErrInvalidTimezone
+		}
+	}
+
+	since, err := parseTime(params.Get("since"), loc)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+
+	until, err := parseTime(params.Get("until"), loc)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+
+	return loc, since, until, nil
+}
+
+func toActivityBucketDtos(buckets []domain.ActivityBucket) []dtos.ActivityBucket {
+	res := make([]dtos.ActivityBucket, 0, len(buckets))
+	for _, b := range buckets {
+		res = append(res, dtos.ActivityBucket{Start: b.Start, Count: b.Count})
+	}
+	return res
+}
+
+func (h *StatsHandler) GetActivity(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	params := r.URL.Query()
+
+	loc, since, until, err := timeRange(params)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	authors, _ := strconv.Atoi(params.Get("authors"))
+
+	activity, err := h.statsUsecase.GetActivity(r.Context(), repoName, domain.ActivityQuery{
+		Interval: domain.ActivityInterval(params.Get("interval")),
+		Since:    since,
+		Until:    until,
+		Location: loc,
+		Authors:  authors,
+	})
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	res := dtos.ActivityResponse{
+		Interval:  string(activity.Interval),
+		Timezone:  activity.Location.String(),
+		Since:     activity.Since,
+		Until:     activity.Until,
+		Total:     activity.Total,
+		Buckets:   toActivityBucketDtos(activity.Buckets),
+		PunchCard: make([]dtos.PunchCardCell, 0, len(activity.PunchCard)),
+		Authors:   make([]dtos.AuthorActivity, 0, len(activity.Authors)),
+	}
+
+	for _, c := range activity.PunchCard {
+		res.PunchCard = append(res.PunchCard, dtos.PunchCardCell{Weekday: c.Weekday, Hour: c.Hour, Count: c.Count})
+	}
+
+	for _, a := range activity.Authors {
+		res.Authors = append(res.Authors, dtos.AuthorActivity{
+			ID:      a.Author.ID,
+			Name:    a.Author.Name,
+			Email:   a.Author.Email,
+			Count:   a.Count,
+			Buckets: toActivityBucketDtos(a.Buckets),
+		})
+	}
+
+	response.SuccessResponse(w, http.StatusOK, res)
+}
+
+func (h *StatsHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	params := r.URL.Query()
+
+	loc, since, until, err := timeRange(params)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	metrics, err := h.statsUsecase.GetDelivery(r.Context(), repoName, domain.DeliveryQuery{
+		Source:   domain.DeploymentSource(params.Get("source")),
+		Branch:   params.Get("branch"),
+		Since:    since,
+		Until:    until,
+		Location: loc,
+	})
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	res := dtos.DeliveryResponse{
+		Source:                string(metrics.Source),
+		Branch:                metrics.Branch,
+		Timezone:              metrics.Location.String(),
+		Since:                 metrics.Since,
+		Until:                 metrics.Until,
+		Deployments:           metrics.Deployments,
+		DeploymentsPerWeek:    metrics.DeploymentsPerWeek(),
+		FailedDeployments:     metrics.FailedDeployments,
+		ChangeFailureRate:     metrics.ChangeFailureRate(),
+		Commits:               metrics.Commits,
+		MedianLeadTimeSeconds: int64(metrics.LeadTime.Seconds()),
+		Buckets:               make([]dtos.DeliveryBucket, 0, len(metrics.Buckets)),
+	}
+
+	for _, b := range metrics.Buckets {
+		res.Buckets = append(res.Buckets, dtos.DeliveryBucket{
+			Start:                 b.Start,
+			Deployments:           b.Deployments,
+			FailedDeployments:     b.FailedDeployments,
+			ChangeFailureRate:     b.ChangeFailureRate(),
+			Commits:               b.Commits,
+			MedianLeadTimeSeconds: int64(b.LeadTime.Seconds()),
+		})
+	}
+
+	response.SuccessResponse(w, http.StatusOK, res)
+}
+
+func (h *StatsHandler) GetReverts(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := repoNameFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	params := r.URL.Query()
+
+	loc, since, until, err := timeRange(params)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	activity, err := h.statsUsecase.GetReverts(r.Context(), repoName, domain.ActivityQuery{
+		Interval: domain.ActivityInterval(params.Get("interval")),
+		Since:    since,
+		Until:    until,
+		Location: loc,
+	})
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	res := dtos.RevertsResponse{
+		Interval: string(activity.Interval),
+		Timezone: activity.Location.String(),
+		Since:    activity.Since,
+		Until:    activity.Until,
+		Total:    activity.Total,
+		Buckets:  toActivityBucketDtos(activity.Buckets),
+		Reverts:  make([]dtos.Revert, 0, len(activity.Reverts)),
+	}
+
+	for _, rv := range activity.Reverts {
+		revert := dtos.Revert{Commit: toCommitDto(rv.Commit)}
+		if rv.Reverted != nil {
+			reverted := toCommitDto(*rv.Reverted)
+			revert.Reverted = &reverted
+			revert.TimeToRevertSeconds = int64(rv.Commit.Date.Sub(rv.Reverted.Date).Seconds())
+		}
+		res.Reverts = append(res.Reverts, revert)
+	}
+
+	response.SuccessResponse(w, http.StatusOK, res)
+}
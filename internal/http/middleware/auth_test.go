@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAPIKeyRepository looks keys up by the hash of their secret
+type stubAPIKeyRepository struct {
+	repository.APIKeyRepository
+	keys map[string]*domain.APIKey
+}
+
+func (s stubAPIKeyRepository) APIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	for secret, key := range s.keys {
+		sum := sha256.Sum256([]byte(secret))
+		if hex.EncodeToString(sum[:]) == keyHash {
+			return key, nil
+		}
+	}
+	return nil, errcodes.ErrNoRecordFound
+}
+
+func ok(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// newAuthHandler serves /repositories to readers and /reindex to editors
+func newAuthHandler(authEnabled bool) http.Handler {
+	revokedAt := time.Now()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repositories", ok)
+	mux.HandleFunc("GET /openapi.json", ok)
+	mux.HandleFunc("POST /reindex", RequireRole(domain.RoleEditor, ok))
+	if !authEnabled {
+		return mux
+	}
+
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(stubAPIKeyRepository{keys: map[string]*domain.APIKey{
+		"gs_reader":  {ID: 1, Role: domain.RoleReader},
+		"gs_editor":  {ID: 2, Role: domain.RoleEditor},
+		"gs_revoked": {ID: 3, Role: domain.RoleAdmin, RevokedAt: &revokedAt},
+	}}, *log.NewLogger())
+	return Authenticate(apiKeyUsecase, mux)
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name          string
+		authEnabled   bool
+		method        string
+		path          string
+		authorization string
+		status        int
+		code          errcodes.Code
+	}{
+		{name: "missing key", authEnabled: true, method: http.MethodGet, path: "/repositories", status: http.StatusUnauthorized, code: errcodes.CodeUnauthenticated},
+		{name: "not a bearer token", authEnabled: true, method: http.MethodGet, path: "/repositories", authorization: "Basic gs_reader", status: http.StatusUnauthorized, code: errcodes.CodeUnauthenticated},
+		{name: "unknown key", authEnabled: true, method: http.MethodGet, path: "/repositories", authorization: "Bearer gs_unknown", status: http.StatusUnauthorized, code: errcodes.CodeUnauthenticated},
+		{name: "revoked key", authEnabled: true, method: http.MethodGet, path: "/repositories", authorization: "Bearer gs_revoked", status: http.StatusUnauthorized, code: errcodes.CodeUnauthenticated},
+		{name: "reader", authEnabled: true, method: http.MethodGet, path: "/repositories", authorization: "Bearer gs_reader", status: http.StatusOK},
+		{name: "reader on an editor route", authEnabled: true, method: http.MethodPost, path: "/reindex", authorization: "Bearer gs_reader", status: http.StatusForbidden, code: errcodes.CodePermissionDenied},
+		{name: "editor on an editor route", authEnabled: true, method: http.MethodPost, path: "/reindex", authorization: "bearer gs_editor", status: http.StatusOK},
+		{name: "public path", authEnabled: true, method: http.MethodGet, path: "/openapi.json", status: http.StatusOK},
+		{name: "auth disabled", authEnabled: false, method: http.MethodPost, path: "/reindex", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := newAuthHandler(tt.authEnabled)
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rec, req)
+
+			// Assert
+			require.Equal(t, tt.status, rec.Code)
+			if tt.code == "" {
+				return
+			}
+
+			var body struct {
+				Error struct {
+					Code errcodes.Code `json:"code"`
+				} `json:"error"`
+			}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+			assert.Equal(t, tt.code, body.Error.Code)
+			if tt.status == http.StatusUnauthorized {
+				assert.Equal(t, `Bearer realm="git-service"`, rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/ratelimit"
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+const (
+	// DefaultRateLimitGroup is the group used for routes without their own limit.
+	DefaultRateLimitGroup = "default"
+	// AuthRateLimitGroup limits the requests of a client that fail authentication.
+	AuthRateLimitGroup = "auth"
+)
+
+// RateLimit limits requests per client with a token bucket for each route
+// group. The group of a route is the first segment of its path, e.g.
+// "commits" for /commits/{owner}/{name}. Clients are identified by their API
+// key, or by their IP address when the request is not authenticated.
+func RateLimit(limiters map[string]*ratelimit.Limiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter, ok := limiters[routeGroup(r.URL.Path)]
+		if !ok {
+			limiter, ok = limiters[DefaultRateLimitGroup]
+		}
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		res := limiter.Allow(clientKey(r))
+		if !allowed(w, res) {
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// LimitAuthFailures throttles clients by IP address once too many of their
+// requests failed authentication, so keys cannot be guessed at the pace of the
+// per key limits. Only requests answered with 401 take a token. It wraps
+// Authenticate, which rejects those requests before RateLimit sees them.
+func LimitAuthFailures(limiter *ratelimit.Limiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := clientIP(r)
+		if !allowed(w, limiter.Peek(key)) {
+			return
+		}
+
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r)
+
+		if sw.status == http.StatusUnauthorized {
+			limiter.Allow(key)
+		}
+	})
+}
+
+// allowed writes the rate limit headers of res, and the error response when
+// the request is not allowed.
+func allowed(w http.ResponseWriter, res ratelimit.Result) bool {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(res.Reset.Unix(), 10))
+
+	if !res.Allowed {
+		retryAfter := int(math.Ceil(res.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		response.Error(w, errcodes.ErrRateLimited.WithDetails(map[string]interface{}{
+			"retry_after": retryAfter,
+		}))
+	}
+	return res.Allowed
+}
+
+func routeGroup(path string) string {
+	group, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return group
+}
+
+func clientKey(r *http.Request) string {
+	if key, ok := APIKeyFromContext(r.Context()); ok {
+		return fmt.Sprintf("key:%d", key.ID)
+	}
+	return clientIP(r)
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// statusWriter records the status of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
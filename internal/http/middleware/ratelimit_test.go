@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(handler http.Handler, path, authorization, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit(t *testing.T) {
+	// Arrange
+	handler := RateLimit(map[string]*ratelimit.Limiter{
+		"commits": ratelimit.New(ratelimit.Limit{Rate: 0.5, Burst: 1}),
+	}, http.HandlerFunc(ok))
+
+	// Act
+	first := serve(handler, "/commits/org/repo", "", "10.0.0.1:1234")
+	limited := serve(handler, "/commits/org/repo", "", "10.0.0.1:1234")
+	otherClient := serve(handler, "/commits/org/repo", "", "10.0.0.2:1234")
+	otherGroup := serve(handler, "/authors/top", "", "10.0.0.1:1234")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "1", first.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", first.Header().Get("X-RateLimit-Remaining"))
+
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "2", limited.Header().Get("Retry-After"))
+	assert.Contains(t, limited.Body.String(), `"rate_limited"`)
+
+	assert.Equal(t, http.StatusOK, otherClient.Code)
+	assert.Equal(t, http.StatusOK, otherGroup.Code)
+}
+
+// TestRateLimit_PerKey tests that authenticated clients are limited by key
+// rather than by address
+func TestRateLimit_PerKey(t *testing.T) {
+	// Arrange
+	limiter := RateLimit(map[string]*ratelimit.Limiter{
+		DefaultRateLimitGroup: ratelimit.New(ratelimit.Limit{Rate: 0.001, Burst: 1}),
+	}, http.HandlerFunc(ok))
+	withKey := func(id uint) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), apiKeyContextKey, &domain.APIKey{ID: id})
+			limiter.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+
+	// Act
+	first := serve(withKey(1), "/repositories", "", "10.0.0.1:1234")
+	otherKey := serve(withKey(2), "/repositories", "", "10.0.0.1:1234")
+	limited := serve(withKey(1), "/repositories", "", "10.0.0.2:1234")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusOK, otherKey.Code)
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+}
+
+// TestLimitAuthFailures tests that clients are throttled by address once
+// their requests failed authentication, while valid keys are not charged
+func TestLimitAuthFailures(t *testing.T) {
+	// Arrange
+	handler := LimitAuthFailures(ratelimit.New(ratelimit.Limit{Rate: 0.001, Burst: 2}), newAuthHandler(true))
+
+	// Act
+	valid := serve(handler, "/repositories", "Bearer gs_reader", "10.0.0.1:1234")
+	notFound := serve(handler, "/missing", "Bearer gs_reader", "10.0.0.1:1234")
+	missing := serve(handler, "/repositories", "", "10.0.0.1:1234")
+	unknown := serve(handler, "/repositories", "Bearer gs_guess", "10.0.0.1:1234")
+	limited := serve(handler, "/repositories", "Bearer gs_reader", "10.0.0.1:1234")
+	otherClient := serve(handler, "/repositories", "Bearer gs_guess", "10.0.0.2:1234")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, valid.Code)
+	assert.Equal(t, http.StatusNotFound, notFound.Code)
+	assert.Equal(t, http.StatusUnauthorized, missing.Code)
+	assert.Equal(t, http.StatusUnauthorized, unknown.Code)
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.NotEmpty(t, limited.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusUnauthorized, otherClient.Code)
+}
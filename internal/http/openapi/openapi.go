@@ -0,0 +1,141 @@
+// Package openapi holds the OpenAPI 3 document of the REST API and a
+// middleware that validates requests against it.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+)
+
+//go:embed openapi.json
+var specJSON []byte
+
+// Document is the subset of an OpenAPI 3 document used by the validator.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// PathItem maps lower case HTTP methods to operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Parameters  []Parameter         `json:"parameters"`
+	RequestBody *RequestBody        `json:"requestBody"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type Schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Format     string             `json:"format"`
+	Enum       []string           `json:"enum"`
+	Minimum    *float64           `json:"minimum"`
+	Maximum    *float64           `json:"maximum"`
+	Required   []string           `json:"required"`
+	Properties map[string]*Schema `json:"properties"`
+	Items      *Schema            `json:"items"`
+	OneOf      []*Schema          `json:"oneOf"`
+}
+
+// JSON returns the raw document as served at GET /openapi.json.
+func JSON() []byte {
+	return specJSON
+}
+
+// Load parses the embedded document.
+func Load() (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(specJSON, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Resolve follows a local $ref, schemas without one are returned as is.
+func (d *Document) Resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+// Operation returns the operation matching method and a concrete request path,
+// along with the values of its path parameters. Literal segments win over
+// parameters, so /authors/top is preferred to /authors/{id}.
+func (d *Document) Operation(method, path string) (*Operation, map[string]string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var (
+		best       *Operation
+		bestParams map[string]string
+		bestScore  = -1
+	)
+
+	for template, item := range d.Paths {
+		op, ok := item[strings.ToLower(method)]
+		if !ok {
+			continue
+		}
+
+		params, score, ok := matchPath(strings.Split(strings.Trim(template, "/"), "/"), segments)
+		if ok && score > bestScore {
+			best, bestParams, bestScore = op, params, score
+		}
+	}
+
+	return best, bestParams, best != nil
+}
+
+// matchPath matches path segments against a template and scores the match by
+// the number of literal segments.
+func matchPath(template, segments []string) (map[string]string, int, bool) {
+	if len(template) != len(segments) {
+		return nil, 0, false
+	}
+
+	params := make(map[string]string)
+	score := 0
+	for i, t := range template {
+		if strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}") {
+			if segments[i] == "" {
+				return nil, 0, false
+			}
+			params[strings.Trim(t, "{}")] = segments[i]
+			continue
+		}
+		if t != segments[i] {
+			return nil, 0, false
+		}
+		score++
+	}
+	return params, score, true
+}
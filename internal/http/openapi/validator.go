@@ -0,0 +1,238 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/just-nibble/git-service/pkg/response"
+)
+
+// maxBodySize bounds the request bodies the validator reads.
+const maxBodySize = 1 << 20
+
+// Validate rejects requests whose parameters or JSON body do not match the
+// operation in doc. Requests without a matching operation are passed on so
+// the router can answer them.
+func Validate(doc *Document, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op, pathParams, ok := doc.Operation(r.Method, r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := doc.validateParameters(op, pathParams, r); err != nil {
+			response.ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if op.RequestBody != nil {
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+			if err != nil {
+				response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if err := doc.validateBody(op.RequestBody, body); err != nil {
+				response.ErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (d *Document) validateParameters(op *Operation, pathParams map[string]string, r *http.Request) error {
+	query := r.URL.Query()
+
+	for _, p := range op.Parameters {
+		var value string
+		var present bool
+
+		switch p.In {
+		case "path":
+			value, present = pathParams[p.Name]
+		case "query":
+			present = query.Has(p.Name)
+			value = query.Get(p.Name)
+		case "header":
+			value = r.Header.Get(p.Name)
+			present = value != ""
+		default:
+			continue
+		}
+
+		if !present {
+			if p.Required {
+				return fmt.Errorf("%s parameter %q is required", p.In, p.Name)
+			}
+			continue
+		}
+
+		schema := d.Resolve(p.Schema)
+		values := []string{value}
+		if schema != nil && schema.Type == "array" {
+			// arrays are sent exploded, as one parameter per item
+			schema, values = d.Resolve(schema.Items), query[p.Name]
+		}
+
+		for _, value := range values {
+			if err := d.validateString(schema, value); err != nil {
+				return fmt.Errorf("%s parameter %q %s", p.In, p.Name, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+func (d *Document) validateBody(body *RequestBody, raw []byte) error {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		if body.Required {
+			return fmt.Errorf("request body is required")
+		}
+		return nil
+	}
+
+	media, ok := body.Content["application/json"]
+	if !ok {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return fmt.Errorf("request body is not valid JSON")
+	}
+
+	return d.validateValue(d.Resolve(media.Schema), value, "body")
+}
+
+// validateString validates a parameter, which always arrives as a string.
+func (d *Document) validateString(s *Schema, value string) error {
+	if s == nil {
+		return nil
+	}
+
+	switch s.Type {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		return checkRange(s, float64(n))
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("must be a number")
+		}
+		return checkRange(s, n)
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("must be a boolean")
+		}
+	case "string":
+		return checkString(s, value)
+	}
+	return nil
+}
+
+// validateValue validates a decoded JSON value.
+func (d *Document) validateValue(s *Schema, value interface{}, path string) error {
+	if s == nil || value == nil {
+		return nil
+	}
+
+	if len(s.OneOf) > 0 {
+		for _, alt := range s.OneOf {
+			if d.validateValue(d.Resolve(alt), value, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s does not match any allowed schema", path)
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an object", path)
+		}
+		for _, name := range s.Required {
+			if v, ok := obj[name]; !ok || v == nil || v == "" {
+				return fmt.Errorf("%s.%s is required", path, name)
+			}
+		}
+		for name, prop := range s.Properties {
+			if err := d.validateValue(d.Resolve(prop), obj[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an array", path)
+		}
+		for i, item := range arr {
+			if err := d.validateValue(d.Resolve(s.Items), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok || (s.Type == "integer" && n != float64(int64(n))) {
+			return fmt.Errorf("%s must be an %s", path, s.Type)
+		}
+		if err := checkRange(s, n); err != nil {
+			return fmt.Errorf("%s %s", path, err.Error())
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", path)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", path)
+		}
+		if err := checkString(s, str); err != nil {
+			return fmt.Errorf("%s %s", path, err.Error())
+		}
+	}
+	return nil
+}
+
+func checkRange(s *Schema, n float64) error {
+	if s.Minimum != nil && n < *s.Minimum {
+		return fmt.Errorf("must be at least %g", *s.Minimum)
+	}
+	if s.Maximum != nil && n > *s.Maximum {
+		return fmt.Errorf("must be at most %g", *s.Maximum)
+	}
+	return nil
+}
+
+func checkString(s *Schema, value string) error {
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, value) {
+		return fmt.Errorf("must be one of: %s", strings.Join(s.Enum, ", "))
+	}
+
+	switch s.Format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("must be an RFC 3339 date-time")
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return fmt.Errorf("must be a date (YYYY-MM-DD)")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	doc, err := Load()
+	require.NoError(t, err)
+
+	handler := Validate(doc, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{name: "valid query", method: http.MethodGet, target: "/commits/chromium/chromium?limit=10&direction=asc", status: http.StatusTeapot},
+		{name: "non integer limit", method: http.MethodGet, target: "/commits/chromium/chromium?limit=ten", status: http.StatusBadRequest},
+		{name: "unknown direction", method: http.MethodGet, target: "/commits/chromium/chromium?direction=up", status: http.StatusBadRequest},
+		{name: "missing required query", method: http.MethodGet, target: "/authors/chromium/chromium/top", status: http.StatusBadRequest},
+		{name: "valid body", method: http.MethodPost, target: "/repositories", body: `{"name":"swaggo/swag"}`, status: http.StatusTeapot},
+		{name: "missing body field", method: http.MethodPost, target: "/repositories", body: `{}`, status: http.StatusBadRequest},
+		{name: "wrong body type", method: http.MethodPost, target: "/repositories", body: `{"name":1}`, status: http.StatusBadRequest},
+		{name: "invalid enum in body", method: http.MethodPost, target: "/admin/keys", body: `{"name":"ci","role":"owner"}`, status: http.StatusBadRequest},
+		{name: "undocumented route", method: http.MethodGet, target: "/unknown", status: http.StatusTeapot},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/handlers"
+	"github.com/just-nibble/git-service/internal/http/middleware"
+)
+
+func NewAPIKeyRouter(router *http.ServeMux, handler handlers.APIKeyHandler) {
+	router.HandleFunc("GET /admin/keys", middleware.RequireRole(domain.RoleAdmin, handler.ListAPIKeys))
+	router.HandleFunc("POST /admin/keys", middleware.RequireRole(domain.RoleAdmin, handler.CreateAPIKey))
+	router.HandleFunc("DELETE /admin/keys/{id}", middleware.RequireRole(domain.RoleAdmin, handler.RevokeAPIKey))
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/handlers"
+	"github.com/just-nibble/git-service/internal/http/middleware"
+)
+
+func NewArchiveRouter(router *http.ServeMux, handler handlers.ArchiveHandler) {
+	router.HandleFunc("GET /repositories/{owner}/{name}/export", middleware.RequireRole(domain.RoleEditor, handler.Export))
+}
@@ -7,5 +7,7 @@ import (
 )
 
 func NewAuthorRouter(router *http.ServeMux, handler handlers.AuthorHandler) {
-	router.HandleFunc("/authors/{owner}/{name}/top", handler.GetTopAuthors)
+	router.HandleFunc("GET /authors/top", handler.GetLeaderboard)
+	router.HandleFunc("GET /authors/{id}", handler.GetAuthor)
+	router.HandleFunc("GET /authors/{owner}/{name}/top", handler.GetTopAuthors)
 }
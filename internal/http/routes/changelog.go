@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/http/handlers"
+)
+
+func NewChangelogRouter(router *http.ServeMux, handler handlers.ChangelogHandler) {
+	router.HandleFunc("GET /repositories/{owner}/{name}/changelog", handler.GetChangelog)
+}
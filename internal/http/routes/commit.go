@@ -7,5 +7,5 @@ import (
 )
 
 func NewCommitRouter(router *http.ServeMux, handler handlers.CommitHandler) {
-	router.HandleFunc("/commits/{owner}/{name}", handler.GetCommitsByRepoName)
+	router.HandleFunc("GET /commits/{owner}/{name}", handler.GetCommitsByRepoName)
 }
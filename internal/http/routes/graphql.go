@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/http/handlers"
+)
+
+func NewGraphQLRouter(router *http.ServeMux, handler handlers.GraphQLHandler) {
+	router.HandleFunc("POST /graphql", handler.Query)
+}
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/http/handlers"
+)
+
+func NewOpenAPIRouter(router *http.ServeMux, handler handlers.OpenAPIHandler) {
+	router.HandleFunc("GET /openapi.json", handler.GetSpec)
+}
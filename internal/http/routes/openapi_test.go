@@ -0,0 +1,244 @@
+package routes
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/http/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dtoSchemas maps every DTO to its schema in the specification. DTOs that are
+// not part of the API map to an empty schema name.
+var dtoSchemas = map[string]struct {
+	typ    reflect.Type
+	schema string
+}{
+	"APIKeyInput":            {reflect.TypeOf(dtos.APIKeyInput{}), "APIKeyInput"},
+	"APIKeyResponse":         {reflect.TypeOf(dtos.APIKeyResponse{}), "APIKeyResponse"},
+	"ActivityBucket":         {reflect.TypeOf(dtos.ActivityBucket{}), "ActivityBucket"},
+	"ActivityResponse":       {reflect.TypeOf(dtos.ActivityResponse{}), "ActivityResponse"},
+	"ArchiveAuthor":          {reflect.TypeOf(dtos.ArchiveAuthor{}), ""},     // archive record
+	"ArchiveCommit":          {reflect.TypeOf(dtos.ArchiveCommit{}), ""},     // archive record
+	"ArchiveFile":            {reflect.TypeOf(dtos.ArchiveFile{}), ""},       // archive record
+	"ArchiveLink":            {reflect.TypeOf(dtos.ArchiveLink{}), ""},       // archive record
+	"ArchiveRepository":      {reflect.TypeOf(dtos.ArchiveRepository{}), ""}, // archive record
+	"APIPagingDto":           {reflect.TypeOf(dtos.APIPagingDto{}), ""},      // sent as query parameters
+	"Author":                 {reflect.TypeOf(dtos.Author{}), "Author"},
+	"AuthorActivity":         {reflect.TypeOf(dtos.AuthorActivity{}), "AuthorActivity"},
+	"AuthorProfile":          {reflect.TypeOf(dtos.AuthorProfile{}), "AuthorProfile"},
+	"ChangelogCommit":        {reflect.TypeOf(dtos.ChangelogCommit{}), "ChangelogCommit"},
+	"ChangelogRef":           {reflect.TypeOf(dtos.ChangelogRef{}), "ChangelogRef"},
+	"ChangelogResponse":      {reflect.TypeOf(dtos.ChangelogResponse{}), "ChangelogResponse"},
+	"ChangelogSection":       {reflect.TypeOf(dtos.ChangelogSection{}), "ChangelogSection"},
+	"Commit":                 {reflect.TypeOf(dtos.Commit{}), ""}, // GitHub payload
+	"CommitReponse":          {reflect.TypeOf(dtos.CommitReponse{}), "CommitReponse"},
+	"CompareResponse":        {reflect.TypeOf(dtos.CompareResponse{}), "CompareResponse"},
+	"DeliveryBucket":         {reflect.TypeOf(dtos.DeliveryBucket{}), "DeliveryBucket"},
+	"DeliveryResponse":       {reflect.TypeOf(dtos.DeliveryResponse{}), "DeliveryResponse"},
+	"DirectoryOwnership":     {reflect.TypeOf(dtos.DirectoryOwnership{}), "DirectoryOwnership"},
+	"GraphQLRequest":         {reflect.TypeOf(dtos.GraphQLRequest{}), "GraphQLRequest"},
+	"Hotspot":                {reflect.TypeOf(dtos.Hotspot{}), "Hotspot"},
+	"MultiCommitsResponse":   {reflect.TypeOf(dtos.MultiCommitsResponse{}), "MultiCommitsResponse"},
+	"OwnershipResponse":      {reflect.TypeOf(dtos.OwnershipResponse{}), "OwnershipResponse"},
+	"PagingInfo":             {reflect.TypeOf(dtos.PagingInfo{}), "PagingInfo"},
+	"PathOwner":              {reflect.TypeOf(dtos.PathOwner{}), "PathOwner"},
+	"PullRequest":            {reflect.TypeOf(dtos.PullRequest{}), "PullRequest"},
+	"PunchCardCell":          {reflect.TypeOf(dtos.PunchCardCell{}), "PunchCardCell"},
+	"Release":                {reflect.TypeOf(dtos.Release{}), "Release"},
+	"RepositoryContribution": {reflect.TypeOf(dtos.RepositoryContribution{}), "RepositoryContribution"},
+	"RepositoryInput":        {reflect.TypeOf(dtos.RepositoryInput{}), "RepositoryInput"},
+	"RepositoryMeta":         {reflect.TypeOf(dtos.RepositoryMeta{}), "RepositoryMeta"},
+	"RetentionPolicy":        {reflect.TypeOf(dtos.RetentionPolicy{}), "RetentionPolicy"},
+	"Revert":                 {reflect.TypeOf(dtos.Revert{}), "Revert"},
+	"RevertsResponse":        {reflect.TypeOf(dtos.RevertsResponse{}), "RevertsResponse"},
+	"Tag":                    {reflect.TypeOf(dtos.Tag{}), "Tag"},
+}
+
+// registeredRoutes returns the patterns passed to HandleFunc in this package.
+func registeredRoutes(t *testing.T) []string {
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	var patterns []string
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		require.NoError(t, err)
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "HandleFunc" || len(call.Args) == 0 {
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			require.True(t, ok, "%s: route patterns must be string literals", fset.Position(call.Pos()))
+
+			pattern, err := strconv.Unquote(lit.Value)
+			require.NoError(t, err)
+			patterns = append(patterns, pattern)
+			return true
+		})
+	}
+	return patterns
+}
+
+// exportedStructs returns the names of the exported struct types in the dtos package.
+func exportedStructs(t *testing.T) []string {
+	files, err := filepath.Glob("../dtos/*.go")
+	require.NoError(t, err)
+
+	var names []string
+	for _, file := range files {
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+		require.NoError(t, err)
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			if _, isStruct := spec.Type.(*ast.StructType); isStruct && spec.Name.IsExported() {
+				names = append(names, spec.Name.Name)
+			}
+			return false
+		})
+	}
+	return names
+}
+
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	patterns := registeredRoutes(t)
+	require.NotEmpty(t, patterns)
+
+	registered := make(map[string]bool)
+	for _, pattern := range patterns {
+		method, path, ok := strings.Cut(pattern, " ")
+		require.True(t, ok, "route %q must declare its method", pattern)
+
+		registered[strings.ToLower(method)+" "+path] = true
+
+		item, ok := doc.Paths[path]
+		if assert.True(t, ok, "path %s is not documented", path) {
+			assert.Contains(t, item, strings.ToLower(method), "operation %s is not documented", pattern)
+		}
+	}
+
+	for path, item := range doc.Paths {
+		for method := range item {
+			assert.True(t, registered[method+" "+path], "documented operation %s %s is not registered", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func TestDTOsMatchOpenAPISpec(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	for _, name := range exportedStructs(t) {
+		dto, ok := dtoSchemas[name]
+		if !assert.True(t, ok, "dtos.%s is missing from dtoSchemas", name) || dto.schema == "" {
+			continue
+		}
+
+		schema, ok := doc.Components.Schemas[dto.schema]
+		if assert.True(t, ok, "schema %s is not documented", dto.schema) {
+			assertStructMatchesSchema(t, doc, dto.typ, schema, name)
+		}
+	}
+}
+
+func assertStructMatchesSchema(t *testing.T, doc *openapi.Document, typ reflect.Type, schema *openapi.Schema, path string) {
+	schema = doc.Resolve(schema)
+	if !assert.Equal(t, "object", schema.Type, "%s must be an object", path) {
+		return
+	}
+
+	fields := make(map[string]bool)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = true
+
+		prop, ok := schema.Properties[name]
+		if !assert.True(t, ok, "%s.%s is not documented", path, name) {
+			continue
+		}
+		assertTypeMatchesSchema(t, doc, field.Type, prop, path+"."+name)
+	}
+
+	for name := range schema.Properties {
+		assert.True(t, fields[name], "%s.%s is documented but not in the DTO", path, name)
+	}
+}
+
+func assertTypeMatchesSchema(t *testing.T, doc *openapi.Document, typ reflect.Type, schema *openapi.Schema, path string) {
+	schema = doc.Resolve(schema)
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ == reflect.TypeOf(time.Time{}):
+		assert.Equal(t, "string", schema.Type, path)
+		assert.Equal(t, "date-time", schema.Format, path)
+	case typ.Kind() == reflect.Struct:
+		assertStructMatchesSchema(t, doc, typ, schema, path)
+	case typ.Kind() == reflect.Slice:
+		if assert.Equal(t, "array", schema.Type, path) {
+			assertTypeMatchesSchema(t, doc, typ.Elem(), schema.Items, path+"[]")
+		}
+	case typ.Kind() == reflect.String:
+		assert.Equal(t, "string", schema.Type, path)
+	case typ.Kind() == reflect.Bool:
+		assert.Equal(t, "boolean", schema.Type, path)
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Uint64:
+		assert.Equal(t, "integer", schema.Type, path)
+	case typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64:
+		assert.Equal(t, "number", schema.Type, path)
+	}
+}
+
+func TestPagingQueryMatchesOpenAPISpec(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	op, _, ok := doc.Operation("GET", "/commits/chromium/chromium")
+	require.True(t, ok)
+
+	params := make(map[string]bool)
+	for _, p := range op.Parameters {
+		if p.In == "query" {
+			params[p.Name] = true
+		}
+	}
+
+	typ := reflect.TypeOf(dtos.APIPagingDto{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		assert.True(t, params[name], "paging parameter %s is not documented", name)
+	}
+}
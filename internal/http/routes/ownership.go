@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/http/handlers"
+)
+
+func NewOwnershipRouter(router *http.ServeMux, handler handlers.OwnershipHandler) {
+	router.HandleFunc("GET /repositories/{owner}/{name}/ownership", handler.GetOwnership)
+}
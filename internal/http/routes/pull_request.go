@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/http/handlers"
+)
+
+func NewPullRequestRouter(router *http.ServeMux, handler handlers.PullRequestHandler) {
+	router.HandleFunc("GET /repositories/{owner}/{name}/pulls", handler.GetPullRequests)
+	router.HandleFunc("GET /repositories/{owner}/{name}/commits/{hash}/pulls", handler.GetCommitPullRequests)
+}
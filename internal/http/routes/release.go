@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/http/handlers"
+)
+
+func NewReleaseRouter(router *http.ServeMux, handler handlers.ReleaseHandler) {
+	router.HandleFunc("GET /repositories/{owner}/{name}/tags", handler.GetTags)
+	router.HandleFunc("GET /repositories/{owner}/{name}/releases", handler.GetReleases)
+	router.HandleFunc("GET /repositories/{owner}/{name}/compare", handler.Compare)
+}
@@ -3,10 +3,15 @@ package routes
 import (
 	"net/http"
 
+	"github.com/just-nibble/git-service/internal/domain"
 	"github.com/just-nibble/git-service/internal/http/handlers"
+	"github.com/just-nibble/git-service/internal/http/middleware"
 )
 
 func NewRepositoryRouter(router *http.ServeMux, handler handlers.RepositoryHandler) {
-	router.HandleFunc("/repositories", handler.AddRepository)
-	router.HandleFunc("/repositories/{owner}/{name}", handler.FetchRepository)
+	router.HandleFunc("GET /repositories", handler.FetchAllRepositories)
+	router.HandleFunc("POST /repositories", middleware.RequireRole(domain.RoleEditor, handler.AddRepository))
+	router.HandleFunc("GET /repositories/{owner}/{name}", handler.FetchRepository)
+	router.HandleFunc("DELETE /repositories/{owner}/{name}", middleware.RequireRole(domain.RoleEditor, handler.RemoveRepository))
+	router.HandleFunc("POST /repositories/{owner}/{name}/reindex", middleware.RequireRole(domain.RoleEditor, handler.ReindexRepository))
 }
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/handlers"
+	"github.com/just-nibble/git-service/internal/http/middleware"
+)
+
+func NewRetentionRouter(router *http.ServeMux, handler handlers.RetentionHandler) {
+	router.HandleFunc("PUT /repositories/{owner}/{name}/retention", middleware.RequireRole(domain.RoleEditor, handler.SetRetention))
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/just-nibble/git-service/internal/http/handlers"
+)
+
+func NewStatsRouter(router *http.ServeMux, handler handlers.StatsHandler) {
+	router.HandleFunc("GET /repositories/{owner}/{name}/stats/activity", handler.GetActivity)
+	router.HandleFunc("GET /repositories/{owner}/{name}/metrics/delivery", handler.GetDelivery)
+	router.HandleFunc("GET /repositories/{owner}/{name}/stats/reverts", handler.GetReverts)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/just-nibble/git-service/internal/domain"
+)
+
+// APIKeyRepository defines an interface for database operations
+type APIKeyRepository interface {
+	SaveAPIKey(ctx context.Context, key domain.APIKey, keyHash string) (*domain.APIKey, error)
+	APIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	AllAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id uint) error
+}
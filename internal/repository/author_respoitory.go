@@ -2,9 +2,21 @@ package repository
 
 import (
 	"context"
+
+	"github.com/just-nibble/git-service/internal/domain"
 )
 
 // AuthorRepository defines an interface for database operations
 type AuthorRepository interface {
-	GetTopAuthors(ctx context.Context, repoName string, limit int) ([]Author, error)
+	GetTopAuthors(ctx context.Context, repoName string, query domain.TopAuthorsQuery) ([]domain.Author, error)
+	// GetTopAuthorsAcross ranks authors across the named repositories, or across
+	// every repository when repoNames is empty
+	GetTopAuthorsAcross(ctx context.Context, repoNames []string, query domain.TopAuthorsQuery) ([]domain.Author, error)
+	AuthorProfile(ctx context.Context, authorID uint) (*domain.AuthorProfile, error)
+	// AuthorProfiles returns the profiles of the authors in the order of
+	// authorIDs, unknown authors are left out
+	AuthorProfiles(ctx context.Context, authorIDs []uint) ([]domain.AuthorProfile, error)
+	// RepairAuthorStats recomputes the commit aggregates and returns the number
+	// of rows that were wrong
+	RepairAuthorStats(ctx context.Context) (int64, error)
 }
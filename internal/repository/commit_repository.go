@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/just-nibble/git-service/internal/domain"
 )
@@ -9,6 +10,44 @@ import (
 // CommitRepository defines an interface for database operations
 type CommitRepository interface {
 	SaveCommit(ctx context.Context, commit domain.Commit) (*domain.Commit, error)
+	// SaveCommits stores the commits of a page that are not stored yet and moves
+	// the cursor of repo to its page and the last stored commit in the same
+	// transaction, it returns the stored commits. It returns
+	// errcodes.ErrNoRecordFound when repo is not stored
+	SaveCommits(ctx context.Context, repo domain.RepositoryMeta, commits []domain.Commit) ([]domain.Commit, error)
+	// ImportCommits stores the commits that are not stored yet like SaveCommits
+	// but leaves the cursor of repo alone, it returns the stored commits
+	ImportCommits(ctx context.Context, repo domain.RepositoryMeta, commits []domain.Commit) ([]domain.Commit, error)
 	GetCommitByHash(ctx context.Context, commitHash string) (*domain.Commit, error)
-	GetCommitsByRepository(ctx context.Context, repoMetadata domain.RepositoryMeta, query domain.APIPaging) ([]domain.Commit, error)
+	GetCommitsByRepository(ctx context.Context, repoMetadata domain.RepositoryMeta, query domain.APIPaging, filter domain.CommitFilter) ([]domain.Commit, domain.PagingInfo, error)
+	// StreamCommits calls fn with every commit of a repository matching filter,
+	// in the order of query, which is not paged. The commits hold their author
+	// and link but not the commits reverting them. An error of fn stops the
+	// stream and is returned
+	StreamCommits(ctx context.Context, repo domain.RepositoryMeta, query domain.APIPaging, filter domain.CommitFilter, fn func(domain.Commit) error) error
+	CommitsBetween(ctx context.Context, repoID uint, since, until time.Time, limit int) ([]domain.Commit, error)
+	// RepositoryCommits returns up to limit commits of a repository with an id
+	// above afterID, by id, with their authors, files and links
+	RepositoryCommits(ctx context.Context, repoID, afterID uint, limit int) ([]domain.Commit, error)
+	// LastCommitID returns the highest id of the commits of a repository, 0
+	// when it has none
+	LastCommitID(ctx context.Context, repoID uint) (uint, error)
+	UnparsedCommits(ctx context.Context, limit int) ([]domain.Commit, error)
+	UpdateConventional(ctx context.Context, commits []domain.Commit) error
+	// CommitsWithoutFiles returns up to limit commits of a repository made since
+	// since whose files are not stored, newest first
+	CommitsWithoutFiles(ctx context.Context, repoID uint, since time.Time, limit int) ([]domain.Commit, error)
+	// SaveCommitFiles stores the changed files and line counts of a stored commit
+	SaveCommitFiles(ctx context.Context, commit domain.Commit) error
+	// UnlinkedCommits returns up to limit commits with an id above afterID that
+	// may revert or amend another commit but have no stored link, by id
+	UnlinkedCommits(ctx context.Context, afterID uint, limit int) ([]domain.Commit, error)
+	SaveCommitLinks(ctx context.Context, commits []domain.Commit) error
+	// ResolveCommitLinks links the links of a repository to the indexed commits
+	// they target and returns the number of links resolved
+	ResolveCommitLinks(ctx context.Context, repoID uint) (int64, error)
+	// PruneCommits deletes up to limit commits of a repository, oldest first,
+	// that are dated before before or older than the newest keep commits. Zero
+	// values do not limit, it returns the number of commits deleted
+	PruneCommits(ctx context.Context, repoID uint, before time.Time, keep, limit int) (int64, error)
 }
@@ -0,0 +1,729 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// repositories are the implementations under test, sharing one empty store
+type repositories struct {
+	meta    repository.RepositoryMetaRepository
+	commits repository.CommitRepository
+	authors repository.AuthorRepository
+}
+
+// backends create the repositories of every implementation
+var backends = map[string]func(t *testing.T) repositories{
+	"memory": func(t *testing.T) repositories {
+		store := repository.NewMemoryStore()
+		return repositories{
+			meta:    repository.NewMemoryRepositoryMetaRepository(store),
+			commits: repository.NewMemoryCommitRepository(store),
+			authors: repository.NewMemoryAuthorRepository(store),
+		}
+	},
+	"gorm": func(t *testing.T) repositories {
+		db := newSQLiteDB(t)
+		return repositories{
+			meta:    repository.NewGormRepositoryMetaRepository(db),
+			commits: repository.NewGormCommitRepository(db),
+			authors: repository.NewGormAuthorRepository(db),
+		}
+	},
+}
+
+// conform runs a test against every implementation
+func conform(t *testing.T, test func(t *testing.T, r repositories)) {
+	for name, newRepositories := range backends {
+		t.Run(name, func(t *testing.T) {
+			test(t, newRepositories(t))
+		})
+	}
+}
+
+// save stores a repository and its commits, parsing their messages
+func (r repositories) save(t *testing.T, name string, commits ...domain.Commit) *domain.RepositoryMeta {
+	t.Helper()
+
+	repo, err := r.meta.SaveRepoMetadata(context.TODO(), domain.RepositoryMeta{Name: name})
+	require.NoError(t, err)
+
+	for _, c := range commits {
+		c.RepoID = repo.ID
+		c.Conventional = domain.ParseConventionalCommit(c.Message)
+		c.Link = domain.ParseCommitLink(c.Message)
+		_, err := r.commits.SaveCommit(context.TODO(), c)
+		require.NoError(t, err)
+	}
+	return repo
+}
+
+func hashes(commits []domain.Commit) []string {
+	hashes := make([]string, 0, len(commits))
+	for _, c := range commits {
+		hashes = append(hashes, c.Hash)
+	}
+	return hashes
+}
+
+func names(authors []domain.Author) []string {
+	names := make([]string, 0, len(authors))
+	for _, a := range authors {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+// TestConformance_RepositoryMeta tests storing, updating and deleting repositories
+func TestConformance_RepositoryMeta(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+
+		saved, err := r.meta.SaveRepoMetadata(ctx, domain.RepositoryMeta{OwnerName: "org", Name: "org/b", StarsCount: 5, Index: true})
+		require.NoError(t, err)
+		assert.NotZero(t, saved.ID)
+		assert.False(t, saved.CreatedAt.IsZero())
+
+		_, err = r.meta.SaveRepoMetadata(ctx, domain.RepositoryMeta{Name: "org/b"})
+		assert.Error(t, err, "names are unique")
+
+		other := r.save(t, "org/a")
+
+		// zero fields are left as they are
+		_, err = r.meta.UpdateRepoMetadata(ctx, domain.RepositoryMeta{ID: saved.ID, Description: "b", LastPage: 3})
+		require.NoError(t, err)
+		require.NoError(t, r.meta.UpdateRepositoryStatus(ctx, saved.ID, false))
+
+		found, err := r.meta.RepoMeta(ctx, "org/b")
+		require.NoError(t, err)
+		assert.Equal(t, "b", found.Description)
+		assert.Equal(t, 5, found.StarsCount)
+		assert.Equal(t, 3, found.LastPage)
+		assert.False(t, found.Index)
+
+		_, err = r.meta.RepoMeta(ctx, "org/c")
+		assert.Equal(t, errcodes.ErrNoRecordFound, err)
+
+		all, err := r.meta.AllRepoMeta(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		assert.Equal(t, []uint{saved.ID, other.ID}, []uint{all[0].ID, all[1].ID})
+
+		require.NoError(t, r.meta.DeleteRepoMeta(ctx, saved.ID))
+		assert.Equal(t, errcodes.ErrNoRecordFound, r.meta.DeleteRepoMeta(ctx, saved.ID))
+
+		_, err = r.meta.ResetRepoMeta(ctx, saved.ID)
+		assert.Equal(t, errcodes.ErrNoRecordFound, err)
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = r.meta.RepoMeta(cancelled, "org/a")
+		assert.Equal(t, errcodes.ErrContextCancelled, err)
+	})
+}
+
+// TestConformance_ResetRepoMeta tests that a reset deletes the commits and rewinds the cursor
+func TestConformance_ResetRepoMeta(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		repo := r.save(t, "org/repo", domain.Commit{Hash: "a1", Message: "one", Date: day(1, 0), Author: jane})
+		_, err := r.meta.UpdateRepoMetadata(ctx, domain.RepositoryMeta{ID: repo.ID, LastPage: 4, LastFetchedCommit: "a1"})
+		require.NoError(t, err)
+
+		reset, err := r.meta.ResetRepoMeta(ctx, repo.ID)
+		require.NoError(t, err)
+
+		assert.Zero(t, reset.LastPage)
+		assert.Empty(t, reset.LastFetchedCommit)
+		assert.True(t, reset.Index)
+
+		_, err = r.commits.GetCommitByHash(ctx, "a1")
+		assert.Equal(t, errcodes.ErrNoRecordFound, err)
+
+		authors, err := r.authors.GetTopAuthors(ctx, "org/repo", domain.TopAuthorsQuery{Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, authors)
+	})
+}
+
+// TestConformance_Commits tests storing, paging and filtering commits
+func TestConformance_Commits(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		repo := r.save(t, "org/repo",
+			domain.Commit{Hash: "c3", Message: "feat(api): three", Date: day(3, 0), Author: jane},
+			domain.Commit{Hash: "c1", Message: "fix: one", Date: day(1, 0), Author: john},
+			domain.Commit{Hash: "c4", Message: "docs: four", Date: day(4, 0), Author: jane},
+			domain.Commit{Hash: "c2", Message: "feat!: two", Date: day(2, 0), Author: jane},
+		)
+		r.save(t, "org/other", domain.Commit{Hash: "o1", Message: "feat: other", Date: day(2, 0), Author: jane})
+
+		_, err := r.commits.SaveCommit(ctx, domain.Commit{Hash: "c1", RepoID: repo.ID, Date: day(1, 0), Author: john})
+		assert.Error(t, err, "hashes are unique")
+
+		_, err = r.commits.SaveCommit(ctx, domain.Commit{Hash: "x1", RepoID: repo.ID + 100, Date: day(1, 0), Author: john})
+		assert.Error(t, err, "the repository must be stored")
+
+		commit, err := r.commits.GetCommitByHash(ctx, "c3")
+		require.NoError(t, err)
+		assert.Equal(t, "feat(api): three", commit.Message)
+		assert.Equal(t, domain.ConventionalCommit{Type: "feat", Scope: "api", Description: "three"}, commit.Conventional)
+		assert.True(t, commit.Date.Equal(day(3, 0)))
+		assert.Nil(t, commit.Link)
+
+		_, err = r.commits.GetCommitByHash(ctx, "missing")
+		assert.Equal(t, errcodes.ErrNoRecordFound, err)
+
+		page, paging, err := r.commits.GetCommitsByRepository(ctx, *repo, domain.APIPaging{Limit: 3, Page: 1, Sort: "date", Direction: "asc"}, domain.CommitFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c1", "c2", "c3"}, hashes(page))
+		assert.Equal(t, domain.PagingInfo{TotalCount: 4, Page: 1, HasNextPage: true, Count: 3}, paging)
+		assert.Equal(t, "John Doe", page[0].Author.Name)
+		assert.Equal(t, 4, page[1].Author.CommitCount, "authors are counted across repositories")
+
+		page, paging, err = r.commits.GetCommitsByRepository(ctx, *repo, domain.APIPaging{Limit: 3, Page: 2, Sort: "date", Direction: "asc"}, domain.CommitFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c4"}, hashes(page))
+		assert.Equal(t, domain.PagingInfo{TotalCount: 4, Page: 2, HasNextPage: false, Count: 1}, paging)
+
+		page, paging, err = r.commits.GetCommitsByRepository(ctx, *repo, domain.APIPaging{Limit: 2, Offset: 1, Sort: "date", Direction: "asc"}, domain.CommitFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c2", "c3"}, hashes(page))
+		assert.True(t, paging.HasNextPage)
+
+		breaking := false
+		page, paging, err = r.commits.GetCommitsByRepository(ctx, *repo, domain.APIPaging{Sort: "commit_hash", Direction: "desc"}, domain.CommitFilter{
+			Types:    []string{"feat", "fix"},
+			Breaking: &breaking,
+			After:    day(1, 0),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c3"}, hashes(page))
+		assert.Equal(t, int64(1), paging.TotalCount)
+
+		_, _, err = r.commits.GetCommitsByRepository(ctx, *repo, domain.APIPaging{Sort: "message"}, domain.CommitFilter{})
+		assert.Equal(t, errcodes.ErrInvalidSort, err)
+
+		between, err := r.commits.CommitsBetween(ctx, repo.ID, day(1, 0), day(4, 0), 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c4", "c3"}, hashes(between))
+		assert.Equal(t, "Jane Doe", between[0].Author.Name)
+
+		between, err = r.commits.CommitsBetween(ctx, repo.ID, day(1, 0).AddDate(-1, 0, 0), day(2, 0), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c2", "c1"}, hashes(between))
+	})
+}
+
+// TestConformance_StreamCommits tests that streamed commits match the listed
+// ones without being paged
+func TestConformance_StreamCommits(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		repo := r.save(t, "org/repo",
+			domain.Commit{Hash: "abcdef1234", Message: "feat(api): cache", Date: day(1, 0), Author: jane},
+			domain.Commit{Hash: "s2", Message: "Revert \"feat(api): cache\"\n\nThis reverts commit abcdef1.", Date: day(2, 0), Author: john},
+			domain.Commit{Hash: "s3", Message: "fixup! feat(api): cache", Date: day(3, 0), Author: jane},
+			domain.Commit{Hash: "s4", Message: "docs: four", Date: day(4, 0), Author: jane},
+		)
+		r.save(t, "org/other", domain.Commit{Hash: "o1", Message: "feat: other", Date: day(2, 0), Author: jane})
+		_, err := r.commits.ResolveCommitLinks(ctx, repo.ID)
+		require.NoError(t, err)
+
+		query := domain.APIPaging{Limit: 1, Sort: "date", Direction: "desc"}
+		listed, _, err := r.commits.GetCommitsByRepository(ctx, *repo, domain.APIPaging{Limit: 10, Sort: "date", Direction: "desc"}, domain.CommitFilter{})
+		require.NoError(t, err)
+
+		var streamed []domain.Commit
+		err = r.commits.StreamCommits(ctx, *repo, query, domain.CommitFilter{}, func(c domain.Commit) error {
+			streamed = append(streamed, c)
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.Len(t, streamed, len(listed))
+		for i := range listed {
+			listed[i].RevertedBy = nil
+			listed[i].Author.CommitCount = 0
+			assert.Equal(t, listed[i].Hash, streamed[i].Hash)
+			assert.True(t, listed[i].Date.Equal(streamed[i].Date))
+			listed[i].Date, streamed[i].Date = time.Time{}, time.Time{}
+			assert.Equal(t, listed[i], streamed[i])
+		}
+		assert.Equal(t, &domain.CommitLink{Kind: domain.LinkFixup, Target: "feat(api): cache", Hash: "abcdef1234"}, streamed[1].Link)
+
+		var filtered []domain.Commit
+		err = r.commits.StreamCommits(ctx, *repo, domain.APIPaging{Sort: "commit_hash", Direction: "asc"}, domain.CommitFilter{Types: []string{"feat", "docs"}}, func(c domain.Commit) error {
+			filtered = append(filtered, c)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"abcdef1234", "s4"}, hashes(filtered))
+
+		stop := fmt.Errorf("stop")
+		calls := 0
+		err = r.commits.StreamCommits(ctx, *repo, query, domain.CommitFilter{}, func(c domain.Commit) error {
+			calls++
+			return stop
+		})
+		assert.Equal(t, stop, err)
+		assert.Equal(t, 1, calls)
+
+		err = r.commits.StreamCommits(ctx, *repo, domain.APIPaging{Sort: "message"}, domain.CommitFilter{}, func(domain.Commit) error { return nil })
+		assert.Equal(t, errcodes.ErrInvalidSort, err)
+	})
+}
+
+// TestConformance_SaveCommits tests saving a page of commits with the cursor of its repository
+func TestConformance_SaveCommits(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		ann := domain.Author{Name: "Ann Roe", Email: "ann@example.com"}
+		repo := r.save(t, "org/repo", domain.Commit{Hash: "c1", Message: "one", Date: day(1, 0), Author: jane})
+
+		cursor := *repo
+		cursor.LastPage = 2
+		saved, err := r.commits.SaveCommits(ctx, cursor, []domain.Commit{
+			{Hash: "c1", Message: "one", Date: day(1, 0), Author: jane},
+			{Hash: "c2", Message: "two", Date: day(2, 0), Author: jane, Link: &domain.CommitLink{Kind: domain.LinkRevert, Target: "c1"}},
+			{Hash: "c3", Message: "three", Date: day(3, 0), Author: ann, Additions: 4},
+			{Hash: "c2", Message: "two", Date: day(2, 0), Author: jane},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c2", "c3"}, hashes(saved))
+		assert.NotZero(t, saved[0].ID)
+		assert.NotEqual(t, saved[0].AuthorID, saved[1].AuthorID)
+
+		found, err := r.meta.RepoMeta(ctx, "org/repo")
+		require.NoError(t, err)
+		assert.Equal(t, 2, found.LastPage)
+		assert.Equal(t, "c3", found.LastFetchedCommit)
+
+		commit, err := r.commits.GetCommitByHash(ctx, "c2")
+		require.NoError(t, err)
+		assert.Equal(t, repo.ID, commit.RepoID)
+		require.NotNil(t, commit.Link)
+		assert.Equal(t, "c1", commit.Link.Target)
+
+		authors, err := r.authors.GetTopAuthors(ctx, "org/repo", domain.TopAuthorsQuery{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, authors, 2)
+		assert.Equal(t, []string{"Jane Doe", "Ann Roe"}, names(authors))
+		assert.Equal(t, 2, authors[0].CommitCount)
+		assert.Equal(t, 4, authors[1].Additions)
+
+		// a page of stored commits only moves the page
+		cursor.LastPage = 3
+		cursor.LastFetchedCommit = "c3"
+		saved, err = r.commits.SaveCommits(ctx, cursor, []domain.Commit{{Hash: "c3", Date: day(3, 0), Author: ann}})
+		require.NoError(t, err)
+		assert.Empty(t, saved)
+
+		found, err = r.meta.RepoMeta(ctx, "org/repo")
+		require.NoError(t, err)
+		assert.Equal(t, 3, found.LastPage)
+		assert.Equal(t, "c3", found.LastFetchedCommit)
+
+		_, err = r.commits.SaveCommits(ctx, domain.RepositoryMeta{ID: repo.ID + 100}, []domain.Commit{{Hash: "x1", Date: day(1, 0), Author: john}})
+		assert.ErrorIs(t, err, errcodes.ErrNoRecordFound, "the repository must be stored")
+	})
+}
+
+// TestConformance_ImportCommits tests saving commits without moving the cursor
+// of their repository
+func TestConformance_ImportCommits(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		repo := r.save(t, "org/repo", domain.Commit{Hash: "c1", Message: "one", Date: day(1, 0), Author: jane})
+		before, err := r.meta.RepoMeta(ctx, "org/repo")
+		require.NoError(t, err)
+
+		cursor := *repo
+		cursor.LastPage = 9
+		saved, err := r.commits.ImportCommits(ctx, cursor, []domain.Commit{
+			{Hash: "c1", Message: "one", Date: day(1, 0), Author: jane},
+			{Hash: "c2", Message: "two", Date: day(2, 0), Author: jane},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c2"}, hashes(saved))
+
+		found, err := r.meta.RepoMeta(ctx, "org/repo")
+		require.NoError(t, err)
+		assert.Equal(t, before.LastPage, found.LastPage)
+		assert.Equal(t, before.LastFetchedCommit, found.LastFetchedCommit)
+
+		authors, err := r.authors.GetTopAuthors(ctx, "org/repo", domain.TopAuthorsQuery{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, authors, 1)
+		assert.Equal(t, 2, authors[0].CommitCount)
+	})
+}
+
+// TestConformance_PruneCommits tests deleting expired commits in batches
+func TestConformance_PruneCommits(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		repo := r.save(t, "org/repo",
+			domain.Commit{Hash: "p1", Message: "one", Date: day(1, 0), Author: jane},
+			domain.Commit{Hash: "p2", Message: "two", Date: day(2, 0), Author: john},
+			domain.Commit{Hash: "p3", Message: "three", Date: day(3, 0), Author: jane},
+			domain.Commit{Hash: "p4", Message: "four", Date: day(4, 0), Author: jane},
+			domain.Commit{Hash: "p5", Message: "five", Date: day(5, 0), Author: john},
+		)
+		r.save(t, "org/other", domain.Commit{Hash: "o1", Message: "other", Date: day(1, 0), Author: jane})
+
+		pruned, err := r.commits.PruneCommits(ctx, repo.ID, day(3, 0), 0, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), pruned, "batches are limited")
+
+		pruned, err = r.commits.PruneCommits(ctx, repo.ID, day(3, 0), 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), pruned)
+
+		pruned, err = r.commits.PruneCommits(ctx, repo.ID, time.Time{}, 2, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), pruned)
+
+		pruned, err = r.commits.PruneCommits(ctx, repo.ID, day(3, 0), 2, 10)
+		require.NoError(t, err)
+		assert.Zero(t, pruned)
+
+		between, err := r.commits.CommitsBetween(ctx, repo.ID, time.Time{}, day(31, 0), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"p5", "p4"}, hashes(between))
+
+		authors, err := r.authors.GetTopAuthors(ctx, "org/repo", domain.TopAuthorsQuery{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, authors, 2)
+		assert.Equal(t, []string{"John Doe", "Jane Doe"}, names(authors))
+		assert.Equal(t, []int{1, 1}, []int{authors[0].CommitCount, authors[1].CommitCount})
+		assert.True(t, authors[1].FirstCommitAt.Equal(day(4, 0)))
+
+		profile, err := r.authors.AuthorProfile(ctx, authors[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, profile.CommitCount, "commits of other repositories are kept")
+
+		repaired, err := r.authors.RepairAuthorStats(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, repaired, "the aggregates match the remaining commits")
+
+		_, err = r.commits.PruneCommits(ctx, repo.ID+100, time.Time{}, 1, 10)
+		assert.Equal(t, errcodes.ErrNoRecordFound, err)
+	})
+}
+
+// TestConformance_CommitFiles tests storing the files of commits once
+func TestConformance_CommitFiles(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		repo := r.save(t, "org/repo",
+			domain.Commit{Hash: "a1", Message: "one", Date: day(1, 0), Author: jane},
+			domain.Commit{Hash: "a2", Message: "two", Date: day(2, 0), Author: jane},
+			domain.Commit{Hash: "a3", Message: "three", Date: day(3, 0), Author: jane},
+		)
+
+		pending, err := r.commits.CommitsWithoutFiles(ctx, repo.ID, day(2, 0), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a3", "a2"}, hashes(pending))
+
+		commit := pending[0]
+		commit.Additions, commit.Deletions = 7, 2
+		commit.Files = []domain.CommitFile{{Path: "main.go", Additions: 7, Deletions: 2}}
+		require.NoError(t, r.commits.SaveCommitFiles(ctx, commit))
+
+		// files are only stored once
+		commit.Additions = 100
+		require.NoError(t, r.commits.SaveCommitFiles(ctx, commit))
+
+		assert.Equal(t, errcodes.ErrNoRecordFound, r.commits.SaveCommitFiles(ctx, domain.Commit{ID: 999, RepoID: repo.ID}))
+
+		pending, err = r.commits.CommitsWithoutFiles(ctx, repo.ID, day(2, 0), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a2"}, hashes(pending))
+
+		stored, err := r.commits.GetCommitByHash(ctx, "a3")
+		require.NoError(t, err)
+		assert.Equal(t, 7, stored.Additions)
+
+		authors, err := r.authors.GetTopAuthors(ctx, "org/repo", domain.TopAuthorsQuery{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, authors, 1)
+		assert.Equal(t, 7, authors[0].Additions)
+		assert.Equal(t, 2, authors[0].Deletions)
+	})
+}
+
+// TestConformance_RepositoryCommits tests paging through the commits of a
+// repository with their files and links
+func TestConformance_RepositoryCommits(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		repo := r.save(t, "org/repo",
+			domain.Commit{Hash: "a1", Message: "feat: one", Date: day(3, 0), Author: jane},
+			domain.Commit{Hash: "a2", Message: "fixup! feat: one", Date: day(1, 0), Author: john},
+			domain.Commit{Hash: "a3", Message: "three", Date: day(2, 0), Author: jane},
+		)
+		r.save(t, "org/other", domain.Commit{Hash: "b1", Message: "other", Date: day(1, 0), Author: jane})
+
+		first, err := r.commits.GetCommitByHash(ctx, "a1")
+		require.NoError(t, err)
+		first.Additions = 3
+		first.Files = []domain.CommitFile{{Path: "a.go", Additions: 1}, {Path: "b.go", Additions: 2}}
+		require.NoError(t, r.commits.SaveCommitFiles(ctx, *first))
+
+		page, err := r.commits.RepositoryCommits(ctx, repo.ID, 0, 2)
+		require.NoError(t, err)
+		rest, err := r.commits.RepositoryCommits(ctx, repo.ID, page[len(page)-1].ID, 2)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"a1", "a2"}, hashes(page))
+		assert.Equal(t, []string{"a3"}, hashes(rest))
+		assert.True(t, page[0].FilesFetched)
+		assert.Equal(t, first.Files, page[0].Files)
+		assert.Equal(t, john.Email, page[1].Author.Email)
+		assert.Equal(t, &domain.CommitLink{Kind: domain.LinkFixup, Target: "feat: one"}, page[1].Link)
+		assert.False(t, rest[0].FilesFetched)
+		assert.Empty(t, rest[0].Files)
+	})
+}
+
+// TestConformance_LastCommitID tests the last commit id of a repository
+func TestConformance_LastCommitID(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		repo := r.save(t, "org/repo",
+			domain.Commit{Hash: "a1", Message: "one", Date: day(2, 0), Author: jane},
+			domain.Commit{Hash: "a2", Message: "two", Date: day(1, 0), Author: john},
+		)
+		r.save(t, "org/other", domain.Commit{Hash: "b1", Message: "other", Date: day(1, 0), Author: jane})
+		empty := r.save(t, "org/empty")
+
+		last, err := r.commits.LastCommitID(ctx, repo.ID)
+		require.NoError(t, err)
+		none, err := r.commits.LastCommitID(ctx, empty.ID)
+		require.NoError(t, err)
+
+		newest, err := r.commits.GetCommitByHash(ctx, "a2")
+		require.NoError(t, err)
+		assert.Equal(t, newest.ID, last)
+		assert.Zero(t, none)
+	})
+}
+
+// TestConformance_CommitLinks tests linking reverts and fixups to their targets
+func TestConformance_CommitLinks(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		repo := r.save(t, "org/repo",
+			domain.Commit{Hash: "abcdef1234", Message: "feat: cache", Date: day(1, 0), Author: jane},
+			domain.Commit{Hash: "b1", Message: "Revert \"feat: cache\"\n\nThis reverts commit abcdef1.", Date: day(2, 0), Author: john},
+			domain.Commit{Hash: "b2", Message: "fixup! feat: cache", Date: day(3, 0), Author: jane},
+			domain.Commit{Hash: "b3", Message: "Revert\n\nThis reverts commit 0000000.", Date: day(4, 0), Author: jane},
+		)
+
+		resolved, err := r.commits.ResolveCommitLinks(ctx, repo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resolved)
+
+		target, err := r.commits.GetCommitByHash(ctx, "abcdef1234")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b1"}, target.RevertedBy)
+
+		fixup, err := r.commits.GetCommitByHash(ctx, "b2")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.CommitLink{Kind: domain.LinkFixup, Target: "feat: cache", Hash: "abcdef1234"}, fixup.Link)
+
+		unresolved, err := r.commits.GetCommitByHash(ctx, "b3")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.CommitLink{Kind: domain.LinkRevert, Target: "0000000"}, unresolved.Link)
+
+		resolved, err = r.commits.ResolveCommitLinks(ctx, repo.ID)
+		require.NoError(t, err)
+		assert.Zero(t, resolved)
+	})
+}
+
+// TestConformance_UnlinkedCommits tests linking commits stored without their links
+func TestConformance_UnlinkedCommits(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		repo := r.save(t, "org/repo")
+		for _, c := range []domain.Commit{
+			{Hash: "a1", Message: "feat: cache", Date: day(1, 0), Author: jane},
+			{Hash: "a2", Message: "squash! feat: cache", Date: day(2, 0), Author: jane},
+			{Hash: "a3", Message: "chore: tidy", Date: day(3, 0), Author: jane},
+		} {
+			c.RepoID = repo.ID
+			_, err := r.commits.SaveCommit(ctx, c)
+			require.NoError(t, err)
+		}
+
+		unlinked, err := r.commits.UnlinkedCommits(ctx, 0, 10)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a2"}, hashes(unlinked))
+
+		after, err := r.commits.UnlinkedCommits(ctx, unlinked[0].ID, 10)
+		require.NoError(t, err)
+		assert.Empty(t, after)
+
+		unlinked[0].Link = domain.ParseCommitLink(unlinked[0].Message)
+		require.NoError(t, r.commits.SaveCommitLinks(ctx, unlinked))
+		require.NoError(t, r.commits.SaveCommitLinks(ctx, unlinked))
+
+		unlinked, err = r.commits.UnlinkedCommits(ctx, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, unlinked)
+
+		resolved, err := r.commits.ResolveCommitLinks(ctx, repo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), resolved)
+	})
+}
+
+// TestConformance_TopAuthors tests the ranking, its tie breaks and its filters
+func TestConformance_TopAuthors(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		bot := domain.Author{Name: "Build Bot", Email: "bot@ci.example.com"}
+		ann := domain.Author{Name: "Ann", Email: "ann@example.com"}
+		r.save(t, "org/a",
+			domain.Commit{Hash: "a1", Message: "one", Date: day(1, 0), Author: jane},
+			domain.Commit{Hash: "a2", Message: "two", Date: day(2, 0), Author: john},
+			domain.Commit{Hash: "a3", Message: "three", Date: day(3, 0), Author: bot},
+			domain.Commit{Hash: "a4", Message: "four", Date: day(4, 0), Author: bot},
+			domain.Commit{Hash: "a5", Message: "five", Date: day(5, 0), Author: ann, Additions: 50},
+		)
+		r.save(t, "org/b",
+			domain.Commit{Hash: "b1", Message: "one", Date: day(6, 0), Author: jane, Additions: 3},
+			domain.Commit{Hash: "b2", Message: "two", Date: day(7, 0), Author: john},
+		)
+
+		// ties are broken by the latest commit
+		authors, err := r.authors.GetTopAuthors(ctx, "org/a", domain.TopAuthorsQuery{Limit: 3})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Build Bot", "Ann", "John Doe"}, names(authors))
+		assert.True(t, authors[0].FirstCommitAt.Equal(day(3, 0)))
+		assert.True(t, authors[0].LastCommitAt.Equal(day(4, 0)))
+
+		authors, err = r.authors.GetTopAuthors(ctx, "org/a", domain.TopAuthorsQuery{Limit: 10, Exclude: []string{"*bot*"}, RankBy: domain.RankByLines})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Ann", "John Doe", "Jane Doe"}, names(authors))
+
+		authors, err = r.authors.GetTopAuthors(ctx, "org/a", domain.TopAuthorsQuery{Limit: 10, Since: day(2, 0), Until: day(4, 0)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Build Bot", "John Doe"}, names(authors))
+		assert.Equal(t, 1, authors[0].CommitCount)
+
+		authors, err = r.authors.GetTopAuthorsAcross(ctx, nil, domain.TopAuthorsQuery{Limit: 2, Exclude: []string{"BOT@*"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"John Doe", "Jane Doe"}, names(authors))
+		assert.Equal(t, 2, authors[0].RepositoryCount)
+
+		authors, err = r.authors.GetTopAuthorsAcross(ctx, []string{"org/b"}, domain.TopAuthorsQuery{Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"John Doe", "Jane Doe"}, names(authors))
+		assert.Equal(t, 1, authors[0].RepositoryCount)
+
+		repaired, err := r.authors.RepairAuthorStats(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, repaired)
+	})
+}
+
+// TestConformance_AuthorProfile tests the contributions of an author
+func TestConformance_AuthorProfile(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		r.save(t, "org/b", domain.Commit{Hash: "b1", Message: "one", Date: day(6, 0), Author: jane})
+		r.save(t, "org/a",
+			domain.Commit{Hash: "a1", Message: "one", Date: day(1, 0), Author: jane},
+			domain.Commit{Hash: "a2", Message: "two", Date: day(2, 0), Author: jane},
+		)
+
+		commit, err := r.commits.GetCommitByHash(ctx, "a1")
+		require.NoError(t, err)
+
+		profile, err := r.authors.AuthorProfile(ctx, commit.AuthorID)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Jane Doe", profile.Name)
+		assert.Equal(t, 3, profile.CommitCount)
+		assert.Equal(t, 2, profile.RepositoryCount)
+		assert.True(t, profile.FirstCommitAt.Equal(day(1, 0)))
+		assert.True(t, profile.LastCommitAt.Equal(day(6, 0)))
+		require.Len(t, profile.Repositories, 2)
+		assert.Equal(t, "org/a", profile.Repositories[0].RepositoryName)
+		assert.Equal(t, 2, profile.Repositories[0].CommitCount)
+
+		_, err = r.authors.AuthorProfile(ctx, 999)
+		assert.Equal(t, errcodes.ErrNoRecordFound, err)
+	})
+}
+
+// TestConformance_AuthorProfiles tests that profiles are read in the order
+// asked for and unknown authors are left out
+func TestConformance_AuthorProfiles(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		r.save(t, "org/a",
+			domain.Commit{Hash: "a1", Message: "one", Date: day(1, 0), Author: jane},
+			domain.Commit{Hash: "a2", Message: "two", Date: day(2, 0), Author: john},
+			domain.Commit{Hash: "a3", Message: "three", Date: day(3, 0), Author: john},
+		)
+
+		first, err := r.commits.GetCommitByHash(ctx, "a1")
+		require.NoError(t, err)
+		second, err := r.commits.GetCommitByHash(ctx, "a2")
+		require.NoError(t, err)
+
+		profiles, err := r.authors.AuthorProfiles(ctx, []uint{second.AuthorID, 999, first.AuthorID, second.AuthorID})
+		require.NoError(t, err)
+
+		require.Len(t, profiles, 2)
+		assert.Equal(t, john.Email, profiles[0].Email)
+		assert.Equal(t, 2, profiles[0].CommitCount)
+		assert.Equal(t, jane.Email, profiles[1].Email)
+		assert.Equal(t, 1, profiles[1].CommitCount)
+		require.Len(t, profiles[1].Repositories, 1)
+		assert.Equal(t, "org/a", profiles[1].Repositories[0].RepositoryName)
+
+		none, err := r.authors.AuthorProfiles(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
+
+// TestConformance_ConcurrentSaves tests saving commits from several goroutines
+func TestConformance_ConcurrentSaves(t *testing.T) {
+	conform(t, func(t *testing.T, r repositories) {
+		ctx := context.TODO()
+		repo := r.save(t, "org/repo")
+
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 10; i++ {
+					_, err := r.commits.SaveCommit(ctx, domain.Commit{Hash: fmt.Sprintf("%d-%d", w, i), RepoID: repo.ID, Date: day(1, i), Author: jane})
+					assert.NoError(t, err)
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		authors, err := r.authors.GetTopAuthors(ctx, "org/repo", domain.TopAuthorsQuery{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, authors, 1)
+		assert.Equal(t, 40, authors[0].CommitCount)
+	})
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "gorm.io/gorm"
+
+// isPostgres reports whether db is a Postgres database, whose time zone and
+// date functions let aggregates be computed by the database.
+func isPostgres(db *gorm.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+)
+
+// MemoryAuthorRepository is an in-memory implementation of AuthorRepository.
+// Rankings are counted from the stored commits, there are no aggregates.
+type MemoryAuthorRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryAuthorRepository initializes a new MemoryAuthorRepository
+func NewMemoryAuthorRepository(store *MemoryStore) AuthorRepository {
+	return &MemoryAuthorRepository{store: store}
+}
+
+func (s *MemoryAuthorRepository) GetTopAuthors(ctx context.Context, repoName string, query domain.TopAuthorsQuery) ([]domain.Author, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	return s.rankAuthors(query, func(repo *Repository) bool { return repo.Name == repoName }), nil
+}
+
+func (s *MemoryAuthorRepository) GetTopAuthorsAcross(ctx context.Context, repoNames []string, query domain.TopAuthorsQuery) ([]domain.Author, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	return s.rankAuthors(query, func(repo *Repository) bool {
+		if len(repoNames) == 0 {
+			return true
+		}
+		for _, name := range repoNames {
+			if repo.Name == name {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// rankAuthors ranks the authors of the commits in the query window to the
+// repositories matching keep, ordered like GormAuthorRepository.rankAuthors.
+func (s *MemoryAuthorRepository) rankAuthors(query domain.TopAuthorsQuery, keep func(*Repository) bool) []domain.Author {
+	ranked := make(map[uint]*domain.Author)
+	repos := make(map[uint]map[uint]bool)
+
+	for _, c := range s.store.commits {
+		repo, ok := s.store.repos[c.RepositoryID]
+		if !ok || !keep(repo) {
+			continue
+		}
+		if !query.Since.IsZero() && c.Date.Before(query.Since) {
+			continue
+		}
+		if !query.Until.IsZero() && !c.Date.Before(query.Until) {
+			continue
+		}
+
+		author, ok := ranked[c.AuthorID]
+		if !ok {
+			stored := s.store.authors[c.AuthorID]
+			if excluded(stored, query.Exclude) {
+				continue
+			}
+			author = &domain.Author{ID: stored.ID, Name: stored.Name, Email: stored.Email, FirstCommitAt: c.Date, LastCommitAt: c.Date}
+			ranked[c.AuthorID] = author
+			repos[c.AuthorID] = make(map[uint]bool)
+		}
+
+		author.CommitCount++
+		author.Additions += c.Additions
+		author.Deletions += c.Deletions
+		if c.Date.Before(author.FirstCommitAt) {
+			author.FirstCommitAt = c.Date
+		}
+		if c.Date.After(author.LastCommitAt) {
+			author.LastCommitAt = c.Date
+		}
+		repos[c.AuthorID][c.RepositoryID] = true
+	}
+
+	authors := make([]domain.Author, 0, len(ranked))
+	for id, author := range ranked {
+		author.RepositoryCount = len(repos[id])
+		authors = append(authors, *author)
+	}
+
+	sort.Slice(authors, func(i, j int) bool {
+		a, b := authors[i], authors[j]
+		if query.RankBy == domain.RankByLines && a.Additions+a.Deletions != b.Additions+b.Deletions {
+			return a.Additions+a.Deletions > b.Additions+b.Deletions
+		}
+		if a.CommitCount != b.CommitCount {
+			return a.CommitCount > b.CommitCount
+		}
+		if !a.LastCommitAt.Equal(b.LastCommitAt) {
+			return a.LastCommitAt.After(b.LastCommitAt)
+		}
+		return a.ID < b.ID
+	})
+
+	return limited(authors, query.Limit)
+}
+
+// excluded reports whether the name or email of an author matches one of the
+// exclude patterns.
+func excluded(author *Author, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchesPattern(pattern, author.Name) || matchesPattern(pattern, author.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPattern reports whether s matches an exclude pattern, ignoring case,
+// the way domain.LikePattern matches in SQL.
+func matchesPattern(pattern, s string) bool {
+	parts := strings.Split(strings.ToLower(pattern), "*")
+	s = strings.ToLower(s)
+
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+
+	last := len(parts) - 1
+	if last == 0 {
+		return s == ""
+	}
+	for _, part := range parts[1:last] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[last])
+}
+
+func (s *MemoryAuthorRepository) AuthorProfile(ctx context.Context, authorID uint) (*domain.AuthorProfile, error) {
+	profiles, err := s.AuthorProfiles(ctx, []uint{authorID})
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, errcodes.ErrNoRecordFound
+	}
+	return &profiles[0], nil
+}
+
+func (s *MemoryAuthorRepository) AuthorProfiles(ctx context.Context, authorIDs []uint) ([]domain.AuthorProfile, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	byAuthor := make(map[uint]map[uint]*domain.RepositoryContribution, len(authorIDs))
+	for _, id := range authorIDs {
+		byAuthor[id] = make(map[uint]*domain.RepositoryContribution)
+	}
+
+	for _, c := range s.store.commits {
+		byRepo, ok := byAuthor[c.AuthorID]
+		if !ok {
+			continue
+		}
+
+		contribution, ok := byRepo[c.RepositoryID]
+		if !ok {
+			contribution = &domain.RepositoryContribution{
+				RepositoryID:   c.RepositoryID,
+				RepositoryName: s.store.repos[c.RepositoryID].Name,
+				FirstCommitAt:  c.Date,
+				LastCommitAt:   c.Date,
+			}
+			byRepo[c.RepositoryID] = contribution
+		}
+
+		contribution.CommitCount++
+		if c.Date.Before(contribution.FirstCommitAt) {
+			contribution.FirstCommitAt = c.Date
+		}
+		if c.Date.After(contribution.LastCommitAt) {
+			contribution.LastCommitAt = c.Date
+		}
+	}
+
+	profiles := make([]domain.AuthorProfile, 0, len(authorIDs))
+	for _, id := range authorIDs {
+		author, ok := s.store.authors[id]
+		byRepo := byAuthor[id]
+		if !ok || byRepo == nil {
+			continue
+		}
+		// a repeated id is only returned once
+		delete(byAuthor, id)
+
+		contributions := make([]domain.RepositoryContribution, 0, len(byRepo))
+		for _, c := range byRepo {
+			contributions = append(contributions, *c)
+		}
+
+		sort.Slice(contributions, func(i, j int) bool {
+			a, b := contributions[i], contributions[j]
+			if a.CommitCount != b.CommitCount {
+				return a.CommitCount > b.CommitCount
+			}
+			return a.RepositoryName < b.RepositoryName
+		})
+
+		profiles = append(profiles, newAuthorProfile(domain.Author{
+			ID:    author.ID,
+			Name:  author.Name,
+			Email: author.Email,
+		}, contributions))
+	}
+
+	return profiles, nil
+}
+
+// RepairAuthorStats has nothing to repair, rankings are counted from commits.
+func (s *MemoryAuthorRepository) RepairAuthorStats(ctx context.Context) (int64, error) {
+	return 0, contextErr(ctx)
+}
@@ -0,0 +1,536 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+)
+
+// MemoryCommitRepository is an in-memory implementation of CommitRepository
+type MemoryCommitRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryCommitRepository initializes a new MemoryCommitRepository
+func NewMemoryCommitRepository(store *MemoryStore) CommitRepository {
+	return &MemoryCommitRepository{store: store}
+}
+
+func (s *MemoryCommitRepository) GetCommitByHash(ctx context.Context, hash string) (*domain.Commit, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	for _, c := range s.store.commits {
+		if c.CommitHash == hash {
+			commits := []domain.Commit{*c.ToDomain()}
+			s.withLinks(commits)
+			return &commits[0], nil
+		}
+	}
+	return nil, errcodes.ErrNoRecordFound
+}
+
+// SaveCommit stores a commit of a stored repository, creating its author when
+// no author with the same name and email is stored.
+func (s *MemoryCommitRepository) SaveCommit(ctx context.Context, commit domain.Commit) (*domain.Commit, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if _, ok := s.store.repos[commit.RepoID]; !ok {
+		return nil, errcodes.ErrNoRecordFound
+	}
+	if s.store.commitByHash(commit.Hash) != nil {
+		return nil, errcodes.ErrCommitAlreadyAdded
+	}
+
+	return s.store.saveCommit(commit, time.Now()), nil
+}
+
+// SaveCommits stores the commits of a page that are not stored yet and moves
+// the cursor of repo, like GormCommitRepository.SaveCommits.
+func (s *MemoryCommitRepository) SaveCommits(ctx context.Context, repo domain.RepositoryMeta, commits []domain.Commit) ([]domain.Commit, error) {
+	return s.saveCommits(ctx, repo, commits, true)
+}
+
+// ImportCommits stores the commits that are not stored yet, like
+// GormCommitRepository.ImportCommits.
+func (s *MemoryCommitRepository) ImportCommits(ctx context.Context, repo domain.RepositoryMeta, commits []domain.Commit) ([]domain.Commit, error) {
+	return s.saveCommits(ctx, repo, commits, false)
+}
+
+func (s *MemoryCommitRepository) saveCommits(ctx context.Context, repo domain.RepositoryMeta, commits []domain.Commit, moveCursor bool) ([]domain.Commit, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	stored, ok := s.store.repos[repo.ID]
+	if !ok {
+		return nil, errcodes.ErrNoRecordFound
+	}
+
+	now := time.Now()
+	saved := make([]domain.Commit, 0, len(commits))
+	for _, commit := range commits {
+		if s.store.commitByHash(commit.Hash) != nil {
+			continue
+		}
+		commit.RepoID = repo.ID
+		saved = append(saved, *s.store.saveCommit(commit, now))
+	}
+
+	if moveCursor {
+		if len(saved) > 0 {
+			repo.LastFetchedCommit = saved[len(saved)-1].Hash
+		}
+		stored.LastFetchedCommit = repo.LastFetchedCommit
+		stored.LastPage = repo.LastPage
+	}
+
+	return saved, nil
+}
+
+func (s *MemoryCommitRepository) GetCommitsByRepository(ctx context.Context, repo domain.RepositoryMeta, query domain.APIPaging, filter domain.CommitFilter) ([]domain.Commit, domain.PagingInfo, error) {
+	queryInfo, offset := getPaginationInfo(query)
+	if err := validatePaging(queryInfo); err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+	if err := contextErr(ctx); err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	matching := s.store.sortedCommits(func(c *Commit) bool {
+		return c.RepositoryID == repo.ID && matchesFilter(c, filter)
+	})
+
+	sort.SliceStable(matching, func(i, j int) bool {
+		if queryInfo.Direction == "desc" {
+			return commitLess(queryInfo.Sort, matching[j], matching[i])
+		}
+		return commitLess(queryInfo.Sort, matching[i], matching[j])
+	})
+
+	page := limited(matching[min(offset, len(matching)):], queryInfo.Limit)
+
+	commits := make([]domain.Commit, 0, len(page))
+	for _, c := range page {
+		loaded := s.store.withAuthor(c)
+		v := loaded.ToDomain()
+		v.Author.CommitCount = loaded.Author.CommitCount
+		commits = append(commits, *v)
+	}
+	s.withLinks(commits)
+
+	pagingInfo := getPagingInfo(queryInfo, len(matching))
+	pagingInfo.Count = len(commits)
+
+	return commits, pagingInfo, nil
+}
+
+// StreamCommits copies the matching commits before calling fn, so a slow reader
+// does not hold the store lock.
+func (s *MemoryCommitRepository) StreamCommits(ctx context.Context, repo domain.RepositoryMeta, query domain.APIPaging, filter domain.CommitFilter, fn func(domain.Commit) error) error {
+	queryInfo, _ := getPaginationInfo(query)
+	if err := validatePaging(queryInfo); err != nil {
+		return err
+	}
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+
+	s.store.mu.RLock()
+	matching := s.store.sortedCommits(func(c *Commit) bool {
+		return c.RepositoryID == repo.ID && matchesFilter(c, filter)
+	})
+	sort.SliceStable(matching, func(i, j int) bool {
+		if queryInfo.Direction == "desc" {
+			return commitLess(queryInfo.Sort, matching[j], matching[i])
+		}
+		return commitLess(queryInfo.Sort, matching[i], matching[j])
+	})
+
+	commits := make([]domain.Commit, 0, len(matching))
+	for _, c := range matching {
+		commits = append(commits, *s.store.withAuthor(c).ToDomain())
+	}
+	s.withLinks(commits)
+	s.store.mu.RUnlock()
+
+	for _, c := range commits {
+		c.RevertedBy = nil
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commitLess reports whether a sorts before b by the sort column.
+func commitLess(column string, a, b *Commit) bool {
+	switch column {
+	case "date":
+		return a.Date.Before(b.Date)
+	case "commit_hash":
+		return a.CommitHash < b.CommitHash
+	default:
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+}
+
+// matchesFilter reports whether a commit matches filter, see filterCommits.
+func matchesFilter(c *Commit, filter domain.CommitFilter) bool {
+	if len(filter.Types) > 0 {
+		found := false
+		for _, t := range filter.Types {
+			found = found || (c.Type != nil && *c.Type == t)
+		}
+		if !found {
+			return false
+		}
+	}
+	if filter.Scope != "" && c.Scope != filter.Scope {
+		return false
+	}
+	if filter.Breaking != nil && c.Breaking != *filter.Breaking {
+		return false
+	}
+	if !filter.After.IsZero() && !c.Date.After(filter.After) {
+		return false
+	}
+	if !filter.Until.IsZero() && c.Date.After(filter.Until) {
+		return false
+	}
+	return true
+}
+
+// sortNewestFirst orders commits by date, then by id, newest first.
+func sortNewestFirst(commits []*Commit) {
+	sort.SliceStable(commits, func(i, j int) bool {
+		if !commits[i].Date.Equal(commits[j].Date) {
+			return commits[i].Date.After(commits[j].Date)
+		}
+		return commits[i].ID > commits[j].ID
+	})
+}
+
+func (s *MemoryCommitRepository) CommitsBetween(ctx context.Context, repoID uint, since, until time.Time, limit int) ([]domain.Commit, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	matching := s.store.sortedCommits(func(c *Commit) bool {
+		return c.RepositoryID == repoID && !c.Date.After(until) && (since.IsZero() || c.Date.After(since))
+	})
+	sortNewestFirst(matching)
+
+	matching = limited(matching, limit)
+	commits := make([]domain.Commit, 0, len(matching))
+	for _, c := range matching {
+		commits = append(commits, *s.store.withAuthor(c).ToDomain())
+	}
+	s.withLinks(commits)
+	return commits, nil
+}
+
+func (s *MemoryCommitRepository) RepositoryCommits(ctx context.Context, repoID, afterID uint, limit int) ([]domain.Commit, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	matching := s.store.sortedCommits(func(c *Commit) bool {
+		return c.RepositoryID == repoID && c.ID > afterID
+	})
+
+	matching = limited(matching, limit)
+	commits := make([]domain.Commit, 0, len(matching))
+	for _, c := range matching {
+		commit := s.store.withAuthor(c).ToDomain()
+		for _, f := range s.store.files[c.ID] {
+			commit.Files = append(commit.Files, domain.CommitFile{Path: f.Path, Additions: f.Additions, Deletions: f.Deletions})
+		}
+		commits = append(commits, *commit)
+	}
+	s.withLinks(commits)
+	return commits, nil
+}
+
+func (s *MemoryCommitRepository) LastCommitID(ctx context.Context, repoID uint) (uint, error) {
+	if err := contextErr(ctx); err != nil {
+		return 0, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	var id uint
+	for _, c := range s.store.commits {
+		if c.RepositoryID == repoID && c.ID > id {
+			id = c.ID
+		}
+	}
+	return id, nil
+}
+
+// UnparsedCommits returns no commits, commits are parsed before they are saved
+// in memory.
+func (s *MemoryCommitRepository) UnparsedCommits(ctx context.Context, limit int) ([]domain.Commit, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+	return []domain.Commit{}, nil
+}
+
+func (s *MemoryCommitRepository) UpdateConventional(ctx context.Context, commits []domain.Commit) error {
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	for _, c := range commits {
+		stored, ok := s.store.commits[c.ID]
+		if !ok {
+			continue
+		}
+		commitType := c.Conventional.Type
+		stored.Type = &commitType
+		stored.Scope = c.Conventional.Scope
+		stored.Breaking = c.Conventional.Breaking
+		stored.Description = c.Conventional.Description
+	}
+	return nil
+}
+
+func (s *MemoryCommitRepository) CommitsWithoutFiles(ctx context.Context, repoID uint, since time.Time, limit int) ([]domain.Commit, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	matching := s.store.sortedCommits(func(c *Commit) bool {
+		return c.RepositoryID == repoID && !c.Date.Before(since) && !c.FilesFetched
+	})
+	sortNewestFirst(matching)
+
+	matching = limited(matching, limit)
+	commits := make([]domain.Commit, 0, len(matching))
+	for _, c := range matching {
+		commits = append(commits, *c.ToDomain())
+	}
+	return commits, nil
+}
+
+func (s *MemoryCommitRepository) SaveCommitFiles(ctx context.Context, commit domain.Commit) error {
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	stored, ok := s.store.commits[commit.ID]
+	if !ok {
+		return errcodes.ErrNoRecordFound
+	}
+	if stored.FilesFetched {
+		return nil
+	}
+
+	stored.Additions = commit.Additions
+	stored.Deletions = commit.Deletions
+	stored.FilesFetched = true
+
+	files := make([]CommitFile, 0, len(commit.Files))
+	for i := range commit.Files {
+		file := ToGormCommitFile(commit.ID, &commit.Files[i])
+		s.store.lastFileID++
+		file.ID = s.store.lastFileID
+		files = append(files, *file)
+	}
+	s.store.files[commit.ID] = files
+	return nil
+}
+
+// withLinks sets the links of commits and the reverts pointing at them, the
+// store must be locked.
+func (s *MemoryCommitRepository) withLinks(commits []domain.Commit) {
+	index := make(map[uint]int, len(commits))
+	for i, c := range commits {
+		index[c.ID] = i
+	}
+
+	links := make([]*CommitLink, 0, len(s.store.links))
+	for _, link := range s.store.links {
+		links = append(links, link)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if !links[i].Date.Equal(links[j].Date) {
+			return links[i].Date.Before(links[j].Date)
+		}
+		return links[i].CommitID < links[j].CommitID
+	})
+
+	for _, link := range links {
+		if i, ok := index[link.CommitID]; ok {
+			commits[i].Link = &domain.CommitLink{Kind: domain.CommitLinkKind(link.Kind), Target: link.Target}
+			if link.TargetID != nil {
+				commits[i].Link.Hash = s.store.commits[*link.TargetID].CommitHash
+			}
+		}
+
+		if link.TargetID == nil || domain.CommitLinkKind(link.Kind) != domain.LinkRevert {
+			continue
+		}
+		if i, ok := index[*link.TargetID]; ok {
+			commits[i].RevertedBy = append(commits[i].RevertedBy, s.store.commits[link.CommitID].CommitHash)
+		}
+	}
+}
+
+// UnlinkedCommits returns up to limit commits with an id above afterID whose
+// message may revert or amend another commit and that have no stored link.
+func (s *MemoryCommitRepository) UnlinkedCommits(ctx context.Context, afterID uint, limit int) ([]domain.Commit, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	matching := s.store.sortedCommits(func(c *Commit) bool {
+		if _, linked := s.store.links[c.ID]; linked || c.ID <= afterID {
+			return false
+		}
+		return strings.Contains(c.Message, "This reverts commit ") ||
+			strings.HasPrefix(c.Message, "fixup! ") || strings.HasPrefix(c.Message, "squash! ")
+	})
+
+	matching = limited(matching, limit)
+	commits := make([]domain.Commit, 0, len(matching))
+	for _, c := range matching {
+		commits = append(commits, *c.ToDomain())
+	}
+	return commits, nil
+}
+
+// SaveCommitLinks stores the links of stored commits, commits without a link or
+// with a stored link are skipped.
+func (s *MemoryCommitRepository) SaveCommitLinks(ctx context.Context, commits []domain.Commit) error {
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	for i := range commits {
+		if commits[i].Link == nil {
+			continue
+		}
+		if _, ok := s.store.links[commits[i].ID]; !ok {
+			s.store.links[commits[i].ID] = ToGormCommitLink(&commits[i])
+		}
+	}
+	return nil
+}
+
+// ResolveCommitLinks links the unresolved links of a repository to their
+// targets, see GormCommitRepository.ResolveCommitLinks.
+func (s *MemoryCommitRepository) ResolveCommitLinks(ctx context.Context, repoID uint) (int64, error) {
+	if err := contextErr(ctx); err != nil {
+		return 0, err
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	var resolved int64
+	for _, source := range s.store.sortedCommits(func(c *Commit) bool { return c.RepositoryID == repoID }) {
+		link, ok := s.store.links[source.ID]
+		if !ok || link.TargetID != nil {
+			continue
+		}
+
+		targets := s.store.sortedCommits(func(c *Commit) bool {
+			if c.RepositoryID != repoID || c.ID == link.CommitID {
+				return false
+			}
+			switch {
+			case domain.CommitLinkKind(link.Kind) != domain.LinkRevert:
+				return !c.Date.After(link.Date) && (c.Message == link.Target || strings.HasPrefix(c.Message, link.Target+"\n"))
+			case len(link.Target) == fullHashLength:
+				return c.CommitHash == link.Target
+			default:
+				return strings.HasPrefix(c.CommitHash, link.Target)
+			}
+		})
+		if len(targets) == 0 {
+			continue
+		}
+
+		sortNewestFirst(targets)
+		targetID := targets[0].ID
+		link.TargetID = &targetID
+		resolved++
+	}
+	return resolved, nil
+}
+
+func (s *MemoryCommitRepository) PruneCommits(ctx context.Context, repoID uint, before time.Time, keep, limit int) (int64, error) {
+	if err := contextErr(ctx); err != nil {
+		return 0, err
+	}
+
+	if before.IsZero() && keep == 0 {
+		return 0, nil
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if _, ok := s.store.repos[repoID]; !ok {
+		return 0, errcodes.ErrNoRecordFound
+	}
+
+	commits := s.store.sortedCommits(func(c *Commit) bool { return c.RepositoryID == repoID })
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].Date.Before(commits[j].Date) })
+
+	var pruned int64
+	for i, c := range commits {
+		if pruned == int64(limit) {
+			break
+		}
+		beyond := keep > 0 && i < len(commits)-keep
+		if beyond || (!before.IsZero() && c.Date.Before(before)) {
+			s.store.deleteCommit(c.ID)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
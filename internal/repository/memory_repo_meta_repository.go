@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+)
+
+// MemoryRepositoryMetaRepository is an in-memory implementation of RepositoryMetaRepository
+type MemoryRepositoryMetaRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryRepositoryMetaRepository initializes a new MemoryRepositoryMetaRepository
+func NewMemoryRepositoryMetaRepository(store *MemoryStore) RepositoryMetaRepository {
+	return &MemoryRepositoryMetaRepository{store: store}
+}
+
+func (r *MemoryRepositoryMetaRepository) SaveRepoMetadata(ctx context.Context, repo domain.RepositoryMeta) (*domain.RepositoryMeta, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.repoByName(repo.Name) != nil {
+		return nil, errcodes.ErrRepoAlreadyAdded
+	}
+
+	stored := ToGormRepo(&repo)
+	if stored.ID == 0 {
+		r.store.lastRepoID++
+		stored.ID = r.store.lastRepoID
+	} else if _, ok := r.store.repos[stored.ID]; ok {
+		return nil, errcodes.ErrRepoAlreadyAdded
+	} else if stored.ID > r.store.lastRepoID {
+		r.store.lastRepoID = stored.ID
+	}
+
+	now := time.Now()
+	if stored.CreatedAt.IsZero() {
+		stored.CreatedAt = now
+	}
+	if stored.UpdatedAt.IsZero() {
+		stored.UpdatedAt = now
+	}
+
+	r.store.repos[stored.ID] = stored
+	return stored.ToDomain(), nil
+}
+
+func (r *MemoryRepositoryMetaRepository) RepoMeta(ctx context.Context, name string) (*domain.RepositoryMeta, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	repo := r.store.repoByName(name)
+	if repo == nil {
+		return nil, errcodes.ErrNoRecordFound
+	}
+	return repo.ToDomain(), nil
+}
+
+func (r *MemoryRepositoryMetaRepository) AllRepoMeta(ctx context.Context) ([]domain.RepositoryMeta, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var repos []domain.RepositoryMeta
+	for _, repo := range r.store.sortedRepos() {
+		repos = append(repos, *repo.ToDomain())
+	}
+	return repos, nil
+}
+
+// UpdateRepoMetadata sets the fields of a stored repository that are not zero in
+// repo, like a GORM update with a struct.
+func (r *MemoryRepositoryMetaRepository) UpdateRepoMetadata(ctx context.Context, repo domain.RepositoryMeta) (*domain.RepositoryMeta, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	update := ToGormRepo(&repo)
+	stored, ok := r.store.repos[repo.ID]
+	if !ok {
+		return update.ToDomain(), nil
+	}
+	if other := r.store.repoByName(update.Name); other != nil && other.ID != stored.ID {
+		return nil, errcodes.ErrRepoAlreadyAdded
+	}
+
+	setIfNotZero(&stored.OwnerName, update.OwnerName)
+	setIfNotZero(&stored.Name, update.Name)
+	setIfNotZero(&stored.Description, update.Description)
+	setIfNotZero(&stored.URL, update.URL)
+	setIfNotZero(&stored.Language, update.Language)
+	setIfNotZero(&stored.ForksCount, update.ForksCount)
+	setIfNotZero(&stored.StarsCount, update.StarsCount)
+	setIfNotZero(&stored.OpenIssuesCount, update.OpenIssuesCount)
+	setIfNotZero(&stored.WatchersCount, update.WatchersCount)
+	setIfNotZero(&stored.LastFetchedCommit, update.LastFetchedCommit)
+	setIfNotZero(&stored.LastPage, update.LastPage)
+	setIfNotZero(&stored.Index, update.Index)
+	if !update.CreatedAt.IsZero() {
+		stored.CreatedAt = update.CreatedAt
+	}
+	stored.UpdatedAt = time.Now()
+
+	return update.ToDomain(), nil
+}
+
+// setIfNotZero sets dst to v unless v is the zero value.
+func setIfNotZero[T comparable](dst *T, v T) {
+	var zero T
+	if v != zero {
+		*dst = v
+	}
+}
+
+func (r *MemoryRepositoryMetaRepository) UpdateRepositoryStatus(ctx context.Context, repoID uint, isFetching bool) error {
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if repo, ok := r.store.repos[repoID]; ok {
+		repo.Index = isFetching
+	}
+	return nil
+}
+
+func (r *MemoryRepositoryMetaRepository) UpdateRetention(ctx context.Context, repoID uint, policy domain.RetentionPolicy) error {
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	repo, ok := r.store.repos[repoID]
+	if !ok {
+		return errcodes.ErrNoRecordFound
+	}
+	repo.RetentionDays = policy.Days
+	repo.RetentionCommits = policy.Commits
+	return nil
+}
+
+func (r *MemoryRepositoryMetaRepository) ResetRepoMeta(ctx context.Context, repoID uint) (*domain.RepositoryMeta, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	repo, ok := r.store.repos[repoID]
+	if !ok {
+		return nil, errcodes.ErrNoRecordFound
+	}
+
+	r.store.deleteRepositoryCommits(repoID)
+	repo.LastPage = 0
+	repo.LastFetchedCommit = ""
+	repo.Index = true
+	repo.UpdatedAt = time.Now()
+
+	return repo.ToDomain(), nil
+}
+
+func (r *MemoryRepositoryMetaRepository) DeleteRepoMeta(ctx context.Context, repoID uint) error {
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.repos[repoID]; !ok {
+		return errcodes.ErrNoRecordFound
+	}
+
+	r.store.deleteRepositoryCommits(repoID)
+	delete(r.store.repos, repoID)
+	return nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+)
+
+// MemoryStore holds the rows of the in-memory repositories. Repositories created
+// from the same store share it the way the GORM repositories share a database,
+// so deleting a repository also deletes its commits. It is safe for concurrent
+// use and meant for tests and short lived instances.
+type MemoryStore struct {
+	mu sync.RWMutex
+
+	repos   map[uint]*Repository
+	authors map[uint]*Author
+	commits map[uint]*Commit
+	// links and files are keyed by the id of their commit
+	links map[uint]*CommitLink
+	files map[uint][]CommitFile
+
+	lastRepoID   uint
+	lastAuthorID uint
+	lastCommitID uint
+	lastFileID   uint
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		repos:   make(map[uint]*Repository),
+		authors: make(map[uint]*Author),
+		commits: make(map[uint]*Commit),
+		links:   make(map[uint]*CommitLink),
+		files:   make(map[uint][]CommitFile),
+	}
+}
+
+// contextErr returns the error a database call made with ctx would fail with.
+func contextErr(ctx context.Context) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.Canceled:
+		return errcodes.ErrContextCancelled
+	default:
+		return ctx.Err()
+	}
+}
+
+// sortedRepos returns the repositories by id.
+func (s *MemoryStore) sortedRepos() []*Repository {
+	repos := make([]*Repository, 0, len(s.repos))
+	for _, repo := range s.repos {
+		repos = append(repos, repo)
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].ID < repos[j].ID })
+	return repos
+}
+
+// repoByName returns the repository with the given name, nil if there is none.
+func (s *MemoryStore) repoByName(name string) *Repository {
+	for _, repo := range s.repos {
+		if repo.Name == name {
+			return repo
+		}
+	}
+	return nil
+}
+
+// sortedCommits returns the commits matching keep by id.
+func (s *MemoryStore) sortedCommits(keep func(*Commit) bool) []*Commit {
+	var commits []*Commit
+	for _, c := range s.commits {
+		if keep(c) {
+			commits = append(commits, c)
+		}
+	}
+	sort.Slice(commits, func(i, j int) bool { return commits[i].ID < commits[j].ID })
+	return commits
+}
+
+// limited returns the first limit items, all of them when limit is negative
+// like a GORM limit.
+func limited[T any](items []T, limit int) []T {
+	if limit >= 0 && limit < len(items) {
+		return items[:limit]
+	}
+	return items
+}
+
+// commitByHash returns the commit with the given hash, nil if there is none.
+func (s *MemoryStore) commitByHash(hash string) *Commit {
+	for _, c := range s.commits {
+		if c.CommitHash == hash {
+			return c
+		}
+	}
+	return nil
+}
+
+// saveCommit stores a new commit with its link, creating its author when no
+// author with the same name and email is stored.
+func (s *MemoryStore) saveCommit(commit domain.Commit, now time.Time) *domain.Commit {
+	var author *Author
+	for _, a := range s.authors {
+		if a.Name == commit.Author.Name && a.Email == commit.Author.Email {
+			author = a
+			break
+		}
+	}
+	if author == nil {
+		s.lastAuthorID++
+		author = &Author{ID: s.lastAuthorID, Name: commit.Author.Name, Email: commit.Author.Email, CreatedAt: now, UpdatedAt: now}
+		s.authors[author.ID] = author
+	}
+	author.CommitCount++
+
+	commit.AuthorID = author.ID
+	stored := ToGormCommit(&commit)
+	s.lastCommitID++
+	stored.ID = s.lastCommitID
+	stored.CreatedAt = now
+	s.commits[stored.ID] = stored
+
+	if commit.Link != nil {
+		commit.ID = stored.ID
+		s.links[stored.ID] = ToGormCommitLink(&commit)
+	}
+
+	saved := stored.ToDomain()
+	saved.Link = commit.Link
+	return saved
+}
+
+// withAuthor returns a copy of a commit with its author loaded.
+func (s *MemoryStore) withAuthor(c *Commit) *Commit {
+	loaded := *c
+	if author, ok := s.authors[c.AuthorID]; ok {
+		loaded.Author = *author
+	}
+	return &loaded
+}
+
+// deleteRepositoryCommits deletes the commits of a repository with their files
+// and links and takes them off the commit counts of their authors.
+func (s *MemoryStore) deleteRepositoryCommits(repoID uint) {
+	for id, c := range s.commits {
+		if c.RepositoryID == repoID {
+			s.deleteCommit(id)
+		}
+	}
+}
+
+// deleteCommit deletes a commit with its files and links, takes it off the
+// commit count of its author and unresolves the links targeting it.
+func (s *MemoryStore) deleteCommit(id uint) {
+	c, ok := s.commits[id]
+	if !ok {
+		return
+	}
+	if author, ok := s.authors[c.AuthorID]; ok {
+		author.CommitCount--
+	}
+	for _, link := range s.links {
+		if link.TargetID != nil && *link.TargetID == id {
+			link.TargetID = nil
+		}
+	}
+	delete(s.commits, id)
+	delete(s.links, id)
+	delete(s.files, id)
+}
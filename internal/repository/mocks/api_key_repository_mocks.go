@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// APIKeyRepository mock
+type APIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *APIKeyRepository) SaveAPIKey(ctx context.Context, key domain.APIKey, keyHash string) (*domain.APIKey, error) {
+	args := m.Called(ctx, key, keyHash)
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *APIKeyRepository) APIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *APIKeyRepository) AllAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.APIKey), args.Error(1)
+}
+
+func (m *APIKeyRepository) RevokeAPIKey(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -3,7 +3,7 @@ package mocks
 import (
 	"context"
 
-	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/internal/domain"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -12,7 +12,30 @@ type AuthorRepository struct {
 	mock.Mock
 }
 
-func (m *AuthorRepository) GetTopAuthors(ctx context.Context, repoName string, limit int) ([]repository.Author, error) {
-	args := m.Called(ctx, repoName, limit)
-	return args.Get(0).([]repository.Author), args.Error(1)
+func (m *AuthorRepository) GetTopAuthors(ctx context.Context, repoName string, query domain.TopAuthorsQuery) ([]domain.Author, error) {
+	args := m.Called(ctx, repoName, query)
+	return args.Get(0).([]domain.Author), args.Error(1)
+}
+
+func (m *AuthorRepository) GetTopAuthorsAcross(ctx context.Context, repoNames []string, query domain.TopAuthorsQuery) ([]domain.Author, error) {
+	args := m.Called(ctx, repoNames, query)
+	return args.Get(0).([]domain.Author), args.Error(1)
+}
+
+func (m *AuthorRepository) AuthorProfile(ctx context.Context, authorID uint) (*domain.AuthorProfile, error) {
+	args := m.Called(ctx, authorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AuthorProfile), args.Error(1)
+}
+
+func (m *AuthorRepository) AuthorProfiles(ctx context.Context, authorIDs []uint) ([]domain.AuthorProfile, error) {
+	args := m.Called(ctx, authorIDs)
+	return args.Get(0).([]domain.AuthorProfile), args.Error(1)
+}
+
+func (m *AuthorRepository) RepairAuthorStats(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
 }
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/just-nibble/git-service/internal/domain"
 	"github.com/stretchr/testify/mock"
@@ -12,9 +13,9 @@ type CommitRepository struct {
 	mock.Mock
 }
 
-func (m *CommitRepository) GetCommitsByRepository(ctx context.Context, repoMetadata domain.RepositoryMeta, query domain.APIPaging) ([]domain.Commit, error) {
-	args := m.Called(ctx, repoMetadata, query)
-	return args.Get(0).([]domain.Commit), args.Error(1)
+func (m *CommitRepository) GetCommitsByRepository(ctx context.Context, repoMetadata domain.RepositoryMeta, query domain.APIPaging, filter domain.CommitFilter) ([]domain.Commit, domain.PagingInfo, error) {
+	args := m.Called(ctx, repoMetadata, query, filter)
+	return args.Get(0).([]domain.Commit), args.Get(1).(domain.PagingInfo), args.Error(2)
 }
 
 func (m *CommitRepository) SaveCommit(ctx context.Context, commit domain.Commit) (*domain.Commit, error) {
@@ -22,7 +23,83 @@ func (m *CommitRepository) SaveCommit(ctx context.Context, commit domain.Commit)
 	return args.Get(0).(*domain.Commit), args.Error(1)
 }
 
+func (m *CommitRepository) SaveCommits(ctx context.Context, repo domain.RepositoryMeta, commits []domain.Commit) ([]domain.Commit, error) {
+	args := m.Called(ctx, repo, commits)
+	return args.Get(0).([]domain.Commit), args.Error(1)
+}
+
+func (m *CommitRepository) ImportCommits(ctx context.Context, repo domain.RepositoryMeta, commits []domain.Commit) ([]domain.Commit, error) {
+	args := m.Called(ctx, repo, commits)
+	return args.Get(0).([]domain.Commit), args.Error(1)
+}
+
+func (m *CommitRepository) PruneCommits(ctx context.Context, repoID uint, before time.Time, keep, limit int) (int64, error) {
+	args := m.Called(ctx, repoID, before, keep, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *CommitRepository) GetCommitByHash(ctx context.Context, commitHash string) (*domain.Commit, error) {
 	args := m.Called(ctx, commitHash)
 	return args.Get(0).(*domain.Commit), args.Error(1)
 }
+
+func (m *CommitRepository) CommitsBetween(ctx context.Context, repoID uint, since, until time.Time, limit int) ([]domain.Commit, error) {
+	args := m.Called(ctx, repoID, since, until, limit)
+	return args.Get(0).([]domain.Commit), args.Error(1)
+}
+
+func (m *CommitRepository) UnparsedCommits(ctx context.Context, limit int) ([]domain.Commit, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]domain.Commit), args.Error(1)
+}
+
+func (m *CommitRepository) UpdateConventional(ctx context.Context, commits []domain.Commit) error {
+	args := m.Called(ctx, commits)
+	return args.Error(0)
+}
+
+func (m *CommitRepository) CommitsWithoutFiles(ctx context.Context, repoID uint, since time.Time, limit int) ([]domain.Commit, error) {
+	args := m.Called(ctx, repoID, since, limit)
+	return args.Get(0).([]domain.Commit), args.Error(1)
+}
+
+func (m *CommitRepository) SaveCommitFiles(ctx context.Context, commit domain.Commit) error {
+	args := m.Called(ctx, commit)
+	return args.Error(0)
+}
+
+func (m *CommitRepository) RepositoryCommits(ctx context.Context, repoID, afterID uint, limit int) ([]domain.Commit, error) {
+	args := m.Called(ctx, repoID, afterID, limit)
+	return args.Get(0).([]domain.Commit), args.Error(1)
+}
+
+func (m *CommitRepository) LastCommitID(ctx context.Context, repoID uint) (uint, error) {
+	args := m.Called(ctx, repoID)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *CommitRepository) StreamCommits(ctx context.Context, repo domain.RepositoryMeta, query domain.APIPaging, filter domain.CommitFilter, fn func(domain.Commit) error) error {
+	args := m.Called(ctx, repo, query, filter)
+	// the commits to stream are returned like those of a listing
+	for _, c := range args.Get(0).([]domain.Commit) {
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *CommitRepository) UnlinkedCommits(ctx context.Context, afterID uint, limit int) ([]domain.Commit, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.Get(0).([]domain.Commit), args.Error(1)
+}
+
+func (m *CommitRepository) SaveCommitLinks(ctx context.Context, commits []domain.Commit) error {
+	args := m.Called(ctx, commits)
+	return args.Error(0)
+}
+
+func (m *CommitRepository) ResolveCommitLinks(ctx context.Context, repoID uint) (int64, error) {
+	args := m.Called(ctx, repoID)
+	return args.Get(0).(int64), args.Error(1)
+}
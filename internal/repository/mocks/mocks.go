@@ -0,0 +1,14 @@
+package mocks
+
+import "github.com/just-nibble/git-service/internal/repository"
+
+// The mocks must keep up with the interfaces they stand in for.
+var (
+	_ repository.APIKeyRepository         = (*APIKeyRepository)(nil)
+	_ repository.AuthorRepository         = (*AuthorRepository)(nil)
+	_ repository.CommitRepository         = (*CommitRepository)(nil)
+	_ repository.PullRequestRepository    = (*PullRequestRepository)(nil)
+	_ repository.RepositoryMetaRepository = (*RepositoryRepository)(nil)
+	_ repository.StatsRepository          = (*StatsRepository)(nil)
+	_ repository.TagRepository            = (*TagRepository)(nil)
+)
@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// PullRequestRepository mock
+type PullRequestRepository struct {
+	mock.Mock
+}
+
+func (m *PullRequestRepository) LastPullRequestUpdate(ctx context.Context, repoID uint) (time.Time, error) {
+	args := m.Called(ctx, repoID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *PullRequestRepository) SavePullRequest(ctx context.Context, pr domain.PullRequest) error {
+	args := m.Called(ctx, pr)
+	return args.Error(0)
+}
+
+func (m *PullRequestRepository) LinkPullRequestCommits(ctx context.Context, repoID uint) (int64, error) {
+	args := m.Called(ctx, repoID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *PullRequestRepository) GetPullRequests(ctx context.Context, repoID uint, state domain.PullRequestState, query domain.APIPaging) ([]domain.PullRequest, domain.PagingInfo, error) {
+	args := m.Called(ctx, repoID, state, query)
+	return args.Get(0).([]domain.PullRequest), args.Get(1).(domain.PagingInfo), args.Error(2)
+}
+
+func (m *PullRequestRepository) PullRequestsForCommit(ctx context.Context, repoID uint, hash string) ([]domain.PullRequest, error) {
+	args := m.Called(ctx, repoID, hash)
+	return args.Get(0).([]domain.PullRequest), args.Error(1)
+}
@@ -26,17 +26,27 @@ func (m *RepositoryRepository) UpdateRepoMetadata(ctx context.Context, repo doma
 	return args.Get(0).(*domain.RepositoryMeta), args.Error(1)
 }
 
-func (m *RepositoryRepository) RepoMetadataByPublicId(ctx context.Context, publicId string) (*domain.RepositoryMeta, error) {
-	args := m.Called(ctx, publicId)
-	return args.Get(0).(*domain.RepositoryMeta), args.Error(1)
-}
-
 func (m *RepositoryRepository) AllRepoMeta(ctx context.Context) ([]domain.RepositoryMeta, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]domain.RepositoryMeta), args.Error(1)
 }
 
-func (m *RepositoryRepository) UpdateRepositoryStatus(ctx context.Context, isFetching bool) error {
-	args := m.Called(ctx, isFetching)
-	return args.Error(1)
+func (m *RepositoryRepository) UpdateRepositoryStatus(ctx context.Context, repoID uint, isFetching bool) error {
+	args := m.Called(ctx, repoID, isFetching)
+	return args.Error(0)
+}
+
+func (m *RepositoryRepository) UpdateRetention(ctx context.Context, repoID uint, policy domain.RetentionPolicy) error {
+	args := m.Called(ctx, repoID, policy)
+	return args.Error(0)
+}
+
+func (m *RepositoryRepository) ResetRepoMeta(ctx context.Context, repoID uint) (*domain.RepositoryMeta, error) {
+	args := m.Called(ctx, repoID)
+	return args.Get(0).(*domain.RepositoryMeta), args.Error(1)
+}
+
+func (m *RepositoryRepository) DeleteRepoMeta(ctx context.Context, repoID uint) error {
+	args := m.Called(ctx, repoID)
+	return args.Error(0)
 }
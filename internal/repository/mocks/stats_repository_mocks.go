@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// StatsRepository mock
+type StatsRepository struct {
+	mock.Mock
+}
+
+func (m *StatsRepository) CommitActivity(ctx context.Context, query domain.ActivityQuery) ([]domain.ActivityBucket, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]domain.ActivityBucket), args.Error(1)
+}
+
+func (m *StatsRepository) PunchCard(ctx context.Context, query domain.ActivityQuery) ([]domain.PunchCardCell, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]domain.PunchCardCell), args.Error(1)
+}
+
+func (m *StatsRepository) AuthorActivity(ctx context.Context, query domain.ActivityQuery) ([]domain.AuthorActivity, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]domain.AuthorActivity), args.Error(1)
+}
+
+func (m *StatsRepository) Deployments(ctx context.Context, query domain.DeliveryQuery) ([]domain.Deployment, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]domain.Deployment), args.Error(1)
+}
+
+func (m *StatsRepository) DeliveredCommits(ctx context.Context, query domain.DeliveryQuery, after, until time.Time) ([]domain.DeliveredCommit, error) {
+	args := m.Called(ctx, query, after, until)
+	return args.Get(0).([]domain.DeliveredCommit), args.Error(1)
+}
+
+func (m *StatsRepository) DirectoryChurn(ctx context.Context, query domain.OwnershipQuery) ([]domain.DirectoryChurn, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]domain.DirectoryChurn), args.Error(1)
+}
+
+func (m *StatsRepository) Hotspots(ctx context.Context, query domain.OwnershipQuery) ([]domain.Hotspot, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]domain.Hotspot), args.Error(1)
+}
+
+func (m *StatsRepository) Reverts(ctx context.Context, query domain.ActivityQuery) ([]domain.Revert, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]domain.Revert), args.Error(1)
+}
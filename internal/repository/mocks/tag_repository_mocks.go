@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// TagRepository mock
+type TagRepository struct {
+	mock.Mock
+}
+
+func (m *TagRepository) AllTags(ctx context.Context, repoID uint) ([]domain.Tag, error) {
+	args := m.Called(ctx, repoID)
+	return args.Get(0).([]domain.Tag), args.Error(1)
+}
+
+func (m *TagRepository) GetTags(ctx context.Context, repoID uint, query domain.APIPaging) ([]domain.Tag, domain.PagingInfo, error) {
+	args := m.Called(ctx, repoID, query)
+	return args.Get(0).([]domain.Tag), args.Get(1).(domain.PagingInfo), args.Error(2)
+}
+
+func (m *TagRepository) TagByName(ctx context.Context, repoID uint, name string) (*domain.Tag, error) {
+	args := m.Called(ctx, repoID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tag), args.Error(1)
+}
+
+func (m *TagRepository) ReplaceTags(ctx context.Context, repoID uint, tags []domain.Tag) error {
+	args := m.Called(ctx, repoID, tags)
+	return args.Error(0)
+}
+
+func (m *TagRepository) GetReleases(ctx context.Context, repoID uint, query domain.APIPaging) ([]domain.Release, domain.PagingInfo, error) {
+	args := m.Called(ctx, repoID, query)
+	return args.Get(0).([]domain.Release), args.Get(1).(domain.PagingInfo), args.Error(2)
+}
+
+func (m *TagRepository) ReplaceReleases(ctx context.Context, repoID uint, releases []domain.Release) error {
+	args := m.Called(ctx, repoID, releases)
+	return args.Error(0)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/pkg/errcodes"
 )
 
 const (
@@ -11,6 +12,25 @@ const (
 	PageDefaultSortDirectionDesc = "desc"
 )
 
+// sortColumns are the columns a page can be ordered by. Sort values are
+// interpolated into the query, so anything else must be rejected.
+var sortColumns = map[string]bool{
+	"created_at":  true,
+	"date":        true,
+	"commit_hash": true,
+}
+
+// validatePaging checks the caller controlled ordering of a page.
+func validatePaging(query domain.APIPaging) error {
+	if !sortColumns[query.Sort] {
+		return errcodes.ErrInvalidSort
+	}
+	if query.Direction != "asc" && query.Direction != "desc" {
+		return errcodes.ErrInvalidDirection
+	}
+	return nil
+}
+
 func getPaginationInfo(query domain.APIPaging) (domain.APIPaging, int) {
 	var offset int
 	// load defaults
@@ -29,7 +49,9 @@ func getPaginationInfo(query domain.APIPaging) (domain.APIPaging, int) {
 		query.Direction = PageDefaultSortDirectionDesc
 	}
 
-	if query.Page > 1 {
+	if query.Offset > 0 {
+		offset = query.Offset
+	} else if query.Page > 1 {
 		offset = query.Limit * (query.Page - 1)
 	}
 	return query, offset
@@ -38,7 +60,12 @@ func getPaginationInfo(query domain.APIPaging) (domain.APIPaging, int) {
 func getPagingInfo(query domain.APIPaging, count int) domain.PagingInfo {
 	var hasNextPage bool
 
-	next := int64((query.Page * query.Limit) - count)
+	end := query.Page * query.Limit
+	if query.Offset > 0 {
+		end = query.Offset + query.Limit
+	}
+
+	next := int64(end - count)
 	if next < 0 {
 		hasNextPage = true
 	}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+)
+
+type APIKey struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"index"`
+	Prefix    string
+	KeyHash   string `gorm:"uniqueIndex"`
+	Role      string
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+func (k *APIKey) ToDomain() *domain.APIKey {
+	return &domain.APIKey{
+		ID:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Role:      domain.Role(k.Role),
+		CreatedAt: k.CreatedAt,
+		RevokedAt: k.RevokedAt,
+	}
+}
+
+func ToGormAPIKey(k *domain.APIKey, keyHash string) *APIKey {
+	return &APIKey{
+		ID:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		KeyHash:   keyHash,
+		Role:      string(k.Role),
+		CreatedAt: k.CreatedAt,
+		RevokedAt: k.RevokedAt,
+	}
+}
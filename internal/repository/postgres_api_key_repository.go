@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"gorm.io/gorm"
+)
+
+// GormAPIKeyRepository is a GORM-based implementation of APIKeyRepository
+type GormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewGormAPIKeyRepository initializes a new GormAPIKeyRepository
+func NewGormAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &GormAPIKeyRepository{db: db}
+}
+
+// SaveAPIKey stores a key; only the hash of the secret is persisted
+func (r *GormAPIKeyRepository) SaveAPIKey(ctx context.Context, key domain.APIKey, keyHash string) (*domain.APIKey, error) {
+	dbKey := ToGormAPIKey(&key, keyHash)
+
+	if err := r.db.WithContext(ctx).Create(dbKey).Error; err != nil {
+		return nil, err
+	}
+	return dbKey.ToDomain(), nil
+}
+
+func (r *GormAPIKeyRepository) APIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key APIKey
+	err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).Find(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	if key.ID == 0 {
+		return nil, errcodes.ErrNoRecordFound
+	}
+	return key.ToDomain(), nil
+}
+
+func (r *GormAPIKeyRepository) AllAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	var dbKeys []APIKey
+
+	if err := r.db.WithContext(ctx).Order("id").Find(&dbKeys).Error; err != nil {
+		return nil, err
+	}
+
+	keys := make([]domain.APIKey, 0, len(dbKeys))
+	for _, k := range dbKeys {
+		keys = append(keys, *k.ToDomain())
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey marks a key as revoked, revoked keys are kept for auditing
+func (r *GormAPIKeyRepository) RevokeAPIKey(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Model(&APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errcodes.ErrNoRecordFound
+	}
+	return nil
+}
@@ -2,10 +2,11 @@ package repository
 
 import "time"
 
+// Author is identified by name and email.
 type Author struct {
 	ID          uint   `gorm:"primaryKey"`
-	Name        string `gorm:"index"`
-	Email       string `gorm:"index"`
+	Name        string `gorm:"index;uniqueIndex:idx_author_identity,priority:1"`
+	Email       string `gorm:"index;uniqueIndex:idx_author_identity,priority:2"`
 	CommitCount int
 	Commits     []Commit `gorm:"foreignKey:AuthorID"`
 	CreatedAt   time.Time
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/pkg/errcodes"
 	"gorm.io/gorm"
 )
 
@@ -16,18 +18,199 @@ func NewGormAuthorRepository(db *gorm.DB) AuthorRepository {
 	return &GormAuthorRepository{db: db}
 }
 
-func (s *GormAuthorRepository) GetTopAuthors(ctx context.Context, repoName string, limit int) ([]Author, error) {
-	var authors []Author
-	err := s.db.WithContext(ctx).
-		Table("author").
-		Select("author.id, author.name, author.email, COUNT(commit.id) as commit_count").
-		Joins("JOIN commit ON commit.author_id = author.id").
-		Joins("JOIN repository ON commit.repository_id = repository.id").
+// GetTopAuthors ranks the authors of a repository by their commits in the query
+// window. Ties are broken by the most recent commit, then by author id.
+func (s *GormAuthorRepository) GetTopAuthors(ctx context.Context, repoName string, query domain.TopAuthorsQuery) ([]domain.Author, error) {
+	var authors []domain.Author
+	err := s.rankAuthors(ctx, query).
 		Where("repository.name = ?", repoName).
-		Group("author.id").
-		Order("commit_count DESC").
-		Limit(limit).
-		Find(&authors).
+		Scan(&authors).
 		Error
 	return authors, err
 }
+
+func (s *GormAuthorRepository) GetTopAuthorsAcross(ctx context.Context, repoNames []string, query domain.TopAuthorsQuery) ([]domain.Author, error) {
+	tx := s.rankAuthors(ctx, query)
+	if len(repoNames) > 0 {
+		tx = tx.Where("repository.name IN ?", repoNames)
+	}
+
+	var authors []domain.Author
+	err := tx.Scan(&authors).Error
+	return authors, err
+}
+
+// rankAuthors builds the ranking query shared by the single and cross-repository
+// rankings, callers restrict it to their repositories. Rankings over all time are
+// read from the per repository aggregates, windowed ones have to count commits.
+func (s *GormAuthorRepository) rankAuthors(ctx context.Context, query domain.TopAuthorsQuery) *gorm.DB {
+	tx := s.db.WithContext(ctx).Table("author")
+
+	if query.Since.IsZero() && query.Until.IsZero() {
+		tx = tx.Select("author.id, author.name, author.email, SUM(stat.commit_count) AS commit_count, " +
+			"COUNT(stat.repository_id) AS repository_count, " +
+			"SUM(stat.additions) AS additions, SUM(stat.deletions) AS deletions, " +
+			"MIN(stat.first_commit_at) AS first_commit_at, MAX(stat.last_commit_at) AS last_commit_at").
+			Joins("JOIN author_repository_stat stat ON stat.author_id = author.id").
+			Joins("JOIN repository ON stat.repository_id = repository.id")
+	} else {
+		tx = tx.Select(`author.id, author.name, author.email, COUNT("commit".id) AS commit_count, ` +
+			`COUNT(DISTINCT "commit".repository_id) AS repository_count, ` +
+			`COALESCE(SUM("commit".additions), 0) AS additions, COALESCE(SUM("commit".deletions), 0) AS deletions, ` +
+			`MIN("commit".date) AS first_commit_at, MAX("commit".date) AS last_commit_at`).
+			Joins(`JOIN "commit" ON "commit".author_id = author.id`).
+			Joins(`JOIN repository ON "commit".repository_id = repository.id`)
+
+		if !query.Since.IsZero() {
+			tx = tx.Where(`"commit".date >= ?`, query.Since)
+		}
+		if !query.Until.IsZero() {
+			tx = tx.Where(`"commit".date < ?`, query.Until)
+		}
+	}
+
+	for _, pattern := range query.Exclude {
+		like := domain.LikePattern(pattern)
+		tx = tx.Where(`LOWER(author.name) NOT LIKE ? ESCAPE '\' AND LOWER(author.email) NOT LIKE ? ESCAPE '\'`, like, like)
+	}
+
+	order := "commit_count DESC, last_commit_at DESC, author.id"
+	if query.RankBy == domain.RankByLines {
+		order = "SUM(additions) + SUM(deletions) DESC, " + order
+	}
+
+	return tx.
+		Group("author.id, author.name, author.email").
+		Order(order).
+		Limit(query.Limit)
+}
+
+func (s *GormAuthorRepository) AuthorProfile(ctx context.Context, authorID uint) (*domain.AuthorProfile, error) {
+	profiles, err := s.AuthorProfiles(ctx, []uint{authorID})
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, errcodes.ErrNoRecordFound
+	}
+	return &profiles[0], nil
+}
+
+// authorContribution is a contribution with the author it belongs to
+type authorContribution struct {
+	AuthorID uint
+	domain.RepositoryContribution
+}
+
+// AuthorProfiles reads the authors and their contributions with a query each,
+// however many authors are asked for.
+func (s *GormAuthorRepository) AuthorProfiles(ctx context.Context, authorIDs []uint) ([]domain.AuthorProfile, error) {
+	if len(authorIDs) == 0 {
+		return []domain.AuthorProfile{}, nil
+	}
+
+	var authors []Author
+	if err := s.db.WithContext(ctx).Where("id IN ?", authorIDs).Find(&authors).Error; err != nil {
+		return nil, err
+	}
+
+	var contributions []authorContribution
+	err := s.db.WithContext(ctx).
+		Table("author_repository_stat stat").
+		Select("stat.author_id, repository.id AS repository_id, repository.name AS repository_name, stat.commit_count, "+
+			"stat.first_commit_at, stat.last_commit_at").
+		Joins("JOIN repository ON stat.repository_id = repository.id").
+		Where("stat.author_id IN ?", authorIDs).
+		Order("stat.commit_count DESC, repository.name").
+		Scan(&contributions).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	byAuthor := make(map[uint][]domain.RepositoryContribution, len(authors))
+	for _, c := range contributions {
+		byAuthor[c.AuthorID] = append(byAuthor[c.AuthorID], c.RepositoryContribution)
+	}
+
+	found := make(map[uint]Author, len(authors))
+	for _, author := range authors {
+		found[author.ID] = author
+	}
+
+	profiles := make([]domain.AuthorProfile, 0, len(authors))
+	for _, id := range authorIDs {
+		author, ok := found[id]
+		if !ok {
+			continue
+		}
+		delete(found, id)
+
+		profiles = append(profiles, newAuthorProfile(domain.Author{
+			ID:    author.ID,
+			Name:  author.Name,
+			Email: author.Email,
+		}, byAuthor[id]))
+	}
+
+	return profiles, nil
+}
+
+// newAuthorProfile totals the contributions of an author
+func newAuthorProfile(author domain.Author, contributions []domain.RepositoryContribution) domain.AuthorProfile {
+	profile := domain.AuthorProfile{Author: author, Repositories: contributions}
+	profile.RepositoryCount = len(contributions)
+
+	for _, c := range contributions {
+		profile.CommitCount += c.CommitCount
+		if profile.FirstCommitAt.IsZero() || c.FirstCommitAt.Before(profile.FirstCommitAt) {
+			profile.FirstCommitAt = c.FirstCommitAt
+		}
+		if c.LastCommitAt.After(profile.LastCommitAt) {
+			profile.LastCommitAt = c.LastCommitAt
+		}
+	}
+
+	if profile.Repositories == nil {
+		profile.Repositories = []domain.RepositoryContribution{}
+	}
+
+	return profile
+}
+
+// RepairAuthorStats recomputes the aggregates of every repository from its
+// commits and returns the number of aggregate rows and author counts that were
+// wrong. Each repository is repaired in its own transaction.
+func (s *GormAuthorRepository) RepairAuthorStats(ctx context.Context) (int64, error) {
+	var repoIDs []uint
+	if err := s.db.WithContext(ctx).Model(&Repository{}).Pluck("id", &repoIDs).Error; err != nil {
+		return 0, err
+	}
+
+	var repaired int64
+	for _, repoID := range repoIDs {
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			n, err := repairRepositoryStats(tx, repoID)
+			repaired += n
+			return err
+		})
+		// the repository was deleted since it was listed
+		if err == gorm.ErrRecordNotFound {
+			continue
+		}
+		if err != nil {
+			return repaired, err
+		}
+	}
+
+	res := s.db.WithContext(ctx).
+		Where("repository_id NOT IN (?)", s.db.Model(&Repository{}).Select("id")).
+		Delete(&AuthorRepositoryStat{})
+	if res.Error != nil {
+		return repaired, res.Error
+	}
+	repaired += res.RowsAffected
+
+	n, err := recountAuthors(s.db.WithContext(ctx))
+	return repaired + n, err
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuthorRepositoryStat aggregates the commits of an author to a repository. The
+// rows are kept in step with the commit table in the transactions that save or
+// delete commits, so rankings do not have to count commits.
+type AuthorRepositoryStat struct {
+	AuthorID      uint `gorm:"primaryKey;autoIncrement:false"`
+	RepositoryID  uint `gorm:"primaryKey;autoIncrement:false;index"`
+	CommitCount   int
+	Additions     int
+	Deletions     int
+	FirstCommitAt time.Time
+	LastCommitAt  time.Time
+	UpdatedAt     time.Time
+}
+
+// addCommitStats counts a newly saved commit in the aggregates of its author.
+func addCommitStats(tx *gorm.DB, c *Commit) error {
+	return addStats(tx, AuthorRepositoryStat{
+		AuthorID:      c.AuthorID,
+		RepositoryID:  c.RepositoryID,
+		CommitCount:   1,
+		Additions:     c.Additions,
+		Deletions:     c.Deletions,
+		FirstCommitAt: c.Date,
+		LastCommitAt:  c.Date,
+	})
+}
+
+// addPageStats counts newly saved commits in the aggregates of their authors,
+// with one update per author.
+func addPageStats(tx *gorm.DB, commits []*Commit) error {
+	var order []uint
+	byAuthor := make(map[uint]*AuthorRepositoryStat)
+	for _, c := range commits {
+		stat, ok := byAuthor[c.AuthorID]
+		if !ok {
+			stat = &AuthorRepositoryStat{AuthorID: c.AuthorID, RepositoryID: c.RepositoryID, FirstCommitAt: c.Date, LastCommitAt: c.Date}
+			byAuthor[c.AuthorID] = stat
+			order = append(order, c.AuthorID)
+		}
+		stat.CommitCount++
+		stat.Additions += c.Additions
+		stat.Deletions += c.Deletions
+		if c.Date.Before(stat.FirstCommitAt) {
+			stat.FirstCommitAt = c.Date
+		}
+		if c.Date.After(stat.LastCommitAt) {
+			stat.LastCommitAt = c.Date
+		}
+	}
+
+	for _, authorID := range order {
+		if err := addStats(tx, *byAuthor[authorID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addStats adds the commits counted in stat to the aggregates of its author.
+func addStats(tx *gorm.DB, stat AuthorRepositoryStat) error {
+	err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "author_id"}, {Name: "repository_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"commit_count":    gorm.Expr("author_repository_stat.commit_count + ?", stat.CommitCount),
+			"additions":       gorm.Expr("author_repository_stat.additions + ?", stat.Additions),
+			"deletions":       gorm.Expr("author_repository_stat.deletions + ?", stat.Deletions),
+			"first_commit_at": gorm.Expr("CASE WHEN ? < author_repository_stat.first_commit_at THEN ? ELSE author_repository_stat.first_commit_at END", stat.FirstCommitAt, stat.FirstCommitAt),
+			"last_commit_at":  gorm.Expr("CASE WHEN ? > author_repository_stat.last_commit_at THEN ? ELSE author_repository_stat.last_commit_at END", stat.LastCommitAt, stat.LastCommitAt),
+			"updated_at":      gorm.Expr("CURRENT_TIMESTAMP"),
+		}),
+	}).Create(&stat).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&Author{}).
+		Where("id = ?", stat.AuthorID).
+		UpdateColumn("commit_count", gorm.Expr("commit_count + ?", stat.CommitCount)).
+		Error
+}
+
+// removeCommitStats takes deleted commits of a repository off the aggregates of
+// their authors. The first and last commit dates are read from the remaining
+// commits and aggregates left without commits are deleted.
+func removeCommitStats(tx *gorm.DB, commits []Commit) error {
+	var order []uint
+	byAuthor := make(map[uint]*AuthorRepositoryStat)
+	for _, c := range commits {
+		stat, ok := byAuthor[c.AuthorID]
+		if !ok {
+			stat = &AuthorRepositoryStat{AuthorID: c.AuthorID, RepositoryID: c.RepositoryID}
+			byAuthor[c.AuthorID] = stat
+			order = append(order, c.AuthorID)
+		}
+		stat.CommitCount++
+		stat.Additions += c.Additions
+		stat.Deletions += c.Deletions
+	}
+
+	remaining := `(SELECT %s(c.date) FROM "commit" c WHERE c.author_id = author_repository_stat.author_id AND c.repository_id = author_repository_stat.repository_id)`
+	for _, authorID := range order {
+		stat := byAuthor[authorID]
+		err := tx.Model(&AuthorRepositoryStat{}).
+			Where("author_id = ? AND repository_id = ?", stat.AuthorID, stat.RepositoryID).
+			Updates(map[string]interface{}{
+				"commit_count":    gorm.Expr("commit_count - ?", stat.CommitCount),
+				"additions":       gorm.Expr("additions - ?", stat.Additions),
+				"deletions":       gorm.Expr("deletions - ?", stat.Deletions),
+				"first_commit_at": gorm.Expr(fmt.Sprintf(remaining, "MIN")),
+				"last_commit_at":  gorm.Expr(fmt.Sprintf(remaining, "MAX")),
+				"updated_at":      gorm.Expr("CURRENT_TIMESTAMP"),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&Author{}).
+			Where("id = ?", stat.AuthorID).
+			UpdateColumn("commit_count", gorm.Expr("commit_count - ?", stat.CommitCount)).
+			Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Where("repository_id = ? AND commit_count <= 0", commits[0].RepositoryID).Delete(&AuthorRepositoryStat{}).Error
+}
+
+// deleteRepositoryStats removes the aggregates of a repository whose commits are
+// deleted and recounts its authors.
+func deleteRepositoryStats(tx *gorm.DB, repoID uint) error {
+	var authorIDs []uint
+	err := tx.Model(&AuthorRepositoryStat{}).
+		Where("repository_id = ?", repoID).
+		Pluck("author_id", &authorIDs).
+		Error
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Where("repository_id = ?", repoID).Delete(&AuthorRepositoryStat{}).Error; err != nil {
+		return err
+	}
+
+	if len(authorIDs) == 0 {
+		return nil
+	}
+
+	_, err = recountAuthors(tx.Where("id IN ?", authorIDs))
+	return err
+}
+
+// recountAuthors sets the commit count of the authors selected by tx to the sum
+// of their aggregates and returns how many counts were wrong.
+func recountAuthors(tx *gorm.DB) (int64, error) {
+	total := "(SELECT COALESCE(SUM(s.commit_count), 0) FROM author_repository_stat s WHERE s.author_id = author.id)"
+
+	res := tx.Model(&Author{}).
+		Where("commit_count <> "+total).
+		UpdateColumn("commit_count", gorm.Expr(total))
+	return res.RowsAffected, res.Error
+}
+
+// lockRepository locks a repository row for the rest of the transaction. Commit
+// writers share the lock, the stats repair takes it exclusively so that it does
+// not race with commits being saved.
+func lockRepository(tx *gorm.DB, repoID uint, exclusive bool) error {
+	strength := clause.LockingStrengthShare
+	if exclusive {
+		strength = clause.LockingStrengthUpdate
+	}
+
+	var repo Repository
+	return tx.Clauses(clause.Locking{Strength: strength}).
+		Select("id").
+		First(&repo, repoID).
+		Error
+}
+
+// repairRepositoryStats recomputes the aggregates of a repository from its
+// commits and returns the number of rows that were wrong.
+func repairRepositoryStats(tx *gorm.DB, repoID uint) (int64, error) {
+	if err := lockRepository(tx, repoID, true); err != nil {
+		return 0, err
+	}
+
+	var fresh []AuthorRepositoryStat
+	err := tx.Model(&Commit{}).
+		Select("author_id, repository_id, COUNT(*) AS commit_count, "+
+			"COALESCE(SUM(additions), 0) AS additions, COALESCE(SUM(deletions), 0) AS deletions, "+
+			"MIN(date) AS first_commit_at, MAX(date) AS last_commit_at").
+		Where("repository_id = ?", repoID).
+		Group("author_id, repository_id").
+		Scan(&fresh).
+		Error
+	if err != nil {
+		return 0, err
+	}
+
+	var current []AuthorRepositoryStat
+	if err := tx.Where("repository_id = ?", repoID).Find(&current).Error; err != nil {
+		return 0, err
+	}
+
+	stored := make(map[uint]AuthorRepositoryStat, len(current))
+	for _, stat := range current {
+		stored[stat.AuthorID] = stat
+	}
+
+	var repaired int64
+	for _, stat := range fresh {
+		old, ok := stored[stat.AuthorID]
+		delete(stored, stat.AuthorID)
+		if ok && sameStats(old, stat) {
+			continue
+		}
+
+		repaired++
+		if err := tx.Save(&stat).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	// rows left over belong to authors without commits in the repository
+	for authorID := range stored {
+		repaired++
+		err := tx.Where("author_id = ? AND repository_id = ?", authorID, repoID).Delete(&AuthorRepositoryStat{}).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return repaired, nil
+}
+
+func sameStats(a, b AuthorRepositoryStat) bool {
+	return a.CommitCount == b.CommitCount &&
+		a.Additions == b.Additions &&
+		a.Deletions == b.Deletions &&
+		a.FirstCommitAt.Equal(b.FirstCommitAt) &&
+		a.LastCommitAt.Equal(b.LastCommitAt)
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"path"
 	"time"
 
 	"github.com/just-nibble/git-service/internal/domain"
@@ -10,25 +11,98 @@ type Commit struct {
 	ID           uint   `gorm:"primaryKey"`
 	CommitHash   string `gorm:"uniqueIndex"`
 	AuthorID     uint
-	RepositoryID uint
+	RepositoryID uint `gorm:"index:idx_commit_repository_date,priority:1"`
 	Message      string
-	Date         time.Time
-	Author       Author `gorm:"foreignKey:AuthorID"`
-	CreatedAt    time.Time
-	LastPage     int
+	Additions    int
+	Deletions    int
+	// Type is NULL for commits stored before messages were parsed
+	Type        *string `gorm:"index"`
+	Scope       string
+	Breaking    bool
+	Description string
+	Date        time.Time `gorm:"index:idx_commit_repository_date,priority:2"`
+	Author      Author    `gorm:"foreignKey:AuthorID"`
+	CreatedAt   time.Time
+	LastPage    int
+	// FilesFetched is set once the changed files and line counts are stored
+	FilesFetched bool
+}
+
+// CommitFile is a file changed by a commit. Directory is the directory of the
+// file as a prefix ending in a slash, empty for files in the root.
+type CommitFile struct {
+	ID        uint   `gorm:"primaryKey"`
+	CommitID  uint   `gorm:"index"`
+	Path      string `gorm:"index"`
+	Directory string `gorm:"index"`
+	Additions int
+	Deletions int
+}
+
+func ToGormCommitFile(commitID uint, f *domain.CommitFile) *CommitFile {
+	dir := path.Dir(f.Path)
+	if dir == "." {
+		dir = ""
+	}
+
+	return &CommitFile{
+		CommitID:  commitID,
+		Path:      f.Path,
+		Directory: domain.PathPrefix(dir),
+		Additions: f.Additions,
+		Deletions: f.Deletions,
+	}
+}
+
+// CommitLink links a commit to the commit it reverts or amends. TargetID is set
+// once the target is indexed, Date is the date of the linking commit.
+type CommitLink struct {
+	CommitID     uint `gorm:"primaryKey"`
+	RepositoryID uint `gorm:"index:idx_commit_link_repository_date,priority:1"`
+	Kind         string
+	Target       string
+	TargetID     *uint     `gorm:"index"`
+	Date         time.Time `gorm:"index:idx_commit_link_repository_date,priority:2"`
+}
+
+func ToGormCommitLink(c *domain.Commit) *CommitLink {
+	return &CommitLink{
+		CommitID:     c.ID,
+		RepositoryID: c.RepoID,
+		Kind:         string(c.Link.Kind),
+		Target:       c.Link.Target,
+		Date:         c.Date,
+	}
 }
 
 func (c *Commit) ToDomain() *domain.Commit {
 	author := domain.Author{
+		ID:    c.AuthorID,
 		Name:  c.Author.Name,
 		Email: c.Author.Email,
 	}
 
+	conventional := domain.ConventionalCommit{
+		Scope:       c.Scope,
+		Breaking:    c.Breaking,
+		Description: c.Description,
+	}
+	if c.Type != nil {
+		conventional.Type = *c.Type
+	}
+
 	return &domain.Commit{
-		Hash:    c.CommitHash,
-		Message: c.Message,
-		Author:  author,
-		Date:    c.Date,
+		ID:           c.ID,
+		AuthorID:     c.AuthorID,
+		RepoID:       c.RepositoryID,
+		Hash:         c.CommitHash,
+		Message:      c.Message,
+		Author:       author,
+		Date:         c.Date,
+		Additions:    c.Additions,
+		Deletions:    c.Deletions,
+		Conventional: conventional,
+		FilesFetched: c.FilesFetched,
 	}
 }
 
@@ -40,5 +114,11 @@ func ToGormCommit(c *domain.Commit) *Commit {
 		AuthorID:     c.AuthorID,
 		Date:         c.Date,
 		RepositoryID: c.RepoID,
+		Additions:    c.Additions,
+		Deletions:    c.Deletions,
+		Type:         &c.Conventional.Type,
+		Scope:        c.Conventional.Scope,
+		Breaking:     c.Conventional.Breaking,
+		Description:  c.Conventional.Description,
 	}
 }
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// fullHashLength is the length of an unabbreviated commit hash
+const fullHashLength = 40
+
+// withLinks sets the links of commits and the reverts pointing at them.
+func withLinks(db *gorm.DB, commits []domain.Commit) error {
+	if len(commits) == 0 {
+		return nil
+	}
+
+	index := make(map[uint]int, len(commits))
+	ids := make([]uint, 0, len(commits))
+	for i, c := range commits {
+		index[c.ID] = i
+		ids = append(ids, c.ID)
+	}
+
+	var rows []struct {
+		CommitID   uint
+		Kind       string
+		Target     string
+		TargetID   *uint
+		CommitHash string
+		TargetHash *string
+	}
+	err := db.Model(&CommitLink{}).
+		Select("commit_link.commit_id, commit_link.kind, commit_link.target, commit_link.target_id, source.commit_hash, target.commit_hash AS target_hash").
+		Joins(`JOIN "commit" source ON source.id = commit_link.commit_id`).
+		Joins(`LEFT JOIN "commit" target ON target.id = commit_link.target_id`).
+		Where("commit_link.commit_id IN ? OR (commit_link.target_id IN ? AND commit_link.kind = ?)", ids, ids, domain.LinkRevert).
+		Order("commit_link.date, commit_link.commit_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if i, ok := index[row.CommitID]; ok {
+			link := &domain.CommitLink{Kind: domain.CommitLinkKind(row.Kind), Target: row.Target}
+			if row.TargetHash != nil {
+				link.Hash = *row.TargetHash
+			}
+			commits[i].Link = link
+		}
+
+		if row.TargetID == nil || domain.CommitLinkKind(row.Kind) != domain.LinkRevert {
+			continue
+		}
+		if i, ok := index[*row.TargetID]; ok {
+			commits[i].RevertedBy = append(commits[i].RevertedBy, row.CommitHash)
+		}
+	}
+	return nil
+}
+
+// UnlinkedCommits returns up to limit commits with an id above afterID whose
+// message may revert or amend another commit and that have no stored link.
+func (s *GormCommitRepository) UnlinkedCommits(ctx context.Context, afterID uint, limit int) ([]domain.Commit, error) {
+	var dbCommits []Commit
+	err := s.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Where("message LIKE ? OR message LIKE ? OR message LIKE ?", "%This reverts commit %", "fixup! %", "squash! %").
+		Where("NOT EXISTS (?)", s.db.Model(&CommitLink{}).Select("1").Where(`commit_link.commit_id = "commit".id`)).
+		Order("id").Limit(limit).Find(&dbCommits).Error
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]domain.Commit, 0, len(dbCommits))
+	for _, commit := range dbCommits {
+		commits = append(commits, *commit.ToDomain())
+	}
+	return commits, nil
+}
+
+// SaveCommitLinks stores the links of stored commits, commits without a link are
+// skipped.
+func (s *GormCommitRepository) SaveCommitLinks(ctx context.Context, commits []domain.Commit) error {
+	links := make([]*CommitLink, 0, len(commits))
+	for i := range commits {
+		if commits[i].Link != nil {
+			links = append(links, ToGormCommitLink(&commits[i]))
+		}
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(links).Error
+}
+
+// ResolveCommitLinks links the unresolved links of a repository to their
+// targets. A revert targets the commit with the named hash, a fixup or squash
+// the latest commit up to its own date with the named subject. Links whose
+// target is not indexed stay unresolved.
+func (s *GormCommitRepository) ResolveCommitLinks(ctx context.Context, repoID uint) (int64, error) {
+	db := s.db.WithContext(ctx)
+
+	var links []CommitLink
+	if err := db.Where("repository_id = ? AND target_id IS NULL", repoID).Order("commit_id").Find(&links).Error; err != nil {
+		return 0, err
+	}
+
+	var resolved int64
+	for _, link := range links {
+		query := db.Model(&Commit{}).Select("id").Where("repository_id = ? AND id <> ?", repoID, link.CommitID)
+
+		switch {
+		case domain.CommitLinkKind(link.Kind) != domain.LinkRevert:
+			query = query.Where(`date <= ? AND (message = ? OR message LIKE ? ESCAPE '\')`, link.Date, link.Target, likePrefix(link.Target+"\n"))
+		case len(link.Target) == fullHashLength:
+			query = query.Where("commit_hash = ?", link.Target)
+		default:
+			query = query.Where(`commit_hash LIKE ? ESCAPE '\'`, likePrefix(link.Target))
+		}
+
+		var targetIDs []uint
+		if err := query.Order("date DESC, id DESC").Limit(1).Pluck("id", &targetIDs).Error; err != nil {
+			return resolved, err
+		}
+		if len(targetIDs) == 0 {
+			continue
+		}
+
+		err := db.Model(&CommitLink{}).Where("commit_id = ?", link.CommitID).Update("target_id", targetIDs[0]).Error
+		if err != nil {
+			return resolved, err
+		}
+		resolved++
+	}
+	return resolved, nil
+}
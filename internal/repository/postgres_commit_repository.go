@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/just-nibble/git-service/internal/domain"
 	"github.com/just-nibble/git-service/pkg/errcodes"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormCommitRepository is a GORM-based implementation of CommitRepository
@@ -31,81 +33,662 @@ func (s *GormCommitRepository) GetCommitByHash(ctx context.Context, hash string)
 	if commit.ID == 0 {
 		return nil, errcodes.ErrNoRecordFound
 	}
-	return commit.ToDomain(), err
+	if err != nil {
+		return nil, err
+	}
+
+	commits := []domain.Commit{*commit.ToDomain()}
+	if err := withLinks(s.db.WithContext(ctx), commits); err != nil {
+		return nil, err
+	}
+	return &commits[0], nil
 }
 
-// SaveCommit stores a repository commit into the database
+// SaveCommit stores a repository commit into the database and counts it in the
+// aggregates of its author in the same transaction
 func (s *GormCommitRepository) SaveCommit(ctx context.Context, commit domain.Commit) (*domain.Commit, error) {
 	if ctx.Err() == context.Canceled {
 		return nil, errcodes.ErrContextCancelled
 	}
 
-	author := Author{}
+	var dbCommit *Commit
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockRepository(tx, commit.RepoID, false); err != nil {
+			return err
+		}
+
+		author := Author{}
+
+		err := tx.Where(&Author{
+			Name:  commit.Author.Name,
+			Email: commit.Author.Email,
+		}).FirstOrCreate(&author).Error
+		if err != nil {
+			return err
+		}
+
+		commit.AuthorID = author.ID
+
+		dbCommit = ToGormCommit(&commit)
+
+		if err := tx.Create(dbCommit).Error; err != nil {
+			return err
+		}
+
+		if commit.Link != nil {
+			commit.ID = dbCommit.ID
+			if err := tx.Create(ToGormCommitLink(&commit)).Error; err != nil {
+				return err
+			}
+		}
+
+		return addCommitStats(tx, dbCommit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	saved := dbCommit.ToDomain()
+	saved.Link = commit.Link
+	return saved, nil
+}
+
+// SaveCommits stores the commits of a page that are not stored yet with their
+// authors and moves the cursor of repo in the same transaction. The cursor is
+// set to the page of repo and to the last stored commit, it keeps the commit of
+// repo when every commit was already stored.
+func (s *GormCommitRepository) SaveCommits(ctx context.Context, repo domain.RepositoryMeta, commits []domain.Commit) ([]domain.Commit, error) {
+	return s.saveCommits(ctx, repo, commits, true)
+}
+
+// ImportCommits stores the commits that are not stored yet with their authors,
+// the cursor of repo is not moved.
+func (s *GormCommitRepository) ImportCommits(ctx context.Context, repo domain.RepositoryMeta, commits []domain.Commit) ([]domain.Commit, error) {
+	return s.saveCommits(ctx, repo, commits, false)
+}
+
+func (s *GormCommitRepository) saveCommits(ctx context.Context, repo domain.RepositoryMeta, commits []domain.Commit, moveCursor bool) ([]domain.Commit, error) {
+	if ctx.Err() == context.Canceled {
+		return nil, errcodes.ErrContextCancelled
+	}
+
+	var saved []domain.Commit
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockRepository(tx, repo.ID, false); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errcodes.ErrNoRecordFound
+			}
+			return err
+		}
+
+		fresh, err := unstoredCommits(tx, commits)
+		if err != nil {
+			return err
+		}
+
+		authorIDs, err := upsertAuthors(tx, fresh)
+		if err != nil {
+			return err
+		}
+
+		dbCommits := make([]*Commit, 0, len(fresh))
+		for i := range fresh {
+			fresh[i].RepoID = repo.ID
+			fresh[i].AuthorID = authorIDs[authorKey{fresh[i].Author.Name, fresh[i].Author.Email}]
+			dbCommits = append(dbCommits, ToGormCommit(&fresh[i]))
+		}
 
-	s.db.WithContext(ctx).Where(&Author{
-		Name:  commit.Author.Name,
-		Email: commit.Author.Email,
-	}).FirstOrCreate(&author)
+		if len(dbCommits) > 0 {
+			res := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(dbCommits, 500)
+			if res.Error != nil {
+				return res.Error
+			}
+			// a commit stored since unstoredCommits would shift the returned ids
+			if res.RowsAffected != int64(len(dbCommits)) {
+				return errcodes.ErrCommitAlreadyAdded
+			}
+		}
 
-	commit.AuthorID = author.ID
+		var links []*CommitLink
+		for i := range fresh {
+			fresh[i].ID = dbCommits[i].ID
+			if fresh[i].Link != nil {
+				links = append(links, ToGormCommitLink(&fresh[i]))
+			}
+		}
+		if len(links) > 0 {
+			if err := tx.CreateInBatches(links, 500).Error; err != nil {
+				return err
+			}
+		}
 
-	dbCommit := ToGormCommit(&commit)
+		if err := addPageStats(tx, dbCommits); err != nil {
+			return err
+		}
 
-	tx := s.db.WithContext(ctx).Create(&dbCommit)
+		if moveCursor {
+			if len(fresh) > 0 {
+				repo.LastFetchedCommit = fresh[len(fresh)-1].Hash
+			}
+			err = tx.Model(&Repository{}).Where("id = ?", repo.ID).Updates(map[string]interface{}{
+				"last_fetched_commit": repo.LastFetchedCommit,
+				"last_page":           repo.LastPage,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
 
-	if tx.Error != nil {
-		if strings.Contains(tx.Error.Error(), `duplicate key value violates unique constraint`) {
-			return nil, tx.Error
+		saved = make([]domain.Commit, 0, len(dbCommits))
+		for i, c := range dbCommits {
+			v := c.ToDomain()
+			v.Link = fresh[i].Link
+			saved = append(saved, *v)
 		}
-		return nil, tx.Error
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return dbCommit.ToDomain(), nil
+
+	return saved, nil
+}
+
+// authorKey identifies an author.
+type authorKey struct {
+	name, email string
+}
+
+// unstoredCommits returns the commits whose hash is not stored, once each.
+func unstoredCommits(tx *gorm.DB, commits []domain.Commit) ([]domain.Commit, error) {
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	hashes := make([]string, 0, len(commits))
+	for _, c := range commits {
+		hashes = append(hashes, c.Hash)
+	}
+
+	var stored []string
+	if err := tx.Model(&Commit{}).Where("commit_hash IN ?", hashes).Pluck("commit_hash", &stored).Error; err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(commits))
+	for _, hash := range stored {
+		seen[hash] = true
+	}
+
+	fresh := make([]domain.Commit, 0, len(commits)-len(stored))
+	for _, c := range commits {
+		if seen[c.Hash] {
+			continue
+		}
+		seen[c.Hash] = true
+		fresh = append(fresh, c)
+	}
+	return fresh, nil
+}
+
+// upsertAuthors stores the authors of commits that are not stored yet and
+// returns the ids of all of them.
+func upsertAuthors(tx *gorm.DB, commits []domain.Commit) (map[authorKey]uint, error) {
+	ids := make(map[authorKey]uint)
+
+	var authors []Author
+	var pairs [][]interface{}
+	for _, c := range commits {
+		key := authorKey{c.Author.Name, c.Author.Email}
+		if _, ok := ids[key]; ok {
+			continue
+		}
+		ids[key] = 0
+		authors = append(authors, Author{Name: key.name, Email: key.email})
+		pairs = append(pairs, []interface{}{key.name, key.email})
+	}
+	if len(authors) == 0 {
+		return ids, nil
+	}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}, {Name: "email"}},
+		DoNothing: true,
+	}).Create(&authors).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// the ids returned by an insert that skips rows do not line up with the
+	// authors, so they are read back
+	var stored []Author
+	if err := tx.Select("id, name, email").Where("(name, email) IN ?", pairs).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	for _, a := range stored {
+		ids[authorKey{a.Name, a.Email}] = a.ID
+	}
+	return ids, nil
 }
 
 // GetAllCommitsByRepositoryName fetches all stores commits by repository name
-func (s *GormCommitRepository) GetCommitsByRepository(ctx context.Context, repo domain.RepositoryMeta, query domain.APIPaging) ([]domain.Commit, error) {
+func (s *GormCommitRepository) GetCommitsByRepository(ctx context.Context, repo domain.RepositoryMeta, query domain.APIPaging, filter domain.CommitFilter) ([]domain.Commit, domain.PagingInfo, error) {
 	var dbCommits []Commit
 
-	var count, queryCount int64
+	var count int64
 
 	queryInfo, offset := getPaginationInfo(query)
+	if err := validatePaging(queryInfo); err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	db := filterCommits(s.db.WithContext(ctx).Model(&Commit{}).Where(&Commit{RepositoryID: repo.ID}), filter)
 
-	db := s.db.WithContext(ctx).Model(&Commit{}).Where(&Commit{RepositoryID: repo.ID})
+	if err := db.Count(&count).Error; err != nil {
+		log.Info().Msgf("count commits error %v", err.Error())
 
-	db.Count(&count)
+		return nil, domain.PagingInfo{}, err
+	}
 
 	db = db.Offset(offset).Limit(queryInfo.Limit).
-		Order(fmt.Sprintf("commit.%s %s", queryInfo.Sort, queryInfo.Direction)).
+		Order(fmt.Sprintf(`"commit".%s %s`, queryInfo.Sort, queryInfo.Direction)).
 		Preload("Author").Find(&dbCommits)
-	db.Count(&queryCount)
 
 	if db.Error != nil {
 		log.Info().Msgf("fetch commits error %v", db.Error.Error())
 
-		return nil, db.Error
+		return nil, domain.PagingInfo{}, db.Error
 	}
 
 	pagingInfo := getPagingInfo(queryInfo, int(count))
 	pagingInfo.Count = len(dbCommits)
 
-	var commits []domain.Commit
+	commits := make([]domain.Commit, 0, len(dbCommits))
 
 	for _, commit := range dbCommits {
-		v := domain.Commit{
-			ID:      commit.ID,
-			Hash:    commit.CommitHash,
-			Message: commit.Message,
-			Author: domain.Author{
-				ID:          commit.AuthorID,
-				Name:        commit.Author.Name,
-				Email:       commit.Author.Email,
-				CommitCount: commit.Author.CommitCount,
-			},
-			RepoID: commit.RepositoryID,
+		v := commit.ToDomain()
+		v.Author.CommitCount = commit.Author.CommitCount
+		commits = append(commits, *v)
+	}
+
+	if err := withLinks(s.db.WithContext(ctx), commits); err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	return commits, pagingInfo, nil
+}
+
+// streamBatch is the number of commits StreamCommits reads at a time on SQLite
+const streamBatch = 500
+
+// streamedCommit is a commit read from a cursor with its author and link.
+type streamedCommit struct {
+	ID           uint
+	CommitHash   string
+	AuthorID     uint
+	RepositoryID uint
+	Message      string
+	Additions    int
+	Deletions    int
+	Type         *string
+	Scope        string
+	Breaking     bool
+	Description  string
+	Date         time.Time
+	FilesFetched bool
+	AuthorName   string
+	AuthorEmail  string
+	LinkKind     *string
+	LinkTarget   *string
+	LinkHash     *string
+}
+
+func (c *streamedCommit) toDomain() domain.Commit {
+	commit := Commit{
+		ID:           c.ID,
+		CommitHash:   c.CommitHash,
+		AuthorID:     c.AuthorID,
+		RepositoryID: c.RepositoryID,
+		Message:      c.Message,
+		Additions:    c.Additions,
+		Deletions:    c.Deletions,
+		Type:         c.Type,
+		Scope:        c.Scope,
+		Breaking:     c.Breaking,
+		Description:  c.Description,
+		Date:         c.Date,
+		FilesFetched: c.FilesFetched,
+		Author:       Author{Name: c.AuthorName, Email: c.AuthorEmail},
+	}
+
+	v := commit.ToDomain()
+	if c.LinkKind != nil {
+		v.Link = &domain.CommitLink{Kind: domain.CommitLinkKind(*c.LinkKind), Target: *c.LinkTarget}
+		if c.LinkHash != nil {
+			v.Link.Hash = *c.LinkHash
+		}
+	}
+	return *v
+}
+
+// StreamCommits reads the commits from a cursor with their author and link
+// joined in, so only one row is held at a time however many commits match.
+func (s *GormCommitRepository) StreamCommits(ctx context.Context, repo domain.RepositoryMeta, query domain.APIPaging, filter domain.CommitFilter, fn func(domain.Commit) error) error {
+	queryInfo, _ := getPaginationInfo(query)
+	if err := validatePaging(queryInfo); err != nil {
+		return err
+	}
+
+	db := filterCommits(s.db.WithContext(ctx).Model(&Commit{}).Where(`"commit".repository_id = ?`, repo.ID), filter).
+		Select(`"commit".id, "commit".commit_hash, "commit".author_id, "commit".repository_id, "commit".message, ` +
+			`"commit".additions, "commit".deletions, "commit".type, "commit".scope, "commit".breaking, "commit".description, ` +
+			`"commit".date, "commit".files_fetched, author.name AS author_name, author.email AS author_email, ` +
+			`commit_link.kind AS link_kind, commit_link.target AS link_target, target.commit_hash AS link_hash`).
+		Joins(`JOIN author ON author.id = "commit".author_id`).
+		Joins(`LEFT JOIN commit_link ON commit_link.commit_id = "commit".id`).
+		Joins(`LEFT JOIN "commit" target ON target.id = commit_link.target_id`).
+		Order(fmt.Sprintf(`"commit".%s %s, "commit".id %s`, queryInfo.Sort, queryInfo.Direction, queryInfo.Direction))
+
+	// SQLite has a single connection, which a cursor would hold until the last
+	// commit is sent, so the commits are read a page at a time there and other
+	// requests run between pages
+	if !isPostgres(s.db) {
+		for offset := 0; ; offset += streamBatch {
+			var page []streamedCommit
+			if err := db.Session(&gorm.Session{}).Offset(offset).Limit(streamBatch).Scan(&page).Error; err != nil {
+				return err
+			}
+			for _, row := range page {
+				if err := fn(row.toDomain()); err != nil {
+					return err
+				}
+			}
+			if len(page) < streamBatch {
+				return nil
+			}
 		}
-		commits = append(commits, v)
 	}
 
+	rows, err := db.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row streamedCommit
+		if err := db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row.toDomain()); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// filterCommits restricts a commit query to the commits matching filter.
+func filterCommits(db *gorm.DB, filter domain.CommitFilter) *gorm.DB {
+	if len(filter.Types) > 0 {
+		db = db.Where(`"commit".type IN ?`, filter.Types)
+	}
+	if filter.Scope != "" {
+		db = db.Where(`"commit".scope = ?`, filter.Scope)
+	}
+	if filter.Breaking != nil {
+		db = db.Where(`"commit".breaking = ?`, *filter.Breaking)
+	}
+	if !filter.After.IsZero() {
+		db = db.Where(`"commit".date > ?`, filter.After)
+	}
+	if !filter.Until.IsZero() {
+		db = db.Where(`"commit".date <= ?`, filter.Until)
+	}
+	return db
+}
+
+// CommitsBetween returns up to limit commits of a repository dated after since,
+// when it is set, up to and including until, newest first.
+func (s *GormCommitRepository) CommitsBetween(ctx context.Context, repoID uint, since, until time.Time, limit int) ([]domain.Commit, error) {
+	db := s.db.WithContext(ctx).Where("repository_id = ? AND date <= ?", repoID, until)
+	if !since.IsZero() {
+		db = db.Where("date > ?", since)
+	}
+
+	var dbCommits []Commit
+	if err := db.Order("date DESC, id DESC").Limit(limit).Preload("Author").Find(&dbCommits).Error; err != nil {
+		return nil, err
+	}
+
+	commits := make([]domain.Commit, 0, len(dbCommits))
+	for _, commit := range dbCommits {
+		commits = append(commits, *commit.ToDomain())
+	}
+	return commits, withLinks(s.db.WithContext(ctx), commits)
+}
+
+// RepositoryCommits returns a page of the commits of a repository in the order
+// they were stored, with everything an archive of them holds.
+func (s *GormCommitRepository) RepositoryCommits(ctx context.Context, repoID, afterID uint, limit int) ([]domain.Commit, error) {
+	var dbCommits []Commit
+	err := s.db.WithContext(ctx).
+		Where("repository_id = ? AND id > ?", repoID, afterID).
+		Order("id").Limit(limit).Preload("Author").Find(&dbCommits).Error
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]domain.Commit, 0, len(dbCommits))
+	index := make(map[uint]int, len(dbCommits))
+	ids := make([]uint, 0, len(dbCommits))
+	for i, commit := range dbCommits {
+		commits = append(commits, *commit.ToDomain())
+		index[commit.ID] = i
+		ids = append(ids, commit.ID)
+	}
+
+	if len(ids) > 0 {
+		var files []CommitFile
+		if err := s.db.WithContext(ctx).Where("commit_id IN ?", ids).Order("id").Find(&files).Error; err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			c := &commits[index[f.CommitID]]
+			c.Files = append(c.Files, domain.CommitFile{Path: f.Path, Additions: f.Additions, Deletions: f.Deletions})
+		}
+	}
+
+	return commits, withLinks(s.db.WithContext(ctx), commits)
+}
+
+// LastCommitID returns the id of the last stored commit of a repository.
+func (s *GormCommitRepository) LastCommitID(ctx context.Context, repoID uint) (uint, error) {
+	var id uint
+	err := s.db.WithContext(ctx).Model(&Commit{}).
+		Where("repository_id = ?", repoID).
+		Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	return id, err
+}
+
+// UnparsedCommits returns up to limit commits stored before commit messages were
+// parsed.
+func (s *GormCommitRepository) UnparsedCommits(ctx context.Context, limit int) ([]domain.Commit, error) {
+	var dbCommits []Commit
+	err := s.db.WithContext(ctx).Where("type IS NULL").Order("id").Limit(limit).Find(&dbCommits).Error
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]domain.Commit, 0, len(dbCommits))
+	for _, commit := range dbCommits {
+		commits = append(commits, *commit.ToDomain())
+	}
+	return commits, nil
+}
+
+// UpdateConventional stores the parsed messages of commits.
+func (s *GormCommitRepository) UpdateConventional(ctx context.Context, commits []domain.Commit) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, c := range commits {
+			err := tx.Model(&Commit{}).Where("id = ?", c.ID).Updates(map[string]interface{}{
+				"type":        c.Conventional.Type,
+				"scope":       c.Conventional.Scope,
+				"breaking":    c.Conventional.Breaking,
+				"description": c.Conventional.Description,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *GormCommitRepository) CommitsWithoutFiles(ctx context.Context, repoID uint, since time.Time, limit int) ([]domain.Commit, error) {
+	var dbCommits []Commit
+	err := s.db.WithContext(ctx).
+		Where("repository_id = ? AND date >= ? AND NOT files_fetched", repoID, since).
+		Order("date DESC").Limit(limit).Find(&dbCommits).Error
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]domain.Commit, 0, len(dbCommits))
+	for _, commit := range dbCommits {
+		commits = append(commits, *commit.ToDomain())
+	}
 	return commits, nil
+}
+
+// SaveCommitFiles stores the files of a commit and adds its line counts to the
+// aggregates of its author, which counted the commit without lines when it was
+// saved.
+func (s *GormCommitRepository) SaveCommitFiles(ctx context.Context, commit domain.Commit) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockRepository(tx, commit.RepoID, false); err != nil {
+			return err
+		}
+
+		var dbCommit Commit
+		err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).First(&dbCommit, commit.ID).Error
+		if err == gorm.ErrRecordNotFound {
+			return errcodes.ErrNoRecordFound
+		}
+		if err != nil {
+			return err
+		}
+		if dbCommit.FilesFetched {
+			return nil
+		}
+
+		// the update below writes the new counts into dbCommit
+		additions, deletions := commit.Additions-dbCommit.Additions, commit.Deletions-dbCommit.Deletions
 
+		err = tx.Model(&dbCommit).Updates(map[string]interface{}{
+			"additions":     commit.Additions,
+			"deletions":     commit.Deletions,
+			"files_fetched": true,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if len(commit.Files) > 0 {
+			files := make([]*CommitFile, 0, len(commit.Files))
+			for i := range commit.Files {
+				files = append(files, ToGormCommitFile(commit.ID, &commit.Files[i]))
+			}
+			if err := tx.CreateInBatches(files, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&AuthorRepositoryStat{}).
+			Where("author_id = ? AND repository_id = ?", dbCommit.AuthorID, dbCommit.RepositoryID).
+			Updates(map[string]interface{}{
+				"additions": gorm.Expr("additions + ?", additions),
+				"deletions": gorm.Expr("deletions + ?", deletions),
+			}).Error
+	})
+}
+
+// PruneCommits deletes a batch of expired commits with their files and links and
+// takes them off the aggregates of their authors. Each batch is a transaction of
+// its own, so pruning a large repository only holds its lock briefly.
+func (s *GormCommitRepository) PruneCommits(ctx context.Context, repoID uint, before time.Time, keep, limit int) (int64, error) {
+	if before.IsZero() && keep == 0 {
+		return 0, nil
+	}
+
+	var pruned int64
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockRepository(tx, repoID, true); err != nil {
+			return err
+		}
+
+		var conds []string
+		var args []interface{}
+		if !before.IsZero() {
+			conds = append(conds, "date < ?")
+			args = append(args, before)
+		}
+		if keep > 0 {
+			// the oldest commit that is kept, the ones ordered before it expire
+			var oldest Commit
+			err := tx.Select("id, date").Where("repository_id = ?", repoID).
+				Order("date DESC, id DESC").Offset(keep - 1).Limit(1).Find(&oldest).Error
+			if err != nil {
+				return err
+			}
+			if oldest.ID != 0 {
+				conds = append(conds, "(date, id) < (?, ?)")
+				args = append(args, oldest.Date, oldest.ID)
+			}
+		}
+		if len(conds) == 0 {
+			return nil
+		}
+
+		var expired []Commit
+		err := tx.Select("id, author_id, repository_id, additions, deletions, date").
+			Where("repository_id = ?", repoID).
+			Where("("+strings.Join(conds, " OR ")+")", args...).
+			Order("date, id").Limit(limit).Find(&expired).Error
+		if err != nil || len(expired) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(expired))
+		for _, c := range expired {
+			ids = append(ids, c.ID)
+		}
+
+		if err := tx.Model(&PullRequestCommit{}).Where("commit_id IN ?", ids).Update("commit_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&CommitLink{}).Where("target_id IN ?", ids).Update("target_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("commit_id IN ?", ids).Delete(&CommitLink{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("commit_id IN ?", ids).Delete(&CommitFile{}).Error; err != nil {
+			return err
+		}
+		// the date bound lets a partitioned table skip the newer partitions
+		err = tx.Where("id IN ? AND date <= ?", ids, expired[len(expired)-1].Date).Delete(&Commit{}).Error
+		if err != nil {
+			return err
+		}
+
+		pruned = int64(len(expired))
+		return removeCommitStats(tx, expired)
+	})
+	if err == gorm.ErrRecordNotFound {
+		return 0, errcodes.ErrNoRecordFound
+	}
+	return pruned, err
 }
@@ -0,0 +1,74 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGormCommitRepository_SaveCommitFiles(t *testing.T) {
+	// Arrange
+	ctx := context.TODO()
+	db := newSQLiteDB(t)
+	repo := saveCommits(t, db, "org/repo", domain.Commit{Hash: "c1", Message: "feat: one", Date: day(1, 0), Author: jane})
+
+	commitRepo := repository.NewGormCommitRepository(db)
+	commit, err := commitRepo.GetCommitByHash(ctx, "c1")
+	require.NoError(t, err)
+
+	commit.Additions, commit.Deletions = 12, 5
+	commit.Files = []domain.CommitFile{
+		{Path: "main.go", Additions: 10, Deletions: 5},
+		{Path: "README.md", Additions: 2},
+	}
+
+	// Act
+	err = commitRepo.SaveCommitFiles(ctx, *commit)
+	// files are only counted once
+	againErr := commitRepo.SaveCommitFiles(ctx, *commit)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, againErr)
+
+	var stat repository.AuthorRepositoryStat
+	require.NoError(t, db.Where("repository_id = ?", repo.ID).First(&stat).Error)
+	assert.Equal(t, 1, stat.CommitCount)
+	assert.Equal(t, 12, stat.Additions)
+	assert.Equal(t, 5, stat.Deletions)
+}
+
+// TestGormCommitRepository_StreamCommits_SQLite tests that streaming commits
+// from SQLite, which has a single connection, lets other queries run meanwhile
+func TestGormCommitRepository_StreamCommits_SQLite(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	db := newSQLiteDB(t)
+	repo := saveCommits(t, db, "org/repo",
+		domain.Commit{Hash: "c1", Message: "feat: one", Date: day(1, 0), Author: jane},
+		domain.Commit{Hash: "c2", Message: "feat: two", Date: day(2, 0), Author: john},
+		domain.Commit{Hash: "c3", Message: "feat: three", Date: day(3, 0), Author: jane},
+	)
+	commitRepo := repository.NewGormCommitRepository(db)
+
+	// Act
+	var streamed []string
+	err := commitRepo.StreamCommits(ctx, *repo, domain.APIPaging{Sort: "date", Direction: "asc"}, domain.CommitFilter{}, func(c domain.Commit) error {
+		found, err := commitRepo.GetCommitByHash(ctx, c.Hash)
+		if err != nil {
+			return err
+		}
+		streamed = append(streamed, found.Hash)
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c1", "c2", "c3"}, streamed)
+}
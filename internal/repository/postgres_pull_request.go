@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+)
+
+// PullRequest is a pull request, the times it was opened and last updated on the
+// git host are OpenedAt and RemoteUpdatedAt.
+type PullRequest struct {
+	ID              uint `gorm:"primaryKey"`
+	RepositoryID    uint `gorm:"uniqueIndex:idx_pull_request_repository_number,priority:1"`
+	Number          int  `gorm:"uniqueIndex:idx_pull_request_repository_number,priority:2"`
+	Title           string
+	AuthorLogin     string `gorm:"index"`
+	State           string `gorm:"index"`
+	BaseBranch      string
+	HeadBranch      string
+	MergeCommitHash string    `gorm:"index"`
+	OpenedAt        time.Time `gorm:"index"`
+	MergedAt        *time.Time
+	ClosedAt        *time.Time
+	RemoteUpdatedAt time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (p *PullRequest) ToDomain() *domain.PullRequest {
+	return &domain.PullRequest{
+		ID:              p.ID,
+		RepoID:          p.RepositoryID,
+		Number:          p.Number,
+		Title:           p.Title,
+		Author:          p.AuthorLogin,
+		State:           domain.PullRequestState(p.State),
+		BaseBranch:      p.BaseBranch,
+		HeadBranch:      p.HeadBranch,
+		MergeCommitHash: p.MergeCommitHash,
+		CreatedAt:       p.OpenedAt,
+		UpdatedAt:       p.RemoteUpdatedAt,
+		MergedAt:        p.MergedAt,
+		ClosedAt:        p.ClosedAt,
+	}
+}
+
+func ToGormPullRequest(p *domain.PullRequest) *PullRequest {
+	return &PullRequest{
+		ID:              p.ID,
+		RepositoryID:    p.RepoID,
+		Number:          p.Number,
+		Title:           p.Title,
+		AuthorLogin:     p.Author,
+		State:           string(p.State),
+		BaseBranch:      p.BaseBranch,
+		HeadBranch:      p.HeadBranch,
+		MergeCommitHash: p.MergeCommitHash,
+		OpenedAt:        p.CreatedAt,
+		MergedAt:        p.MergedAt,
+		ClosedAt:        p.ClosedAt,
+		RemoteUpdatedAt: p.UpdatedAt,
+	}
+}
+
+// PullRequestCommit links a pull request to a commit. CommitID is set once the
+// commit is indexed, commits that never reach an indexed branch stay unlinked.
+type PullRequestCommit struct {
+	PullRequestID uint   `gorm:"primaryKey"`
+	CommitHash    string `gorm:"primaryKey;index"`
+	CommitID      *uint  `gorm:"index"`
+}
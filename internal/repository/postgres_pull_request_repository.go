@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormPullRequestRepository is a GORM-based implementation of PullRequestRepository
+type GormPullRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewGormPullRequestRepository initializes a new GormPullRequestRepository
+func NewGormPullRequestRepository(db *gorm.DB) PullRequestRepository {
+	return &GormPullRequestRepository{db: db}
+}
+
+func (s *GormPullRequestRepository) LastPullRequestUpdate(ctx context.Context, repoID uint) (time.Time, error) {
+	var last *time.Time
+	err := s.db.WithContext(ctx).Model(&PullRequest{}).Where("repository_id = ?", repoID).
+		Select("MAX(remote_updated_at)").Scan(&last).Error
+	if err != nil || last == nil {
+		return time.Time{}, err
+	}
+	return *last, nil
+}
+
+func (s *GormPullRequestRepository) SavePullRequest(ctx context.Context, pr domain.PullRequest) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		dbPR := ToGormPullRequest(&pr)
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "repository_id"}, {Name: "number"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"title", "author_login", "state", "base_branch", "head_branch", "merge_commit_hash",
+				"opened_at", "merged_at", "closed_at", "remote_updated_at", "updated_at",
+			}),
+		}).Create(dbPR).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("pull_request_id = ?", dbPR.ID).Delete(&PullRequestCommit{}).Error; err != nil {
+			return err
+		}
+
+		if len(pr.Commits) == 0 {
+			return nil
+		}
+
+		links := make([]PullRequestCommit, 0, len(pr.Commits))
+		for _, hash := range pr.Commits {
+			links = append(links, PullRequestCommit{PullRequestID: dbPR.ID, CommitHash: hash})
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(links, 500).Error; err != nil {
+			return err
+		}
+
+		return linkCommits(tx, pr.RepoID, "pull_request_commit.pull_request_id = ?", dbPR.ID).Error
+	})
+}
+
+func (s *GormPullRequestRepository) LinkPullRequestCommits(ctx context.Context, repoID uint) (int64, error) {
+	db := s.db.WithContext(ctx)
+	res := linkCommits(db, repoID, "pull_request_commit.pull_request_id IN (?)", db.Model(&PullRequest{}).Select("id").Where("repository_id = ?", repoID))
+	return res.RowsAffected, res.Error
+}
+
+// linkCommits links the unlinked pull request commits matched by query to the
+// commit of the repository with the same hash.
+func linkCommits(tx *gorm.DB, repoID uint, query interface{}, args ...interface{}) *gorm.DB {
+	commit := tx.Session(&gorm.Session{NewDB: true}).Model(&Commit{}).Select(`"commit".id`).
+		Where(`"commit".repository_id = ? AND "commit".commit_hash = pull_request_commit.commit_hash`, repoID)
+
+	return tx.Model(&PullRequestCommit{}).Where(query, args...).
+		Where("pull_request_commit.commit_id IS NULL AND EXISTS (?)", commit).
+		Update("commit_id", commit)
+}
+
+// unlinkRepositoryCommits unlinks pull requests from the commits of a repository
+// before the commits are deleted.
+func unlinkRepositoryCommits(tx *gorm.DB, repoID uint) error {
+	return tx.Model(&PullRequestCommit{}).
+		Where("commit_id IN (?)", tx.Session(&gorm.Session{NewDB: true}).Model(&Commit{}).Select("id").Where("repository_id = ?", repoID)).
+		Update("commit_id", nil).Error
+}
+
+// GetPullRequests returns a page of the pull requests of a repository, most
+// recently opened first.
+func (s *GormPullRequestRepository) GetPullRequests(ctx context.Context, repoID uint, state domain.PullRequestState, query domain.APIPaging) ([]domain.PullRequest, domain.PagingInfo, error) {
+	var dbPRs []PullRequest
+	var count int64
+
+	queryInfo, offset := getPaginationInfo(query)
+
+	db := s.db.WithContext(ctx).Model(&PullRequest{}).Where("repository_id = ?", repoID)
+	if state != "" {
+		db = db.Where("state = ?", state)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	if err := db.Order("opened_at DESC, number DESC").Offset(offset).Limit(queryInfo.Limit).Find(&dbPRs).Error; err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	pagingInfo := getPagingInfo(queryInfo, int(count))
+	pagingInfo.Count = len(dbPRs)
+
+	return toDomainPullRequests(dbPRs), pagingInfo, nil
+}
+
+func (s *GormPullRequestRepository) PullRequestsForCommit(ctx context.Context, repoID uint, hash string) ([]domain.PullRequest, error) {
+	var dbPRs []PullRequest
+	err := s.db.WithContext(ctx).Where("repository_id = ?", repoID).
+		Where("merge_commit_hash = ? OR id IN (?)", hash, s.db.Model(&PullRequestCommit{}).Select("pull_request_id").Where("commit_hash = ?", hash)).
+		Order("opened_at, number").Find(&dbPRs).Error
+	if err != nil {
+		return nil, err
+	}
+	return toDomainPullRequests(dbPRs), nil
+}
+
+func toDomainPullRequests(dbPRs []PullRequest) []domain.PullRequest {
+	prs := make([]domain.PullRequest, 0, len(dbPRs))
+	for _, p := range dbPRs {
+		prs = append(prs, *p.ToDomain())
+	}
+	return prs
+}
+
+// deleteRepositoryPullRequests deletes the pull requests of a repository and
+// their commits.
+func deleteRepositoryPullRequests(tx *gorm.DB, repoID uint) error {
+	prs := tx.Session(&gorm.Session{NewDB: true}).Model(&PullRequest{}).Select("id").Where("repository_id = ?", repoID)
+	err := tx.Where("pull_request_id IN (?)", prs).
+		Delete(&PullRequestCommit{}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Where("repository_id = ?", repoID).Delete(&PullRequest{}).Error
+}
@@ -24,11 +24,14 @@ type Repository struct {
 	LastPage          int
 	LastFetchedCommit string
 	Index             bool
+	RetentionDays     int `gorm:"not null;default:0"`
+	RetentionCommits  int `gorm:"not null;default:0"`
 }
 
 func (pr *Repository) ToDomain() *domain.RepositoryMeta {
 	return &domain.RepositoryMeta{
 		ID:                pr.ID,
+		OwnerName:         pr.OwnerName,
 		Name:              pr.Name,
 		Description:       pr.Description,
 		URL:               pr.URL,
@@ -42,12 +45,14 @@ func (pr *Repository) ToDomain() *domain.RepositoryMeta {
 		LastFetchedCommit: pr.LastFetchedCommit,
 		Index:             pr.Index,
 		LastPage:          pr.LastPage,
+		Retention:         domain.RetentionPolicy{Days: pr.RetentionDays, Commits: pr.RetentionCommits},
 	}
 }
 
 func ToGormRepo(r *domain.RepositoryMeta) *Repository {
 	return &Repository{
 		ID:                r.ID,
+		OwnerName:         r.OwnerName,
 		Name:              r.Name,
 		Description:       r.Description,
 		URL:               r.URL,
@@ -61,5 +66,7 @@ func ToGormRepo(r *domain.RepositoryMeta) *Repository {
 		LastFetchedCommit: r.LastFetchedCommit,
 		Index:             r.Index,
 		LastPage:          r.LastPage,
+		RetentionDays:     r.Retention.Days,
+		RetentionCommits:  r.Retention.Commits,
 	}
 }
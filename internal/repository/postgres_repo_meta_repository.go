@@ -73,9 +73,118 @@ func (r *GormRepositoryMetaRepository) UpdateRepoMetadata(ctx context.Context, r
 	return dbRepo.ToDomain(), nil
 }
 
-func (r *GormRepositoryMetaRepository) UpdateRepositoryStatus(ctx context.Context, isFetching bool) error {
+// UpdateRepositoryStatus sets the index flag of a single repository. It is kept
+// separate from UpdateRepoMetadata because GORM skips zero values on struct updates.
+func (r *GormRepositoryMetaRepository) UpdateRepositoryStatus(ctx context.Context, repoID uint, isFetching bool) error {
 	return r.db.WithContext(ctx).Model(&Repository{}).
-		Where("index = ?", true).
+		Where("id = ?", repoID).
 		Update("index", isFetching).
 		Error
 }
+
+// UpdateRetention sets the retention policy of a repository. Like the index flag
+// it is kept separate from UpdateRepoMetadata so that limits can be cleared.
+func (r *GormRepositoryMetaRepository) UpdateRetention(ctx context.Context, repoID uint, policy domain.RetentionPolicy) error {
+	res := r.db.WithContext(ctx).Model(&Repository{}).
+		Where("id = ?", repoID).
+		Updates(map[string]interface{}{
+			"retention_days":    policy.Days,
+			"retention_commits": policy.Commits,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errcodes.ErrNoRecordFound
+	}
+	return nil
+}
+
+// ResetRepoMeta deletes the stored commits of a repository and rewinds its
+// cursor so that it is indexed again from the start.
+func (r *GormRepositoryMetaRepository) ResetRepoMeta(ctx context.Context, repoID uint) (*domain.RepositoryMeta, error) {
+	var repo Repository
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteRepositoryCommits(tx, repoID); err != nil {
+			return err
+		}
+
+		if err := deleteRepositoryStats(tx, repoID); err != nil {
+			return err
+		}
+
+		err := tx.Model(&Repository{}).Where("id = ?", repoID).Updates(map[string]interface{}{
+			"last_page":           0,
+			"last_fetched_commit": "",
+			"index":               true,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.First(&repo, repoID).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		return nil, errcodes.ErrNoRecordFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.ToDomain(), nil
+}
+
+// DeleteRepoMeta deletes a repository together with its commits, tags, releases
+// and pull requests.
+func (r *GormRepositoryMetaRepository) DeleteRepoMeta(ctx context.Context, repoID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteRepositoryCommits(tx, repoID); err != nil {
+			return err
+		}
+
+		if err := deleteRepositoryStats(tx, repoID); err != nil {
+			return err
+		}
+
+		if err := tx.Where("repository_id = ?", repoID).Delete(&Tag{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("repository_id = ?", repoID).Delete(&Release{}).Error; err != nil {
+			return err
+		}
+
+		if err := deleteRepositoryPullRequests(tx, repoID); err != nil {
+			return err
+		}
+
+		res := tx.Delete(&Repository{}, repoID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errcodes.ErrNoRecordFound
+		}
+		return nil
+	})
+}
+
+// deleteRepositoryCommits deletes the commits of a repository with their files
+// and links, and unlinks them from pull requests.
+func deleteRepositoryCommits(tx *gorm.DB, repoID uint) error {
+	if err := unlinkRepositoryCommits(tx, repoID); err != nil {
+		return err
+	}
+
+	if err := tx.Where("repository_id = ?", repoID).Delete(&CommitLink{}).Error; err != nil {
+		return err
+	}
+
+	commits := tx.Session(&gorm.Session{NewDB: true}).Model(&Commit{}).Select("id").Where("repository_id = ?", repoID)
+	if err := tx.Where("commit_id IN (?)", commits).Delete(&CommitFile{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Where("repository_id = ?", repoID).Delete(&Commit{}).Error
+}
@@ -0,0 +1,363 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"gorm.io/gorm"
+)
+
+// GormStatsRepository is a GORM-based implementation of StatsRepository. The
+// aggregates are computed by the database using the (repository_id, date)
+// index of the commit table. Databases other than Postgres have no time zone
+// support, activity is bucketed in Go for them.
+type GormStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewGormStatsRepository initializes a new GormStatsRepository
+func NewGormStatsRepository(db *gorm.DB) StatsRepository {
+	return &GormStatsRepository{db: db}
+}
+
+type bucketRow struct {
+	AuthorID uint
+	Bucket   time.Time
+	Count    int
+}
+
+// localDate is the commit date as wall clock time in the query location.
+const localDate = `("commit".date AT TIME ZONE ?)`
+
+func (s *GormStatsRepository) commits(ctx context.Context, query domain.ActivityQuery) *gorm.DB {
+	return s.db.WithContext(ctx).Table("commit").
+		Where(`"commit".repository_id = ? AND "commit".date >= ? AND "commit".date < ?`, query.RepoID, query.Since, query.Until)
+}
+
+// commitDates returns the author and date of the commits selected by db, for
+// databases that cannot aggregate in the query location.
+func commitDates(db *gorm.DB) ([]bucketRow, error) {
+	var rows []bucketRow
+	err := db.Select(`"commit".author_id, "commit".date AS bucket, 1 AS count`).Scan(&rows).Error
+	return rows, err
+}
+
+// activityBuckets counts the commits selected by db per bucket, and per author
+// as well when byAuthor is set, ordered by bucket.
+func (s *GormStatsRepository) activityBuckets(db *gorm.DB, query domain.ActivityQuery, byAuthor bool) ([]bucketRow, error) {
+	if !isPostgres(s.db) {
+		dates, err := commitDates(db)
+		if err != nil {
+			return nil, err
+		}
+
+		type key struct {
+			authorID uint
+			start    int64
+		}
+		index := make(map[key]int)
+		rows := []bucketRow{}
+		for _, d := range dates {
+			row := bucketRow{Bucket: query.Interval.Truncate(d.Bucket.In(query.Location))}
+			if byAuthor {
+				row.AuthorID = d.AuthorID
+			}
+
+			k := key{row.AuthorID, row.Bucket.Unix()}
+			i, ok := index[k]
+			if !ok {
+				i = len(rows)
+				index[k] = i
+				rows = append(rows, row)
+			}
+			rows[i].Count++
+		}
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].Bucket.Before(rows[j].Bucket) })
+		return rows, nil
+	}
+
+	selects, group := "date_trunc(?, "+localDate+") AS bucket, COUNT(*) AS count", "bucket"
+	if byAuthor {
+		selects, group = `"commit".author_id, `+selects, `"commit".author_id, bucket`
+	}
+
+	var rows []bucketRow
+	err := db.Select(selects, string(query.Interval), query.Location.String()).Group(group).Order("bucket").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Bucket = inLocation(rows[i].Bucket, query.Location)
+	}
+	return rows, nil
+}
+
+func (s *GormStatsRepository) CommitActivity(ctx context.Context, query domain.ActivityQuery) ([]domain.ActivityBucket, error) {
+	rows, err := s.activityBuckets(s.commits(ctx, query), query, false)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]domain.ActivityBucket, 0, len(rows))
+	for _, row := range rows {
+		buckets = append(buckets, domain.ActivityBucket{Start: row.Bucket, Count: row.Count})
+	}
+	return buckets, nil
+}
+
+func (s *GormStatsRepository) PunchCard(ctx context.Context, query domain.ActivityQuery) ([]domain.PunchCardCell, error) {
+	var cells []domain.PunchCardCell
+
+	if !isPostgres(s.db) {
+		dates, err := commitDates(s.commits(ctx, query))
+		if err != nil {
+			return nil, err
+		}
+
+		var counts [7][24]int
+		for _, d := range dates {
+			local := d.Bucket.In(query.Location)
+			counts[local.Weekday()][local.Hour()]++
+		}
+		for weekday := range counts {
+			for hour, count := range counts[weekday] {
+				if count > 0 {
+					cells = append(cells, domain.PunchCardCell{Weekday: weekday, Hour: hour, Count: count})
+				}
+			}
+		}
+		return cells, nil
+	}
+
+	tz := query.Location.String()
+	err := s.commits(ctx, query).
+		Select("CAST(EXTRACT(DOW FROM "+localDate+") AS INTEGER) AS weekday, CAST(EXTRACT(HOUR FROM "+localDate+") AS INTEGER) AS hour, COUNT(*) AS count", tz, tz).
+		Group("weekday, hour").
+		Order("weekday, hour").
+		Scan(&cells).Error
+	return cells, err
+}
+
+func (s *GormStatsRepository) AuthorActivity(ctx context.Context, query domain.ActivityQuery) ([]domain.AuthorActivity, error) {
+	var top []struct {
+		AuthorID uint
+		Name     string
+		Email    string
+		Count    int
+	}
+
+	err := s.commits(ctx, query).
+		Select(`"commit".author_id, author.name, author.email, COUNT(*) AS count`).
+		Joins(`JOIN author ON author.id = "commit".author_id`).
+		Group(`"commit".author_id, author.name, author.email`).
+		Order(`count DESC, "commit".author_id`).
+		Limit(query.Authors).
+		Scan(&top).Error
+	if err != nil || len(top) == 0 {
+		return nil, err
+	}
+
+	authorIDs := make([]uint, 0, len(top))
+	for _, a := range top {
+		authorIDs = append(authorIDs, a.AuthorID)
+	}
+
+	rows, err := s.activityBuckets(s.commits(ctx, query).Where(`"commit".author_id IN ?`, authorIDs), query, true)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make(map[uint][]domain.ActivityBucket, len(top))
+	for _, row := range rows {
+		buckets[row.AuthorID] = append(buckets[row.AuthorID], domain.ActivityBucket{Start: row.Bucket, Count: row.Count})
+	}
+
+	authors := make([]domain.AuthorActivity, 0, len(top))
+	for _, a := range top {
+		authors = append(authors, domain.AuthorActivity{
+			Author:  domain.Author{ID: a.AuthorID, Name: a.Name, Email: a.Email},
+			Count:   a.Count,
+			Buckets: buckets[a.AuthorID],
+		})
+	}
+	return authors, nil
+}
+
+// inLocation reads a timestamp without time zone, which the driver returns as
+// UTC, as wall clock time in loc.
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// deployments selects the at and cutoff times of the deployments of a repository
+// as the deployment table.
+func (s *GormStatsRepository) deployments(ctx context.Context, query domain.DeliveryQuery) *gorm.DB {
+	return s.db.WithContext(ctx).Table("(?) AS deployment", deploymentSource(s.db, query))
+}
+
+func deploymentSource(db *gorm.DB, query domain.DeliveryQuery) *gorm.DB {
+	switch query.Source {
+	case domain.DeploymentTags:
+		return db.Table("tag").Select("tag.date AS at, tag.date AS cutoff").
+			Where("tag.repository_id = ?", query.RepoID)
+	case domain.DeploymentBranch:
+		return db.Table("pull_request").Select("pull_request.merged_at AS at, pull_request.merged_at AS cutoff").
+			Where("pull_request.repository_id = ? AND pull_request.state = ? AND pull_request.base_branch = ?", query.RepoID, domain.PullRequestMerged, query.Branch)
+	default:
+		// releases of tags that are not synced ship the commits up to their publication
+		return db.Table("release").Select("release.published_at AS at, COALESCE(tag.date, release.published_at) AS cutoff").
+			Joins("LEFT JOIN tag ON tag.repository_id = release.repository_id AND tag.name = release.tag_name").
+			Where("release.repository_id = ? AND NOT release.prerelease", query.RepoID)
+	}
+}
+
+func (s *GormStatsRepository) Deployments(ctx context.Context, query domain.DeliveryQuery) ([]domain.Deployment, error) {
+	var previous []domain.Deployment
+	err := s.deployments(ctx, query).Where("deployment.at < ?", query.Since).Order("deployment.at DESC").Limit(1).Scan(&previous).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var deployments []domain.Deployment
+	err = s.deployments(ctx, query).Where("deployment.at >= ? AND deployment.at < ?", query.Since, query.Until).Order("deployment.at").Scan(&deployments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return append(previous, deployments...), nil
+}
+
+func (s *GormStatsRepository) DeliveredCommits(ctx context.Context, query domain.DeliveryQuery, after, until time.Time) ([]domain.DeliveredCommit, error) {
+	var commits []domain.DeliveredCommit
+
+	failure := `COALESCE("commit".type = 'revert', FALSE) OR LOWER("commit".message) LIKE 'revert "%'`
+	args := []interface{}{}
+	if query.FailurePattern != "" {
+		failure += ` OR LOWER("commit".message) LIKE ? ESCAPE '\'`
+		args = append(args, query.FailurePattern)
+	}
+
+	err := s.db.WithContext(ctx).Table("commit").
+		Select(`"commit".date AS date, (`+failure+") AS failure", args...).
+		Where(`"commit".repository_id = ? AND "commit".date > ? AND "commit".date <= ?`, query.RepoID, after, until).
+		Order(`"commit".date`).
+		Scan(&commits).Error
+	if err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// fileChanges selects the file changes under the query path of commits made
+// since the query start.
+func (s *GormStatsRepository) fileChanges(ctx context.Context, query domain.OwnershipQuery) *gorm.DB {
+	db := s.db.WithContext(ctx).Table("commit_file").
+		Joins(`JOIN "commit" ON "commit".id = commit_file.commit_id`).
+		Where(`"commit".repository_id = ? AND "commit".date >= ?`, query.RepoID, query.Since)
+	if query.Path != "" {
+		db = db.Where(`commit_file.path LIKE ? ESCAPE '\'`, likePrefix(query.Path))
+	}
+	return db
+}
+
+// likePrefix returns a LIKE pattern, escaped with `\`, matching the strings
+// that start with prefix.
+func likePrefix(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix) + "%"
+}
+
+func (s *GormStatsRepository) DirectoryChurn(ctx context.Context, query domain.OwnershipQuery) ([]domain.DirectoryChurn, error) {
+	var rows []struct {
+		Directory string
+		AuthorID  uint
+		Name      string
+		Email     string
+		Changes   int
+		Churn     int
+	}
+
+	err := s.fileChanges(ctx, query).
+		Joins(`JOIN author ON author.id = "commit".author_id`).
+		Select("commit_file.directory, author.id AS author_id, author.name, author.email, " +
+			"COUNT(*) AS changes, SUM(commit_file.additions + commit_file.deletions) AS churn").
+		Group("commit_file.directory, author.id, author.name, author.email").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	churn := make([]domain.DirectoryChurn, 0, len(rows))
+	for _, row := range rows {
+		churn = append(churn, domain.DirectoryChurn{
+			Directory: row.Directory,
+			Author:    domain.Author{ID: row.AuthorID, Name: row.Name, Email: row.Email},
+			Changes:   row.Changes,
+			Churn:     row.Churn,
+		})
+	}
+	return churn, nil
+}
+
+func (s *GormStatsRepository) Hotspots(ctx context.Context, query domain.OwnershipQuery) ([]domain.Hotspot, error) {
+	var hotspots []domain.Hotspot
+
+	err := s.fileChanges(ctx, query).
+		Select("commit_file.path, COUNT(*) AS commits, SUM(commit_file.additions + commit_file.deletions) AS churn").
+		Group("commit_file.path").
+		Order("SUM(commit_file.additions + commit_file.deletions) DESC, commit_file.path").
+		Limit(query.Limit).
+		Scan(&hotspots).Error
+	if err != nil {
+		return nil, err
+	}
+	return hotspots, nil
+}
+
+func (s *GormStatsRepository) Reverts(ctx context.Context, query domain.ActivityQuery) ([]domain.Revert, error) {
+	db := s.db.WithContext(ctx)
+
+	var links []CommitLink
+	err := db.Where("repository_id = ? AND kind = ? AND date >= ? AND date < ?", query.RepoID, domain.LinkRevert, query.Since, query.Until).
+		Order("date DESC, commit_id DESC").Find(&links).Error
+	if err != nil || len(links) == 0 {
+		return []domain.Revert{}, err
+	}
+
+	ids := make([]uint, 0, 2*len(links))
+	for _, l := range links {
+		ids = append(ids, l.CommitID)
+		if l.TargetID != nil {
+			ids = append(ids, *l.TargetID)
+		}
+	}
+
+	var dbCommits []Commit
+	if err := db.Preload("Author").Find(&dbCommits, ids).Error; err != nil {
+		return nil, err
+	}
+	commits := make(map[uint]*domain.Commit, len(dbCommits))
+	for _, c := range dbCommits {
+		commits[c.ID] = c.ToDomain()
+	}
+
+	reverts := make([]domain.Revert, 0, len(links))
+	for _, l := range links {
+		commit, ok := commits[l.CommitID]
+		if !ok {
+			continue
+		}
+		revert := domain.Revert{Commit: *commit}
+		revert.Commit.Link = &domain.CommitLink{Kind: domain.LinkRevert, Target: l.Target}
+		if l.TargetID != nil {
+			if target, ok := commits[*l.TargetID]; ok {
+				revert.Reverted = target
+				revert.Commit.Link.Hash = target.Hash
+			}
+		}
+		reverts = append(reverts, revert)
+	}
+	return reverts, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+)
+
+type Tag struct {
+	ID           uint      `gorm:"primaryKey"`
+	RepositoryID uint      `gorm:"uniqueIndex:idx_tag_repository_name,priority:1"`
+	Name         string    `gorm:"uniqueIndex:idx_tag_repository_name,priority:2"`
+	CommitHash   string    `gorm:"index"`
+	Date         time.Time `gorm:"index"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (t *Tag) ToDomain() *domain.Tag {
+	return &domain.Tag{
+		ID:     t.ID,
+		RepoID: t.RepositoryID,
+		Name:   t.Name,
+		Hash:   t.CommitHash,
+		Date:   t.Date,
+	}
+}
+
+func ToGormTag(t *domain.Tag) *Tag {
+	return &Tag{
+		ID:           t.ID,
+		RepositoryID: t.RepoID,
+		Name:         t.Name,
+		CommitHash:   t.Hash,
+		Date:         t.Date,
+	}
+}
+
+type Release struct {
+	ID           uint   `gorm:"primaryKey"`
+	RepositoryID uint   `gorm:"uniqueIndex:idx_release_repository_tag,priority:1"`
+	TagName      string `gorm:"uniqueIndex:idx_release_repository_tag,priority:2"`
+	Name         string
+	Body         string
+	Prerelease   bool
+	PublishedAt  time.Time `gorm:"index"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (r *Release) ToDomain() *domain.Release {
+	return &domain.Release{
+		ID:          r.ID,
+		RepoID:      r.RepositoryID,
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Body,
+		Prerelease:  r.Prerelease,
+		PublishedAt: r.PublishedAt,
+	}
+}
+
+func ToGormRelease(r *domain.Release) *Release {
+	return &Release{
+		ID:           r.ID,
+		RepositoryID: r.RepoID,
+		TagName:      r.TagName,
+		Name:         r.Name,
+		Body:         r.Body,
+		Prerelease:   r.Prerelease,
+		PublishedAt:  r.PublishedAt,
+	}
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormTagRepository is a GORM-based implementation of TagRepository
+type GormTagRepository struct {
+	db *gorm.DB
+}
+
+// NewGormTagRepository initializes a new GormTagRepository
+func NewGormTagRepository(db *gorm.DB) TagRepository {
+	return &GormTagRepository{db: db}
+}
+
+func (s *GormTagRepository) AllTags(ctx context.Context, repoID uint) ([]domain.Tag, error) {
+	var dbTags []Tag
+	if err := s.db.WithContext(ctx).Where("repository_id = ?", repoID).Find(&dbTags).Error; err != nil {
+		return nil, err
+	}
+
+	tags := make([]domain.Tag, 0, len(dbTags))
+	for _, t := range dbTags {
+		tags = append(tags, *t.ToDomain())
+	}
+	return tags, nil
+}
+
+// GetTags returns a page of the tags of a repository, newest first. Tags can not
+// be sorted by other columns.
+func (s *GormTagRepository) GetTags(ctx context.Context, repoID uint, query domain.APIPaging) ([]domain.Tag, domain.PagingInfo, error) {
+	var dbTags []Tag
+	var count int64
+
+	queryInfo, offset := getPaginationInfo(query)
+
+	db := s.db.WithContext(ctx).Model(&Tag{}).Where("repository_id = ?", repoID)
+	if err := db.Count(&count).Error; err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	if err := db.Order("date DESC, name").Offset(offset).Limit(queryInfo.Limit).Find(&dbTags).Error; err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	pagingInfo := getPagingInfo(queryInfo, int(count))
+	pagingInfo.Count = len(dbTags)
+
+	tags := make([]domain.Tag, 0, len(dbTags))
+	for _, t := range dbTags {
+		tags = append(tags, *t.ToDomain())
+	}
+	return tags, pagingInfo, nil
+}
+
+func (s *GormTagRepository) TagByName(ctx context.Context, repoID uint, name string) (*domain.Tag, error) {
+	var tag Tag
+	err := s.db.WithContext(ctx).Where("repository_id = ? AND name = ?", repoID, name).First(&tag).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errcodes.ErrNoRecordFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tag.ToDomain(), nil
+}
+
+// ReplaceTags upserts tags and then deletes the tags of the repository that
+// were not part of them, recognized by not having been touched by the upsert.
+func (s *GormTagRepository) ReplaceTags(ctx context.Context, repoID uint, tags []domain.Tag) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		start := time.Now()
+
+		if len(tags) > 0 {
+			dbTags := make([]*Tag, 0, len(tags))
+			for i := range tags {
+				tags[i].RepoID = repoID
+				dbTags = append(dbTags, ToGormTag(&tags[i]))
+			}
+
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "repository_id"}, {Name: "name"}},
+				DoUpdates: clause.AssignmentColumns([]string{"commit_hash", "date", "updated_at"}),
+			}).CreateInBatches(dbTags, 500).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("repository_id = ? AND updated_at < ?", repoID, start).Delete(&Tag{}).Error
+	})
+}
+
+// GetReleases returns a page of the releases of a repository, most recently
+// published first.
+func (s *GormTagRepository) GetReleases(ctx context.Context, repoID uint, query domain.APIPaging) ([]domain.Release, domain.PagingInfo, error) {
+	var dbReleases []Release
+	var count int64
+
+	queryInfo, offset := getPaginationInfo(query)
+
+	db := s.db.WithContext(ctx).Model(&Release{}).Where("repository_id = ?", repoID)
+	if err := db.Count(&count).Error; err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	if err := db.Order("published_at DESC, tag_name").Offset(offset).Limit(queryInfo.Limit).Find(&dbReleases).Error; err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	pagingInfo := getPagingInfo(queryInfo, int(count))
+	pagingInfo.Count = len(dbReleases)
+
+	releases := make([]domain.Release, 0, len(dbReleases))
+	for _, r := range dbReleases {
+		releases = append(releases, *r.ToDomain())
+	}
+	return releases, pagingInfo, nil
+}
+
+// ReplaceReleases upserts releases and deletes the other releases of the
+// repository, the same way as ReplaceTags.
+func (s *GormTagRepository) ReplaceReleases(ctx context.Context, repoID uint, releases []domain.Release) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		start := time.Now()
+
+		if len(releases) > 0 {
+			dbReleases := make([]*Release, 0, len(releases))
+			for i := range releases {
+				releases[i].RepoID = repoID
+				dbReleases = append(dbReleases, ToGormRelease(&releases[i]))
+			}
+
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "repository_id"}, {Name: "tag_name"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name", "body", "prerelease", "published_at", "updated_at"}),
+			}).CreateInBatches(dbReleases, 500).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("repository_id = ? AND updated_at < ?", repoID, start).Delete(&Release{}).Error
+	})
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+)
+
+// PullRequestRepository defines an interface for the pull requests of repositories
+type PullRequestRepository interface {
+	// LastPullRequestUpdate returns the latest update time of the stored pull
+	// requests of a repository, zero when none are stored
+	LastPullRequestUpdate(ctx context.Context, repoID uint) (time.Time, error)
+	// SavePullRequest upserts a pull request and replaces its commits
+	SavePullRequest(ctx context.Context, pr domain.PullRequest) error
+	// LinkPullRequestCommits links the commits of pull requests that were indexed
+	// after the pull request was stored
+	LinkPullRequestCommits(ctx context.Context, repoID uint) (int64, error)
+	// GetPullRequests returns a page of pull requests, an empty state matches all
+	GetPullRequests(ctx context.Context, repoID uint, state domain.PullRequestState, query domain.APIPaging) ([]domain.PullRequest, domain.PagingInfo, error)
+	// PullRequestsForCommit returns the pull requests that contain or merged a commit
+	PullRequestsForCommit(ctx context.Context, repoID uint, hash string) ([]domain.PullRequest, error)
+}
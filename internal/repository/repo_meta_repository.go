@@ -12,5 +12,10 @@ type RepositoryMetaRepository interface {
 	UpdateRepoMetadata(ctx context.Context, repo domain.RepositoryMeta) (*domain.RepositoryMeta, error)
 	RepoMeta(ctx context.Context, name string) (*domain.RepositoryMeta, error)
 	AllRepoMeta(ctx context.Context) ([]domain.RepositoryMeta, error)
-	UpdateRepositoryStatus(ctx context.Context, isFetching bool) error
+	UpdateRepositoryStatus(ctx context.Context, repoID uint, isFetching bool) error
+	// UpdateRetention sets the retention policy of a repository, zero values
+	// included
+	UpdateRetention(ctx context.Context, repoID uint, policy domain.RetentionPolicy) error
+	ResetRepoMeta(ctx context.Context, repoID uint) (*domain.RepositoryMeta, error)
+	DeleteRepoMeta(ctx context.Context, repoID uint) error
 }
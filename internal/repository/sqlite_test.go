@@ -0,0 +1,264 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newSQLiteDB returns a migrated in memory database
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db := database.NewSQLiteDatabase(":memory:")
+	require.NoError(t, db.ConnectDB(context.TODO()))
+	require.NoError(t, db.Migrate(context.TODO()))
+	t.Cleanup(func() { db.CloseDb(context.TODO()) })
+
+	return db.GetDB()
+}
+
+// saveCommits stores a repository and its commits, the commits get their repository id set
+func saveCommits(t *testing.T, db *gorm.DB, name string, commits ...domain.Commit) *domain.RepositoryMeta {
+	t.Helper()
+
+	repo, err := repository.NewGormRepositoryMetaRepository(db).SaveRepoMetadata(context.TODO(), domain.RepositoryMeta{Name: name})
+	require.NoError(t, err)
+
+	commitRepo := repository.NewGormCommitRepository(db)
+	for _, c := range commits {
+		c.RepoID = repo.ID
+		c.Conventional = domain.ParseConventionalCommit(c.Message)
+		c.Link = domain.ParseCommitLink(c.Message)
+		_, err := commitRepo.SaveCommit(context.TODO(), c)
+		require.NoError(t, err)
+	}
+	return repo
+}
+
+func day(d, h int) time.Time {
+	return time.Date(2024, 3, d, h, 0, 0, 0, time.UTC)
+}
+
+var (
+	jane = domain.Author{Name: "Jane Doe", Email: "jane@example.com"}
+	john = domain.Author{Name: "John Doe", Email: "john@example.com"}
+)
+
+// TestSQLite_Commits tests listing, filtering and linking commits
+func TestSQLite_Commits(t *testing.T) {
+	// Arrange
+	db := newSQLiteDB(t)
+	hash := "0123456789abcdef0123456789abcdef01234567"
+	repo := saveCommits(t, db, "org/repo",
+		domain.Commit{Hash: "bbb", Message: "Revert \"feat: cache\"\n\nThis reverts commit " + hash + ".", Date: day(3, 0), Author: john},
+		domain.Commit{Hash: hash, Message: "feat: cache", Date: day(1, 0), Author: jane},
+		domain.Commit{Hash: "ccc", Message: "fix(api): nil check", Date: day(2, 0), Author: jane},
+	)
+	commitRepo := repository.NewGormCommitRepository(db)
+
+	// Act
+	resolved, err := commitRepo.ResolveCommitLinks(context.TODO(), repo.ID)
+	require.NoError(t, err)
+
+	commits, paging, err := commitRepo.GetCommitsByRepository(context.TODO(), *repo, domain.APIPaging{Limit: 10, Page: 1}, domain.CommitFilter{Types: []string{"feat", "fix"}, Until: day(2, 0)})
+	require.NoError(t, err)
+
+	reverted, err := commitRepo.GetCommitByHash(context.TODO(), hash)
+	require.NoError(t, err)
+
+	revert, err := commitRepo.GetCommitByHash(context.TODO(), "bbb")
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, int64(1), resolved)
+	assert.Equal(t, 2, paging.Count)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, []string{"bbb"}, reverted.RevertedBy)
+	assert.Equal(t, &domain.CommitLink{Kind: domain.LinkRevert, Target: hash, Hash: hash}, revert.Link)
+	assert.Equal(t, day(1, 0), reverted.Date)
+}
+
+// TestSQLite_TopAuthors tests the rankings from the aggregates and from commits in a window
+func TestSQLite_TopAuthors(t *testing.T) {
+	// Arrange
+	db := newSQLiteDB(t)
+	saveCommits(t, db, "org/repo",
+		domain.Commit{Hash: "a1", Message: "one", Date: day(1, 0), Author: jane},
+		domain.Commit{Hash: "a2", Message: "two", Date: day(5, 0), Author: jane},
+		domain.Commit{Hash: "b1", Message: "three", Date: day(6, 0), Author: john},
+	)
+	authorRepo := repository.NewGormAuthorRepository(db)
+
+	// Act
+	allTime, err := authorRepo.GetTopAuthors(context.TODO(), "org/repo", domain.TopAuthorsQuery{Limit: 10})
+	require.NoError(t, err)
+
+	windowed, err := authorRepo.GetTopAuthors(context.TODO(), "org/repo", domain.TopAuthorsQuery{
+		Limit:   10,
+		Since:   time.Date(2024, 3, 5, 1, 0, 0, 0, time.FixedZone("CET", 3600)), // midnight UTC
+		Exclude: []string{"john*"},
+	})
+	require.NoError(t, err)
+
+	repaired, err := authorRepo.RepairAuthorStats(context.TODO())
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, allTime, 2)
+	assert.Equal(t, "Jane Doe", allTime[0].Name)
+	assert.Equal(t, 2, allTime[0].CommitCount)
+	assert.True(t, allTime[0].FirstCommitAt.Equal(day(1, 0)))
+	assert.True(t, allTime[0].LastCommitAt.Equal(day(5, 0)))
+
+	require.Len(t, windowed, 1)
+	assert.Equal(t, 1, windowed[0].CommitCount)
+	assert.True(t, windowed[0].FirstCommitAt.Equal(day(5, 0)))
+
+	assert.Zero(t, repaired)
+}
+
+// TestSQLite_Activity tests bucketing commits in the query location
+func TestSQLite_Activity(t *testing.T) {
+	// Arrange
+	db := newSQLiteDB(t)
+	repo := saveCommits(t, db, "org/repo",
+		domain.Commit{Hash: "a1", Message: "one", Date: day(3, 23), Author: jane}, // Monday 00:00 in Berlin
+		domain.Commit{Hash: "a2", Message: "two", Date: day(4, 9), Author: jane},
+		domain.Commit{Hash: "b1", Message: "three", Date: day(11, 9), Author: john},
+	)
+	statsRepo := repository.NewGormStatsRepository(db)
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	query := domain.ActivityQuery{
+		RepoID:   repo.ID,
+		Interval: domain.IntervalWeek,
+		Since:    time.Date(2024, 3, 4, 0, 0, 0, 0, loc),
+		Until:    time.Date(2024, 3, 18, 0, 0, 0, 0, loc),
+		Location: loc,
+		Authors:  5,
+	}
+
+	// Act
+	buckets, err := statsRepo.CommitActivity(context.TODO(), query)
+	require.NoError(t, err)
+
+	punchCard, err := statsRepo.PunchCard(context.TODO(), query)
+	require.NoError(t, err)
+
+	authors, err := statsRepo.AuthorActivity(context.TODO(), query)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, []domain.ActivityBucket{
+		{Start: query.Since, Count: 2},
+		{Start: query.Since.AddDate(0, 0, 7), Count: 1},
+	}, buckets)
+	assert.Equal(t, []domain.PunchCardCell{{Weekday: 1, Hour: 0, Count: 1}, {Weekday: 1, Hour: 10, Count: 2}}, punchCard)
+	require.Len(t, authors, 2)
+	assert.Equal(t, "Jane Doe", authors[0].Author.Name)
+	assert.Equal(t, []domain.ActivityBucket{{Start: query.Since, Count: 2}}, authors[0].Buckets)
+}
+
+// TestSQLite_Delivery tests deployments from releases and failures among delivered commits
+func TestSQLite_Delivery(t *testing.T) {
+	// Arrange
+	db := newSQLiteDB(t)
+	repo := saveCommits(t, db, "org/repo",
+		domain.Commit{Hash: "a1", Message: "feat: one", Date: day(1, 0), Author: jane},
+		domain.Commit{Hash: "a2", Message: "Revert \"feat: one\"", Date: day(2, 0), Author: jane},
+		domain.Commit{Hash: "a3", Message: "Hotfix login", Date: day(3, 0), Author: jane},
+	)
+	tagRepo := repository.NewGormTagRepository(db)
+	require.NoError(t, tagRepo.ReplaceTags(context.TODO(), repo.ID, []domain.Tag{{Name: "v1", Hash: "a3", Date: day(3, 0)}}))
+	require.NoError(t, tagRepo.ReplaceReleases(context.TODO(), repo.ID, []domain.Release{
+		{TagName: "v1", PublishedAt: day(4, 0)},
+		{TagName: "v2-rc", Prerelease: true, PublishedAt: day(5, 0)},
+		{TagName: "v0", PublishedAt: day(1, 12)},
+	}))
+	statsRepo := repository.NewGormStatsRepository(db)
+
+	query := domain.DeliveryQuery{RepoID: repo.ID, Source: domain.DeploymentReleases, Since: day(2, 0), Until: day(10, 0), FailurePattern: "hotfix%"}
+
+	// Act
+	deployments, err := statsRepo.Deployments(context.TODO(), query)
+	require.NoError(t, err)
+
+	delivered, err := statsRepo.DeliveredCommits(context.TODO(), query, day(1, 12), day(3, 0))
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, deployments, 2)
+	assert.True(t, deployments[0].Cutoff.Equal(day(1, 12)))
+	assert.True(t, deployments[1].At.Equal(day(4, 0)))
+	assert.True(t, deployments[1].Cutoff.Equal(day(3, 0)))
+	require.Len(t, delivered, 2)
+	assert.True(t, delivered[0].Failure)
+	assert.True(t, delivered[1].Failure)
+}
+
+// TestSQLite_Ownership tests storing commit files and the churn per directory
+func TestSQLite_Ownership(t *testing.T) {
+	// Arrange
+	db := newSQLiteDB(t)
+	repo := saveCommits(t, db, "org/repo", domain.Commit{Hash: "a1", Message: "one", Date: day(1, 0), Author: jane})
+	commitRepo := repository.NewGormCommitRepository(db)
+
+	pending, err := commitRepo.CommitsWithoutFiles(context.TODO(), repo.ID, day(1, 0), 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	commit := pending[0]
+	commit.Additions, commit.Deletions = 12, 3
+	commit.Files = []domain.CommitFile{
+		{Path: "src/net/http/server.go", Additions: 10, Deletions: 2},
+		{Path: "src/io/io.go", Additions: 2, Deletions: 1},
+		{Path: "README.md"},
+	}
+
+	// Act
+	require.NoError(t, commitRepo.SaveCommitFiles(context.TODO(), commit))
+
+	churn, err := repository.NewGormStatsRepository(db).DirectoryChurn(context.TODO(), domain.OwnershipQuery{RepoID: repo.ID, Path: "src/", Since: day(1, 0), Limit: 10})
+	require.NoError(t, err)
+
+	pending, err = commitRepo.CommitsWithoutFiles(context.TODO(), repo.ID, day(1, 0), 10)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Empty(t, pending)
+	byDirectory := make(map[string]int, len(churn))
+	for _, c := range churn {
+		byDirectory[c.Directory] = c.Churn
+	}
+	assert.Equal(t, map[string]int{"src/net/http/": 12, "src/io/": 3}, byDirectory)
+}
+
+// TestSQLite_DeleteRepository tests that deleting a repository deletes its rows
+func TestSQLite_DeleteRepository(t *testing.T) {
+	// Arrange
+	db := newSQLiteDB(t)
+	repo := saveCommits(t, db, "org/repo",
+		domain.Commit{Hash: "a1", Message: "one", Date: day(1, 0), Author: jane},
+		domain.Commit{Hash: "a2", Message: "fixup! one", Date: day(2, 0), Author: jane},
+	)
+
+	// Act
+	err := repository.NewGormRepositoryMetaRepository(db).DeleteRepoMeta(context.TODO(), repo.ID)
+
+	// Assert
+	require.NoError(t, err)
+	var commits, links, stats int64
+	db.Model(&repository.Commit{}).Count(&commits)
+	db.Model(&repository.CommitLink{}).Count(&links)
+	db.Model(&repository.AuthorRepositoryStat{}).Count(&stats)
+	assert.Zero(t, commits+links+stats)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+)
+
+// StatsRepository defines an interface for aggregate queries over commits
+type StatsRepository interface {
+	CommitActivity(ctx context.Context, query domain.ActivityQuery) ([]domain.ActivityBucket, error)
+	PunchCard(ctx context.Context, query domain.ActivityQuery) ([]domain.PunchCardCell, error)
+	AuthorActivity(ctx context.Context, query domain.ActivityQuery) ([]domain.AuthorActivity, error)
+	// Deployments returns the deployments in the query range and the last one
+	// before it, oldest first
+	Deployments(ctx context.Context, query domain.DeliveryQuery) ([]domain.Deployment, error)
+	// DeliveredCommits returns the commits dated after after up to and including
+	// until, oldest first
+	DeliveredCommits(ctx context.Context, query domain.DeliveryQuery, after, until time.Time) ([]domain.DeliveredCommit, error)
+	// DirectoryChurn returns the churn of every author in every directory under
+	// the query path
+	DirectoryChurn(ctx context.Context, query domain.OwnershipQuery) ([]domain.DirectoryChurn, error)
+	// Hotspots returns the files under the query path with the most churn
+	Hotspots(ctx context.Context, query domain.OwnershipQuery) ([]domain.Hotspot, error)
+	// Reverts returns the reverts dated in the query range, newest first
+	Reverts(ctx context.Context, query domain.ActivityQuery) ([]domain.Revert, error)
+}
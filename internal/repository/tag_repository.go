@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/just-nibble/git-service/internal/domain"
+)
+
+// TagRepository defines an interface for the tags and releases of repositories
+type TagRepository interface {
+	AllTags(ctx context.Context, repoID uint) ([]domain.Tag, error)
+	GetTags(ctx context.Context, repoID uint, query domain.APIPaging) ([]domain.Tag, domain.PagingInfo, error)
+	TagByName(ctx context.Context, repoID uint, name string) (*domain.Tag, error)
+	// ReplaceTags stores tags as the complete set of tags of a repository
+	ReplaceTags(ctx context.Context, repoID uint, tags []domain.Tag) error
+	GetReleases(ctx context.Context, repoID uint, query domain.APIPaging) ([]domain.Release, domain.PagingInfo, error)
+	// ReplaceReleases stores releases as the complete set of releases of a repository
+	ReplaceReleases(ctx context.Context, repoID uint, releases []domain.Release) error
+}
@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/just-nibble/git-service/internal/usecases"
+	indexerv1 "github.com/just-nibble/git-service/pkg/pb/indexer/v1"
+)
+
+// authorService serves the rankings and profiles of AuthorUseCase. Repairing
+// the author stats is a background job and is not exposed.
+type authorService struct {
+	indexerv1.UnimplementedAuthorServiceServer
+	authorUsecase usecases.AuthorUseCase
+}
+
+func (s *authorService) GetTopAuthors(ctx context.Context, req *indexerv1.GetTopAuthorsRequest) (*indexerv1.GetTopAuthorsResponse, error) {
+	query, err := rankingQuery(req.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+
+	authors, err := s.authorUsecase.GetTopAuthors(ctx, req.GetRepository(), query)
+	if err != nil {
+		return nil, err
+	}
+	return &indexerv1.GetTopAuthorsResponse{Authors: toAuthors(authors)}, nil
+}
+
+func (s *authorService) GetLeaderboard(ctx context.Context, req *indexerv1.GetLeaderboardRequest) (*indexerv1.GetLeaderboardResponse, error) {
+	query, err := rankingQuery(req.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+
+	authors, err := s.authorUsecase.GetTopAuthorsAcross(ctx, req.GetGroup(), query)
+	if err != nil {
+		return nil, err
+	}
+	return &indexerv1.GetLeaderboardResponse{Authors: toAuthors(authors)}, nil
+}
+
+func (s *authorService) GetAuthor(ctx context.Context, req *indexerv1.GetAuthorRequest) (*indexerv1.AuthorProfile, error) {
+	profile, err := s.authorUsecase.GetAuthorProfile(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, err
+	}
+	return toAuthorProfile(*profile), nil
+}
+
+func (s *authorService) BatchGetAuthors(ctx context.Context, req *indexerv1.BatchGetAuthorsRequest) (*indexerv1.BatchGetAuthorsResponse, error) {
+	ids := make([]uint, 0, len(req.GetIds()))
+	for _, id := range req.GetIds() {
+		ids = append(ids, uint(id))
+	}
+
+	profiles, err := s.authorUsecase.GetAuthorProfiles(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &indexerv1.BatchGetAuthorsResponse{Authors: make([]*indexerv1.AuthorProfile, 0, len(profiles))}
+	for _, p := range profiles {
+		res.Authors = append(res.Authors, toAuthorProfile(p))
+	}
+	return res, nil
+}
@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/usecases"
+	indexerv1 "github.com/just-nibble/git-service/pkg/pb/indexer/v1"
+	"google.golang.org/grpc"
+)
+
+// commitService serves the commit listings of GitCommitUsecase. The backfills
+// of parsed messages and links run once at startup and are not exposed.
+type commitService struct {
+	indexerv1.UnimplementedCommitServiceServer
+	commitUsecase usecases.GitCommitUsecase
+	pollInterval  time.Duration
+	// subscriptions is cancelled when the server shuts down
+	subscriptions context.Context
+}
+
+func (s *commitService) ListCommits(ctx context.Context, req *indexerv1.ListCommitsRequest) (*indexerv1.ListCommitsResponse, error) {
+	query, err := commitPaging(req.GetPage(), req.GetLimit(), req.GetOrder())
+	if err != nil {
+		return nil, err
+	}
+
+	commits, paging, err := s.commitUsecase.GetAllCommitsByRepository(ctx, req.GetRepository(), query, commitFilter(req.GetFilter()))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &indexerv1.ListCommitsResponse{
+		Commits: make([]*indexerv1.Commit, 0, len(commits)),
+		PageInfo: &indexerv1.PageInfo{
+			TotalCount:  paging.TotalCount,
+			Page:        int32(paging.Page),
+			HasNextPage: paging.HasNextPage,
+			Count:       int32(paging.Count),
+		},
+	}
+	for _, c := range commits {
+		res.Commits = append(res.Commits, toCommit(c))
+	}
+	return res, nil
+}
+
+func (s *commitService) StreamCommits(req *indexerv1.StreamCommitsRequest, stream grpc.ServerStreamingServer[indexerv1.Commit]) error {
+	query, err := commitPaging(0, 0, req.GetOrder())
+	if err != nil {
+		return err
+	}
+
+	return s.commitUsecase.StreamCommits(stream.Context(), req.GetRepository(), query, commitFilter(req.GetFilter()), func(c domain.Commit) error {
+		return stream.Send(toCommit(c))
+	})
+}
+
+func (s *commitService) SubscribeCommits(req *indexerv1.SubscribeCommitsRequest, stream grpc.ServerStreamingServer[indexerv1.Commit]) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	stop := context.AfterFunc(s.subscriptions, cancel)
+	defer stop()
+
+	return s.commitUsecase.SubscribeCommits(ctx, req.GetRepository(), s.pollInterval, func(c domain.Commit) error {
+		return stream.Send(toCommit(c))
+	})
+}
@@ -0,0 +1,179 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	indexerv1 "github.com/just-nibble/git-service/pkg/pb/indexer/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultRankingLimit is the number of authors ranked when a query does not
+// set it.
+const defaultRankingLimit = 10
+
+var linkKinds = map[domain.CommitLinkKind]indexerv1.CommitLink_Kind{
+	domain.LinkRevert: indexerv1.CommitLink_KIND_REVERT,
+	domain.LinkFixup:  indexerv1.CommitLink_KIND_FIXUP,
+	domain.LinkSquash: indexerv1.CommitLink_KIND_SQUASH,
+}
+
+var rankings = map[indexerv1.AuthorRanking]domain.AuthorRanking{
+	// the usecases rank by commits by default
+	indexerv1.AuthorRanking_AUTHOR_RANKING_UNSPECIFIED: "",
+	indexerv1.AuthorRanking_AUTHOR_RANKING_COMMITS:     domain.RankByCommits,
+	indexerv1.AuthorRanking_AUTHOR_RANKING_LINES:       domain.RankByLines,
+}
+
+func toRepository(r domain.RepositoryMeta) *indexerv1.Repository {
+	return &indexerv1.Repository{
+		Id:              uint64(r.ID),
+		Name:            r.Name,
+		Owner:           r.OwnerName,
+		Description:     r.Description,
+		Language:        r.Language,
+		Url:             r.URL,
+		ForksCount:      int64(r.ForksCount),
+		StarsCount:      int64(r.StarsCount),
+		OpenIssuesCount: int64(r.OpenIssuesCount),
+		WatchersCount:   int64(r.WatchersCount),
+		Retention: &indexerv1.RetentionPolicy{
+			Days:    int64(r.Retention.Days),
+			Commits: int64(r.Retention.Commits),
+		},
+		CreatedAt: timestamp(r.CreatedAt),
+		UpdatedAt: timestamp(r.UpdatedAt),
+	}
+}
+
+func toCommit(c domain.Commit) *indexerv1.Commit {
+	author := toAuthor(c.Author)
+	// listings only join the name and email of authors
+	author.Id = uint64(c.AuthorID)
+
+	commit := &indexerv1.Commit{
+		Id:          uint64(c.ID),
+		Hash:        c.Hash,
+		Message:     c.Message,
+		Date:        timestamp(c.Date),
+		Author:      author,
+		Additions:   int64(c.Additions),
+		Deletions:   int64(c.Deletions),
+		Type:        c.Conventional.Type,
+		Scope:       c.Conventional.Scope,
+		Breaking:    c.Conventional.Breaking,
+		Description: c.Conventional.Description,
+		RevertedBy:  c.RevertedBy,
+	}
+	if c.Link != nil {
+		commit.Link = &indexerv1.CommitLink{Kind: linkKinds[c.Link.Kind], Target: c.Link.Target, Hash: c.Link.Hash}
+	}
+	return commit
+}
+
+func toAuthor(a domain.Author) *indexerv1.Author {
+	return &indexerv1.Author{
+		Id:              uint64(a.ID),
+		Name:            a.Name,
+		Email:           a.Email,
+		CommitCount:     int64(a.CommitCount),
+		RepositoryCount: int64(a.RepositoryCount),
+		Additions:       int64(a.Additions),
+		Deletions:       int64(a.Deletions),
+		FirstCommitAt:   timestamp(a.FirstCommitAt),
+		LastCommitAt:    timestamp(a.LastCommitAt),
+	}
+}
+
+func toAuthors(authors []domain.Author) []*indexerv1.Author {
+	res := make([]*indexerv1.Author, 0, len(authors))
+	for _, a := range authors {
+		res = append(res, toAuthor(a))
+	}
+	return res
+}
+
+func toAuthorProfile(p domain.AuthorProfile) *indexerv1.AuthorProfile {
+	profile := &indexerv1.AuthorProfile{
+		Author:       toAuthor(p.Author),
+		Repositories: make([]*indexerv1.RepositoryContribution, 0, len(p.Repositories)),
+	}
+	for _, c := range p.Repositories {
+		profile.Repositories = append(profile.Repositories, &indexerv1.RepositoryContribution{
+			Repository:    c.RepositoryName,
+			CommitCount:   int64(c.CommitCount),
+			FirstCommitAt: timestamp(c.FirstCommitAt),
+			LastCommitAt:  timestamp(c.LastCommitAt),
+		})
+	}
+	return profile
+}
+
+// timestamp leaves unknown times unset.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func commitPaging(page, limit int32, order *indexerv1.CommitOrder) (domain.APIPaging, error) {
+	if page < 0 || limit < 0 {
+		return domain.APIPaging{}, errcodes.ErrInvalidPaging
+	}
+
+	paging := dtos.APIPagingDto{
+		Page:      int(page),
+		Limit:     int(limit),
+		Sort:      order.GetSort(),
+		Direction: order.GetDirection(),
+	}.Capped()
+
+	return domain.APIPaging{
+		Limit:     paging.Limit,
+		Page:      paging.Page,
+		Sort:      paging.Sort,
+		Direction: paging.Direction,
+	}, nil
+}
+
+func commitFilter(f *indexerv1.CommitFilter) domain.CommitFilter {
+	filter := domain.CommitFilter{
+		Types: f.GetTypes(),
+		Scope: f.GetScope(),
+	}
+	if f != nil {
+		filter.Breaking = f.Breaking
+	}
+	return filter
+}
+
+func rankingQuery(q *indexerv1.RankingQuery) (domain.TopAuthorsQuery, error) {
+	limit := int(q.GetLimit())
+	if limit < 0 {
+		return domain.TopAuthorsQuery{}, errcodes.ErrInvalidPaging
+	}
+	if limit == 0 {
+		limit = defaultRankingLimit
+	}
+
+	rankBy, ok := rankings[q.GetRankBy()]
+	if !ok {
+		return domain.TopAuthorsQuery{}, errcodes.ErrInvalidRanking
+	}
+
+	query := domain.TopAuthorsQuery{
+		Limit:   min(limit, dtos.MaxLimit),
+		RankBy:  rankBy,
+		Exclude: q.GetExclude(),
+	}
+	if q.GetSince() != nil {
+		query.Since = q.GetSince().AsTime()
+	}
+	if q.GetUntil() != nil {
+		query.Until = q.GetUntil().AsTime()
+	}
+	return query, nil
+}
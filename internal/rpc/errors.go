@@ -0,0 +1,60 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo details of errors.
+const errorDomain = "git-service"
+
+// grpcCodes are the status codes of the error codes of the REST API.
+var grpcCodes = map[errcodes.Code]codes.Code{
+	errcodes.CodeInvalidArgument:  codes.InvalidArgument,
+	errcodes.CodeUnauthenticated:  codes.Unauthenticated,
+	errcodes.CodePermissionDenied: codes.PermissionDenied,
+	errcodes.CodeNotFound:         codes.NotFound,
+	errcodes.CodeAlreadyExists:    codes.AlreadyExists,
+	errcodes.CodeRateLimited:      codes.ResourceExhausted,
+	errcodes.CodeCancelled:        codes.Canceled,
+	errcodes.CodeInternal:         codes.Internal,
+}
+
+// toStatus converts an error of the usecases to a status. The code of the REST
+// API is the reason of an ErrorInfo detail, with the details of the error as
+// its metadata. Statuses are returned as they are.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	code := errcodes.CodeOf(err)
+	st := status.New(grpcCodes[code], err.Error())
+
+	info := &errdetails.ErrorInfo{Reason: strings.ToUpper(string(code)), Domain: errorDomain}
+	var e *errcodes.Error
+	if errors.As(err, &e) && len(e.Details) > 0 {
+		info.Metadata = make(map[string]string, len(e.Details))
+		for k, v := range e.Details {
+			info.Metadata[k] = fmt.Sprint(v)
+		}
+	}
+
+	if detailed, err := st.WithDetails(info); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
@@ -0,0 +1,194 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	indexerv1 "github.com/just-nibble/git-service/pkg/pb/indexer/v1"
+	"github.com/just-nibble/git-service/pkg/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+type contextKey string
+
+const apiKeyContextKey contextKey = "api_key"
+
+const (
+	// defaultRateLimitGroup is the group of services without their own limit,
+	// as for the routes of the REST API.
+	defaultRateLimitGroup = "default"
+	// authRateLimitGroup limits the calls of a client that fail authentication.
+	authRateLimitGroup = "auth"
+)
+
+// rateLimitGroups are the route groups of the REST API whose limits apply to
+// the services.
+var rateLimitGroups = map[string]string{
+	indexerv1.RepositoryService_ServiceDesc.ServiceName: "repositories",
+	indexerv1.CommitService_ServiceDesc.ServiceName:     "commits",
+	indexerv1.AuthorService_ServiceDesc.ServiceName:     "authors",
+}
+
+// editorMethods change the tracked repositories, like the routes of the REST
+// API that require the editor role.
+var editorMethods = map[string]bool{
+	indexerv1.RepositoryService_AddRepository_FullMethodName:     true,
+	indexerv1.RepositoryService_RemoveRepository_FullMethodName:  true,
+	indexerv1.RepositoryService_ReindexRepository_FullMethodName: true,
+}
+
+// APIKeyFromContext returns the key that authenticated the call, if any.
+func APIKeyFromContext(ctx context.Context) (*domain.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey).(*domain.APIKey)
+	return key, ok
+}
+
+// interceptors authenticate, authorize and rate limit calls to the services
+// and convert the errors of the usecases to statuses.
+type interceptors struct {
+	apiKeys  usecases.APIKeyUsecase
+	limiters map[string]*ratelimit.Limiter
+}
+
+func (i interceptors) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := i.admit(ctx, info.FullMethod)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp, err := handler(ctx, req)
+	return resp, toStatus(err)
+}
+
+func (i interceptors) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := i.admit(ss.Context(), info.FullMethod)
+	if err != nil {
+		return toStatus(err)
+	}
+
+	return toStatus(handler(srv, serverStream{ServerStream: ss, ctx: ctx}))
+}
+
+// admit returns the context to serve a call with, or an error when the call
+// must be rejected. The health and reflection services are public.
+func (i interceptors) admit(ctx context.Context, fullMethod string) (context.Context, error) {
+	if strings.HasPrefix(fullMethod, "/grpc.") {
+		return ctx, nil
+	}
+
+	if i.apiKeys != nil {
+		key, err := i.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if editorMethods[fullMethod] && !key.Role.Allows(domain.RoleEditor) {
+			return nil, errcodes.ErrForbidden
+		}
+		ctx = context.WithValue(ctx, apiKeyContextKey, key)
+	}
+
+	if err := i.allow(ctx, fullMethod); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// authenticate returns the key of the call. Clients whose calls failed
+// authentication too often are rejected by their address, like the REST API
+// does, so keys cannot be guessed at the pace of the per key limits.
+func (i interceptors) authenticate(ctx context.Context) (*domain.APIKey, error) {
+	limiter, limited := i.limiters[authRateLimitGroup]
+	client := clientKey(ctx)
+	if limited {
+		if res := limiter.Peek(client); !res.Allowed {
+			return nil, rateLimited(res)
+		}
+	}
+
+	var key *domain.APIKey
+	var err error = errcodes.ErrUnauthorized
+	if secret, ok := bearerToken(ctx); ok {
+		key, err = i.apiKeys.Authenticate(ctx, secret)
+	}
+
+	if limited && errcodes.CodeOf(err) == errcodes.CodeUnauthenticated {
+		limiter.Allow(client)
+	}
+	return key, err
+}
+
+// allow takes a token of the client from the limiter of the group of the
+// service, a stream takes a single token however long it runs.
+func (i interceptors) allow(ctx context.Context, fullMethod string) error {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	limiter, ok := i.limiters[rateLimitGroups[service]]
+	if !ok {
+		limiter, ok = i.limiters[defaultRateLimitGroup]
+	}
+	if !ok {
+		return nil
+	}
+
+	res := limiter.Allow(clientKey(ctx))
+	if !res.Allowed {
+		return rateLimited(res)
+	}
+	return nil
+}
+
+func rateLimited(res ratelimit.Result) error {
+	return errcodes.ErrRateLimited.WithDetails(map[string]interface{}{
+		"retry_after": int(math.Ceil(res.RetryAfter.Seconds())),
+	})
+}
+
+// bearerToken reads the key of the `authorization: Bearer <key>` metadata.
+func bearerToken(ctx context.Context) (string, bool) {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) == 0 {
+		return "", false
+	}
+
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// clientKey identifies clients like the rate limits of the REST API do, by
+// their API key or else their address.
+func clientKey(ctx context.Context) string {
+	if key, ok := APIKeyFromContext(ctx); ok {
+		return fmt.Sprintf("key:%d", key.ID)
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "ip:"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	return "ip:" + host
+}
+
+// serverStream is a stream with the context of an admitted call.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/usecases"
+	indexerv1 "github.com/just-nibble/git-service/pkg/pb/indexer/v1"
+)
+
+// repositoryService serves the repository operations of RepoMetaUsecase. Its
+// lifecycle operations, resuming and stopping indexing, belong to the process
+// and are not exposed.
+type repositoryService struct {
+	indexerv1.UnimplementedRepositoryServiceServer
+	repoMetaUsecase usecases.RepoMetaUsecase
+}
+
+func (s *repositoryService) AddRepository(ctx context.Context, req *indexerv1.AddRepositoryRequest) (*indexerv1.Repository, error) {
+	repo, err := s.repoMetaUsecase.InitiateIndexing(ctx, dtos.RepositoryInput{Name: req.GetName()})
+	if err != nil {
+		return nil, err
+	}
+	return toRepository(*repo), nil
+}
+
+func (s *repositoryService) GetRepository(ctx context.Context, req *indexerv1.GetRepositoryRequest) (*indexerv1.Repository, error) {
+	repo, err := s.repoMetaUsecase.FindRepoByName(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return toRepository(*repo), nil
+}
+
+func (s *repositoryService) ListRepositories(ctx context.Context, req *indexerv1.ListRepositoriesRequest) (*indexerv1.ListRepositoriesResponse, error) {
+	repos, err := s.repoMetaUsecase.RetrieveAllRepos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &indexerv1.ListRepositoriesResponse{Repositories: make([]*indexerv1.Repository, 0, len(repos))}
+	for _, repo := range repos {
+		res.Repositories = append(res.Repositories, toRepository(repo))
+	}
+	return res, nil
+}
+
+func (s *repositoryService) RemoveRepository(ctx context.Context, req *indexerv1.RemoveRepositoryRequest) (*indexerv1.RemoveRepositoryResponse, error) {
+	if err := s.repoMetaUsecase.RemoveRepository(ctx, req.GetName()); err != nil {
+		return nil, err
+	}
+	return &indexerv1.RemoveRepositoryResponse{}, nil
+}
+
+func (s *repositoryService) ReindexRepository(ctx context.Context, req *indexerv1.ReindexRepositoryRequest) (*indexerv1.Repository, error) {
+	repo, err := s.repoMetaUsecase.ReindexRepository(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return toRepository(*repo), nil
+}
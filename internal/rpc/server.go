@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/usecases"
+	indexerv1 "github.com/just-nibble/git-service/pkg/pb/indexer/v1"
+	"github.com/just-nibble/git-service/pkg/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// defaultPollInterval is how often subscriptions check for new commits when
+// Options do not set it.
+const defaultPollInterval = 5 * time.Second
+
+// Options configure the server, zero values turn authentication and rate
+// limiting off.
+type Options struct {
+	// APIKeys authenticates calls with the API keys of the REST API
+	APIKeys usecases.APIKeyUsecase
+	// Limiters limit the calls of each client per route group of the REST API,
+	// they are shared with it so both APIs draw from the same buckets
+	Limiters map[string]*ratelimit.Limiter
+	// PollInterval is how often subscriptions check for new commits
+	PollInterval time.Duration
+}
+
+// Server serves the repositories, commits and authors of the REST API over
+// gRPC, with the health and reflection services.
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+	// endSubscriptions ends the commit subscriptions, which never finish on
+	// their own and would hold up a graceful stop
+	endSubscriptions context.CancelFunc
+}
+
+func NewServer(repoMetaUsecase usecases.RepoMetaUsecase, commitUsecase usecases.GitCommitUsecase, authorUsecase usecases.AuthorUseCase, opts Options) *Server {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+
+	i := interceptors{apiKeys: opts.APIKeys, limiters: opts.Limiters}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(i.unary),
+		grpc.ChainStreamInterceptor(i.stream),
+	)
+
+	subscriptions, endSubscriptions := context.WithCancel(context.Background())
+
+	indexerv1.RegisterRepositoryServiceServer(server, &repositoryService{repoMetaUsecase: repoMetaUsecase})
+	indexerv1.RegisterCommitServiceServer(server, &commitService{
+		commitUsecase: commitUsecase,
+		pollInterval:  opts.PollInterval,
+		subscriptions: subscriptions,
+	})
+	indexerv1.RegisterAuthorServiceServer(server, &authorService{authorUsecase: authorUsecase})
+
+	healthServer := health.NewServer()
+	for _, service := range []string{
+		indexerv1.RepositoryService_ServiceDesc.ServiceName,
+		indexerv1.CommitService_ServiceDesc.ServiceName,
+		indexerv1.AuthorService_ServiceDesc.ServiceName,
+	} {
+		healthServer.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return &Server{grpc: server, health: healthServer, endSubscriptions: endSubscriptions}
+}
+
+// Serve accepts connections on lis until the server is shut down.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Shutdown reports the services as not serving, ends the subscriptions and
+// waits for the other calls to finish. Calls still running when ctx is done
+// are cancelled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+	s.endSubscriptions()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,346 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/internal/usecases"
+	"github.com/just-nibble/git-service/pkg/config"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/log"
+	indexerv1 "github.com/just-nibble/git-service/pkg/pb/indexer/v1"
+	"github.com/just-nibble/git-service/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// stubAPIKeys authenticates the keys it holds by their secret
+type stubAPIKeys struct {
+	usecases.APIKeyUsecase
+	keys map[string]*domain.APIKey
+}
+
+func (s stubAPIKeys) Authenticate(ctx context.Context, secret string) (*domain.APIKey, error) {
+	key, ok := s.keys[secret]
+	if !ok {
+		return nil, errcodes.ErrUnauthorized
+	}
+	return key, nil
+}
+
+type testServer struct {
+	server  *Server
+	conn    *grpc.ClientConn
+	commits repository.CommitRepository
+	repo    *domain.RepositoryMeta
+}
+
+// newTestServer serves a repository with commits of two authors over an in
+// memory connection
+func newTestServer(t *testing.T, opts Options) *testServer {
+	t.Helper()
+	ctx := context.TODO()
+
+	store := repository.NewMemoryStore()
+	repoMetaRepository := repository.NewMemoryRepositoryMetaRepository(store)
+	commitRepository := repository.NewMemoryCommitRepository(store)
+	authorRepository := repository.NewMemoryAuthorRepository(store)
+
+	repo, err := repoMetaRepository.SaveRepoMetadata(ctx, domain.RepositoryMeta{Name: "org/repo", OwnerName: "org"})
+	require.NoError(t, err)
+
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commits := []domain.Commit{
+		{Hash: "c1", Message: "feat: one", Date: date, Author: domain.Author{Name: "Jane", Email: "jane@example.com"}},
+		{Hash: "c2", Message: "fix(api): two", Date: date.Add(time.Hour), Author: domain.Author{Name: "John", Email: "john@example.com"}},
+		{Hash: "c3", Message: "feat!: three", Date: date.Add(2 * time.Hour), Author: domain.Author{Name: "Jane", Email: "jane@example.com"}},
+	}
+	for i := range commits {
+		commits[i].Conventional = domain.ParseConventionalCommit(commits[i].Message)
+	}
+	_, err = commitRepository.SaveCommits(ctx, *repo, commits)
+	require.NoError(t, err)
+
+	logger := *log.NewLogger()
+	server := NewServer(
+		usecases.NewrepoMetaUsecase(repoMetaRepository, commitRepository, authorRepository, nil, nil, nil, config.Config{}, logger),
+		usecases.NewGitCommitUsecase(commitRepository, repoMetaRepository),
+		usecases.NewAuthorUseCase(authorRepository, nil, logger),
+		opts,
+	)
+
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Shutdown(context.TODO())
+	})
+
+	return &testServer{server: server, conn: conn, commits: commitRepository, repo: repo}
+}
+
+// reason returns the code of the REST API a status carries
+func reason(t *testing.T, err error) string {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a status: %v", err)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func receiveAll(t *testing.T, stream grpc.ServerStreamingClient[indexerv1.Commit]) []string {
+	t.Helper()
+
+	var hashes []string
+	for {
+		commit, err := stream.Recv()
+		if err == io.EOF {
+			return hashes
+		}
+		require.NoError(t, err)
+		hashes = append(hashes, commit.Hash)
+	}
+}
+
+// TestServer_Commits tests listing and streaming commits and the statuses of
+// errors
+func TestServer_Commits(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, Options{})
+	client := indexerv1.NewCommitServiceClient(s.conn)
+	ctx := context.TODO()
+
+	// Act
+	page, err := client.ListCommits(ctx, &indexerv1.ListCommitsRequest{
+		Repository: "org/repo",
+		Limit:      2,
+		Order:      &indexerv1.CommitOrder{Sort: "date"},
+	})
+	require.NoError(t, err)
+	filtered, err := client.ListCommits(ctx, &indexerv1.ListCommitsRequest{
+		Repository: "org/repo",
+		Filter:     &indexerv1.CommitFilter{Types: []string{"feat"}},
+	})
+	require.NoError(t, err)
+	stream, err := client.StreamCommits(ctx, &indexerv1.StreamCommitsRequest{
+		Repository: "org/repo",
+		Order:      &indexerv1.CommitOrder{Sort: "date", Direction: "asc"},
+	})
+	require.NoError(t, err)
+	streamed := receiveAll(t, stream)
+
+	_, missingErr := client.ListCommits(ctx, &indexerv1.ListCommitsRequest{Repository: "org/missing"})
+	_, pagingErr := client.ListCommits(ctx, &indexerv1.ListCommitsRequest{Repository: "org/repo", Limit: -1})
+	_, sortErr := client.ListCommits(ctx, &indexerv1.ListCommitsRequest{Repository: "org/repo", Order: &indexerv1.CommitOrder{Sort: "size"}})
+
+	// Assert
+	require.Len(t, page.Commits, 2)
+	newest := page.Commits[0]
+	assert.Equal(t, "c3", newest.Hash)
+	assert.Equal(t, "feat", newest.Type)
+	assert.True(t, newest.Breaking)
+	assert.Equal(t, "Jane", newest.Author.Name)
+	assert.NotZero(t, newest.Author.Id)
+	assert.Equal(t, int64(3), page.PageInfo.TotalCount)
+	assert.True(t, page.PageInfo.HasNextPage)
+
+	assert.Len(t, filtered.Commits, 2)
+	assert.Equal(t, []string{"c1", "c2", "c3"}, streamed)
+
+	assert.Equal(t, codes.NotFound, status.Code(missingErr))
+	assert.Equal(t, "NOT_FOUND", reason(t, missingErr))
+	assert.Equal(t, codes.InvalidArgument, status.Code(pagingErr))
+	assert.Equal(t, codes.InvalidArgument, status.Code(sortErr))
+}
+
+// TestServer_SubscribeCommits tests that subscribers receive the commits stored
+// after they subscribed and that shutting down ends the subscription
+func TestServer_SubscribeCommits(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, Options{PollInterval: 10 * time.Millisecond})
+	client := indexerv1.NewCommitServiceClient(s.conn)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+	stream, err := client.SubscribeCommits(ctx, &indexerv1.SubscribeCommitsRequest{Repository: "org/repo"})
+	require.NoError(t, err)
+	// the subscription starts once the server received the call
+	time.Sleep(100 * time.Millisecond)
+
+	// Act
+	commit := domain.Commit{Hash: "c4", Message: "docs: four", Date: time.Now(), Author: domain.Author{Name: "Jane", Email: "jane@example.com"}}
+	commit.Conventional = domain.ParseConventionalCommit(commit.Message)
+	_, err = s.commits.SaveCommits(context.TODO(), *s.repo, []domain.Commit{commit})
+	require.NoError(t, err)
+
+	received, err := stream.Recv()
+	require.NoError(t, err)
+
+	shutdownErr := s.server.Shutdown(ctx)
+	_, endErr := stream.Recv()
+
+	// Assert
+	assert.Equal(t, "c4", received.Hash)
+	assert.Equal(t, "docs", received.Type)
+	assert.NoError(t, shutdownErr, "the subscription does not hold up a graceful stop")
+	assert.Equal(t, io.EOF, endErr)
+}
+
+// TestServer_Authors tests rankings and profiles
+func TestServer_Authors(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, Options{})
+	client := indexerv1.NewAuthorServiceClient(s.conn)
+	ctx := context.TODO()
+
+	// Act
+	top, err := client.GetTopAuthors(ctx, &indexerv1.GetTopAuthorsRequest{Repository: "org/repo"})
+	require.NoError(t, err)
+	leaderboard, err := client.GetLeaderboard(ctx, &indexerv1.GetLeaderboardRequest{Query: &indexerv1.RankingQuery{Limit: 1}})
+	require.NoError(t, err)
+	profiles, err := client.BatchGetAuthors(ctx, &indexerv1.BatchGetAuthorsRequest{Ids: []uint64{top.Authors[1].Id, 999, top.Authors[0].Id}})
+	require.NoError(t, err)
+
+	_, missingErr := client.GetAuthor(ctx, &indexerv1.GetAuthorRequest{Id: 999})
+	_, groupErr := client.GetLeaderboard(ctx, &indexerv1.GetLeaderboardRequest{Group: "unknown"})
+
+	// Assert
+	require.Len(t, top.Authors, 2)
+	assert.Equal(t, "Jane", top.Authors[0].Name)
+	assert.Equal(t, int64(2), top.Authors[0].CommitCount)
+
+	require.Len(t, leaderboard.Authors, 1)
+	assert.Equal(t, int64(1), leaderboard.Authors[0].RepositoryCount)
+
+	require.Len(t, profiles.Authors, 2)
+	assert.Equal(t, "org/repo", profiles.Authors[0].Repositories[0].Repository)
+
+	assert.Equal(t, codes.NotFound, status.Code(missingErr))
+	assert.Equal(t, codes.NotFound, status.Code(groupErr))
+}
+
+// TestServer_Auth tests that calls need an API key, that changing repositories
+// needs the editor role and that health checks and reflection are public
+func TestServer_Auth(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, Options{APIKeys: stubAPIKeys{keys: map[string]*domain.APIKey{
+		"reader-key": {ID: 1, Role: domain.RoleReader},
+	}}})
+	repositories := indexerv1.NewRepositoryServiceClient(s.conn)
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.TODO(), "authorization", "Bearer "+key)
+	}
+
+	// Act
+	_, anonymousErr := repositories.ListRepositories(context.TODO(), &indexerv1.ListRepositoriesRequest{})
+	_, invalidErr := repositories.ListRepositories(withKey("wrong"), &indexerv1.ListRepositoriesRequest{})
+	list, readerErr := repositories.ListRepositories(withKey("reader-key"), &indexerv1.ListRepositoriesRequest{})
+	_, removeErr := repositories.RemoveRepository(withKey("reader-key"), &indexerv1.RemoveRepositoryRequest{Name: "org/repo"})
+	stream, err := indexerv1.NewCommitServiceClient(s.conn).StreamCommits(context.TODO(), &indexerv1.StreamCommitsRequest{Repository: "org/repo"})
+	require.NoError(t, err)
+	_, streamErr := stream.Recv()
+
+	health, healthErr := healthpb.NewHealthClient(s.conn).Check(context.TODO(), &healthpb.HealthCheckRequest{
+		Service: indexerv1.CommitService_ServiceDesc.ServiceName,
+	})
+	reflection, err := reflectionpb.NewServerReflectionClient(s.conn).ServerReflectionInfo(context.TODO())
+	require.NoError(t, err)
+	require.NoError(t, reflection.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	services, reflectionErr := reflection.Recv()
+
+	// Assert
+	assert.Equal(t, codes.Unauthenticated, status.Code(anonymousErr))
+	assert.Equal(t, codes.Unauthenticated, status.Code(invalidErr))
+	assert.NoError(t, readerErr)
+	assert.Len(t, list.GetRepositories(), 1)
+	assert.Equal(t, codes.PermissionDenied, status.Code(removeErr))
+	assert.Equal(t, codes.Unauthenticated, status.Code(streamErr))
+
+	require.NoError(t, healthErr)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, health.Status)
+	require.NoError(t, reflectionErr)
+	var names []string
+	for _, service := range services.GetListServicesResponse().GetService() {
+		names = append(names, service.Name)
+	}
+	assert.Contains(t, names, indexerv1.RepositoryService_ServiceDesc.ServiceName)
+}
+
+// TestServer_RateLimit tests that the limit of the route group of a service
+// applies to its calls
+func TestServer_RateLimit(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, Options{Limiters: map[string]*ratelimit.Limiter{
+		"repositories": ratelimit.New(ratelimit.Limit{Rate: 0.001, Burst: 1}),
+	}})
+	repositories := indexerv1.NewRepositoryServiceClient(s.conn)
+	authors := indexerv1.NewAuthorServiceClient(s.conn)
+	ctx := context.TODO()
+
+	// Act
+	_, firstErr := repositories.GetRepository(ctx, &indexerv1.GetRepositoryRequest{Name: "org/repo"})
+	_, limitedErr := repositories.GetRepository(ctx, &indexerv1.GetRepositoryRequest{Name: "org/repo"})
+	_, otherErr := authors.GetTopAuthors(ctx, &indexerv1.GetTopAuthorsRequest{Repository: "org/repo"})
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(limitedErr))
+	assert.Equal(t, "RATE_LIMITED", reason(t, limitedErr))
+	assert.NoError(t, otherErr)
+}
+
+// TestServer_RateLimitAuthFailures tests that clients are throttled once their
+// calls failed authentication, while valid keys are not charged
+func TestServer_RateLimitAuthFailures(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, Options{
+		APIKeys: stubAPIKeys{keys: map[string]*domain.APIKey{
+			"reader-key": {ID: 1, Role: domain.RoleReader},
+		}},
+		Limiters: map[string]*ratelimit.Limiter{
+			"auth": ratelimit.New(ratelimit.Limit{Rate: 0.001, Burst: 1}),
+		},
+	})
+	repositories := indexerv1.NewRepositoryServiceClient(s.conn)
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.TODO(), "authorization", "Bearer "+key)
+	}
+
+	// Act
+	_, validErr := repositories.ListRepositories(withKey("reader-key"), &indexerv1.ListRepositoriesRequest{})
+	_, failedErr := repositories.ListRepositories(withKey("wrong"), &indexerv1.ListRepositoriesRequest{})
+	_, limitedErr := repositories.ListRepositories(withKey("guess"), &indexerv1.ListRepositoriesRequest{})
+
+	// Assert
+	assert.NoError(t, validErr)
+	assert.Equal(t, codes.Unauthenticated, status.Code(failedErr))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(limitedErr))
+}
@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/log"
+)
+
+const (
+	// apiKeyPrefix marks secrets issued by this service
+	apiKeyPrefix = "gs_"
+	// apiKeyDisplayLen is the number of leading characters kept to identify a key
+	apiKeyDisplayLen = 10
+	bootstrapKeyName = "bootstrap-admin"
+)
+
+type APIKeyUsecase interface {
+	CreateKey(ctx context.Context, input dtos.APIKeyInput) (*domain.APIKey, string, error)
+	ListKeys(ctx context.Context) ([]domain.APIKey, error)
+	RevokeKey(ctx context.Context, id uint) error
+	Authenticate(ctx context.Context, secret string) (*domain.APIKey, error)
+	EnsureBootstrapKey(ctx context.Context, secret string) error
+}
+
+type apiKeyUsecase struct {
+	apiKeyRepo repository.APIKeyRepository
+	logger     log.Log
+}
+
+func NewAPIKeyUsecase(apiKeyRepo repository.APIKeyRepository, logger log.Log) APIKeyUsecase {
+	return &apiKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+		logger:     logger,
+	}
+}
+
+// CreateKey issues a new key and returns its secret, which is not stored and
+// cannot be retrieved again.
+func (uc *apiKeyUsecase) CreateKey(ctx context.Context, input dtos.APIKeyInput) (*domain.APIKey, string, error) {
+	role := domain.Role(input.Role)
+	if !role.Valid() {
+		return nil, "", errcodes.ErrInvalidRole
+	}
+
+	secret, err := generateAPIKey()
+	if err != nil {
+		uc.logger.Error.Printf("Failed to generate API key: %s", err.Error())
+		return nil, "", err
+	}
+
+	key, err := uc.apiKeyRepo.SaveAPIKey(ctx, domain.APIKey{
+		Name:   input.Name,
+		Prefix: secret[:apiKeyDisplayLen],
+		Role:   role,
+	}, hashAPIKey(secret))
+	if err != nil {
+		uc.logger.Error.Printf("Failed to save API key %s: %s", input.Name, err.Error())
+		return nil, "", err
+	}
+
+	uc.logger.Info.Printf("API key %s created with role %s", key.Name, key.Role)
+	return key, secret, nil
+}
+
+func (uc *apiKeyUsecase) ListKeys(ctx context.Context) ([]domain.APIKey, error) {
+	return uc.apiKeyRepo.AllAPIKeys(ctx)
+}
+
+func (uc *apiKeyUsecase) RevokeKey(ctx context.Context, id uint) error {
+	if err := uc.apiKeyRepo.RevokeAPIKey(ctx, id); err != nil {
+		uc.logger.Error.Printf("Failed to revoke API key %d: %s", id, err.Error())
+		return err
+	}
+	uc.logger.Info.Printf("API key %d revoked", id)
+	return nil
+}
+
+// Authenticate resolves a secret to an active key.
+func (uc *apiKeyUsecase) Authenticate(ctx context.Context, secret string) (*domain.APIKey, error) {
+	if secret == "" {
+		return nil, errcodes.ErrUnauthorized
+	}
+
+	key, err := uc.apiKeyRepo.APIKeyByHash(ctx, hashAPIKey(secret))
+	if err == errcodes.ErrNoRecordFound {
+		return nil, errcodes.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if key.RevokedAt != nil || !key.Role.Valid() {
+		return nil, errcodes.ErrUnauthorized
+	}
+	return key, nil
+}
+
+// EnsureBootstrapKey stores the admin key configured for the deployment, so the
+// first real keys can be created through the admin endpoints. Bootstrap keys
+// stored for an earlier secret are revoked, so rotating the secret retires the
+// old one.
+func (uc *apiKeyUsecase) EnsureBootstrapKey(ctx context.Context, secret string) error {
+	keyHash := hashAPIKey(secret)
+
+	key, err := uc.apiKeyRepo.APIKeyByHash(ctx, keyHash)
+	if err == errcodes.ErrNoRecordFound {
+		prefix := secret
+		if len(prefix) > apiKeyDisplayLen {
+			prefix = prefix[:apiKeyDisplayLen]
+		}
+
+		key, err = uc.apiKeyRepo.SaveAPIKey(ctx, domain.APIKey{
+			Name:   bootstrapKeyName,
+			Prefix: prefix,
+			Role:   domain.RoleAdmin,
+		}, keyHash)
+		if err != nil {
+			uc.logger.Error.Printf("Failed to save bootstrap admin key: %s", err.Error())
+			return err
+		}
+		uc.logger.Info.Println("Bootstrap admin key stored")
+	}
+	if err != nil {
+		return err
+	}
+
+	return uc.revokeBootstrapKeys(ctx, key.ID)
+}
+
+// revokeBootstrapKeys revokes the active bootstrap keys other than the one
+// with id keep.
+func (uc *apiKeyUsecase) revokeBootstrapKeys(ctx context.Context, keep uint) error {
+	keys, err := uc.apiKeyRepo.AllAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		if k.Name != bootstrapKeyName || k.ID == keep || k.RevokedAt != nil {
+			continue
+		}
+		if err := uc.RevokeKey(ctx, k.ID); err != nil {
+			return err
+		}
+		uc.logger.Info.Printf("Revoked bootstrap admin key %s replaced by ADMIN_API_KEY", k.Prefix)
+	}
+	return nil
+}
+
+func generateAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + strings.TrimRight(base64.URLEncoding.EncodeToString(b), "="), nil
+}
+
+// hashAPIKey hashes a secret for storage. Keys are random and long, so a fast
+// hash is enough and lets keys be looked up by their hash.
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
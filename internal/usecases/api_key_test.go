@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/repository/mocks"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestAPIKeyUsecase_CreateKey tests that only the hash of a new key is stored
+func TestAPIKeyUsecase_CreateKey(t *testing.T) {
+	// Arrange
+	mockAPIKeyRepository := new(mocks.APIKeyRepository)
+
+	var storedHash string
+	mockAPIKeyRepository.On("SaveAPIKey", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { storedHash = args.String(2) }).
+		Return(&domain.APIKey{ID: 1, Name: "ci", Role: domain.RoleEditor}, nil)
+
+	uc := NewAPIKeyUsecase(mockAPIKeyRepository, *log.NewLogger())
+
+	// Act
+	key, secret, err := uc.CreateKey(context.TODO(), dtos.APIKeyInput{Name: "ci", Role: "editor"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoleEditor, key.Role)
+	assert.True(t, strings.HasPrefix(secret, apiKeyPrefix))
+	assert.Equal(t, hashAPIKey(secret), storedHash)
+	assert.NotContains(t, storedHash, secret)
+	mockAPIKeyRepository.AssertExpectations(t)
+}
+
+// TestAPIKeyUsecase_CreateKey_InvalidRole tests that unknown roles are rejected
+func TestAPIKeyUsecase_CreateKey_InvalidRole(t *testing.T) {
+	// Arrange
+	mockAPIKeyRepository := new(mocks.APIKeyRepository)
+	uc := NewAPIKeyUsecase(mockAPIKeyRepository, *log.NewLogger())
+
+	// Act
+	_, _, err := uc.CreateKey(context.TODO(), dtos.APIKeyInput{Name: "ci", Role: "owner"})
+
+	// Assert
+	assert.Equal(t, errcodes.ErrInvalidRole, err)
+	mockAPIKeyRepository.AssertNotCalled(t, "SaveAPIKey", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAPIKeyUsecase_Authenticate tests key lookup for active, revoked and unknown keys
+func TestAPIKeyUsecase_Authenticate(t *testing.T) {
+	revokedAt := time.Now()
+
+	tests := []struct {
+		name    string
+		secret  string
+		key     *domain.APIKey
+		repoErr error
+		wantErr error
+	}{
+		{name: "active", secret: "gs_active", key: &domain.APIKey{ID: 1, Role: domain.RoleReader}},
+		{name: "revoked", secret: "gs_revoked", key: &domain.APIKey{ID: 2, Role: domain.RoleAdmin, RevokedAt: &revokedAt}, wantErr: errcodes.ErrUnauthorized},
+		{name: "unknown", secret: "gs_unknown", key: (*domain.APIKey)(nil), repoErr: errcodes.ErrNoRecordFound, wantErr: errcodes.ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockAPIKeyRepository := new(mocks.APIKeyRepository)
+			mockAPIKeyRepository.On("APIKeyByHash", mock.Anything, hashAPIKey(tt.secret)).Return(tt.key, tt.repoErr)
+
+			uc := NewAPIKeyUsecase(mockAPIKeyRepository, *log.NewLogger())
+
+			// Act
+			key, err := uc.Authenticate(context.TODO(), tt.secret)
+
+			// Assert
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.key, key)
+			}
+			mockAPIKeyRepository.AssertExpectations(t)
+		})
+	}
+}
+
+// TestAPIKeyUsecase_EnsureBootstrapKey_Rotation tests that a new bootstrap
+// secret is stored and the keys of earlier secrets are revoked
+func TestAPIKeyUsecase_EnsureBootstrapKey_Rotation(t *testing.T) {
+	// Arrange
+	revokedAt := time.Now()
+	mockAPIKeyRepository := new(mocks.APIKeyRepository)
+	mockAPIKeyRepository.On("APIKeyByHash", mock.Anything, hashAPIKey("gs_new_admin_secret")).Return((*domain.APIKey)(nil), errcodes.ErrNoRecordFound)
+	mockAPIKeyRepository.On("SaveAPIKey", mock.Anything, mock.MatchedBy(func(k domain.APIKey) bool {
+		return k.Name == bootstrapKeyName && k.Role == domain.RoleAdmin
+	}), hashAPIKey("gs_new_admin_secret")).Return(&domain.APIKey{ID: 4, Name: bootstrapKeyName, Role: domain.RoleAdmin}, nil)
+	mockAPIKeyRepository.On("AllAPIKeys", mock.Anything).Return([]domain.APIKey{
+		{ID: 1, Name: bootstrapKeyName, Role: domain.RoleAdmin},
+		{ID: 2, Name: bootstrapKeyName, Role: domain.RoleAdmin, RevokedAt: &revokedAt},
+		{ID: 3, Name: "ci", Role: domain.RoleAdmin},
+		{ID: 4, Name: bootstrapKeyName, Role: domain.RoleAdmin},
+	}, nil)
+	mockAPIKeyRepository.On("RevokeAPIKey", mock.Anything, uint(1)).Return(nil)
+
+	uc := NewAPIKeyUsecase(mockAPIKeyRepository, *log.NewLogger())
+
+	// Act
+	err := uc.EnsureBootstrapKey(context.TODO(), "gs_new_admin_secret")
+
+	// Assert
+	assert.NoError(t, err)
+	mockAPIKeyRepository.AssertExpectations(t)
+	mockAPIKeyRepository.AssertNumberOfCalls(t, "RevokeAPIKey", 1)
+}
+
+// TestAPIKeyUsecase_EnsureBootstrapKey_Existing tests that a stored bootstrap
+// secret is kept as is
+func TestAPIKeyUsecase_EnsureBootstrapKey_Existing(t *testing.T) {
+	// Arrange
+	key := domain.APIKey{ID: 1, Name: bootstrapKeyName, Role: domain.RoleAdmin}
+	mockAPIKeyRepository := new(mocks.APIKeyRepository)
+	mockAPIKeyRepository.On("APIKeyByHash", mock.Anything, hashAPIKey("gs_admin_secret")).Return(&key, nil)
+	mockAPIKeyRepository.On("AllAPIKeys", mock.Anything).Return([]domain.APIKey{key}, nil)
+
+	uc := NewAPIKeyUsecase(mockAPIKeyRepository, *log.NewLogger())
+
+	// Act
+	err := uc.EnsureBootstrapKey(context.TODO(), "gs_admin_secret")
+
+	// Assert
+	assert.NoError(t, err)
+	mockAPIKeyRepository.AssertNotCalled(t, "SaveAPIKey", mock.Anything, mock.Anything, mock.Anything)
+	mockAPIKeyRepository.AssertNotCalled(t, "RevokeAPIKey", mock.Anything, mock.Anything)
+}
+
+// TestRole_Allows tests the role hierarchy
+func TestRole_Allows(t *testing.T) {
+	assert.True(t, domain.RoleAdmin.Allows(domain.RoleEditor))
+	assert.True(t, domain.RoleEditor.Allows(domain.RoleReader))
+	assert.False(t, domain.RoleReader.Allows(domain.RoleEditor))
+	assert.False(t, domain.RoleEditor.Allows(domain.RoleAdmin))
+	assert.False(t, domain.Role("guest").Allows(domain.RoleReader))
+}
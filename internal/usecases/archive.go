@@ -0,0 +1,253 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/pkg/archive"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+)
+
+// archiveBatch bounds the commits read or saved at a time
+const archiveBatch = 500
+
+// Record types of a repository archive, the repository record comes first and
+// each author record before the first commit of the author.
+const (
+	recordRepository = "repository"
+	recordAuthor     = "author"
+	recordCommit     = "commit"
+)
+
+type ArchiveUsecase interface {
+	// Export writes an archive of a repository with its authors and commits to w
+	Export(ctx context.Context, repoName string, w io.Writer) (domain.ArchiveSummary, error)
+	// Import stores the repository, authors and commits of an archive, commits
+	// that are already stored are skipped. progress is called after each batch
+	// of commits
+	Import(ctx context.Context, r io.Reader, progress func(domain.ArchiveSummary)) (domain.ArchiveSummary, error)
+}
+
+type archiveUsecase struct {
+	repositoryMetaRepository repository.RepositoryMetaRepository
+	commitRepository         repository.CommitRepository
+}
+
+func NewArchiveUsecase(repositoryMetaRepository repository.RepositoryMetaRepository, commitRepository repository.CommitRepository) ArchiveUsecase {
+	return &archiveUsecase{
+		repositoryMetaRepository: repositoryMetaRepository,
+		commitRepository:         commitRepository,
+	}
+}
+
+func (u *archiveUsecase) Export(ctx context.Context, repoName string, w io.Writer) (domain.ArchiveSummary, error) {
+	summary := domain.ArchiveSummary{Repository: repoName}
+
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return summary, err
+	}
+
+	aw, err := archive.NewWriter(w, repo.Name)
+	if err != nil {
+		return summary, err
+	}
+	if err := aw.Write(recordRepository, repo.ToArchive()); err != nil {
+		return summary, err
+	}
+
+	written := make(map[uint]bool)
+	var afterID uint
+	for {
+		commits, err := u.commitRepository.RepositoryCommits(ctx, repo.ID, afterID, archiveBatch)
+		if err != nil {
+			return summary, err
+		}
+
+		for _, c := range commits {
+			if !written[c.AuthorID] {
+				author := dtos.ArchiveAuthor{ID: c.AuthorID, Name: c.Author.Name, Email: c.Author.Email}
+				if err := aw.Write(recordAuthor, author); err != nil {
+					return summary, err
+				}
+				written[c.AuthorID] = true
+				summary.Authors++
+			}
+
+			if err := aw.Write(recordCommit, c.ToArchive()); err != nil {
+				return summary, err
+			}
+			summary.Commits++
+		}
+
+		if len(commits) < archiveBatch {
+			break
+		}
+		afterID = commits[len(commits)-1].ID
+	}
+
+	return summary, aw.Close()
+}
+
+// Import stores the commits of an archive a batch at a time through
+// ImportCommits, so author statistics are kept and an interrupted import can be
+// run again. A repository that is already stored keeps its indexing cursor, a
+// new one gets the cursor of the archive with its index flag cleared, so the
+// monitor indexes it from there.
+func (u *archiveUsecase) Import(ctx context.Context, r io.Reader, progress func(domain.ArchiveSummary)) (domain.ArchiveSummary, error) {
+	var summary domain.ArchiveSummary
+
+	ar, err := archive.NewReader(r)
+	if err != nil {
+		return summary, fmt.Errorf("%w: %s", errcodes.ErrInvalidArchive, err.Error())
+	}
+	summary.Repository = ar.Manifest().Repository
+
+	var archived dtos.ArchiveRepository
+	if err := nextRecord(ar, recordRepository, &archived); err != nil {
+		return summary, err
+	}
+	if archived.Name == "" {
+		return summary, fmt.Errorf("%w: repository record has no name", errcodes.ErrInvalidArchive)
+	}
+	if archived.Name != ar.Manifest().Repository {
+		return summary, fmt.Errorf("%w: repository record %s does not match the manifest", errcodes.ErrInvalidArchive, archived.Name)
+	}
+
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, archived.Name)
+	if err == errcodes.ErrNoRecordFound {
+		// no job of this process indexes the repository, the monitor only
+		// resumes repositories whose initial indexing is not running
+		imported := domain.RepositoryFromArchive(archived)
+		imported.Index = false
+		repo, err = u.repositoryMetaRepository.SaveRepoMetadata(ctx, imported)
+	}
+	if err != nil {
+		return summary, err
+	}
+	authors := make(map[uint]domain.Author)
+	batch := make([]domain.Commit, 0, archiveBatch)
+	flush := func() error {
+		if err := u.saveBatch(ctx, *repo, batch, &summary); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		if progress != nil {
+			progress(summary)
+		}
+		return nil
+	}
+
+	for {
+		recordType, data, err := ar.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return summary, fmt.Errorf("%w: %s", errcodes.ErrInvalidArchive, err.Error())
+		}
+
+		switch recordType {
+		case recordAuthor:
+			var author dtos.ArchiveAuthor
+			if err := json.Unmarshal(data, &author); err != nil {
+				return summary, fmt.Errorf("%w: %s", errcodes.ErrInvalidArchive, err.Error())
+			}
+			authors[author.ID] = domain.Author{Name: author.Name, Email: author.Email}
+			summary.Authors++
+
+		case recordCommit:
+			var commit dtos.ArchiveCommit
+			if err := json.Unmarshal(data, &commit); err != nil {
+				return summary, fmt.Errorf("%w: %s", errcodes.ErrInvalidArchive, err.Error())
+			}
+			author, ok := authors[commit.AuthorID]
+			if !ok {
+				return summary, fmt.Errorf("%w: commit %s comes before its author", errcodes.ErrInvalidArchive, commit.Hash)
+			}
+			batch = append(batch, domain.CommitFromArchive(commit, author))
+			if len(batch) == archiveBatch {
+				if err := flush(); err != nil {
+					return summary, err
+				}
+			}
+
+		default:
+			return summary, fmt.Errorf("%w: unknown %q record", errcodes.ErrInvalidArchive, recordType)
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return summary, err
+		}
+	}
+
+	_, err = u.commitRepository.ResolveCommitLinks(ctx, repo.ID)
+	return summary, err
+}
+
+// saveBatch stores the commits of a batch that are not stored yet and the files
+// of the commits whose files are in the archive but not stored.
+func (u *archiveUsecase) saveBatch(ctx context.Context, repo domain.RepositoryMeta, batch []domain.Commit, summary *domain.ArchiveSummary) error {
+	saved, err := u.commitRepository.ImportCommits(ctx, repo, batch)
+	if err != nil {
+		return err
+	}
+	summary.Commits += len(batch)
+	summary.Imported += len(saved)
+
+	ids := make(map[string]uint, len(saved))
+	for _, c := range saved {
+		ids[c.Hash] = c.ID
+	}
+
+	for _, c := range batch {
+		if !c.FilesFetched {
+			continue
+		}
+
+		id, ok := ids[c.Hash]
+		if !ok {
+			// stored by an earlier import, SaveCommitFiles skips it when its
+			// files are stored too
+			stored, err := u.commitRepository.GetCommitByHash(ctx, c.Hash)
+			if err != nil {
+				return err
+			}
+			id = stored.ID
+		}
+
+		c.ID = id
+		c.RepoID = repo.ID
+		if err := u.commitRepository.SaveCommitFiles(ctx, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextRecord decodes the next record of an archive, which must be of the given
+// type.
+func nextRecord(ar *archive.Reader, recordType string, v interface{}) error {
+	got, data, err := ar.Next()
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: no %s record", errcodes.ErrInvalidArchive, recordType)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", errcodes.ErrInvalidArchive, err.Error())
+	}
+	if got != recordType {
+		return fmt.Errorf("%w: expected a %s record, got %q", errcodes.ErrInvalidArchive, recordType, got)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %s", errcodes.ErrInvalidArchive, err.Error())
+	}
+	return nil
+}
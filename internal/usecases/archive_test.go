@@ -0,0 +1,173 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/pkg/archive"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryArchiveUsecase() (ArchiveUsecase, repository.RepositoryMetaRepository, repository.CommitRepository) {
+	store := repository.NewMemoryStore()
+	repoMetaRepository := repository.NewMemoryRepositoryMetaRepository(store)
+	commitRepository := repository.NewMemoryCommitRepository(store)
+	return NewArchiveUsecase(repoMetaRepository, commitRepository), repoMetaRepository, commitRepository
+}
+
+// TestArchiveUsecase_RoundTrip tests that a repository exported while it was
+// being indexed is imported with its cursor, authors, files and links and its
+// index flag cleared, and that importing it twice stores nothing new
+func TestArchiveUsecase_RoundTrip(t *testing.T) {
+	// Arrange
+	ctx := context.TODO()
+	source, sourceRepos, sourceCommits := newMemoryArchiveUsecase()
+
+	repo, err := sourceRepos.SaveRepoMetadata(ctx, domain.RepositoryMeta{
+		Name:      "org/repo",
+		OwnerName: "org",
+		Index:     true,
+		Retention: domain.RetentionPolicy{Days: 90},
+	})
+	require.NoError(t, err)
+
+	jane := domain.Author{Name: "Jane", Email: "jane@example.com"}
+	john := domain.Author{Name: "John", Email: "john@example.com"}
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commits := []domain.Commit{
+		{Hash: "a1", Message: "feat: cache", Date: date, Author: jane},
+		{Hash: "a2", Message: "fixup! feat: cache", Date: date.Add(time.Hour), Author: john},
+		{Hash: "a3", Message: "docs: readme", Date: date.Add(2 * time.Hour), Author: jane},
+	}
+	for i := range commits {
+		commits[i].Conventional = domain.ParseConventionalCommit(commits[i].Message)
+		commits[i].Link = domain.ParseCommitLink(commits[i].Message)
+	}
+	repo.LastPage = 4
+	saved, err := sourceCommits.SaveCommits(ctx, *repo, commits)
+	require.NoError(t, err)
+
+	withFiles := saved[0]
+	withFiles.Additions = 3
+	withFiles.Files = []domain.CommitFile{{Path: "cache.go", Additions: 3}}
+	require.NoError(t, sourceCommits.SaveCommitFiles(ctx, withFiles))
+
+	var buf bytes.Buffer
+	exported, err := source.Export(ctx, "org/repo", &buf)
+	require.NoError(t, err)
+
+	target, targetRepos, targetCommits := newMemoryArchiveUsecase()
+
+	// Act
+	var reported []domain.ArchiveSummary
+	imported, err := target.Import(ctx, bytes.NewReader(buf.Bytes()), func(s domain.ArchiveSummary) {
+		reported = append(reported, s)
+	})
+	require.NoError(t, err)
+	again, againErr := target.Import(ctx, bytes.NewReader(buf.Bytes()), nil)
+
+	// Assert
+	assert.Equal(t, domain.ArchiveSummary{Repository: "org/repo", Authors: 2, Commits: 3}, exported)
+	assert.Equal(t, domain.ArchiveSummary{Repository: "org/repo", Authors: 2, Commits: 3, Imported: 3}, imported)
+	assert.Equal(t, []domain.ArchiveSummary{imported}, reported)
+	assert.NoError(t, againErr)
+	assert.Zero(t, again.Imported)
+
+	stored, err := targetRepos.RepoMeta(ctx, "org/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "a3", stored.LastFetchedCommit)
+	assert.Equal(t, 4, stored.LastPage)
+	assert.False(t, stored.Index)
+	assert.Equal(t, domain.RetentionPolicy{Days: 90}, stored.Retention)
+
+	copied, err := targetCommits.RepositoryCommits(ctx, stored.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, copied, 3)
+	assert.Equal(t, withFiles.Files, copied[0].Files)
+	assert.Equal(t, 3, copied[0].Additions)
+	assert.Equal(t, "feat", copied[0].Conventional.Type)
+	assert.Equal(t, "John", copied[1].Author.Name)
+	assert.Equal(t, &domain.CommitLink{Kind: domain.LinkFixup, Target: "feat: cache", Hash: "a1"}, copied[1].Link)
+}
+
+// TestArchiveUsecase_Import_KeepsCursor tests that importing into a stored
+// repository leaves the cursor of its indexer alone, also when the import fails
+// after storing commits
+func TestArchiveUsecase_Import_KeepsCursor(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	source, sourceRepos, sourceCommits := newMemoryArchiveUsecase()
+	repo, err := sourceRepos.SaveRepoMetadata(ctx, domain.RepositoryMeta{Name: "org/repo", OwnerName: "org"})
+	require.NoError(t, err)
+	jane := domain.Author{Name: "Jane", Email: "jane@example.com"}
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = sourceCommits.SaveCommits(ctx, *repo, []domain.Commit{
+		{Hash: "a1", Message: "feat: one", Date: date, Author: jane},
+		{Hash: "a2", Message: "feat: two", Date: date.Add(time.Hour), Author: jane},
+	})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = source.Export(ctx, "org/repo", &buf)
+	require.NoError(t, err)
+
+	target, targetRepos, targetCommits := newMemoryArchiveUsecase()
+	indexed, err := targetRepos.SaveRepoMetadata(ctx, domain.RepositoryMeta{Name: "org/repo", OwnerName: "org"})
+	require.NoError(t, err)
+	indexed.LastPage = 7
+	_, err = targetCommits.SaveCommits(ctx, *indexed, []domain.Commit{{Hash: "b1", Message: "fix: b", Date: date, Author: jane}})
+	require.NoError(t, err)
+
+	// Act
+	imported, err := target.Import(ctx, bytes.NewReader(buf.Bytes()), func(domain.ArchiveSummary) { cancel() })
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, 2, imported.Imported)
+	stored, err := targetRepos.RepoMeta(context.TODO(), "org/repo")
+	require.NoError(t, err)
+	assert.Equal(t, 7, stored.LastPage)
+	assert.Equal(t, "b1", stored.LastFetchedCommit)
+}
+
+// TestArchiveUsecase_Import_ManifestMismatch tests that an archive whose
+// repository record is not the repository of its manifest is rejected
+func TestArchiveUsecase_Import_ManifestMismatch(t *testing.T) {
+	// Arrange
+	uc, repos, _ := newMemoryArchiveUsecase()
+
+	var buf bytes.Buffer
+	aw, err := archive.NewWriter(&buf, "org/repo")
+	require.NoError(t, err)
+	require.NoError(t, aw.Write(recordRepository, dtos.ArchiveRepository{Name: "org/other", Owner: "org"}))
+	require.NoError(t, aw.Close())
+
+	// Act
+	_, err = uc.Import(context.TODO(), &buf, nil)
+
+	// Assert
+	assert.ErrorIs(t, err, errcodes.ErrInvalidArchive)
+	all, err := repos.AllRepoMeta(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+// TestArchiveUsecase_Import_Invalid tests that other files are rejected
+func TestArchiveUsecase_Import_Invalid(t *testing.T) {
+	// Arrange
+	uc, _, _ := newMemoryArchiveUsecase()
+
+	// Act
+	_, err := uc.Import(context.TODO(), bytes.NewReader([]byte("not an archive")), nil)
+
+	// Assert
+	assert.ErrorIs(t, err, errcodes.ErrInvalidArchive)
+}
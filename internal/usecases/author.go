@@ -2,43 +2,122 @@ package usecases
 
 import (
 	"context"
+	"time"
 
 	"github.com/just-nibble/git-service/internal/domain"
 	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/log"
 )
 
 type AuthorUseCase interface {
-	GetTopAuthors(ctx context.Context, repoName string, limit int) ([]domain.Author, error)
+	GetTopAuthors(ctx context.Context, repoName string, query domain.TopAuthorsQuery) ([]domain.Author, error)
+	// GetTopAuthorsAcross ranks authors across a configured group of
+	// repositories, or across every repository when group is empty
+	GetTopAuthorsAcross(ctx context.Context, group string, query domain.TopAuthorsQuery) ([]domain.Author, error)
+	GetAuthorProfile(ctx context.Context, authorID uint) (*domain.AuthorProfile, error)
+	// GetAuthorProfiles returns the profiles of several authors at once, in the
+	// order of authorIDs and without the unknown ones
+	GetAuthorProfiles(ctx context.Context, authorIDs []uint) ([]domain.AuthorProfile, error)
+	// RepairStats recomputes the author aggregates every interval until ctx is done
+	RepairStats(ctx context.Context, interval time.Duration)
 }
 
 type authorUseCase struct {
 	authorRepository repository.AuthorRepository
+	repositoryGroups map[string][]string
+	logger           log.Log
 }
 
-func NewAuthorUseCase(authorRepository repository.AuthorRepository) AuthorUseCase {
+func NewAuthorUseCase(authorRepository repository.AuthorRepository, repositoryGroups map[string][]string, logger log.Log) AuthorUseCase {
 	return &authorUseCase{
 		authorRepository: authorRepository,
+		repositoryGroups: repositoryGroups,
+		logger:           logger,
 	}
 }
 
-func (s *authorUseCase) GetTopAuthors(ctx context.Context, repoName string, limit int) ([]domain.Author, error) {
+func (s *authorUseCase) GetTopAuthors(ctx context.Context, repoName string, query domain.TopAuthorsQuery) ([]domain.Author, error) {
+	query, err := topAuthorsDefaults(query)
+	if err != nil {
+		return nil, err
+	}
 
-	as, err := s.authorRepository.GetTopAuthors(ctx, repoName, limit)
+	authors, err := s.authorRepository.GetTopAuthors(ctx, repoName, query)
 	if err != nil {
-		return []domain.Author{}, nil
+		return nil, err
+	}
+
+	if authors == nil {
+		authors = []domain.Author{}
 	}
 
-	var authors []domain.Author
+	return authors, nil
+}
+
+func (s *authorUseCase) GetTopAuthorsAcross(ctx context.Context, group string, query domain.TopAuthorsQuery) ([]domain.Author, error) {
+	query, err := topAuthorsDefaults(query)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, v := range as {
-		author := domain.Author{
-			Name:        v.Name,
-			Email:       v.Name,
-			CommitCount: v.CommitCount,
+	var repoNames []string
+	if group != "" {
+		var ok bool
+		if repoNames, ok = s.repositoryGroups[group]; !ok {
+			return nil, errcodes.ErrUnknownGroup
 		}
+	}
+
+	authors, err := s.authorRepository.GetTopAuthorsAcross(ctx, repoNames, query)
+	if err != nil {
+		return nil, err
+	}
 
-		authors = append(authors, author)
+	if authors == nil {
+		authors = []domain.Author{}
 	}
 
 	return authors, nil
 }
+
+func (s *authorUseCase) GetAuthorProfile(ctx context.Context, authorID uint) (*domain.AuthorProfile, error) {
+	return s.authorRepository.AuthorProfile(ctx, authorID)
+}
+
+func (s *authorUseCase) GetAuthorProfiles(ctx context.Context, authorIDs []uint) ([]domain.AuthorProfile, error) {
+	return s.authorRepository.AuthorProfiles(ctx, authorIDs)
+}
+
+// RepairStats fixes aggregates that drifted from the commits, e.g. because
+// commits were changed outside of the service. The first run also backfills the
+// aggregates of commits stored before they existed.
+func (s *authorUseCase) RepairStats(ctx context.Context, interval time.Duration) {
+	for ctx.Err() == nil {
+		repaired, err := s.authorRepository.RepairAuthorStats(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error.Printf("Error repairing author stats: %s", err.Error())
+		}
+
+		if repaired > 0 {
+			s.logger.Info.Printf("Repaired %d author stats", repaired)
+		}
+
+		sleepCtx(ctx, interval)
+	}
+}
+
+func topAuthorsDefaults(query domain.TopAuthorsQuery) (domain.TopAuthorsQuery, error) {
+	if query.RankBy == "" {
+		query.RankBy = domain.RankByCommits
+	}
+	if !query.RankBy.Valid() {
+		return query, errcodes.ErrInvalidRanking
+	}
+
+	if !query.Since.IsZero() && !query.Until.IsZero() && !query.Since.Before(query.Until) {
+		return query, errcodes.ErrInvalidTimeRange
+	}
+
+	return query, nil
+}
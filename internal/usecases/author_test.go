@@ -3,9 +3,12 @@ package usecases
 import (
 	"context"
 	"testing"
+	"time"
 
-	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/internal/domain"
 	"github.com/just-nibble/git-service/internal/repository/mocks"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -14,22 +17,115 @@ import (
 func TestAuthorUseCase_GetTopAuthors(t *testing.T) {
 	// Arrange
 	mockAuthorRepository := new(mocks.AuthorRepository)
-	mockAuthors := []repository.Author{
-		{ID: 1, Name: "John Doe", CommitCount: 10},
-		{ID: 2, Name: "Jane Smith", CommitCount: 5},
+	mockAuthors := []domain.Author{
+		{ID: 1, Name: "John Doe", Email: "john.doe@example.com", CommitCount: 10},
+		{ID: 2, Name: "Jane Smith", Email: "jane.smith@example.com", CommitCount: 5},
 	}
 
-	mockAuthorRepository.On("GetTopAuthors", mock.Anything, "repo1", 2).Return(mockAuthors, nil)
+	query := domain.TopAuthorsQuery{Limit: 2, Exclude: []string{"*[bot]"}}
+	expected := query
+	expected.RankBy = domain.RankByCommits
+	mockAuthorRepository.On("GetTopAuthors", mock.Anything, "repo1", expected).Return(mockAuthors, nil)
 
-	uc := NewAuthorUseCase(mockAuthorRepository)
+	uc := NewAuthorUseCase(mockAuthorRepository, nil, *log.NewLogger())
 
 	// Act
-	authors, err := uc.GetTopAuthors(context.TODO(), "repo1", 2)
+	authors, err := uc.GetTopAuthors(context.TODO(), "repo1", query)
 
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(authors))
 	assert.Equal(t, "John Doe", authors[0].Name)
+	assert.Equal(t, "john.doe@example.com", authors[0].Email)
 	assert.Equal(t, "Jane Smith", authors[1].Name)
 	mockAuthorRepository.AssertExpectations(t)
 }
+
+// TestAuthorUseCase_GetTopAuthors_InvalidQuery tests that invalid queries are rejected before querying
+func TestAuthorUseCase_GetTopAuthors_InvalidQuery(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		query domain.TopAuthorsQuery
+		err   error
+	}{
+		{"ranking", domain.TopAuthorsQuery{Limit: 5, RankBy: "stars"}, errcodes.ErrInvalidRanking},
+		{"reversed window", domain.TopAuthorsQuery{Limit: 5, Since: now, Until: now.Add(-time.Hour)}, errcodes.ErrInvalidTimeRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewAuthorUseCase(new(mocks.AuthorRepository), nil, *log.NewLogger())
+
+			_, err := uc.GetTopAuthors(context.TODO(), "repo1", tt.query)
+
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+// TestAuthorUseCase_GetTopAuthorsAcross tests ranking across a configured group of repositories
+func TestAuthorUseCase_GetTopAuthorsAcross(t *testing.T) {
+	// Arrange
+	mockAuthorRepository := new(mocks.AuthorRepository)
+	groups := map[string][]string{"web": {"org/frontend", "org/backend"}}
+
+	query := domain.TopAuthorsQuery{Limit: 10, RankBy: domain.RankByCommits}
+	mockAuthorRepository.On("GetTopAuthorsAcross", mock.Anything, groups["web"], query).Return([]domain.Author{
+		{ID: 1, Name: "John Doe", CommitCount: 12, RepositoryCount: 2},
+	}, nil)
+	mockAuthorRepository.On("GetTopAuthorsAcross", mock.Anything, []string(nil), query).Return([]domain.Author{}, nil)
+
+	uc := NewAuthorUseCase(mockAuthorRepository, groups, *log.NewLogger())
+
+	// Act
+	grouped, err := uc.GetTopAuthorsAcross(context.TODO(), "web", query)
+	assert.NoError(t, err)
+	all, err := uc.GetTopAuthorsAcross(context.TODO(), "", query)
+	assert.NoError(t, err)
+	_, unknownErr := uc.GetTopAuthorsAcross(context.TODO(), "mobile", query)
+
+	// Assert
+	assert.Equal(t, 2, grouped[0].RepositoryCount)
+	assert.Empty(t, all)
+	assert.ErrorIs(t, unknownErr, errcodes.ErrUnknownGroup)
+	mockAuthorRepository.AssertExpectations(t)
+}
+
+func TestLikePattern(t *testing.T) {
+	assert.Equal(t, "%[bot]", domain.LikePattern("*[Bot]"))
+	assert.Equal(t, `ci\_user%`, domain.LikePattern("ci_user*"))
+	assert.Equal(t, `100\%`, domain.LikePattern("100%"))
+}
+
+// TestAuthorUseCase_RepairStats tests that the repair job runs every interval until cancelled
+func TestAuthorUseCase_RepairStats(t *testing.T) {
+	// Arrange
+	mockAuthorRepository := new(mocks.AuthorRepository)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := 0
+	mockAuthorRepository.On("RepairAuthorStats", mock.Anything).Return(int64(2), nil).Run(func(mock.Arguments) {
+		if runs++; runs == 3 {
+			cancel()
+		}
+	})
+
+	uc := NewAuthorUseCase(mockAuthorRepository, nil, *log.NewLogger())
+
+	// Act
+	done := make(chan struct{})
+	go func() {
+		uc.RepairStats(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	// Assert
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RepairStats did not return after the context was cancelled")
+	}
+	mockAuthorRepository.AssertNumberOfCalls(t, "RepairAuthorStats", 3)
+}
@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/git"
+)
+
+const (
+	// defaultChangelogRef is the end of a changelog without a to ref
+	defaultChangelogRef = "HEAD"
+	// maxChangelogCommits bounds the commits rendered into one changelog
+	maxChangelogCommits = 5000
+)
+
+type ChangelogUsecase interface {
+	// GetChangelog groups the commits after from up to and including to. An empty
+	// from starts at the first commit, an empty to ends at the default branch.
+	GetChangelog(ctx context.Context, repoName, from, to string) (*domain.Changelog, error)
+}
+
+type changelogUsecase struct {
+	commitRepository         repository.CommitRepository
+	repositoryMetaRepository repository.RepositoryMetaRepository
+	refs                     refResolver
+}
+
+func NewChangelogUsecase(commitRepository repository.CommitRepository, repositoryMetaRepository repository.RepositoryMetaRepository, tagRepository repository.TagRepository, gitClient git.GitClient) ChangelogUsecase {
+	return &changelogUsecase{
+		commitRepository:         commitRepository,
+		repositoryMetaRepository: repositoryMetaRepository,
+		refs:                     refResolver{commitRepository, tagRepository, gitClient},
+	}
+}
+
+func (u *changelogUsecase) GetChangelog(ctx context.Context, repoName, from, to string) (*domain.Changelog, error) {
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == "" {
+		to = defaultChangelogRef
+	}
+
+	toCommit, err := u.refs.resolve(ctx, *repo, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromCommit *domain.Commit
+	var since time.Time
+	if from != "" {
+		if fromCommit, err = u.refs.resolve(ctx, *repo, from); err != nil {
+			return nil, err
+		}
+		if !fromCommit.Date.Before(toCommit.Date) {
+			return nil, errcodes.ErrInvalidRefRange
+		}
+		since = fromCommit.Date
+	}
+
+	commits, err := u.commitRepository.CommitsBetween(ctx, repo.ID, since, toCommit.Date, maxChangelogCommits+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) > maxChangelogCommits {
+		return nil, errcodes.ErrChangelogTooLarge
+	}
+
+	return domain.NewChangelog(*repo, fromCommit, *toCommit, commits), nil
+}
@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository/mocks"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	gitmocks "github.com/just-nibble/git-service/pkg/git/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChangelogUsecase_GetChangelog tests resolving a stored hash and a tag into a changelog
+func TestChangelogUsecase_GetChangelog(t *testing.T) {
+	// Arrange
+	mockCommitRepository := new(mocks.CommitRepository)
+	mockRepoRepository := new(mocks.RepositoryRepository)
+	mockTagRepository := new(mocks.TagRepository)
+	mockGitClient := new(gitmocks.GitClient)
+
+	repo := &domain.RepositoryMeta{ID: 1, Name: "org/repo"}
+	from := &domain.Commit{Hash: "aaa", RepoID: 1, Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	to := &domain.Commit{Hash: "bbb", Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}
+	commits := []domain.Commit{
+		{Hash: "bbb", Conventional: domain.ConventionalCommit{Type: "feat", Description: "b"}},
+		{Hash: "abc", Conventional: domain.ConventionalCommit{Type: "fix", Description: "a"}},
+	}
+
+	mockRepoRepository.On("RepoMeta", mock.Anything, "org/repo").Return(repo, nil)
+	mockCommitRepository.On("GetCommitByHash", mock.Anything, "aaa").Return(from, nil)
+	mockCommitRepository.On("GetCommitByHash", mock.Anything, "v1.1.0").Return((*domain.Commit)(nil), errcodes.ErrNoRecordFound)
+	mockTagRepository.On("TagByName", mock.Anything, uint(1), "v1.1.0").Return(nil, errcodes.ErrNoRecordFound)
+	mockGitClient.On("ResolveRef", mock.Anything, "org/repo", "v1.1.0").Return(to, nil)
+	mockCommitRepository.On("CommitsBetween", mock.Anything, uint(1), from.Date, to.Date, maxChangelogCommits+1).Return(commits, nil)
+
+	uc := NewChangelogUsecase(mockCommitRepository, mockRepoRepository, mockTagRepository, mockGitClient)
+
+	// Act
+	changelog, err := uc.GetChangelog(context.TODO(), "org/repo", "aaa", "v1.1.0")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, from, changelog.From)
+	assert.Equal(t, "bbb", changelog.To.Hash)
+	require.Len(t, changelog.Sections, 2)
+	assert.Equal(t, "feat", changelog.Sections[0].Type)
+	assert.Equal(t, "fix", changelog.Sections[1].Type)
+}
+
+// TestChangelogUsecase_GetChangelog_ReversedRange tests that from must be older than to
+func TestChangelogUsecase_GetChangelog_ReversedRange(t *testing.T) {
+	// Arrange
+	mockCommitRepository := new(mocks.CommitRepository)
+	mockRepoRepository := new(mocks.RepositoryRepository)
+
+	mockRepoRepository.On("RepoMeta", mock.Anything, "org/repo").Return(&domain.RepositoryMeta{ID: 1, Name: "org/repo"}, nil)
+	mockCommitRepository.On("GetCommitByHash", mock.Anything, "new").Return(&domain.Commit{Hash: "new", RepoID: 1, Date: time.Now()}, nil)
+	mockCommitRepository.On("GetCommitByHash", mock.Anything, "old").Return(&domain.Commit{Hash: "old", RepoID: 1, Date: time.Now().Add(-time.Hour)}, nil)
+
+	uc := NewChangelogUsecase(mockCommitRepository, mockRepoRepository, new(mocks.TagRepository), new(gitmocks.GitClient))
+
+	// Act
+	_, err := uc.GetChangelog(context.TODO(), "org/repo", "new", "old")
+
+	// Assert
+	assert.ErrorIs(t, err, errcodes.ErrInvalidRefRange)
+}
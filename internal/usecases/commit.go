@@ -2,13 +2,30 @@ package usecases
 
 import (
 	"context"
+	"time"
 
 	"github.com/just-nibble/git-service/internal/domain"
 	"github.com/just-nibble/git-service/internal/repository"
 )
 
+// parseBatchSize is the number of stored commits parsed per transaction
+const parseBatchSize = 500
+
 type GitCommitUsecase interface {
-	GetAllCommitsByRepository(ctx context.Context, repoName string, query domain.APIPaging) ([]domain.Commit, error)
+	GetAllCommitsByRepository(ctx context.Context, repoName string, query domain.APIPaging, filter domain.CommitFilter) ([]domain.Commit, domain.PagingInfo, error)
+	// StreamCommits calls fn with every commit of a repository matching filter
+	// in the order of query, without paging
+	StreamCommits(ctx context.Context, repoName string, query domain.APIPaging, filter domain.CommitFilter, fn func(domain.Commit) error) error
+	// SubscribeCommits calls fn with the commits of a repository stored after it
+	// is called, in the order they are stored, checking for them every interval
+	// until ctx is done or fn returns an error
+	SubscribeCommits(ctx context.Context, repoName string, interval time.Duration, fn func(domain.Commit) error) error
+	// ParseStoredMessages parses the messages of commits stored before messages
+	// were parsed on save and returns the number of commits updated
+	ParseStoredMessages(ctx context.Context) (int, error)
+	// LinkStoredCommits stores the links of reverts and fixups stored before
+	// links were parsed on save and returns the number of links stored
+	LinkStoredCommits(ctx context.Context) (int, error)
 }
 
 type gitCommitUsecase struct {
@@ -23,17 +40,113 @@ func NewGitCommitUsecase(commitRepository repository.CommitRepository, repositor
 	}
 }
 
-func (u *gitCommitUsecase) GetAllCommitsByRepository(ctx context.Context, repoName string, query domain.APIPaging) ([]domain.Commit, error) {
+func (u *gitCommitUsecase) GetAllCommitsByRepository(ctx context.Context, repoName string, query domain.APIPaging, filter domain.CommitFilter) ([]domain.Commit, domain.PagingInfo, error) {
 	// Fetch commits from the dbbase
 	repoMetaData, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
 	if err != nil {
-		return nil, err
+		return nil, domain.PagingInfo{}, err
+	}
+
+	commitsResp, pagingInfo, err := u.commitRepository.GetCommitsByRepository(ctx, *repoMetaData, query, filter)
+	if err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	return commitsResp, pagingInfo, nil
+}
+
+func (u *gitCommitUsecase) StreamCommits(ctx context.Context, repoName string, query domain.APIPaging, filter domain.CommitFilter, fn func(domain.Commit) error) error {
+	repoMetaData, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return err
+	}
+
+	return u.commitRepository.StreamCommits(ctx, *repoMetaData, query, filter, fn)
+}
+
+func (u *gitCommitUsecase) SubscribeCommits(ctx context.Context, repoName string, interval time.Duration, fn func(domain.Commit) error) error {
+	repoMetaData, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return err
 	}
 
-	commitsResp, err := u.commitRepository.GetCommitsByRepository(ctx, *repoMetaData, query)
+	lastID, err := u.commitRepository.LastCommitID(ctx, repoMetaData.ID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return commitsResp, nil
+	for {
+		sleepCtx(ctx, interval)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// a full page may be followed by more commits stored since the last check
+		for {
+			commits, err := u.commitRepository.RepositoryCommits(ctx, repoMetaData.ID, lastID, parseBatchSize)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			for _, c := range commits {
+				if err := fn(c); err != nil {
+					return err
+				}
+				lastID = c.ID
+			}
+			if len(commits) < parseBatchSize {
+				break
+			}
+		}
+	}
+}
+
+func (u *gitCommitUsecase) ParseStoredMessages(ctx context.Context) (int, error) {
+	parsed := 0
+
+	for {
+		commits, err := u.commitRepository.UnparsedCommits(ctx, parseBatchSize)
+		if err != nil || len(commits) == 0 {
+			return parsed, err
+		}
+
+		for i := range commits {
+			commits[i].Conventional = domain.ParseConventionalCommit(commits[i].Message)
+		}
+
+		if err := u.commitRepository.UpdateConventional(ctx, commits); err != nil {
+			return parsed, err
+		}
+		parsed += len(commits)
+	}
+}
+
+func (u *gitCommitUsecase) LinkStoredCommits(ctx context.Context) (int, error) {
+	linked := 0
+
+	var afterID uint
+	for {
+		commits, err := u.commitRepository.UnlinkedCommits(ctx, afterID, parseBatchSize)
+		if err != nil || len(commits) == 0 {
+			return linked, err
+		}
+		// candidates are matched loosely, the ones that do not parse are
+		// skipped by moving past them
+		afterID = commits[len(commits)-1].ID
+
+		links := make([]domain.Commit, 0, len(commits))
+		for _, c := range commits {
+			if c.Link = domain.ParseCommitLink(c.Message); c.Link != nil {
+				links = append(links, c)
+			}
+		}
+
+		if err := u.commitRepository.SaveCommitLinks(ctx, links); err != nil {
+			return linked, err
+		}
+		linked += len(links)
+	}
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/just-nibble/git-service/internal/domain"
 	"github.com/just-nibble/git-service/internal/repository/mocks"
+	"github.com/just-nibble/git-service/pkg/errcodes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -46,15 +47,18 @@ func TestGitCommitUsecase_GetAllCommitsByRepository_Success(t *testing.T) {
 
 	// Update the mock to return domain.RepositoryMeta
 	mockRepoRepository.On("RepoMeta", mock.Anything, "repo1").Return(mockRepoMeta, nil)
-	mockCommitRepository.On("GetCommitsByRepository", mock.Anything, *mockRepoMeta, query).Return(mockCommitsResp, nil)
+	mockPagingInfo := domain.PagingInfo{TotalCount: 2, Page: 1, Count: 2}
+	filter := domain.CommitFilter{Types: []string{"feat", "fix"}}
+	mockCommitRepository.On("GetCommitsByRepository", mock.Anything, *mockRepoMeta, query, filter).Return(mockCommitsResp, mockPagingInfo, nil)
 
 	uc := NewGitCommitUsecase(mockCommitRepository, mockRepoRepository)
 
 	// Act
-	commits, err := uc.GetAllCommitsByRepository(context.TODO(), "repo1", query)
+	commits, pagingInfo, err := uc.GetAllCommitsByRepository(context.TODO(), "repo1", query, filter)
 
 	// Assert
 	assert.NoError(t, err)
+	assert.Equal(t, mockPagingInfo, pagingInfo)
 	assert.Equal(t, 2, len(commits))
 	assert.Equal(t, "123", commits[0].Hash)
 	assert.Equal(t, "Initial commit", commits[0].Message)
@@ -71,3 +75,138 @@ func TestGitCommitUsecase_GetAllCommitsByRepository_Success(t *testing.T) {
 	mockRepoRepository.AssertExpectations(t)
 	mockCommitRepository.AssertExpectations(t)
 }
+
+// TestGitCommitUsecase_ParseStoredMessages tests that unparsed commits are parsed in batches
+func TestGitCommitUsecase_ParseStoredMessages(t *testing.T) {
+	// Arrange
+	mockCommitRepository := new(mocks.CommitRepository)
+
+	batch := []domain.Commit{
+		{ID: 1, Message: "feat(api)!: drop v0 routes"},
+		{ID: 2, Message: "Update README"},
+	}
+	mockCommitRepository.On("UnparsedCommits", mock.Anything, parseBatchSize).Return(batch, nil).Once()
+	mockCommitRepository.On("UnparsedCommits", mock.Anything, parseBatchSize).Return([]domain.Commit{}, nil).Once()
+	mockCommitRepository.On("UpdateConventional", mock.Anything, mock.MatchedBy(func(commits []domain.Commit) bool {
+		return commits[0].Conventional == domain.ConventionalCommit{Type: "feat", Scope: "api", Breaking: true, Description: "drop v0 routes"} &&
+			commits[1].Conventional == domain.ConventionalCommit{}
+	})).Return(nil)
+
+	uc := NewGitCommitUsecase(mockCommitRepository, new(mocks.RepositoryRepository))
+
+	// Act
+	parsed, err := uc.ParseStoredMessages(context.TODO())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, parsed)
+	mockCommitRepository.AssertExpectations(t)
+}
+
+// TestGitCommitUsecase_LinkStoredCommits tests that stored reverts and fixups are
+// linked and that candidates which do not parse are skipped
+func TestGitCommitUsecase_LinkStoredCommits(t *testing.T) {
+	// Arrange
+	mockCommitRepository := new(mocks.CommitRepository)
+
+	batch := []domain.Commit{
+		{ID: 3, Message: "Revert \"feat: cache\"\n\nThis reverts commit abcdef1."},
+		{ID: 5, Message: "docs: mention This reverts commit in the guide"},
+		{ID: 8, Message: "fixup! feat: cache"},
+	}
+	mockCommitRepository.On("UnlinkedCommits", mock.Anything, uint(0), parseBatchSize).Return(batch, nil).Once()
+	mockCommitRepository.On("UnlinkedCommits", mock.Anything, uint(8), parseBatchSize).Return([]domain.Commit{}, nil).Once()
+	mockCommitRepository.On("SaveCommitLinks", mock.Anything, mock.MatchedBy(func(commits []domain.Commit) bool {
+		return len(commits) == 2 &&
+			*commits[0].Link == domain.CommitLink{Kind: domain.LinkRevert, Target: "abcdef1"} &&
+			*commits[1].Link == domain.CommitLink{Kind: domain.LinkFixup, Target: "feat: cache"}
+	})).Return(nil)
+
+	uc := NewGitCommitUsecase(mockCommitRepository, new(mocks.RepositoryRepository))
+
+	// Act
+	linked, err := uc.LinkStoredCommits(context.TODO())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, linked)
+	mockCommitRepository.AssertExpectations(t)
+}
+
+// TestGitCommitUsecase_StreamCommits tests that the commits of a repository are
+// streamed and that an unknown repository streams nothing
+func TestGitCommitUsecase_StreamCommits(t *testing.T) {
+	// Arrange
+	mockCommitRepository := new(mocks.CommitRepository)
+	mockRepoRepository := new(mocks.RepositoryRepository)
+
+	repo := &domain.RepositoryMeta{ID: 1, Name: "org/repo"}
+	query := domain.APIPaging{Sort: "date", Direction: "asc"}
+	filter := domain.CommitFilter{Scope: "api"}
+	mockRepoRepository.On("RepoMeta", mock.Anything, "org/repo").Return(repo, nil)
+	mockRepoRepository.On("RepoMeta", mock.Anything, "org/missing").Return((*domain.RepositoryMeta)(nil), errcodes.ErrNoRecordFound)
+	mockCommitRepository.On("StreamCommits", mock.Anything, *repo, query, filter).Return([]domain.Commit{{Hash: "a1"}, {Hash: "a2"}}, nil)
+
+	uc := NewGitCommitUsecase(mockCommitRepository, mockRepoRepository)
+
+	// Act
+	var streamed []string
+	err := uc.StreamCommits(context.TODO(), "org/repo", query, filter, func(c domain.Commit) error {
+		streamed = append(streamed, c.Hash)
+		return nil
+	})
+	missingErr := uc.StreamCommits(context.TODO(), "org/missing", query, filter, func(domain.Commit) error {
+		t.Fatal("no commit is streamed")
+		return nil
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a1", "a2"}, streamed)
+	assert.Equal(t, errcodes.ErrNoRecordFound, missingErr)
+}
+
+// TestGitCommitUsecase_SubscribeCommits tests that only the commits stored after
+// subscribing are sent, each once
+func TestGitCommitUsecase_SubscribeCommits(t *testing.T) {
+	// Arrange
+	mockCommitRepository := new(mocks.CommitRepository)
+	mockRepoRepository := new(mocks.RepositoryRepository)
+
+	repo := &domain.RepositoryMeta{ID: 1, Name: "org/repo"}
+	mockRepoRepository.On("RepoMeta", mock.Anything, "org/repo").Return(repo, nil)
+	mockRepoRepository.On("RepoMeta", mock.Anything, "org/missing").Return((*domain.RepositoryMeta)(nil), errcodes.ErrNoRecordFound)
+	mockCommitRepository.On("LastCommitID", mock.Anything, uint(1)).Return(uint(10), nil)
+	mockCommitRepository.On("RepositoryCommits", mock.Anything, uint(1), uint(10), parseBatchSize).
+		Return([]domain.Commit{{ID: 11, Hash: "a1"}, {ID: 12, Hash: "a2"}}, nil).Once()
+	mockCommitRepository.On("RepositoryCommits", mock.Anything, uint(1), uint(12), parseBatchSize).
+		Return([]domain.Commit{}, nil)
+
+	uc := NewGitCommitUsecase(mockCommitRepository, mockRepoRepository)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	// Act
+	var received []string
+	err := uc.SubscribeCommits(ctx, "org/repo", time.Millisecond, func(c domain.Commit) error {
+		received = append(received, c.Hash)
+		if len(received) == 2 {
+			// the next check must not repeat the commits sent
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				cancel()
+			}()
+		}
+		return nil
+	})
+	missingErr := uc.SubscribeCommits(context.TODO(), "org/missing", time.Millisecond, func(domain.Commit) error {
+		t.Fatal("no commit is sent")
+		return nil
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a1", "a2"}, received)
+	assert.Equal(t, errcodes.ErrNoRecordFound, missingErr)
+	mockCommitRepository.AssertExpectations(t)
+}
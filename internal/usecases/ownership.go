@@ -0,0 +1,143 @@
+package usecases
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+)
+
+const (
+	defaultOwnershipMonths = 6
+	defaultOwnershipLimit  = 10
+	maxOwnershipLimit      = 50
+	// directoryOwners is the number of owners listed per directory
+	directoryOwners = 3
+)
+
+type OwnershipUsecase interface {
+	// GetOwnership ranks the authors of the files under path and of its
+	// directories over the last months, and ranks the files by churn
+	GetOwnership(ctx context.Context, repoName, path string, months, limit int) (*domain.Ownership, error)
+}
+
+type ownershipUsecase struct {
+	statsRepository          repository.StatsRepository
+	repositoryMetaRepository repository.RepositoryMetaRepository
+	// maxMonths is the period files are fetched for
+	maxMonths int
+}
+
+func NewOwnershipUsecase(statsRepository repository.StatsRepository, repositoryMetaRepository repository.RepositoryMetaRepository, maxMonths int) OwnershipUsecase {
+	return &ownershipUsecase{
+		statsRepository:          statsRepository,
+		repositoryMetaRepository: repositoryMetaRepository,
+		maxMonths:                maxMonths,
+	}
+}
+
+// GetOwnership caps months to the period files are fetched for and limit to
+// maxOwnershipLimit. Directories are the direct subdirectories of path, most
+// churned first.
+func (u *ownershipUsecase) GetOwnership(ctx context.Context, repoName, path string, months, limit int) (*domain.Ownership, error) {
+	if months <= 0 {
+		months = defaultOwnershipMonths
+	}
+	if months > u.maxMonths {
+		months = u.maxMonths
+	}
+	if limit <= 0 {
+		limit = defaultOwnershipLimit
+	}
+	if limit > maxOwnershipLimit {
+		limit = maxOwnershipLimit
+	}
+
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	query := domain.OwnershipQuery{
+		RepoID: repo.ID,
+		Path:   domain.PathPrefix(path),
+		Since:  time.Now().UTC().AddDate(0, -months, 0),
+		Limit:  limit,
+	}
+
+	churn, err := u.statsRepository.DirectoryChurn(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	hotspots, err := u.statsRepository.Hotspots(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if hotspots == nil {
+		hotspots = []domain.Hotspot{}
+	}
+
+	ownership := &domain.Ownership{
+		Path:     query.Path,
+		Since:    query.Since,
+		Hotspots: hotspots,
+	}
+
+	ownership.Owners, ownership.BusFactor = domain.Owners(churn)
+	for _, o := range ownership.Owners {
+		ownership.Changes += o.Changes
+		ownership.Churn += o.Churn
+	}
+	ownership.Owners = truncate(ownership.Owners, limit)
+
+	ownership.Directories = directories(query.Path, churn)
+	ownership.Directories = truncate(ownership.Directories, limit)
+
+	return ownership, nil
+}
+
+// directories rolls the churn up into the direct subdirectories of prefix.
+func directories(prefix string, churn []domain.DirectoryChurn) []domain.DirectoryOwnership {
+	byDirectory := make(map[string][]domain.DirectoryChurn)
+	for _, c := range churn {
+		rest := strings.TrimPrefix(c.Directory, prefix)
+		name, _, ok := strings.Cut(rest, "/")
+		if !ok {
+			// files directly in prefix
+			continue
+		}
+		dir := prefix + name + "/"
+		byDirectory[dir] = append(byDirectory[dir], c)
+	}
+
+	dirs := make([]domain.DirectoryOwnership, 0, len(byDirectory))
+	for path, c := range byDirectory {
+		dir := domain.DirectoryOwnership{Path: path}
+		dir.Owners, dir.BusFactor = domain.Owners(c)
+		for _, o := range dir.Owners {
+			dir.Changes += o.Changes
+			dir.Churn += o.Churn
+		}
+		dir.Owners = truncate(dir.Owners, directoryOwners)
+		dirs = append(dirs, dir)
+	}
+
+	sort.Slice(dirs, func(i, j int) bool {
+		if dirs[i].Churn != dirs[j].Churn {
+			return dirs[i].Churn > dirs[j].Churn
+		}
+		return dirs[i].Path < dirs[j].Path
+	})
+	return dirs
+}
+
+func truncate[T any](s []T, n int) []T {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOwnershipUsecase_GetOwnership tests rolling the churn up into the direct
+// subdirectories of the path and the bus factors
+func TestOwnershipUsecase_GetOwnership(t *testing.T) {
+	// Arrange
+	mockStatsRepository := new(mocks.StatsRepository)
+	mockRepoRepository := new(mocks.RepositoryRepository)
+
+	john := domain.Author{ID: 1, Name: "John Doe"}
+	jane := domain.Author{ID: 2, Name: "Jane Doe"}
+
+	mockRepoRepository.On("RepoMeta", mock.Anything, "repo1").Return(&domain.RepositoryMeta{ID: 7, Name: "repo1"}, nil)
+
+	isQuery := mock.MatchedBy(func(q domain.OwnershipQuery) bool {
+		return q.RepoID == 7 && q.Path == "src/" && q.Limit == defaultOwnershipLimit
+	})
+	mockStatsRepository.On("DirectoryChurn", mock.Anything, isQuery).Return([]domain.DirectoryChurn{
+		{Directory: "src/", Author: jane, Changes: 1, Churn: 10},
+		{Directory: "src/net/", Author: john, Changes: 2, Churn: 60},
+		{Directory: "src/net/http/", Author: jane, Changes: 3, Churn: 40},
+		{Directory: "src/io/", Author: jane, Changes: 1, Churn: 20},
+		{Directory: "src/io/", Author: john, Changes: 1, Churn: 20},
+	}, nil)
+	mockStatsRepository.On("Hotspots", mock.Anything, isQuery).Return([]domain.Hotspot(nil), nil)
+
+	uc := NewOwnershipUsecase(mockStatsRepository, mockRepoRepository, 12)
+
+	// Act
+	ownership, err := uc.GetOwnership(context.TODO(), "repo1", "/src", 0, 0)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "src/", ownership.Path)
+	assert.Equal(t, 8, ownership.Changes)
+	assert.Equal(t, 150, ownership.Churn)
+	assert.Equal(t, 1, ownership.BusFactor) // John caused 80 of 150 lines
+	require.Len(t, ownership.Owners, 2)
+	assert.Equal(t, john, ownership.Owners[0].Author)
+	assert.InDelta(t, 80.0/150, ownership.Owners[0].Share, 1e-9)
+
+	require.Len(t, ownership.Directories, 2)
+	assert.Equal(t, "src/net/", ownership.Directories[0].Path)
+	assert.Equal(t, 100, ownership.Directories[0].Churn)
+	assert.Equal(t, 5, ownership.Directories[0].Changes)
+	assert.Equal(t, 1, ownership.Directories[0].BusFactor)
+	assert.Equal(t, "src/io/", ownership.Directories[1].Path)
+	assert.Equal(t, 1, ownership.Directories[1].BusFactor) // an even split still needs one author for half
+	assert.Empty(t, ownership.Hotspots)
+	assert.NotNil(t, ownership.Hotspots)
+}
+
+// TestOwners_BusFactor tests the fewest authors that together caused half of the churn
+func TestOwners_BusFactor(t *testing.T) {
+	churn := func(c ...int) []domain.DirectoryChurn {
+		res := make([]domain.DirectoryChurn, 0, len(c))
+		for i, n := range c {
+			res = append(res, domain.DirectoryChurn{Author: domain.Author{ID: uint(i + 1)}, Churn: n})
+		}
+		return res
+	}
+
+	tests := []struct {
+		name  string
+		churn []domain.DirectoryChurn
+		want  int
+	}{
+		{"no changes", nil, 0},
+		{"single author", churn(10), 1},
+		{"dominant author", churn(60, 20, 20), 1},
+		{"spread", churn(30, 30, 20, 20), 2},
+		{"even", churn(10, 10, 10, 10, 10), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, busFactor := domain.Owners(tt.churn)
+
+			assert.Equal(t, tt.want, busFactor)
+		})
+	}
+}
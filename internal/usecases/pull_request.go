@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+)
+
+type PullRequestUsecase interface {
+	// GetPullRequests pages through the pull requests of a repository, an empty
+	// state matches all of them
+	GetPullRequests(ctx context.Context, repoName string, state domain.PullRequestState, query domain.APIPaging) ([]domain.PullRequest, domain.PagingInfo, error)
+	// PullRequestsForCommit returns the pull requests that introduced a commit
+	PullRequestsForCommit(ctx context.Context, repoName, hash string) ([]domain.PullRequest, error)
+}
+
+type pullRequestUsecase struct {
+	pullRequestRepository    repository.PullRequestRepository
+	repositoryMetaRepository repository.RepositoryMetaRepository
+}
+
+func NewPullRequestUsecase(pullRequestRepository repository.PullRequestRepository, repositoryMetaRepository repository.RepositoryMetaRepository) PullRequestUsecase {
+	return &pullRequestUsecase{
+		pullRequestRepository:    pullRequestRepository,
+		repositoryMetaRepository: repositoryMetaRepository,
+	}
+}
+
+func (u *pullRequestUsecase) GetPullRequests(ctx context.Context, repoName string, state domain.PullRequestState, query domain.APIPaging) ([]domain.PullRequest, domain.PagingInfo, error) {
+	if state != "" && !state.Valid() {
+		return nil, domain.PagingInfo{}, errcodes.ErrInvalidPullState
+	}
+
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	return u.pullRequestRepository.GetPullRequests(ctx, repo.ID, state, query)
+}
+
+func (u *pullRequestUsecase) PullRequestsForCommit(ctx context.Context, repoName, hash string) ([]domain.PullRequest, error) {
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.pullRequestRepository.PullRequestsForCommit(ctx, repo.ID, hash)
+}
@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository/mocks"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestPullRequestUsecase_GetPullRequests tests filtering pull requests by state
+func TestPullRequestUsecase_GetPullRequests(t *testing.T) {
+	// Arrange
+	mockPullRepository := new(mocks.PullRequestRepository)
+	mockRepoRepository := new(mocks.RepositoryRepository)
+
+	query := domain.APIPaging{Limit: 10, Page: 1}
+	prs := []domain.PullRequest{{Number: 3, State: domain.PullRequestMerged}}
+	paging := domain.PagingInfo{TotalCount: 1, Page: 1, Count: 1}
+
+	mockRepoRepository.On("RepoMeta", mock.Anything, "org/repo").Return(&domain.RepositoryMeta{ID: 1}, nil)
+	mockPullRepository.On("GetPullRequests", mock.Anything, uint(1), domain.PullRequestMerged, query).Return(prs, paging, nil)
+
+	uc := NewPullRequestUsecase(mockPullRepository, mockRepoRepository)
+
+	// Act
+	res, pagingInfo, err := uc.GetPullRequests(context.TODO(), "org/repo", domain.PullRequestMerged, query)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, prs, res)
+	assert.Equal(t, paging, pagingInfo)
+}
+
+// TestPullRequestUsecase_GetPullRequests_InvalidState tests that unknown states are rejected
+func TestPullRequestUsecase_GetPullRequests_InvalidState(t *testing.T) {
+	// Arrange
+	mockRepoRepository := new(mocks.RepositoryRepository)
+	uc := NewPullRequestUsecase(new(mocks.PullRequestRepository), mockRepoRepository)
+
+	// Act
+	_, _, err := uc.GetPullRequests(context.TODO(), "org/repo", "draft", domain.APIPaging{})
+
+	// Assert
+	assert.ErrorIs(t, err, errcodes.ErrInvalidPullState)
+	mockRepoRepository.AssertNotCalled(t, "RepoMeta", mock.Anything, mock.Anything)
+}
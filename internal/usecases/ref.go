@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/git"
+)
+
+// refResolver turns the refs accepted by the API into commits of a repository
+type refResolver struct {
+	commitRepository repository.CommitRepository
+	tagRepository    repository.TagRepository
+	gitClient        git.GitClient
+}
+
+// resolve finds a ref as a stored commit hash, then as a stored tag, and falls
+// back to asking the git host, which also knows branches.
+func (r refResolver) resolve(ctx context.Context, repo domain.RepositoryMeta, ref string) (*domain.Commit, error) {
+	commit, err := r.commitRepository.GetCommitByHash(ctx, ref)
+	if err == nil && commit.RepoID == repo.ID {
+		return commit, nil
+	}
+	if err != nil && err != errcodes.ErrNoRecordFound {
+		return nil, err
+	}
+
+	tag, err := r.tagRepository.TagByName(ctx, repo.ID, ref)
+	if err == nil {
+		commit, err := r.commitRepository.GetCommitByHash(ctx, tag.Hash)
+		if err == nil && commit.RepoID == repo.ID {
+			return commit, nil
+		}
+		if err != nil && err != errcodes.ErrNoRecordFound {
+			return nil, err
+		}
+		return &domain.Commit{Hash: tag.Hash, RepoID: repo.ID, Date: tag.Date}, nil
+	}
+	if err != errcodes.ErrNoRecordFound {
+		return nil, err
+	}
+
+	return r.gitClient.ResolveRef(ctx, repo.Name, ref)
+}
@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/git"
+)
+
+type ReleaseUsecase interface {
+	GetTags(ctx context.Context, repoName string, query domain.APIPaging) ([]domain.Tag, domain.PagingInfo, error)
+	GetReleases(ctx context.Context, repoName string, query domain.APIPaging) ([]domain.Release, domain.PagingInfo, error)
+	// Compare pages through the commits after from up to and including to,
+	// where both are commit hashes, tags or branches.
+	Compare(ctx context.Context, repoName, from, to string, query domain.APIPaging) (*domain.Comparison, domain.PagingInfo, error)
+}
+
+type releaseUsecase struct {
+	tagRepository            repository.TagRepository
+	commitRepository         repository.CommitRepository
+	repositoryMetaRepository repository.RepositoryMetaRepository
+	refs                     refResolver
+}
+
+func NewReleaseUsecase(tagRepository repository.TagRepository, commitRepository repository.CommitRepository, repositoryMetaRepository repository.RepositoryMetaRepository, gitClient git.GitClient) ReleaseUsecase {
+	return &releaseUsecase{
+		tagRepository:            tagRepository,
+		commitRepository:         commitRepository,
+		repositoryMetaRepository: repositoryMetaRepository,
+		refs:                     refResolver{commitRepository, tagRepository, gitClient},
+	}
+}
+
+func (u *releaseUsecase) GetTags(ctx context.Context, repoName string, query domain.APIPaging) ([]domain.Tag, domain.PagingInfo, error) {
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	return u.tagRepository.GetTags(ctx, repo.ID, query)
+}
+
+func (u *releaseUsecase) GetReleases(ctx context.Context, repoName string, query domain.APIPaging) ([]domain.Release, domain.PagingInfo, error) {
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	return u.tagRepository.GetReleases(ctx, repo.ID, query)
+}
+
+func (u *releaseUsecase) Compare(ctx context.Context, repoName, from, to string, query domain.APIPaging) (*domain.Comparison, domain.PagingInfo, error) {
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	fromCommit, err := u.refs.resolve(ctx, *repo, from)
+	if err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	toCommit, err := u.refs.resolve(ctx, *repo, to)
+	if err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	if !fromCommit.Date.Before(toCommit.Date) {
+		return nil, domain.PagingInfo{}, errcodes.ErrInvalidRefRange
+	}
+
+	filter := domain.CommitFilter{After: fromCommit.Date, Until: toCommit.Date}
+
+	commits, pagingInfo, err := u.commitRepository.GetCommitsByRepository(ctx, *repo, query, filter)
+	if err != nil {
+		return nil, domain.PagingInfo{}, err
+	}
+
+	return &domain.Comparison{From: *fromCommit, To: *toCommit, Commits: commits}, pagingInfo, nil
+}
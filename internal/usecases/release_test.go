@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository/mocks"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	gitmocks "github.com/just-nibble/git-service/pkg/git/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReleaseUsecase_Compare tests resolving a stored tag and a stored hash into a commit range
+func TestReleaseUsecase_Compare(t *testing.T) {
+	// Arrange
+	mockTagRepository := new(mocks.TagRepository)
+	mockCommitRepository := new(mocks.CommitRepository)
+	mockRepoRepository := new(mocks.RepositoryRepository)
+
+	repo := &domain.RepositoryMeta{ID: 1, Name: "org/repo"}
+	tag := &domain.Tag{Name: "v1.0.0", Hash: "aaa", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	to := &domain.Commit{Hash: "bbb", RepoID: 1, Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}
+	query := domain.APIPaging{Limit: 10, Page: 1}
+	commits := []domain.Commit{{Hash: "bbb"}, {Hash: "abc"}}
+	paging := domain.PagingInfo{TotalCount: 2, Page: 1, Count: 2}
+
+	mockRepoRepository.On("RepoMeta", mock.Anything, "org/repo").Return(repo, nil)
+	mockCommitRepository.On("GetCommitByHash", mock.Anything, "v1.0.0").Return((*domain.Commit)(nil), errcodes.ErrNoRecordFound)
+	mockTagRepository.On("TagByName", mock.Anything, uint(1), "v1.0.0").Return(tag, nil)
+	mockCommitRepository.On("GetCommitByHash", mock.Anything, "aaa").Return((*domain.Commit)(nil), errcodes.ErrNoRecordFound)
+	mockCommitRepository.On("GetCommitByHash", mock.Anything, "bbb").Return(to, nil)
+	mockCommitRepository.On("GetCommitsByRepository", mock.Anything, *repo, query, domain.CommitFilter{After: tag.Date, Until: to.Date}).
+		Return(commits, paging, nil)
+
+	uc := NewReleaseUsecase(mockTagRepository, mockCommitRepository, mockRepoRepository, new(gitmocks.GitClient))
+
+	// Act
+	comparison, pagingInfo, err := uc.Compare(context.TODO(), "org/repo", "v1.0.0", "bbb", query)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "aaa", comparison.From.Hash)
+	assert.Equal(t, tag.Date, comparison.From.Date)
+	assert.Equal(t, *to, comparison.To)
+	assert.Equal(t, commits, comparison.Commits)
+	assert.Equal(t, paging, pagingInfo)
+}
@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/just-nibble/git-service/internal/domain"
@@ -18,26 +19,70 @@ type RepoMetaUsecase interface {
 	InitiateIndexing(ctx context.Context, input dtos.RepositoryInput) (*domain.RepositoryMeta, error)
 	FindRepoByName(ctx context.Context, name string) (*domain.RepositoryMeta, error)
 	RetrieveAllRepos(ctx context.Context) ([]domain.RepositoryMeta, error)
+	RemoveRepository(ctx context.Context, name string) error
+	ReindexRepository(ctx context.Context, name string) (*domain.RepositoryMeta, error)
+	// RegisterRepository saves a repository without indexing it, the monitor
+	// indexes it from the first page once ResumeIndexing runs
+	RegisterRepository(ctx context.Context, input dtos.RepositoryInput) (*domain.RepositoryMeta, error)
+	// ResetRepository discards the stored commits of a repository without
+	// indexing it again, the monitor does once ResumeIndexing runs
+	ResetRepository(ctx context.Context, name string) (*domain.RepositoryMeta, error)
 	ResumeIndexing(ctx context.Context) error
-	ModifyRepoStatus(ctx context.Context, active bool) error
+	Shutdown(ctx context.Context) error
 }
+
+const (
+	// checkpointTimeout bounds the write an interrupted job makes to persist its cursor.
+	checkpointTimeout = 5 * time.Second
+	// listPerPage is the page size tags, releases and pull requests are fetched with
+	listPerPage = 100
+	// commitFilesBatch bounds the commits whose files are fetched per monitor tick
+	commitFilesBatch = 500
+)
+
 type repoMetaUsecase struct {
 	repoMetaRepo repository.RepositoryMetaRepository
 	commitRepo   repository.CommitRepository
 	authorRepo   repository.AuthorRepository
+	tagRepo      repository.TagRepository
+	pullRepo     repository.PullRequestRepository
 	gitClient    git.GitClient
 	cfg          config.Config
 	logger       log.Log
+
+	// jobsCtx is the parent context of every background indexing job, it is
+	// cancelled by Shutdown.
+	jobsCtx    context.Context
+	cancelJobs context.CancelFunc
+	jobs       sync.WaitGroup
+	mu         sync.Mutex
+	stopped    bool
+	repoJobs   map[uint]*jobScope
 }
 
-func NewrepoMetaUsecase(repoMetaRepo repository.RepositoryMetaRepository, commitRepo repository.CommitRepository, authorRepo repository.AuthorRepository, gitClient git.GitClient, cfg config.Config, logger log.Log) *repoMetaUsecase {
+// jobScope groups the jobs of a single repository so they can be stopped
+// together when the repository is removed or re-indexed.
+type jobScope struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewrepoMetaUsecase(repoMetaRepo repository.RepositoryMetaRepository, commitRepo repository.CommitRepository, authorRepo repository.AuthorRepository, tagRepo repository.TagRepository, pullRepo repository.PullRequestRepository, gitClient git.GitClient, cfg config.Config, logger log.Log) *repoMetaUsecase {
+	jobsCtx, cancelJobs := context.WithCancel(context.Background())
+
 	return &repoMetaUsecase{
 		repoMetaRepo: repoMetaRepo,
 		commitRepo:   commitRepo,
 		authorRepo:   authorRepo,
+		tagRepo:      tagRepo,
+		pullRepo:     pullRepo,
 		gitClient:    gitClient,
 		cfg:          cfg,
 		logger:       logger,
+		jobsCtx:      jobsCtx,
+		cancelJobs:   cancelJobs,
+		repoJobs:     make(map[uint]*jobScope),
 	}
 }
 
@@ -61,16 +106,128 @@ func (uc *repoMetaUsecase) RetrieveAllRepos(ctx context.Context) ([]domain.Repos
 	return repos, nil
 }
 
-func (uc *repoMetaUsecase) ModifyRepoStatus(ctx context.Context, active bool) error {
-	if err := uc.repoMetaRepo.UpdateRepositoryStatus(ctx, active); err != nil {
-		uc.logger.Error.Printf("Failed to update repository status to %v: %s", active, err.Error())
+// Shutdown cancels all running indexing jobs and waits for them to checkpoint
+// their progress. It returns ctx.Err() if the jobs do not finish before ctx is done.
+func (uc *repoMetaUsecase) Shutdown(ctx context.Context) error {
+	uc.mu.Lock()
+	uc.stopped = true
+	uc.mu.Unlock()
+
+	uc.cancelJobs()
+
+	done := make(chan struct{})
+	go func() {
+		uc.jobs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		uc.logger.Info.Println("All indexing jobs stopped")
+		return nil
+	case <-ctx.Done():
+		uc.logger.Error.Printf("Timed out waiting for indexing jobs to stop: %s", ctx.Err())
+		return ctx.Err()
+	}
+}
+
+// startJob runs job in the background with the job context of repoID, unless
+// Shutdown has already been called.
+func (uc *repoMetaUsecase) startJob(repoID uint, job func(ctx context.Context)) bool {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.stopped {
+		return false
+	}
+
+	scope, ok := uc.repoJobs[repoID]
+	if !ok {
+		ctx, cancel := context.WithCancel(uc.jobsCtx)
+		scope = &jobScope{ctx: ctx, cancel: cancel}
+		uc.repoJobs[repoID] = scope
+	}
+
+	uc.jobs.Add(1)
+	scope.wg.Add(1)
+	go func() {
+		defer uc.jobs.Done()
+		defer scope.wg.Done()
+		job(scope.ctx)
+	}()
+	return true
+}
+
+// stopRepoJobs cancels the jobs of repoID and waits until they have checkpointed.
+func (uc *repoMetaUsecase) stopRepoJobs(repoID uint) {
+	uc.mu.Lock()
+	scope, ok := uc.repoJobs[repoID]
+	delete(uc.repoJobs, repoID)
+	uc.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	scope.cancel()
+	scope.wg.Wait()
+}
+
+// checkpoint persists the cursor of a job that was stopped before finishing.
+// The job context is already cancelled at this point, so a fresh one is used.
+func (uc *repoMetaUsecase) checkpoint(repo domain.RepositoryMeta, lastCommit string, page int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), checkpointTimeout)
+	defer cancel()
+
+	repo.LastFetchedCommit = lastCommit
+	repo.LastPage = page
+	if _, err := uc.repoMetaRepo.UpdateRepoMetadata(ctx, repo); err != nil {
+		uc.logger.Error.Printf("Failed to checkpoint repository %s at page %d: %s", repo.Name, page, err.Error())
 		return err
 	}
-	uc.logger.Info.Printf("Repository status successfully updated to %v", active)
+
+	if repo.Index {
+		// The initial indexing was interrupted, clear the flag so the monitor
+		// picks the repository up from its cursor on the next start.
+		if err := uc.repoMetaRepo.UpdateRepositoryStatus(ctx, repo.ID, false); err != nil {
+			uc.logger.Error.Printf("Failed to reset index flag for repository %s: %s", repo.Name, err.Error())
+			return err
+		}
+	}
+
+	uc.logger.Info.Printf("Checkpointed repository %s at page %d", repo.Name, page)
 	return nil
 }
 
 func (uc *repoMetaUsecase) InitiateIndexing(ctx context.Context, input dtos.RepositoryInput) (*domain.RepositoryMeta, error) {
+	savedRepoMeta, err := uc.saveRepository(ctx, input, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if !uc.startJob(savedRepoMeta.ID, func(ctx context.Context) { uc.processIndexing(ctx, *savedRepoMeta) }) {
+		uc.logger.Error.Printf("Indexing for repository %s not started, service is shutting down", input.Name)
+		return savedRepoMeta, nil
+	}
+
+	uc.logger.Info.Printf("Indexing initiated for repository %s", input.Name)
+
+	return savedRepoMeta, nil
+}
+
+func (uc *repoMetaUsecase) RegisterRepository(ctx context.Context, input dtos.RepositoryInput) (*domain.RepositoryMeta, error) {
+	savedRepoMeta, err := uc.saveRepository(ctx, input, false)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info.Printf("Repository %s registered for indexing", input.Name)
+	return savedRepoMeta, nil
+}
+
+// saveRepository fetches the metadata of a repository that is not added yet and
+// saves it with its index flag set to index.
+func (uc *repoMetaUsecase) saveRepository(ctx context.Context, input dtos.RepositoryInput, index bool) (*domain.RepositoryMeta, error) {
 	if !validator.IsRepository(input.Name) {
 		uc.logger.Error.Printf("Invalid format for repository name: %s", input.Name)
 		return nil, errcodes.ErrInvalidRepositoryName
@@ -93,61 +250,146 @@ func (uc *repoMetaUsecase) InitiateIndexing(ctx context.Context, input dtos.Repo
 		return nil, err
 	}
 
-	repoMeta.Index = true
+	repoMeta.Index = index
 
 	savedRepoMeta, err := uc.repoMetaRepo.SaveRepoMetadata(ctx, *repoMeta)
 	if err != nil {
 		uc.logger.Error.Printf("Failed to save metadata for repository %s: %s", input.Name, err.Error())
 		return nil, err
 	}
+	return savedRepoMeta, nil
+}
 
-	uc.logger.Info.Printf("Indexing initiated for repository %s", input.Name)
-	go uc.processIndexing(ctx, *savedRepoMeta)
+// RemoveRepository stops the jobs of a repository and deletes it with its commits.
+func (uc *repoMetaUsecase) RemoveRepository(ctx context.Context, name string) error {
+	repo, err := uc.repoMetaRepo.RepoMeta(ctx, name)
+	if err != nil {
+		uc.logger.Error.Printf("Could not find repository named %s: %s", name, err.Error())
+		return err
+	}
 
-	return savedRepoMeta, nil
+	uc.stopRepoJobs(repo.ID)
+
+	if err := uc.repoMetaRepo.DeleteRepoMeta(ctx, repo.ID); err != nil {
+		uc.logger.Error.Printf("Failed to remove repository %s: %s", name, err.Error())
+		return err
+	}
+
+	uc.logger.Info.Printf("Repository %s removed", name)
+	return nil
+}
+
+// ReindexRepository discards the stored commits of a repository and indexes it
+// again from the first page.
+func (uc *repoMetaUsecase) ReindexRepository(ctx context.Context, name string) (*domain.RepositoryMeta, error) {
+	repo, err := uc.repoMetaRepo.RepoMeta(ctx, name)
+	if err != nil {
+		uc.logger.Error.Printf("Could not find repository named %s: %s", name, err.Error())
+		return nil, err
+	}
+
+	uc.stopRepoJobs(repo.ID)
+
+	repo, err = uc.repoMetaRepo.ResetRepoMeta(ctx, repo.ID)
+	if err != nil {
+		uc.logger.Error.Printf("Failed to reset repository %s: %s", name, err.Error())
+		return nil, err
+	}
+
+	reset := *repo
+	if !uc.startJob(reset.ID, func(ctx context.Context) { uc.processIndexing(ctx, reset) }) {
+		uc.logger.Error.Printf("Re-indexing for repository %s not started, service is shutting down", name)
+		return repo, nil
+	}
+	uc.startJob(reset.ID, func(ctx context.Context) { uc.monitorCommits(ctx, reset) })
+
+	uc.logger.Info.Printf("Re-indexing initiated for repository %s", name)
+	return repo, nil
+}
+
+func (uc *repoMetaUsecase) ResetRepository(ctx context.Context, name string) (*domain.RepositoryMeta, error) {
+	repo, err := uc.repoMetaRepo.RepoMeta(ctx, name)
+	if err != nil {
+		uc.logger.Error.Printf("Could not find repository named %s: %s", name, err.Error())
+		return nil, err
+	}
+
+	repo, err = uc.repoMetaRepo.ResetRepoMeta(ctx, repo.ID)
+	if err != nil {
+		uc.logger.Error.Printf("Failed to reset repository %s: %s", name, err.Error())
+		return nil, err
+	}
+
+	// Clear the flag so the monitor indexes the repository from the first page
+	if err := uc.repoMetaRepo.UpdateRepositoryStatus(ctx, repo.ID, false); err != nil {
+		uc.logger.Error.Printf("Failed to reset index flag for repository %s: %s", name, err.Error())
+		return nil, err
+	}
+	repo.Index = false
+
+	uc.logger.Info.Printf("Repository %s reset for re-indexing", name)
+	return repo, nil
 }
 
 func (uc *repoMetaUsecase) processIndexing(ctx context.Context, repo domain.RepositoryMeta) {
 	page := repo.LastPage
-	var latestCommit string
+	latestCommit := repo.LastFetchedCommit
+	// every page of the run is fetched and filtered with the same cutoff
+	cutoff := uc.retentionPolicy(repo).Cutoff(time.Now())
+	since := uc.since(cutoff)
 
 	uc.logger.Info.Printf("Starting commit retrieval for repository %s from page %d", repo.Name, page)
 	for {
 		select {
 		case <-ctx.Done():
 			uc.logger.Info.Printf("Indexing operation canceled for repository %s", repo.Name)
+			uc.checkpoint(repo, latestCommit, page)
 			return
 		default:
-			commits, hasMore, err := uc.gitClient.FetchCommits(ctx, repo, uc.cfg.DefaultStartDate, uc.cfg.DefaultEndDate, "", int(page), uc.cfg.GitCommitFetchPerPage)
-			if err != nil {
-				uc.logger.Error.Printf("Error retrieving commits for repository %s: %s", repo.Name, err.Error())
-				time.Sleep(5 * time.Second)
-				continue
+			commits, hasMore, err := uc.gitClient.FetchCommits(ctx, repo, since, uc.cfg.DefaultEndDate, "", int(page), uc.cfg.GitCommitFetchPerPage)
+			if err == nil {
+				commits, hasMore, err = uc.retainedCommits(ctx, repo, cutoff, commits, hasMore)
 			}
-
-			for _, commit := range commits {
-				commit.RepoID = repo.ID
-				if _, err = uc.commitRepo.SaveCommit(ctx, commit); err != nil {
-					uc.logger.Error.Printf("Error saving commit %s for repository %s: %s", commit.Hash, repo.Name, err.Error())
+			if err != nil {
+				if ctx.Err() != nil {
 					continue
 				}
-				latestCommit = commit.Hash
+				uc.logger.Error.Printf("Error retrieving commits for repository %s: %s", repo.Name, err.Error())
+				sleepCtx(ctx, 5*time.Second)
+				continue
 			}
 
 			repo.LastFetchedCommit = latestCommit
 			repo.LastPage = page
-			if _, err = uc.repoMetaRepo.UpdateRepoMetadata(ctx, repo); err != nil {
-				uc.logger.Error.Printf("Failed to update metadata for repository %s: %v", repo.Name, err)
+			saved, err := uc.commitRepo.SaveCommits(ctx, repo, parseCommits(repo, commits))
+			if err != nil {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err == errcodes.ErrNoRecordFound {
+					// removed by another process, such as the CLI
+					uc.logger.Info.Printf("Repository %s was removed, indexing stopped", repo.Name)
+					return
+				}
+				uc.logger.Error.Printf("Error saving page %d of commits for repository %s: %s", page, repo.Name, err.Error())
+				sleepCtx(ctx, 5*time.Second)
 				continue
 			}
+			if len(saved) > 0 {
+				latestCommit = saved[len(saved)-1].Hash
+			}
 
 			if !hasMore {
 				repo.Index = false
-				if _, err = uc.repoMetaRepo.UpdateRepoMetadata(ctx, repo); err != nil {
+				if err = uc.repoMetaRepo.UpdateRepositoryStatus(ctx, repo.ID, false); err != nil {
 					uc.logger.Error.Printf("Error updating indexing status for repository %s: %s", repo.Name, err.Error())
 				}
 				uc.logger.Info.Printf("Indexing finished for repository %s", repo.Name)
-				break
+				uc.resolveCommitLinks(ctx, repo)
+				uc.syncTags(ctx, repo)
+				uc.syncPullRequests(ctx, repo)
+				uc.syncCommitFiles(ctx, repo)
+				return
 			}
 			page++
 		}
@@ -163,7 +405,7 @@ func (uc *repoMetaUsecase) ResumeIndexing(ctx context.Context) error {
 	}
 
 	for _, repo := range repositories {
-		go uc.monitorCommits(ctx, repo)
+		uc.startJob(repo.ID, func(ctx context.Context) { uc.monitorCommits(ctx, repo) })
 	}
 	return nil
 }
@@ -188,6 +430,11 @@ func (uc *repoMetaUsecase) monitorCommits(ctx context.Context, repo domain.Repos
 				uc.logger.Info.Printf("Resuming commit fetching for repository %s", repo.Name)
 				uc.updateCommits(ctx, *repoMeta)
 			}
+
+			uc.resolveCommitLinks(ctx, *repoMeta)
+			uc.syncTags(ctx, *repoMeta)
+			uc.syncPullRequests(ctx, *repoMeta)
+			uc.syncCommitFiles(ctx, *repoMeta)
 		}
 	}
 }
@@ -197,15 +444,21 @@ func (uc *repoMetaUsecase) updateCommits(ctx context.Context, repo domain.Reposi
 	page := repo.LastPage
 	lastCommit := repo.LastFetchedCommit
 	endDate := uc.cfg.DefaultEndDate
+	cutoff := uc.retentionPolicy(repo).Cutoff(time.Now())
+	since := uc.since(cutoff)
 
 	for {
 		select {
 		case <-ctx.Done():
 			uc.logger.Info.Printf("Commit reconciliation halted for repository %s", repo.Name)
+			uc.checkpoint(repo, lastCommit, page)
 			return
 		default:
-			commits, hasMore, err := uc.gitClient.FetchCommits(ctx, repo, uc.cfg.DefaultStartDate, endDate, lastCommit, int(page), uc.cfg.GitCommitFetchPerPage)
+			commits, hasMore, err := uc.gitClient.FetchCommits(ctx, repo, since, endDate, lastCommit, int(page), uc.cfg.GitCommitFetchPerPage)
 			if err != nil {
+				if ctx.Err() != nil {
+					continue
+				}
 				uc.logger.Error.Printf("Error fetching commits for repository %s: %s", repo.Name, err.Error())
 				return
 			}
@@ -217,34 +470,270 @@ func (uc *repoMetaUsecase) updateCommits(ctx context.Context, repo domain.Reposi
 				continue
 			}
 
-			for _, commit := range commits {
-				if _, err = uc.commitRepo.GetCommitByHash(ctx, commit.Hash); err != nil {
-					if err == errcodes.ErrNoRecordFound {
-						commit.RepoID = repo.ID
-						if _, err = uc.commitRepo.SaveCommit(ctx, commit); err != nil {
-							uc.logger.Error.Printf("Error saving commit %s for repository %s: %s", commit.Hash, repo.Name, err.Error())
-							continue
-						}
-						lastCommit = commit.Hash
-					} else {
-						uc.logger.Error.Printf("Error retrieving commit %s for repository %s: %s", commit.Hash, repo.Name, err.Error())
-					}
+			commits, hasMore, err = uc.retainedCommits(ctx, repo, cutoff, commits, hasMore)
+			if err != nil {
+				if ctx.Err() != nil {
+					continue
 				}
+				uc.logger.Error.Printf("Error applying the retention policy of repository %s: %s", repo.Name, err.Error())
+				return
 			}
 
 			repo.LastFetchedCommit = lastCommit
 			repo.LastPage = page
-			if _, err = uc.repoMetaRepo.UpdateRepoMetadata(ctx, repo); err != nil && err != errcodes.ErrContextCancelled {
-				uc.logger.Error.Printf("Error updating repository metadata %s: %v", repo.Name, err)
+			saved, err := uc.commitRepo.SaveCommits(ctx, repo, parseCommits(repo, commits))
+			if err != nil {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err == errcodes.ErrNoRecordFound {
+					uc.logger.Info.Printf("Repository %s was removed, commit reconciliation stopped", repo.Name)
+					return
+				}
+				uc.logger.Error.Printf("Error saving page %d of commits for repository %s: %s", page, repo.Name, err.Error())
 				return
 			}
+			if len(saved) > 0 {
+				lastCommit = saved[len(saved)-1].Hash
+			}
 
 			if !hasMore {
 				uc.logger.Info.Printf("No more commits to fetch for repository %s", repo.Name)
-				break
+				return
 			}
 			page++
 			endDate = time.Now()
 		}
 	}
 }
+
+// retentionPolicy returns the retention policy of repo, the configured default
+// when it has none of its own.
+func (uc *repoMetaUsecase) retentionPolicy(repo domain.RepositoryMeta) domain.RetentionPolicy {
+	if !repo.Retention.IsZero() {
+		return repo.Retention
+	}
+	return domain.RetentionPolicy{Days: uc.cfg.RetentionDays, Commits: uc.cfg.RetentionCommits}
+}
+
+// since returns the date commits are fetched from, the retention cutoff of an
+// indexing run when it is later than the configured start date.
+func (uc *repoMetaUsecase) since(cutoff time.Time) time.Time {
+	if cutoff.After(uc.cfg.DefaultStartDate) {
+		return cutoff
+	}
+	return uc.cfg.DefaultStartDate
+}
+
+// retainedCommits drops the fetched commits the retention policy of repo would
+// prune, so pruned commits are not indexed again: those dated before cutoff,
+// the age cutoff of the policy at the start of the indexing run, and, once the
+// newest commits it keeps are stored, those older than them.
+// Pages are fetched newest first, hasMore is cleared when every commit of the
+// page was dropped since older pages would be dropped as well.
+func (uc *repoMetaUsecase) retainedCommits(ctx context.Context, repo domain.RepositoryMeta, cutoff time.Time, commits []domain.Commit, hasMore bool) ([]domain.Commit, bool, error) {
+	policy := uc.retentionPolicy(repo)
+	if policy.IsZero() || len(commits) == 0 {
+		return commits, hasMore, nil
+	}
+
+	if policy.Commits > 0 {
+		// the oldest commit that is kept, when as many commits are stored
+		oldest, _, err := uc.commitRepo.GetCommitsByRepository(ctx, repo,
+			domain.APIPaging{Limit: 1, Page: policy.Commits, Sort: "date", Direction: "desc"}, domain.CommitFilter{})
+		if err != nil {
+			return nil, false, err
+		}
+		if len(oldest) > 0 && oldest[0].Date.After(cutoff) {
+			cutoff = oldest[0].Date
+		}
+	}
+	if cutoff.IsZero() {
+		return commits, hasMore, nil
+	}
+
+	retained := make([]domain.Commit, 0, len(commits))
+	for _, commit := range commits {
+		if !commit.Date.Before(cutoff) {
+			retained = append(retained, commit)
+		}
+	}
+	return retained, hasMore && len(retained) > 0, nil
+}
+
+// parseCommits sets the repository and the parsed message of fetched commits.
+func parseCommits(repo domain.RepositoryMeta, commits []domain.Commit) []domain.Commit {
+	for i := range commits {
+		commits[i].RepoID = repo.ID
+		commits[i].Conventional = domain.ParseConventionalCommit(commits[i].Message)
+		commits[i].Link = domain.ParseCommitLink(commits[i].Message)
+	}
+	return commits
+}
+
+// sleepCtx pauses for d or until ctx is done, whichever comes first.
+func sleepCtx(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// resolveCommitLinks links reverts and fixups to the commits they target, which
+// may be indexed after them.
+func (uc *repoMetaUsecase) resolveCommitLinks(ctx context.Context, repo domain.RepositoryMeta) {
+	if _, err := uc.commitRepo.ResolveCommitLinks(ctx, repo.ID); err != nil {
+		uc.logger.Error.Printf("Error resolving commit links for repository %s: %s", repo.Name, err.Error())
+	}
+}
+
+// syncTags mirrors the tags and releases of a repository. The date of a tag is
+// the date of its commit, which is looked up on the git host when the commit is
+// not indexed. Only new or moved tags are looked up.
+func (uc *repoMetaUsecase) syncTags(ctx context.Context, repo domain.RepositoryMeta) {
+	stored, err := uc.tagRepo.AllTags(ctx, repo.ID)
+	if err != nil {
+		uc.logger.Error.Printf("Error retrieving tags for repository %s: %s", repo.Name, err.Error())
+		return
+	}
+
+	known := make(map[string]domain.Tag, len(stored))
+	for _, t := range stored {
+		known[t.Name] = t
+	}
+
+	var tags []domain.Tag
+	for page, hasMore := 1, true; hasMore; page++ {
+		var fetched []domain.Tag
+		fetched, hasMore, err = uc.gitClient.FetchTags(ctx, repo.Name, page, listPerPage)
+		if err != nil {
+			uc.logger.Error.Printf("Error fetching tags for repository %s: %s", repo.Name, err.Error())
+			return
+		}
+
+		for _, tag := range fetched {
+			if old, ok := known[tag.Name]; ok && old.Hash == tag.Hash {
+				tags = append(tags, old)
+				continue
+			}
+
+			commit, err := uc.commitRepo.GetCommitByHash(ctx, tag.Hash)
+			if err == errcodes.ErrNoRecordFound {
+				commit, err = uc.gitClient.ResolveRef(ctx, repo.Name, tag.Hash)
+			}
+			if err != nil {
+				uc.logger.Error.Printf("Error resolving tag %s for repository %s: %s", tag.Name, repo.Name, err.Error())
+				return
+			}
+
+			tag.Date = commit.Date
+			tags = append(tags, tag)
+		}
+	}
+
+	if err = uc.tagRepo.ReplaceTags(ctx, repo.ID, tags); err != nil {
+		uc.logger.Error.Printf("Error saving tags for repository %s: %s", repo.Name, err.Error())
+		return
+	}
+
+	var releases []domain.Release
+	for page, hasMore := 1, true; hasMore; page++ {
+		var fetched []domain.Release
+		fetched, hasMore, err = uc.gitClient.FetchReleases(ctx, repo.Name, page, listPerPage)
+		if err != nil {
+			uc.logger.Error.Printf("Error fetching releases for repository %s: %s", repo.Name, err.Error())
+			return
+		}
+		releases = append(releases, fetched...)
+	}
+
+	if err = uc.tagRepo.ReplaceReleases(ctx, repo.ID, releases); err != nil {
+		uc.logger.Error.Printf("Error saving releases for repository %s: %s", repo.Name, err.Error())
+	}
+}
+
+// syncPullRequests stores the pull requests updated since the last sync with
+// their commits. They are saved oldest first so that an interrupted sync resumes
+// after the last saved pull request.
+func (uc *repoMetaUsecase) syncPullRequests(ctx context.Context, repo domain.RepositoryMeta) {
+	since, err := uc.pullRepo.LastPullRequestUpdate(ctx, repo.ID)
+	if err != nil {
+		uc.logger.Error.Printf("Error retrieving pull requests for repository %s: %s", repo.Name, err.Error())
+		return
+	}
+
+	var updated []domain.PullRequest
+	for page, hasMore := 1, true; hasMore; page++ {
+		var fetched []domain.PullRequest
+		fetched, hasMore, err = uc.gitClient.FetchPullRequests(ctx, repo.Name, page, listPerPage)
+		if err != nil {
+			uc.logger.Error.Printf("Error fetching pull requests for repository %s: %s", repo.Name, err.Error())
+			return
+		}
+
+		for _, pr := range fetched {
+			if pr.UpdatedAt.Before(since) {
+				hasMore = false
+				break
+			}
+			updated = append(updated, pr)
+		}
+	}
+
+	for i := len(updated) - 1; i >= 0; i-- {
+		pr := updated[i]
+		pr.RepoID = repo.ID
+
+		for page, hasMore := 1, true; hasMore; page++ {
+			var hashes []string
+			hashes, hasMore, err = uc.gitClient.FetchPullRequestCommits(ctx, repo.Name, pr.Number, page, listPerPage)
+			if err != nil {
+				uc.logger.Error.Printf("Error fetching commits of pull request #%d for repository %s: %s", pr.Number, repo.Name, err.Error())
+				return
+			}
+			pr.Commits = append(pr.Commits, hashes...)
+		}
+
+		if err = uc.pullRepo.SavePullRequest(ctx, pr); err != nil {
+			uc.logger.Error.Printf("Error saving pull request #%d for repository %s: %s", pr.Number, repo.Name, err.Error())
+			return
+		}
+	}
+
+	if _, err = uc.pullRepo.LinkPullRequestCommits(ctx, repo.ID); err != nil {
+		uc.logger.Error.Printf("Error linking pull request commits for repository %s: %s", repo.Name, err.Error())
+	}
+}
+
+// syncCommitFiles fetches the changed files of the commits made in the last
+// OwnershipMonths, newest first and a batch per call. Commits the git host no
+// longer knows are stored without files.
+func (uc *repoMetaUsecase) syncCommitFiles(ctx context.Context, repo domain.RepositoryMeta) {
+	since := time.Now().AddDate(0, -uc.cfg.OwnershipMonths, 0)
+
+	commits, err := uc.commitRepo.CommitsWithoutFiles(ctx, repo.ID, since, commitFilesBatch)
+	if err != nil {
+		uc.logger.Error.Printf("Error retrieving commits without files for repository %s: %s", repo.Name, err.Error())
+		return
+	}
+
+	for _, commit := range commits {
+		fetched, err := uc.gitClient.FetchCommitFiles(ctx, repo.Name, commit.Hash)
+		if err == errcodes.ErrUnknownRef {
+			fetched, err = &domain.Commit{}, nil
+		}
+		if err != nil {
+			uc.logger.Error.Printf("Error fetching files of commit %s for repository %s: %s", commit.Hash, repo.Name, err.Error())
+			return
+		}
+
+		commit.Additions, commit.Deletions, commit.Files = fetched.Additions, fetched.Deletions, fetched.Files
+		if err := uc.commitRepo.SaveCommitFiles(ctx, commit); err != nil {
+			uc.logger.Error.Printf("Error saving files of commit %s for repository %s: %s", commit.Hash, repo.Name, err.Error())
+			return
+		}
+	}
+}
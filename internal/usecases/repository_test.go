@@ -0,0 +1,333 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/http/dtos"
+	"github.com/just-nibble/git-service/internal/repository/mocks"
+	"github.com/just-nibble/git-service/pkg/config"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	gitmocks "github.com/just-nibble/git-service/pkg/git/mocks"
+	"github.com/just-nibble/git-service/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepoMetaUsecase_Shutdown_CheckpointsInterruptedIndexing tests that an
+// interrupted initial indexing job persists its cursor and clears the index flag
+func TestRepoMetaUsecase_Shutdown_CheckpointsInterruptedIndexing(t *testing.T) {
+	// Arrange
+	mockRepoRepository := new(mocks.RepositoryRepository)
+	mockCommitRepository := new(mocks.CommitRepository)
+	mockGitClient := new(gitmocks.GitClient)
+
+	repoMeta := &domain.RepositoryMeta{ID: 1, Name: "owner/repo", LastPage: 3, LastFetchedCommit: "abc"}
+	savedMeta := *repoMeta
+	savedMeta.Index = true
+
+	fetching := make(chan struct{})
+
+	mockRepoRepository.On("RepoMeta", mock.Anything, "owner/repo").Return((*domain.RepositoryMeta)(nil), errcodes.ErrNoRecordFound)
+	mockGitClient.On("FetchRepoMetadata", mock.Anything, "owner/repo").Return(repoMeta, nil)
+	mockRepoRepository.On("SaveRepoMetadata", mock.Anything, savedMeta).Return(&savedMeta, nil)
+	mockGitClient.On("FetchCommits", mock.Anything, savedMeta, mock.Anything, mock.Anything, "", 3, mock.Anything).
+		Run(func(args mock.Arguments) {
+			close(fetching)
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return([]domain.Commit{}, false, context.Canceled)
+	mockRepoRepository.On("UpdateRepoMetadata", mock.Anything, mock.MatchedBy(func(r domain.RepositoryMeta) bool {
+		return r.ID == 1 && r.LastPage == 3 && r.LastFetchedCommit == "abc"
+	})).Return(&savedMeta, nil)
+	mockRepoRepository.On("UpdateRepositoryStatus", mock.Anything, uint(1), false).Return(nil)
+
+	uc := NewrepoMetaUsecase(mockRepoRepository, mockCommitRepository, nil, nil, nil, mockGitClient, config.Config{}, *log.NewLogger())
+
+	// Act
+	_, err := uc.InitiateIndexing(context.TODO(), dtos.RepositoryInput{Name: "owner/repo"})
+	assert.NoError(t, err)
+	<-fetching
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err = uc.Shutdown(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepoRepository.AssertExpectations(t)
+	mockGitClient.AssertExpectations(t)
+}
+
+// TestRepoMetaUsecase_Shutdown_NoJobs tests that shutting down without running
+// jobs leaves every repository untouched
+func TestRepoMetaUsecase_Shutdown_NoJobs(t *testing.T) {
+	// Arrange
+	mockRepoRepository := new(mocks.RepositoryRepository)
+	mockCommitRepository := new(mocks.CommitRepository)
+	mockGitClient := new(gitmocks.GitClient)
+
+	uc := NewrepoMetaUsecase(mockRepoRepository, mockCommitRepository, nil, nil, nil, mockGitClient, config.Config{}, *log.NewLogger())
+
+	// Act
+	err := uc.Shutdown(context.TODO())
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepoRepository.AssertNotCalled(t, "UpdateRepositoryStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRepoMetaUsecase_RegisterRepository tests that a registered repository is
+// saved with the index flag cleared and no job is started
+func TestRepoMetaUsecase_RegisterRepository(t *testing.T) {
+	// Arrange
+	mockRepoRepository := new(mocks.RepositoryRepository)
+	mockGitClient := new(gitmocks.GitClient)
+
+	repoMeta := &domain.RepositoryMeta{Name: "owner/repo"}
+	savedMeta := domain.RepositoryMeta{ID: 1, Name: "owner/repo"}
+
+	mockRepoRepository.On("RepoMeta", mock.Anything, "owner/repo").Return((*domain.RepositoryMeta)(nil), errcodes.ErrNoRecordFound)
+	mockGitClient.On("FetchRepoMetadata", mock.Anything, "owner/repo").Return(repoMeta, nil)
+	mockRepoRepository.On("SaveRepoMetadata", mock.Anything, domain.RepositoryMeta{Name: "owner/repo"}).Return(&savedMeta, nil)
+
+	uc := NewrepoMetaUsecase(mockRepoRepository, nil, nil, nil, nil, mockGitClient, config.Config{}, *log.NewLogger())
+
+	// Act
+	repo, err := uc.RegisterRepository(context.TODO(), dtos.RepositoryInput{Name: "owner/repo"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, repo.Index)
+	assert.Empty(t, uc.repoJobs)
+	mockRepoRepository.AssertExpectations(t)
+	mockGitClient.AssertExpectations(t)
+	mockGitClient.AssertNotCalled(t, "FetchCommits", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRepoMetaUsecase_ResetRepository tests that a reset repository has its
+// index flag cleared so the monitor indexes it, and no job is started
+func TestRepoMetaUsecase_ResetRepository(t *testing.T) {
+	// Arrange
+	mockRepoRepository := new(mocks.RepositoryRepository)
+
+	stored := &domain.RepositoryMeta{ID: 1, Name: "owner/repo", LastPage: 7, LastFetchedCommit: "abc"}
+	reset := &domain.RepositoryMeta{ID: 1, Name: "owner/repo", Index: true}
+
+	mockRepoRepository.On("RepoMeta", mock.Anything, "owner/repo").Return(stored, nil)
+	mockRepoRepository.On("ResetRepoMeta", mock.Anything, uint(1)).Return(reset, nil)
+	mockRepoRepository.On("UpdateRepositoryStatus", mock.Anything, uint(1), false).Return(nil)
+
+	uc := NewrepoMetaUsecase(mockRepoRepository, nil, nil, nil, nil, nil, config.Config{}, *log.NewLogger())
+
+	// Act
+	repo, err := uc.ResetRepository(context.TODO(), "owner/repo")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, repo.Index)
+	assert.Zero(t, repo.LastPage)
+	assert.Empty(t, uc.repoJobs)
+	mockRepoRepository.AssertExpectations(t)
+}
+
+// TestRepoMetaUsecase_ProcessIndexing_RemovedRepository tests that indexing
+// stops instead of retrying when its repository was removed
+func TestRepoMetaUsecase_ProcessIndexing_RemovedRepository(t *testing.T) {
+	// Arrange
+	mockCommitRepository := new(mocks.CommitRepository)
+	mockGitClient := new(gitmocks.GitClient)
+
+	repo := domain.RepositoryMeta{ID: 1, Name: "owner/repo", Index: true}
+	page := []domain.Commit{{Hash: "aaa", Message: "feat: a"}}
+
+	mockGitClient.On("FetchCommits", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "", 0, mock.Anything).Return(page, true, nil).Once()
+	mockCommitRepository.On("SaveCommits", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Commit(nil), errcodes.ErrNoRecordFound).Once()
+
+	uc := NewrepoMetaUsecase(nil, mockCommitRepository, nil, nil, nil, mockGitClient, config.Config{}, *log.NewLogger())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Act
+	uc.processIndexing(ctx, repo)
+
+	// Assert
+	assert.NoError(t, ctx.Err())
+	mockCommitRepository.AssertExpectations(t)
+	mockGitClient.AssertExpectations(t)
+}
+
+// TestRepoMetaUsecase_UpdateCommits_SavesPages tests that each fetched page is
+// saved with the cursor of its page and the last stored commit
+func TestRepoMetaUsecase_UpdateCommits_SavesPages(t *testing.T) {
+	// Arrange
+	mockCommitRepository := new(mocks.CommitRepository)
+	mockGitClient := new(gitmocks.GitClient)
+
+	repo := domain.RepositoryMeta{ID: 1, Name: "owner/repo", LastPage: 4, LastFetchedCommit: "aaa"}
+	first := []domain.Commit{{Hash: "bbb", Message: "feat: b"}, {Hash: "ccc", Message: "fix: c"}}
+	second := []domain.Commit{{Hash: "ccc", Message: "fix: c"}}
+
+	mockGitClient.On("FetchCommits", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "aaa", 4, mock.Anything).Return(first, true, nil)
+	mockGitClient.On("FetchCommits", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "ccc", 5, mock.Anything).Return(second, false, nil)
+	mockCommitRepository.On("SaveCommits", mock.Anything, mock.MatchedBy(func(r domain.RepositoryMeta) bool {
+		return r.LastPage == 4 && r.LastFetchedCommit == "aaa"
+	}), mock.MatchedBy(func(commits []domain.Commit) bool {
+		return len(commits) == 2 && commits[0].RepoID == 1 && commits[0].Conventional.Type == "feat"
+	})).Return(first, nil)
+	mockCommitRepository.On("SaveCommits", mock.Anything, mock.MatchedBy(func(r domain.RepositoryMeta) bool {
+		return r.LastPage == 5 && r.LastFetchedCommit == "ccc"
+	}), mock.Anything).Return([]domain.Commit{}, nil)
+
+	uc := NewrepoMetaUsecase(nil, mockCommitRepository, nil, nil, nil, mockGitClient, config.Config{}, *log.NewLogger())
+
+	// Act
+	uc.updateCommits(context.TODO(), repo)
+
+	// Assert
+	mockCommitRepository.AssertExpectations(t)
+	mockGitClient.AssertExpectations(t)
+}
+
+// TestRepoMetaUsecase_UpdateCommits_FixedCutoff tests that every page of a
+// run is fetched from the same retention cutoff
+func TestRepoMetaUsecase_UpdateCommits_FixedCutoff(t *testing.T) {
+	// Arrange
+	mockCommitRepository := new(mocks.CommitRepository)
+	mockGitClient := new(gitmocks.GitClient)
+
+	repo := domain.RepositoryMeta{ID: 1, Name: "owner/repo", LastPage: 1}
+	recent := time.Now().Add(-time.Hour)
+	first := []domain.Commit{{Hash: "bbb", Message: "feat: b", Date: recent}}
+	second := []domain.Commit{{Hash: "ccc", Message: "fix: c", Date: recent}}
+
+	var since []time.Time
+	record := func(args mock.Arguments) { since = append(since, args.Get(2).(time.Time)) }
+	mockGitClient.On("FetchCommits", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "", 1, mock.Anything).Run(record).Return(first, true, nil)
+	mockGitClient.On("FetchCommits", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "bbb", 2, mock.Anything).Run(record).Return(second, false, nil)
+	mockCommitRepository.On("SaveCommits", mock.Anything, mock.MatchedBy(func(r domain.RepositoryMeta) bool { return r.LastPage == 1 }), mock.Anything).Return(first, nil)
+	mockCommitRepository.On("SaveCommits", mock.Anything, mock.MatchedBy(func(r domain.RepositoryMeta) bool { return r.LastPage == 2 }), mock.Anything).Return(second, nil)
+
+	uc := NewrepoMetaUsecase(nil, mockCommitRepository, nil, nil, nil, mockGitClient, config.Config{RetentionDays: 30}, *log.NewLogger())
+
+	// Act
+	before := time.Now()
+	uc.updateCommits(context.TODO(), repo)
+
+	// Assert
+	mockGitClient.AssertExpectations(t)
+	mockCommitRepository.AssertExpectations(t)
+	require.Len(t, since, 2)
+	assert.Equal(t, since[0], since[1])
+	assert.WithinDuration(t, before.AddDate(0, 0, -30), since[0], time.Second)
+}
+
+// TestRepoMetaUsecase_SyncTags tests that unchanged tags keep their stored date
+// and moved tags are dated by their commit
+func TestRepoMetaUsecase_SyncTags(t *testing.T) {
+	// Arrange
+	mockCommitRepository := new(mocks.CommitRepository)
+	mockTagRepository := new(mocks.TagRepository)
+	mockGitClient := new(gitmocks.GitClient)
+
+	repo := domain.RepositoryMeta{ID: 1, Name: "owner/repo"}
+	stored := domain.Tag{ID: 7, RepoID: 1, Name: "v1.0.0", Hash: "aaa", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	moved := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	remote := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	releases := []domain.Release{{TagName: "v1.0.0", Name: "First"}}
+
+	mockTagRepository.On("AllTags", mock.Anything, uint(1)).Return([]domain.Tag{stored}, nil)
+	mockGitClient.On("FetchTags", mock.Anything, "owner/repo", 1, listPerPage).
+		Return([]domain.Tag{{Name: "v1.0.0", Hash: "aaa"}, {Name: "v1.1.0", Hash: "bbb"}}, true, nil)
+	mockGitClient.On("FetchTags", mock.Anything, "owner/repo", 2, listPerPage).
+		Return([]domain.Tag{{Name: "v2.0.0", Hash: "ccc"}}, false, nil)
+	mockCommitRepository.On("GetCommitByHash", mock.Anything, "bbb").Return(&domain.Commit{Hash: "bbb", Date: moved}, nil)
+	mockCommitRepository.On("GetCommitByHash", mock.Anything, "ccc").Return((*domain.Commit)(nil), errcodes.ErrNoRecordFound)
+	mockGitClient.On("ResolveRef", mock.Anything, "owner/repo", "ccc").Return(&domain.Commit{Hash: "ccc", Date: remote}, nil)
+	mockTagRepository.On("ReplaceTags", mock.Anything, uint(1), []domain.Tag{
+		stored,
+		{Name: "v1.1.0", Hash: "bbb", Date: moved},
+		{Name: "v2.0.0", Hash: "ccc", Date: remote},
+	}).Return(nil)
+	mockGitClient.On("FetchReleases", mock.Anything, "owner/repo", 1, listPerPage).Return(releases, false, nil)
+	mockTagRepository.On("ReplaceReleases", mock.Anything, uint(1), releases).Return(nil)
+
+	uc := NewrepoMetaUsecase(nil, mockCommitRepository, nil, mockTagRepository, nil, mockGitClient, config.Config{}, *log.NewLogger())
+
+	// Act
+	uc.syncTags(context.TODO(), repo)
+
+	// Assert
+	mockTagRepository.AssertExpectations(t)
+	mockGitClient.AssertExpectations(t)
+	mockCommitRepository.AssertNotCalled(t, "GetCommitByHash", mock.Anything, "aaa")
+}
+
+// TestRepoMetaUsecase_SyncPullRequests tests that pull requests updated since the
+// last sync are saved oldest first with their commits
+func TestRepoMetaUsecase_SyncPullRequests(t *testing.T) {
+	// Arrange
+	mockPullRepository := new(mocks.PullRequestRepository)
+	mockGitClient := new(gitmocks.GitClient)
+
+	repo := domain.RepositoryMeta{ID: 1, Name: "owner/repo"}
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := domain.PullRequest{Number: 2, State: domain.PullRequestOpen, UpdatedAt: since.Add(2 * time.Hour)}
+	older := domain.PullRequest{Number: 1, State: domain.PullRequestMerged, UpdatedAt: since.Add(time.Hour)}
+	stale := domain.PullRequest{Number: 0, State: domain.PullRequestClosed, UpdatedAt: since.Add(-time.Hour)}
+
+	var saved []int
+	mockPullRepository.On("LastPullRequestUpdate", mock.Anything, uint(1)).Return(since, nil)
+	mockGitClient.On("FetchPullRequests", mock.Anything, "owner/repo", 1, listPerPage).Return([]domain.PullRequest{newer, older, stale}, true, nil)
+	mockGitClient.On("FetchPullRequestCommits", mock.Anything, "owner/repo", 1, 1, listPerPage).Return([]string{"aaa"}, true, nil)
+	mockGitClient.On("FetchPullRequestCommits", mock.Anything, "owner/repo", 1, 2, listPerPage).Return([]string{"bbb"}, false, nil)
+	mockGitClient.On("FetchPullRequestCommits", mock.Anything, "owner/repo", 2, 1, listPerPage).Return([]string{"ccc"}, false, nil)
+	mockPullRepository.On("SavePullRequest", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { saved = append(saved, args.Get(1).(domain.PullRequest).Number) }).
+		Return(nil)
+	mockPullRepository.On("LinkPullRequestCommits", mock.Anything, uint(1)).Return(int64(0), nil)
+
+	uc := NewrepoMetaUsecase(nil, nil, nil, nil, mockPullRepository, mockGitClient, config.Config{}, *log.NewLogger())
+
+	// Act
+	uc.syncPullRequests(context.TODO(), repo)
+
+	// Assert
+	assert.Equal(t, []int{1, 2}, saved)
+	mockPullRepository.AssertCalled(t, "SavePullRequest", mock.Anything, domain.PullRequest{
+		RepoID: 1, Number: 1, State: domain.PullRequestMerged, UpdatedAt: older.UpdatedAt, Commits: []string{"aaa", "bbb"},
+	})
+	mockGitClient.AssertNotCalled(t, "FetchPullRequests", mock.Anything, "owner/repo", 2, listPerPage)
+	mockPullRepository.AssertExpectations(t)
+}
+
+// TestRepoMetaUsecase_SyncCommitFiles tests that fetched files are saved and that
+// commits unknown to the git host are saved without files
+func TestRepoMetaUsecase_SyncCommitFiles(t *testing.T) {
+	// Arrange
+	mockCommitRepository := new(mocks.CommitRepository)
+	mockGitClient := new(gitmocks.GitClient)
+
+	repo := domain.RepositoryMeta{ID: 1, Name: "owner/repo"}
+	files := []domain.CommitFile{{Path: "src/main.go", Additions: 3, Deletions: 1}}
+
+	mockCommitRepository.On("CommitsWithoutFiles", mock.Anything, uint(1), mock.Anything, commitFilesBatch).
+		Return([]domain.Commit{{ID: 1, Hash: "aaa"}, {ID: 2, Hash: "bbb"}}, nil)
+	mockGitClient.On("FetchCommitFiles", mock.Anything, "owner/repo", "aaa").
+		Return(&domain.Commit{Hash: "aaa", Additions: 3, Deletions: 1, Files: files}, nil)
+	mockGitClient.On("FetchCommitFiles", mock.Anything, "owner/repo", "bbb").Return(nil, errcodes.ErrUnknownRef)
+	mockCommitRepository.On("SaveCommitFiles", mock.Anything, mock.Anything).Return(nil)
+
+	uc := NewrepoMetaUsecase(nil, mockCommitRepository, nil, nil, nil, mockGitClient, config.Config{OwnershipMonths: 12}, *log.NewLogger())
+
+	// Act
+	uc.syncCommitFiles(context.TODO(), repo)
+
+	// Assert
+	mockCommitRepository.AssertCalled(t, "SaveCommitFiles", mock.Anything, domain.Commit{ID: 1, Hash: "aaa", Additions: 3, Deletions: 1, Files: files})
+	mockCommitRepository.AssertCalled(t, "SaveCommitFiles", mock.Anything, domain.Commit{ID: 2, Hash: "bbb"})
+}
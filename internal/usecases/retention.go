@@ -0,0 +1,114 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/log"
+)
+
+// pruneBatch bounds the commits deleted per transaction
+const pruneBatch = 1000
+
+type RetentionUsecase interface {
+	// SetRetention sets the retention policy of a repository, a zero policy
+	// falls back to the default one
+	SetRetention(ctx context.Context, repoName string, policy domain.RetentionPolicy) (*domain.RepositoryMeta, error)
+	// PruneCommits deletes the expired commits of every repository and returns
+	// how many were deleted
+	PruneCommits(ctx context.Context) (int64, error)
+	// Prune prunes commits every interval until ctx is done
+	Prune(ctx context.Context, interval time.Duration)
+}
+
+type retentionUsecase struct {
+	repositoryMetaRepository repository.RepositoryMetaRepository
+	commitRepository         repository.CommitRepository
+	// defaultPolicy applies to repositories without a policy of their own
+	defaultPolicy domain.RetentionPolicy
+	logger        log.Log
+}
+
+func NewRetentionUsecase(repositoryMetaRepository repository.RepositoryMetaRepository, commitRepository repository.CommitRepository, defaultPolicy domain.RetentionPolicy, logger log.Log) RetentionUsecase {
+	return &retentionUsecase{
+		repositoryMetaRepository: repositoryMetaRepository,
+		commitRepository:         commitRepository,
+		defaultPolicy:            defaultPolicy,
+		logger:                   logger,
+	}
+}
+
+func (u *retentionUsecase) SetRetention(ctx context.Context, repoName string, policy domain.RetentionPolicy) (*domain.RepositoryMeta, error) {
+	if !policy.Valid() {
+		return nil, errcodes.ErrInvalidRetention
+	}
+
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repositoryMetaRepository.UpdateRetention(ctx, repo.ID, policy); err != nil {
+		return nil, err
+	}
+
+	repo.Retention = policy
+	return repo, nil
+}
+
+// PruneCommits deletes the expired commits of each repository in batches, so
+// indexing jobs are only blocked for one batch at a time.
+func (u *retentionUsecase) PruneCommits(ctx context.Context) (int64, error) {
+	repos, err := u.repositoryMetaRepository.AllRepoMeta(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+
+	var total int64
+	for _, repo := range repos {
+		policy := repo.Retention
+		if policy.IsZero() {
+			policy = u.defaultPolicy
+		}
+		if policy.IsZero() {
+			continue
+		}
+
+		for {
+			pruned, err := u.commitRepository.PruneCommits(ctx, repo.ID, policy.Cutoff(now), policy.Commits, pruneBatch)
+			total += pruned
+			// the repository was deleted since it was listed
+			if err == errcodes.ErrNoRecordFound {
+				break
+			}
+			if err != nil {
+				return total, err
+			}
+			if pruned < pruneBatch {
+				break
+			}
+		}
+	}
+
+	return total, nil
+}
+
+func (u *retentionUsecase) Prune(ctx context.Context, interval time.Duration) {
+	for ctx.Err() == nil {
+		pruned, err := u.PruneCommits(ctx)
+		if err != nil && ctx.Err() == nil {
+			u.logger.Error.Printf("Error pruning expired commits: %s", err.Error())
+		}
+
+		if pruned > 0 {
+			u.logger.Info.Printf("Pruned %d expired commits", pruned)
+		}
+
+		sleepCtx(ctx, interval)
+	}
+}
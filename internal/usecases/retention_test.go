@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/internal/repository/mocks"
+	"github.com/just-nibble/git-service/pkg/config"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	gitmocks "github.com/just-nibble/git-service/pkg/git/mocks"
+	"github.com/just-nibble/git-service/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRetentionUsecase_PruneCommits tests that repositories are pruned in
+// batches with their own policy or the default one
+func TestRetentionUsecase_PruneCommits(t *testing.T) {
+	// Arrange
+	mockRepoRepository := new(mocks.RepositoryRepository)
+	mockCommitRepository := new(mocks.CommitRepository)
+
+	mockRepoRepository.On("AllRepoMeta", mock.Anything).Return([]domain.RepositoryMeta{
+		{ID: 1, Name: "org/own", Retention: domain.RetentionPolicy{Commits: 10}},
+		{ID: 2, Name: "org/default"},
+		{ID: 3, Name: "org/deleted"},
+	}, nil)
+	mockCommitRepository.On("PruneCommits", mock.Anything, uint(1), time.Time{}, 10, pruneBatch).Return(int64(pruneBatch), nil).Once()
+	mockCommitRepository.On("PruneCommits", mock.Anything, uint(1), time.Time{}, 10, pruneBatch).Return(int64(3), nil).Once()
+	isCutoff := mock.MatchedBy(func(cutoff time.Time) bool {
+		return time.Since(cutoff) > 29*24*time.Hour && time.Since(cutoff) < 31*24*time.Hour
+	})
+	mockCommitRepository.On("PruneCommits", mock.Anything, uint(2), isCutoff, 0, pruneBatch).Return(int64(2), nil)
+	mockCommitRepository.On("PruneCommits", mock.Anything, uint(3), isCutoff, 0, pruneBatch).Return(int64(0), errcodes.ErrNoRecordFound)
+
+	uc := NewRetentionUsecase(mockRepoRepository, mockCommitRepository, domain.RetentionPolicy{Days: 30}, *log.NewLogger())
+
+	// Act
+	pruned, err := uc.PruneCommits(context.TODO())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(pruneBatch+5), pruned)
+	mockCommitRepository.AssertExpectations(t)
+}
+
+// TestRetentionUsecase_SetRetention tests that negative limits are rejected
+func TestRetentionUsecase_SetRetention(t *testing.T) {
+	// Arrange
+	mockRepoRepository := new(mocks.RepositoryRepository)
+
+	policy := domain.RetentionPolicy{Days: 90}
+	mockRepoRepository.On("RepoMeta", mock.Anything, "org/repo").Return(&domain.RepositoryMeta{ID: 1, Name: "org/repo"}, nil)
+	mockRepoRepository.On("UpdateRetention", mock.Anything, uint(1), policy).Return(nil)
+
+	uc := NewRetentionUsecase(mockRepoRepository, nil, domain.RetentionPolicy{}, *log.NewLogger())
+
+	// Act
+	repo, err := uc.SetRetention(context.TODO(), "org/repo", policy)
+	_, invalidErr := uc.SetRetention(context.TODO(), "org/repo", domain.RetentionPolicy{Commits: -1})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, policy, repo.Retention)
+	assert.Equal(t, errcodes.ErrInvalidRetention, invalidErr)
+	mockRepoRepository.AssertExpectations(t)
+}
+
+// TestRetentionUsecase_PrunedCommitsStayPruned tests that indexing a repository
+// again after pruning does not store the pruned commits again
+func TestRetentionUsecase_PrunedCommitsStayPruned(t *testing.T) {
+	now := time.Now()
+	jane := domain.Author{Name: "Jane", Email: "jane@example.com"}
+	// the git host lists commits newest first and ignores the date range after
+	// a commit, so every fetch returns the full history
+	history := []domain.Commit{
+		{Hash: "c4", Message: "feat: four", Date: now.AddDate(0, 0, -1), Author: jane},
+		{Hash: "c3", Message: "feat: three", Date: now.AddDate(0, 0, -2), Author: jane},
+		{Hash: "c2", Message: "feat: two", Date: now.AddDate(0, 0, -30), Author: jane},
+		{Hash: "c1", Message: "feat: one", Date: now.AddDate(0, 0, -40), Author: jane},
+	}
+
+	tests := []struct {
+		name          string
+		repoPolicy    domain.RetentionPolicy
+		defaultPolicy domain.RetentionPolicy
+	}{
+		{name: "commits limit of the repository", repoPolicy: domain.RetentionPolicy{Commits: 2}},
+		{name: "default days limit", defaultPolicy: domain.RetentionPolicy{Days: 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.TODO()
+			store := repository.NewMemoryStore()
+			repoMetaRepository := repository.NewMemoryRepositoryMetaRepository(store)
+			commitRepository := repository.NewMemoryCommitRepository(store)
+
+			repo, err := repoMetaRepository.SaveRepoMetadata(ctx, domain.RepositoryMeta{Name: "org/repo", OwnerName: "org"})
+			require.NoError(t, err)
+
+			mockGitClient := new(gitmocks.GitClient)
+			mockGitClient.On("FetchCommits", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(history, false, nil)
+
+			// the history is indexed before the policies are set
+			NewrepoMetaUsecase(repoMetaRepository, commitRepository, nil, nil, nil, mockGitClient, config.Config{}, *log.NewLogger()).
+				updateCommits(ctx, *repo)
+
+			repo.Retention = tt.repoPolicy
+			require.NoError(t, repoMetaRepository.UpdateRetention(ctx, repo.ID, tt.repoPolicy))
+			cfg := config.Config{RetentionDays: tt.defaultPolicy.Days, RetentionCommits: tt.defaultPolicy.Commits}
+			repoMetaUsecase := NewrepoMetaUsecase(repoMetaRepository, commitRepository, nil, nil, nil, mockGitClient, cfg, *log.NewLogger())
+			retentionUsecase := NewRetentionUsecase(repoMetaRepository, commitRepository, tt.defaultPolicy, *log.NewLogger())
+
+			// Act
+			pruned, err := retentionUsecase.PruneCommits(ctx)
+			require.NoError(t, err)
+			repoMetaUsecase.updateCommits(ctx, *repo)
+
+			// Assert
+			assert.Equal(t, int64(2), pruned)
+			commits, _, err := commitRepository.GetCommitsByRepository(ctx, *repo, domain.APIPaging{Sort: "date", Direction: "desc"}, domain.CommitFilter{})
+			require.NoError(t, err)
+			hashes := make([]string, 0, len(commits))
+			for _, c := range commits {
+				hashes = append(hashes, c.Hash)
+			}
+			assert.Equal(t, []string{"c4", "c3"}, hashes)
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package usecases
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+)
+
+const (
+	defaultActivityAuthors = 5
+	maxActivityAuthors     = 50
+	// maxActivityBuckets bounds the series a single request can ask for
+	maxActivityBuckets = 1000
+	// maxListedReverts bounds the reverts listed next to the counts
+	maxListedReverts = 100
+)
+
+type StatsUsecase interface {
+	GetActivity(ctx context.Context, repoName string, query domain.ActivityQuery) (*domain.Activity, error)
+	GetDelivery(ctx context.Context, repoName string, query domain.DeliveryQuery) (*domain.DeliveryMetrics, error)
+	GetReverts(ctx context.Context, repoName string, query domain.ActivityQuery) (*domain.RevertActivity, error)
+}
+
+type statsUsecase struct {
+	statsRepository          repository.StatsRepository
+	repositoryMetaRepository repository.RepositoryMetaRepository
+	// delivery holds the configured source, branch and failure pattern of
+	// delivery queries
+	delivery domain.DeliveryQuery
+}
+
+func NewStatsUsecase(statsRepository repository.StatsRepository, repositoryMetaRepository repository.RepositoryMetaRepository, delivery domain.DeliveryQuery) StatsUsecase {
+	return &statsUsecase{
+		statsRepository:          statsRepository,
+		repositoryMetaRepository: repositoryMetaRepository,
+		delivery:                 delivery,
+	}
+}
+
+// GetActivity counts the commits of a repository per interval, per weekday and
+// hour, and per author. Zero values in the query are replaced by defaults: weekly
+// buckets in UTC over the last 12 intervals.
+func (u *statsUsecase) GetActivity(ctx context.Context, repoName string, query domain.ActivityQuery) (*domain.Activity, error) {
+	query, err := activityDefaults(query)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+	query.RepoID = repo.ID
+
+	buckets, err := u.statsRepository.CommitActivity(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	punchCard, err := u.statsRepository.PunchCard(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	authors, err := u.statsRepository.AuthorActivity(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := &domain.Activity{
+		Interval:  query.Interval,
+		Location:  query.Location,
+		Since:     query.Since,
+		Until:     query.Until,
+		Buckets:   fillBuckets(query, buckets),
+		PunchCard: punchCard,
+		Authors:   make([]domain.AuthorActivity, 0, len(authors)),
+	}
+	if activity.PunchCard == nil {
+		activity.PunchCard = []domain.PunchCardCell{}
+	}
+
+	for _, b := range buckets {
+		activity.Total += b.Count
+	}
+
+	for _, a := range authors {
+		a.Buckets = fillBuckets(query, a.Buckets)
+		activity.Authors = append(activity.Authors, a)
+	}
+
+	return activity, nil
+}
+
+func activityDefaults(query domain.ActivityQuery) (domain.ActivityQuery, error) {
+	if query.Interval == "" {
+		query.Interval = domain.IntervalWeek
+	}
+	if !query.Interval.Valid() {
+		return query, errcodes.ErrInvalidInterval
+	}
+
+	if query.Location == nil {
+		query.Location = time.UTC
+	}
+
+	if query.Until.IsZero() {
+		query.Until = time.Now()
+	}
+	query.Until = query.Until.In(query.Location)
+
+	if query.Since.IsZero() {
+		since := query.Interval.Truncate(query.Until)
+		for i := 1; i < 12; i++ {
+			since = previous(query.Interval, since)
+		}
+		query.Since = since
+	}
+	query.Since = query.Since.In(query.Location)
+
+	if !query.Since.Before(query.Until) {
+		return query, errcodes.ErrInvalidTimeRange
+	}
+
+	n := 0
+	for t := query.Interval.Truncate(query.Since); t.Before(query.Until); t = query.Interval.Next(t) {
+		if n++; n > maxActivityBuckets {
+			return query, errcodes.ErrTooManyIntervals
+		}
+	}
+
+	if query.Authors <= 0 {
+		query.Authors = defaultActivityAuthors
+	}
+	if query.Authors > maxActivityAuthors {
+		query.Authors = maxActivityAuthors
+	}
+
+	return query, nil
+}
+
+func previous(interval domain.ActivityInterval, t time.Time) time.Time {
+	switch interval {
+	case domain.IntervalWeek:
+		return t.AddDate(0, 0, -7)
+	case domain.IntervalMonth:
+		return t.AddDate(0, -1, 0)
+	default:
+		return t.AddDate(0, 0, -1)
+	}
+}
+
+// fillBuckets returns one bucket for every interval in the query range, so
+// intervals without commits are reported with a zero count.
+func fillBuckets(query domain.ActivityQuery, buckets []domain.ActivityBucket) []domain.ActivityBucket {
+	counts := make(map[int64]int, len(buckets))
+	for _, b := range buckets {
+		counts[b.Start.Unix()] = b.Count
+	}
+
+	filled := []domain.ActivityBucket{}
+	for t := query.Interval.Truncate(query.Since); t.Before(query.Until); t = query.Interval.Next(t) {
+		filled = append(filled, domain.ActivityBucket{Start: t, Count: counts[t.Unix()]})
+	}
+	return filled
+}
+
+// GetReverts counts the reverts of a repository per interval and lists the most
+// recent ones. The query defaults are those of GetActivity.
+func (u *statsUsecase) GetReverts(ctx context.Context, repoName string, query domain.ActivityQuery) (*domain.RevertActivity, error) {
+	query, err := activityDefaults(query)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+	query.RepoID = repo.ID
+
+	reverts, err := u.statsRepository.Reverts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int64]int)
+	for _, r := range reverts {
+		counts[query.Interval.Truncate(r.Commit.Date.In(query.Location)).Unix()]++
+	}
+
+	buckets := make([]domain.ActivityBucket, 0, len(counts))
+	for start, count := range counts {
+		buckets = append(buckets, domain.ActivityBucket{Start: time.Unix(start, 0), Count: count})
+	}
+
+	return &domain.RevertActivity{
+		Interval: query.Interval,
+		Location: query.Location,
+		Since:    query.Since,
+		Until:    query.Until,
+		Total:    len(reverts),
+		Buckets:  fillBuckets(query, buckets),
+		Reverts:  truncate(reverts, maxListedReverts),
+	}, nil
+}
+
+// GetDelivery computes lead time for changes, deployment frequency and change
+// failure rate per week. A deployment ships the commits dated after the cutoff
+// of the previous deployment, or after the start of the range when there is
+// none. A deployment fails when it ships a revert or hotfix.
+func (u *statsUsecase) GetDelivery(ctx context.Context, repoName string, query domain.DeliveryQuery) (*domain.DeliveryMetrics, error) {
+	query, err := u.deliveryDefaults(query)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := u.repositoryMetaRepository.RepoMeta(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+	query.RepoID = repo.ID
+
+	deployments, err := u.statsRepository.Deployments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	shipped := query.Since
+	if len(deployments) > 0 && deployments[0].At.Before(query.Since) {
+		shipped = deployments[0].Cutoff
+		deployments = deployments[1:]
+	}
+
+	last := shipped
+	for _, d := range deployments {
+		if d.Cutoff.After(last) {
+			last = d.Cutoff
+		}
+	}
+
+	var commits []domain.DeliveredCommit
+	if last.After(shipped) {
+		if commits, err = u.statsRepository.DeliveredCommits(ctx, query, shipped, last); err != nil {
+			return nil, err
+		}
+	}
+
+	metrics := &domain.DeliveryMetrics{
+		Source:   query.Source,
+		Branch:   query.Branch,
+		Location: query.Location,
+		Since:    query.Since,
+		Until:    query.Until,
+	}
+
+	week := domain.IntervalWeek
+	buckets := make(map[int64]*domain.DeliveryBucket)
+	leadTimes := make(map[int64][]time.Duration)
+	var allLeadTimes []time.Duration
+
+	next := 0
+	for _, d := range deployments {
+		start := week.Truncate(d.At.In(query.Location)).Unix()
+		bucket, ok := buckets[start]
+		if !ok {
+			bucket = &domain.DeliveryBucket{}
+			buckets[start] = bucket
+		}
+		bucket.Deployments++
+
+		failed := false
+		for ; next < len(commits) && !commits[next].Date.After(d.Cutoff); next++ {
+			leadTime := d.At.Sub(commits[next].Date)
+			if leadTime < 0 {
+				leadTime = 0
+			}
+			leadTimes[start] = append(leadTimes[start], leadTime)
+			allLeadTimes = append(allLeadTimes, leadTime)
+			failed = failed || commits[next].Failure
+		}
+		if failed {
+			bucket.FailedDeployments++
+		}
+	}
+
+	for t := week.Truncate(query.Since); t.Before(query.Until); t = week.Next(t) {
+		bucket := domain.DeliveryBucket{Start: t}
+		if b, ok := buckets[t.Unix()]; ok {
+			bucket.Deployments = b.Deployments
+			bucket.FailedDeployments = b.FailedDeployments
+			bucket.Commits = len(leadTimes[t.Unix()])
+			bucket.LeadTime = median(leadTimes[t.Unix()])
+		}
+		metrics.Buckets = append(metrics.Buckets, bucket)
+
+		metrics.Deployments += bucket.Deployments
+		metrics.FailedDeployments += bucket.FailedDeployments
+		metrics.Commits += bucket.Commits
+	}
+	metrics.LeadTime = median(allLeadTimes)
+
+	return metrics, nil
+}
+
+// deliveryDefaults fills the query with the configured defaults and a range of
+// the last 12 weeks in UTC.
+func (u *statsUsecase) deliveryDefaults(query domain.DeliveryQuery) (domain.DeliveryQuery, error) {
+	if query.Source == "" {
+		query.Source = u.delivery.Source
+	}
+	if !query.Source.Valid() {
+		return query, errcodes.ErrInvalidDeploymentSource
+	}
+	if query.Branch == "" {
+		query.Branch = u.delivery.Branch
+	}
+	if query.Source != domain.DeploymentBranch {
+		query.Branch = ""
+	}
+	query.FailurePattern = u.delivery.FailurePattern
+
+	activity, err := activityDefaults(domain.ActivityQuery{
+		Interval: domain.IntervalWeek,
+		Since:    query.Since,
+		Until:    query.Until,
+		Location: query.Location,
+	})
+	if err != nil {
+		return query, err
+	}
+	query.Since, query.Until, query.Location = activity.Since, activity.Until, activity.Location
+
+	return query, nil
+}
+
+// median returns the median of durations, zero for none. durations is sorted in
+// place.
+func median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	mid := len(durations) / 2
+	if len(durations)%2 == 0 {
+		return (durations[mid-1] + durations[mid]) / 2
+	}
+	return durations[mid]
+}
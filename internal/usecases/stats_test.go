@@ -0,0 +1,210 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/just-nibble/git-service/internal/repository/mocks"
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStatsUsecase_GetActivity tests that missing buckets are filled with zero counts
+func TestStatsUsecase_GetActivity(t *testing.T) {
+	// Arrange
+	mockStatsRepository := new(mocks.StatsRepository)
+	mockRepoRepository := new(mocks.RepositoryRepository)
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	since := time.Date(2024, 3, 4, 0, 0, 0, 0, loc) // a Monday
+	until := time.Date(2024, 3, 25, 0, 0, 0, 0, loc)
+	week := func(n int) time.Time { return since.AddDate(0, 0, 7*n) }
+
+	mockRepoRepository.On("RepoMeta", mock.Anything, "repo1").Return(&domain.RepositoryMeta{ID: 7, Name: "repo1"}, nil)
+
+	isQuery := mock.MatchedBy(func(q domain.ActivityQuery) bool {
+		return q.RepoID == 7 && q.Interval == domain.IntervalWeek && q.Authors == defaultActivityAuthors
+	})
+	mockStatsRepository.On("CommitActivity", mock.Anything, isQuery).Return([]domain.ActivityBucket{
+		{Start: week(0), Count: 3},
+		{Start: week(2), Count: 1},
+	}, nil)
+	mockStatsRepository.On("PunchCard", mock.Anything, isQuery).Return([]domain.PunchCardCell{{Weekday: 1, Hour: 9, Count: 4}}, nil)
+	mockStatsRepository.On("AuthorActivity", mock.Anything, isQuery).Return([]domain.AuthorActivity{
+		{Author: domain.Author{ID: 1, Name: "John Doe"}, Count: 4, Buckets: []domain.ActivityBucket{{Start: week(2), Count: 1}}},
+	}, nil)
+
+	uc := NewStatsUsecase(mockStatsRepository, mockRepoRepository, domain.DeliveryQuery{})
+
+	// Act
+	activity, err := uc.GetActivity(context.TODO(), "repo1", domain.ActivityQuery{
+		Interval: domain.IntervalWeek,
+		Since:    since,
+		Until:    until,
+		Location: loc,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 4, activity.Total)
+	assert.Equal(t, []domain.ActivityBucket{
+		{Start: week(0), Count: 3},
+		{Start: week(1), Count: 0},
+		{Start: week(2), Count: 1},
+	}, activity.Buckets)
+	assert.Len(t, activity.PunchCard, 1)
+	require.Len(t, activity.Authors, 1)
+	assert.Equal(t, []int{0, 0, 1}, []int{
+		activity.Authors[0].Buckets[0].Count,
+		activity.Authors[0].Buckets[1].Count,
+		activity.Authors[0].Buckets[2].Count,
+	})
+	mockStatsRepository.AssertExpectations(t)
+}
+
+// TestStatsUsecase_GetActivity_InvalidQuery tests that invalid queries are rejected before querying
+func TestStatsUsecase_GetActivity_InvalidQuery(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		query domain.ActivityQuery
+		err   error
+	}{
+		{"interval", domain.ActivityQuery{Interval: "year"}, errcodes.ErrInvalidInterval},
+		{"reversed range", domain.ActivityQuery{Since: now, Until: now.Add(-time.Hour)}, errcodes.ErrInvalidTimeRange},
+		{"too many buckets", domain.ActivityQuery{Interval: domain.IntervalDay, Since: now.AddDate(-5, 0, 0), Until: now}, errcodes.ErrTooManyIntervals},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewStatsUsecase(new(mocks.StatsRepository), new(mocks.RepositoryRepository), domain.DeliveryQuery{})
+
+			_, err := uc.GetActivity(context.TODO(), "repo1", tt.query)
+
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+// TestActivityInterval_Truncate tests bucket starts around a daylight saving change
+func TestActivityInterval_Truncate(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	sunday := time.Date(2024, 3, 31, 15, 30, 0, 0, loc)
+
+	assert.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, loc), domain.IntervalDay.Truncate(sunday))
+	assert.Equal(t, time.Date(2024, 3, 25, 0, 0, 0, 0, loc), domain.IntervalWeek.Truncate(sunday))
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, loc), domain.IntervalMonth.Truncate(sunday))
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, loc), domain.IntervalDay.Next(domain.IntervalDay.Truncate(sunday)))
+}
+
+// TestStatsUsecase_GetDelivery tests assigning commits to the deployments that
+// shipped them and the weekly lead times and failure counts
+func TestStatsUsecase_GetDelivery(t *testing.T) {
+	// Arrange
+	mockStatsRepository := new(mocks.StatsRepository)
+	mockRepoRepository := new(mocks.RepositoryRepository)
+
+	day := func(d, h int) time.Time { return time.Date(2024, 3, d, h, 0, 0, 0, time.UTC) }
+	since, until := day(4, 0), day(18, 0) // Mondays
+
+	mockRepoRepository.On("RepoMeta", mock.Anything, "repo1").Return(&domain.RepositoryMeta{ID: 7, Name: "repo1"}, nil)
+
+	isQuery := mock.MatchedBy(func(q domain.DeliveryQuery) bool {
+		return q.RepoID == 7 && q.Source == domain.DeploymentTags && q.Branch == "" && q.FailurePattern == "hotfix%"
+	})
+	mockStatsRepository.On("Deployments", mock.Anything, isQuery).Return([]domain.Deployment{
+		{At: day(1, 0), Cutoff: day(1, 0)},
+		{At: day(5, 12), Cutoff: day(5, 10)},
+		{At: day(12, 12), Cutoff: day(12, 0)},
+		{At: day(13, 0), Cutoff: day(2, 0)}, // a backport ships nothing new
+	}, nil)
+	mockStatsRepository.On("DeliveredCommits", mock.Anything, isQuery, day(1, 0), day(12, 0)).Return([]domain.DeliveredCommit{
+		{Date: day(4, 12)},
+		{Date: day(5, 10)},
+		{Date: day(11, 12), Failure: true},
+		{Date: day(12, 0)},
+	}, nil)
+
+	uc := NewStatsUsecase(mockStatsRepository, mockRepoRepository, domain.DeliveryQuery{
+		Source:         domain.DeploymentBranch,
+		Branch:         "main",
+		FailurePattern: "hotfix%",
+	})
+
+	// Act
+	metrics, err := uc.GetDelivery(context.TODO(), "repo1", domain.DeliveryQuery{
+		Source: domain.DeploymentTags,
+		Since:  since,
+		Until:  until,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []domain.DeliveryBucket{
+		{Start: since, Deployments: 1, Commits: 2, LeadTime: 13 * time.Hour},
+		{Start: day(11, 0), Deployments: 2, FailedDeployments: 1, Commits: 2, LeadTime: 18 * time.Hour},
+	}, metrics.Buckets)
+	assert.Equal(t, 3, metrics.Deployments)
+	assert.Equal(t, 4, metrics.Commits)
+	assert.Equal(t, 18*time.Hour, metrics.LeadTime)
+	assert.InDelta(t, 1.5, metrics.DeploymentsPerWeek(), 1e-9)
+	assert.InDelta(t, 1.0/3, metrics.ChangeFailureRate(), 1e-9)
+}
+
+// TestStatsUsecase_GetDelivery_InvalidSource tests that unknown deployment sources are rejected
+func TestStatsUsecase_GetDelivery_InvalidSource(t *testing.T) {
+	// Arrange
+	uc := NewStatsUsecase(new(mocks.StatsRepository), new(mocks.RepositoryRepository), domain.DeliveryQuery{Source: domain.DeploymentReleases})
+
+	// Act
+	_, err := uc.GetDelivery(context.TODO(), "repo1", domain.DeliveryQuery{Source: "deploys"})
+
+	// Assert
+	assert.ErrorIs(t, err, errcodes.ErrInvalidDeploymentSource)
+}
+
+// TestStatsUsecase_GetReverts tests counting reverts per interval in the query location
+func TestStatsUsecase_GetReverts(t *testing.T) {
+	// Arrange
+	mockStatsRepository := new(mocks.StatsRepository)
+	mockRepoRepository := new(mocks.RepositoryRepository)
+
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	since := time.Date(2024, 3, 4, 0, 0, 0, 0, loc) // a Monday
+	until := time.Date(2024, 3, 18, 0, 0, 0, 0, loc)
+
+	mockRepoRepository.On("RepoMeta", mock.Anything, "repo1").Return(&domain.RepositoryMeta{ID: 7, Name: "repo1"}, nil)
+
+	isQuery := mock.MatchedBy(func(q domain.ActivityQuery) bool { return q.RepoID == 7 && q.Interval == domain.IntervalWeek })
+	reverted := &domain.Commit{Hash: "aaa", Date: since}
+	mockStatsRepository.On("Reverts", mock.Anything, isQuery).Return([]domain.Revert{
+		// Monday 02:00 UTC is still Sunday in New York
+		{Commit: domain.Commit{Hash: "ccc", Date: time.Date(2024, 3, 11, 2, 0, 0, 0, time.UTC)}},
+		{Commit: domain.Commit{Hash: "bbb", Date: since.Add(time.Hour)}, Reverted: reverted},
+	}, nil)
+
+	uc := NewStatsUsecase(mockStatsRepository, mockRepoRepository, domain.DeliveryQuery{})
+
+	// Act
+	activity, err := uc.GetReverts(context.TODO(), "repo1", domain.ActivityQuery{Since: since, Until: until, Location: loc})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, activity.Total)
+	assert.Equal(t, []domain.ActivityBucket{
+		{Start: since, Count: 2},
+		{Start: since.AddDate(0, 0, 7), Count: 0},
+	}, activity.Buckets)
+	assert.Len(t, activity.Reverts, 2)
+}
@@ -82,6 +82,11 @@ func executeRequest(req *http.Request) (*http.Response, error) {
 }
 
 func (c *RestClient) Get(urlPath string, args ...interface{}) (*HTTPResponse, error) {
+	return c.GetWithContext(context.Background(), urlPath, args...)
+}
+
+// GetWithContext is Get with a request cancelled along with ctx.
+func (c *RestClient) GetWithContext(ctx context.Context, urlPath string, args ...interface{}) (*HTTPResponse, error) {
 	var queryParams map[string]string
 	var headers map[string]string
 
@@ -104,7 +109,7 @@ func (c *RestClient) Get(urlPath string, args ...interface{}) (*HTTPResponse, er
 		QueryParams: queryParams,
 	}
 
-	req, err := createHTTPRequest(requestConfig)
+	req, err := createHTTPRequest(ctx, requestConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -118,12 +123,12 @@ func (c *RestClient) Get(urlPath string, args ...interface{}) (*HTTPResponse, er
 }
 
 // createHTTPRequest constructs an HTTP request from the given configuration.
-func createHTTPRequest(config RequestConfig) (*http.Request, error) {
+func createHTTPRequest(ctx context.Context, config RequestConfig) (*http.Request, error) {
 	if len(config.QueryParams) > 0 {
 		config.URL = appendQueryParams(config.URL, config.QueryParams)
 	}
 
-	req, err := http.NewRequest(string(config.Method), config.URL, bytes.NewBuffer(config.Body))
+	req, err := http.NewRequestWithContext(ctx, string(config.Method), config.URL, bytes.NewBuffer(config.Body))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,176 @@
+// Package archive reads and writes repository archives. An archive is a gzip
+// compressed stream of JSON records, one per line, that starts with a manifest
+// and ends with a trailer counting the records in between, so a truncated
+// archive is detected.
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// Format identifies repository archives.
+	Format = "git-service-archive"
+	// Version is the version of the record layout written by Writer.
+	Version = 1
+)
+
+const (
+	typeManifest = "manifest"
+	typeEnd      = "end"
+)
+
+// ErrTruncated is returned by Reader.Next when an archive ends before its
+// trailer or holds fewer records than the trailer counts.
+var ErrTruncated = errors.New("archive is truncated")
+
+// Manifest is the first record of an archive.
+type Manifest struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	Repository string    `json:"repository"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// trailer is the last record of an archive.
+type trailer struct {
+	Counts map[string]int `json:"counts"`
+}
+
+type record struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Writer writes the records of an archive.
+type Writer struct {
+	gz     *gzip.Writer
+	enc    *json.Encoder
+	counts map[string]int
+}
+
+// NewWriter writes the manifest of the archive of a repository to w. Close must
+// be called to complete the archive.
+func NewWriter(w io.Writer, repository string) (*Writer, error) {
+	gz := gzip.NewWriter(w)
+	aw := &Writer{gz: gz, enc: json.NewEncoder(gz), counts: make(map[string]int)}
+
+	manifest := Manifest{Format: Format, Version: Version, Repository: repository, ExportedAt: time.Now().UTC()}
+	if err := aw.write(typeManifest, manifest); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+// Write writes a record of the given type, v is encoded as JSON.
+func (w *Writer) Write(recordType string, v interface{}) error {
+	if recordType == typeManifest || recordType == typeEnd {
+		return fmt.Errorf("record type %q is reserved", recordType)
+	}
+	if err := w.write(recordType, v); err != nil {
+		return err
+	}
+	w.counts[recordType]++
+	return nil
+}
+
+func (w *Writer) write(recordType string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return w.enc.Encode(record{Type: recordType, Data: data})
+}
+
+// Close writes the trailer and flushes the archive, it does not close the
+// underlying writer.
+func (w *Writer) Close() error {
+	if err := w.write(typeEnd, trailer{Counts: w.counts}); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+// Reader reads the records of an archive.
+type Reader struct {
+	gz       *gzip.Reader
+	dec      *json.Decoder
+	manifest Manifest
+	counts   map[string]int
+	done     bool
+}
+
+// NewReader reads the manifest of an archive and checks its format and version.
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("archive is not gzip compressed: %w", err)
+	}
+
+	ar := &Reader{gz: gz, dec: json.NewDecoder(gz), counts: make(map[string]int)}
+
+	var first record
+	if err := ar.dec.Decode(&first); err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if first.Type != typeManifest {
+		return nil, fmt.Errorf("archive starts with a %q record, expected a manifest", first.Type)
+	}
+	if err := json.Unmarshal(first.Data, &ar.manifest); err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if ar.manifest.Format != Format {
+		return nil, fmt.Errorf("unknown archive format %q", ar.manifest.Format)
+	}
+	if ar.manifest.Version < 1 || ar.manifest.Version > Version {
+		return nil, fmt.Errorf("unsupported archive version %d, expected at most %d", ar.manifest.Version, Version)
+	}
+	return ar, nil
+}
+
+// Manifest returns the manifest of the archive.
+func (r *Reader) Manifest() Manifest {
+	return r.manifest
+}
+
+// Next returns the type and the JSON data of the next record. It returns io.EOF
+// once the trailer is read and matches the records read.
+func (r *Reader) Next() (string, json.RawMessage, error) {
+	if r.done {
+		return "", nil, io.EOF
+	}
+
+	var rec record
+	if err := r.dec.Decode(&rec); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return "", nil, ErrTruncated
+		}
+		return "", nil, err
+	}
+
+	switch rec.Type {
+	case typeManifest:
+		return "", nil, errors.New("archive holds a second manifest")
+	case typeEnd:
+		var end trailer
+		if err := json.Unmarshal(rec.Data, &end); err != nil {
+			return "", nil, fmt.Errorf("failed to read trailer: %w", err)
+		}
+		for recordType, count := range end.Counts {
+			if r.counts[recordType] != count {
+				return "", nil, fmt.Errorf("%w, read %d %s records of %d", ErrTruncated, r.counts[recordType], recordType, count)
+			}
+		}
+		r.done = true
+		return "", nil, io.EOF
+	}
+
+	r.counts[rec.Type]++
+	return rec.Type, rec.Data, nil
+}
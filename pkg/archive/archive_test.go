@@ -0,0 +1,104 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeArchive(t *testing.T, records int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "org/repo")
+	require.NoError(t, err)
+	for i := 0; i < records; i++ {
+		require.NoError(t, w.Write("commit", map[string]int{"n": i}))
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// TestArchive_RoundTrip tests that records are read back in order
+func TestArchive_RoundTrip(t *testing.T) {
+	// Arrange
+	data := writeArchive(t, 3)
+
+	// Act
+	r, err := NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	var read []int
+	for {
+		recordType, raw, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, "commit", recordType)
+
+		var v map[string]int
+		require.NoError(t, json.Unmarshal(raw, &v))
+		read = append(read, v["n"])
+	}
+
+	// Assert
+	assert.Equal(t, Format, r.Manifest().Format)
+	assert.Equal(t, "org/repo", r.Manifest().Repository)
+	assert.Equal(t, []int{0, 1, 2}, read)
+}
+
+// TestArchive_Truncated tests that an archive cut before its trailer is
+// rejected
+func TestArchive_Truncated(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := io.Copy(gz, bytes.NewReader([]byte(
+		`{"type":"manifest","data":{"format":"git-service-archive","version":1}}`+"\n"+
+			`{"type":"commit","data":{}}`+"\n")))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	r, err := NewReader(&buf)
+	require.NoError(t, err)
+
+	// Act
+	_, _, first := r.Next()
+	_, _, second := r.Next()
+
+	// Assert
+	assert.NoError(t, first)
+	assert.ErrorIs(t, second, ErrTruncated)
+}
+
+// TestNewReader_Invalid tests that other files and newer versions are rejected
+func TestNewReader_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"format":  `{"type":"manifest","data":{"format":"other","version":1}}`,
+		"version": `{"type":"manifest","data":{"format":"git-service-archive","version":99}}`,
+		"first":   `{"type":"commit","data":{}}`,
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			_, err := gz.Write([]byte(content + "\n"))
+			require.NoError(t, err)
+			require.NoError(t, gz.Close())
+
+			_, err = NewReader(&buf)
+
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := NewReader(bytes.NewReader([]byte("not gzip")))
+	assert.Error(t, err)
+}
@@ -2,32 +2,65 @@ package config
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator"
 	"github.com/just-nibble/git-service/pkg/env"
 	"github.com/just-nibble/git-service/pkg/log"
+	"github.com/just-nibble/git-service/pkg/ratelimit"
 )
 
+// RateLimitGroups are the route groups that can have their own rate limit,
+// configured with RATE_LIMIT_<GROUP>. RATE_LIMIT_DEFAULT applies to the rest.
+var RateLimitGroups = []string{"repositories", "commits", "authors", "admin"}
+
 type Config struct {
 	DefaultRepository     string
 	DefaultStartDate      time.Time
 	DefaultEndDate        time.Time
 	MonitorInterval       time.Duration
-	DBHost                string `validate:"required"`
-	DBUser                string `validate:"required"`
-	DBPassword            string `validate:"required"`
-	DBName                string `validate:"required"`
-	DBPort                uint   `validate:"required"`
-	SSLMode               string `validate:"required"`
+	DBDriver              string `validate:"oneof=postgres sqlite"`
+	DBHost                string `validate:"required_without=SQLitePath"`
+	DBUser                string `validate:"required_without=SQLitePath"`
+	DBPassword            string `validate:"required_without=SQLitePath"`
+	DBName                string `validate:"required_without=SQLitePath"`
+	DBPort                uint   `validate:"required_without=SQLitePath"`
+	SSLMode               string `validate:"required_without=SQLitePath"`
 	DSN                   string
+	SQLitePath            string
+	MigrateOnStart        bool
 	GitClientToken        string
 	GitClientBaseURL      string
 	GitCommitFetchPerPage int
 	ServerAddress         string
 	ServerPort            string
+	ShutdownTimeout       time.Duration
+	AuthEnabled           bool
+	AdminAPIKey           string
+	RateLimitEnabled      bool
+	RateLimits            map[string]ratelimit.Limit
+	OpenAPIValidation     bool
+	LegacyRoutes          bool
+	LegacyRoutesSunset    string
+	RepositoryGroups      map[string][]string
+	StatsRepairInterval   time.Duration
+	DeliverySource        string
+	DeliveryBranch        string
+	DeliveryHotfixes      string
+	OwnershipMonths       int
+	RetentionDays         int
+	RetentionCommits      int
+	RetentionInterval     time.Duration
+	GraphQLMaxDepth       int
+	GraphQLMaxComplexity  int
+	GraphQLMaxFanout      int
+	GRPCEnabled           bool
+	GRPCPort              string
+	GRPCPollInterval      time.Duration
 }
 
 func LoadConfig(log log.Log) (*Config, error) {
@@ -44,6 +77,20 @@ func LoadConfig(log log.Log) (*Config, error) {
 		return nil, err
 	}
 
+	shutdownTimeout := env.Getenv("SHUTDOWN_TIMEOUT", "30s")
+	shutdownDuration, err := time.ParseDuration(shutdownTimeout)
+	if err != nil {
+		log.Error.Printf("Invalid SHUTDOWN_TIMEOUT :[%s] env format: %s", shutdownTimeout, err.Error())
+		return nil, err
+	}
+
+	statsRepairInterval := env.Getenv("STATS_REPAIR_INTERVAL", "24h")
+	statsRepairDuration, err := time.ParseDuration(statsRepairInterval)
+	if err != nil {
+		log.Error.Printf("Invalid STATS_REPAIR_INTERVAL :[%s] env format: %s", statsRepairInterval, err.Error())
+		return nil, err
+	}
+
 	var sDate time.Time
 	var eDate time.Time
 
@@ -76,20 +123,144 @@ func LoadConfig(log log.Log) (*Config, error) {
 		}
 	}
 
-	dBPort, err := strconv.Atoi(os.Getenv("DB_PORT"))
+	authEnabled, err := strconv.ParseBool(env.Getenv("AUTH_ENABLED", "true"))
+	if err != nil {
+		log.Error.Printf("Invalid AUTH_ENABLED [%s] env format: %s", os.Getenv("AUTH_ENABLED"), err.Error())
+		return nil, err
+	}
+
+	rateLimitEnabled, err := strconv.ParseBool(env.Getenv("RATE_LIMIT_ENABLED", "true"))
 	if err != nil {
-		log.Error.Printf("Invalid DB_PORT [%d] env format: %s", dBPort, err.Error())
+		log.Error.Printf("Invalid RATE_LIMIT_ENABLED [%s] env format: %s", os.Getenv("RATE_LIMIT_ENABLED"), err.Error())
+		return nil, err
+	}
+
+	openAPIValidation, err := strconv.ParseBool(env.Getenv("OPENAPI_VALIDATION", "false"))
+	if err != nil {
+		log.Error.Printf("Invalid OPENAPI_VALIDATION [%s] env format: %s", os.Getenv("OPENAPI_VALIDATION"), err.Error())
+		return nil, err
+	}
+
+	legacyRoutes, err := strconv.ParseBool(env.Getenv("LEGACY_ROUTES", "true"))
+	if err != nil {
+		log.Error.Printf("Invalid LEGACY_ROUTES [%s] env format: %s", os.Getenv("LEGACY_ROUTES"), err.Error())
+		return nil, err
+	}
+
+	legacySunset := os.Getenv("LEGACY_ROUTES_SUNSET")
+	if legacySunset != "" {
+		sunset, err := time.Parse(time.RFC3339, legacySunset)
+		if err != nil {
+			log.Error.Printf("Invalid LEGACY_ROUTES_SUNSET [%s] env format: %s", legacySunset, err.Error())
+			return nil, err
+		}
+		legacySunset = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	rateLimits, err := loadRateLimits(log)
+	if err != nil {
+		return nil, err
+	}
+
+	repositoryGroups, err := ParseRepositoryGroups(os.Getenv("REPOSITORY_GROUPS"))
+	if err != nil {
+		log.Error.Printf("Invalid REPOSITORY_GROUPS [%s] env format: %s", os.Getenv("REPOSITORY_GROUPS"), err.Error())
+		return nil, err
+	}
+
+	ownership := env.Getenv("OWNERSHIP_MONTHS", "12")
+	ownershipMonths, err := strconv.Atoi(ownership)
+	if err != nil || ownershipMonths < 1 {
+		log.Error.Printf("Invalid OWNERSHIP_MONTHS [%s] env format, expected a positive number of months", ownership)
+		return nil, fmt.Errorf("invalid OWNERSHIP_MONTHS: %s", ownership)
+	}
+
+	retentionDays := env.Getenv("RETENTION_DAYS", "0")
+	retentionDaysCount, err := strconv.Atoi(retentionDays)
+	if err != nil || retentionDaysCount < 0 {
+		log.Error.Printf("Invalid RETENTION_DAYS [%s] env format, expected a number of days, 0 keeps every commit", retentionDays)
+		return nil, fmt.Errorf("invalid RETENTION_DAYS: %s", retentionDays)
+	}
+
+	retentionCommits := env.Getenv("RETENTION_COMMITS", "0")
+	retentionCommitsCount, err := strconv.Atoi(retentionCommits)
+	if err != nil || retentionCommitsCount < 0 {
+		log.Error.Printf("Invalid RETENTION_COMMITS [%s] env format, expected a number of commits, 0 keeps every commit", retentionCommits)
+		return nil, fmt.Errorf("invalid RETENTION_COMMITS: %s", retentionCommits)
+	}
+
+	retentionInterval := env.Getenv("RETENTION_INTERVAL", "24h")
+	retentionDuration, err := time.ParseDuration(retentionInterval)
+	if err != nil {
+		log.Error.Printf("Invalid RETENTION_INTERVAL :[%s] env format: %s", retentionInterval, err.Error())
+		return nil, err
+	}
+
+	graphQLDepth := env.Getenv("GRAPHQL_MAX_DEPTH", "10")
+	graphQLMaxDepth, err := strconv.Atoi(graphQLDepth)
+	if err != nil || graphQLMaxDepth < 0 {
+		log.Error.Printf("Invalid GRAPHQL_MAX_DEPTH [%s] env format, expected a number of levels, 0 disables the limit", graphQLDepth)
+		return nil, fmt.Errorf("invalid GRAPHQL_MAX_DEPTH: %s", graphQLDepth)
+	}
+
+	graphQLComplexity := env.Getenv("GRAPHQL_MAX_COMPLEXITY", "2000")
+	graphQLMaxComplexity, err := strconv.Atoi(graphQLComplexity)
+	if err != nil || graphQLMaxComplexity < 0 {
+		log.Error.Printf("Invalid GRAPHQL_MAX_COMPLEXITY [%s] env format, expected a number, 0 disables the limit", graphQLComplexity)
+		return nil, fmt.Errorf("invalid GRAPHQL_MAX_COMPLEXITY: %s", graphQLComplexity)
+	}
+
+	graphQLFanout := env.Getenv("GRAPHQL_MAX_FANOUT", "20")
+	graphQLMaxFanout, err := strconv.Atoi(graphQLFanout)
+	if err != nil || graphQLMaxFanout < 0 {
+		log.Error.Printf("Invalid GRAPHQL_MAX_FANOUT [%s] env format, expected a number, 0 disables the limit", graphQLFanout)
+		return nil, fmt.Errorf("invalid GRAPHQL_MAX_FANOUT: %s", graphQLFanout)
+	}
+
+	grpcEnabled, err := strconv.ParseBool(env.Getenv("GRPC_ENABLED", "true"))
+	if err != nil {
+		log.Error.Printf("Invalid GRPC_ENABLED [%s] env format: %s", os.Getenv("GRPC_ENABLED"), err.Error())
+		return nil, err
+	}
+
+	grpcPollInterval := env.Getenv("GRPC_POLL_INTERVAL", "5s")
+	grpcPollDuration, err := time.ParseDuration(grpcPollInterval)
+	if err != nil || grpcPollDuration <= 0 {
+		log.Error.Printf("Invalid GRPC_POLL_INTERVAL [%s] env format, expected a positive duration", grpcPollInterval)
+		return nil, fmt.Errorf("invalid GRPC_POLL_INTERVAL: %s", grpcPollInterval)
+	}
+
+	dbDriver := env.Getenv("DB_DRIVER", "postgres")
+
+	var dBPort int
+	var sqlitePath string
+	if dbDriver == "sqlite" {
+		sqlitePath = env.Getenv("SQLITE_PATH", "git-service.db")
+	} else {
+		dBPort, err = strconv.Atoi(os.Getenv("DB_PORT"))
+		if err != nil {
+			log.Error.Printf("Invalid DB_PORT [%s] env format: %s", os.Getenv("DB_PORT"), err.Error())
+			return nil, err
+		}
+	}
+
+	migrateOnStart, err := strconv.ParseBool(env.Getenv("MIGRATE_ON_START", "false"))
+	if err != nil {
+		log.Error.Printf("Invalid MIGRATE_ON_START [%s] env format: %s", os.Getenv("MIGRATE_ON_START"), err.Error())
 		return nil, err
 	}
 
 	configVar := Config{
 		GitClientToken:        os.Getenv("GITHUB_TOKEN"),
+		DBDriver:              dbDriver,
 		DBHost:                os.Getenv("DB_HOST"),
 		DBUser:                os.Getenv("DB_USER"),
 		DBPassword:            os.Getenv("DB_PASSWORD"),
 		DBName:                os.Getenv("DB_NAME"),
 		DBPort:                uint(dBPort),
 		SSLMode:               env.Getenv("DB_SSL_MODE", "disable"),
+		SQLitePath:            sqlitePath,
+		MigrateOnStart:        migrateOnStart,
 		MonitorInterval:       intervalDuration,
 		DefaultStartDate:      sDate,
 		DefaultEndDate:        eDate,
@@ -97,6 +268,29 @@ func LoadConfig(log log.Log) (*Config, error) {
 		GitClientBaseURL:      os.Getenv("GIT_API_BASE_URL"),
 		ServerAddress:         env.Getenv("SERVER_ADDRESS", "localhost"),
 		ServerPort:            env.Getenv("SERVER_PORT", "8080"),
+		ShutdownTimeout:       shutdownDuration,
+		AuthEnabled:           authEnabled,
+		AdminAPIKey:           os.Getenv("ADMIN_API_KEY"),
+		RateLimitEnabled:      rateLimitEnabled,
+		RateLimits:            rateLimits,
+		OpenAPIValidation:     openAPIValidation,
+		LegacyRoutes:          legacyRoutes,
+		LegacyRoutesSunset:    legacySunset,
+		RepositoryGroups:      repositoryGroups,
+		StatsRepairInterval:   statsRepairDuration,
+		DeliverySource:        env.Getenv("DELIVERY_SOURCE", "releases"),
+		DeliveryBranch:        env.Getenv("DELIVERY_BRANCH", "main"),
+		DeliveryHotfixes:      env.Getenv("DELIVERY_HOTFIX_PATTERN", "hotfix*"),
+		OwnershipMonths:       ownershipMonths,
+		RetentionDays:         retentionDaysCount,
+		RetentionCommits:      retentionCommitsCount,
+		RetentionInterval:     retentionDuration,
+		GraphQLMaxDepth:       graphQLMaxDepth,
+		GraphQLMaxComplexity:  graphQLMaxComplexity,
+		GraphQLMaxFanout:      graphQLMaxFanout,
+		GRPCEnabled:           grpcEnabled,
+		GRPCPort:              env.Getenv("GRPC_PORT", "9090"),
+		GRPCPollInterval:      grpcPollDuration,
 		DefaultRepository:     env.Getenv("DEFAULT_REPOSITORY", "chromium/chromium"),
 	}
 	configVar.DSN = fmt.Sprintf(
@@ -113,3 +307,72 @@ func LoadConfig(log log.Log) (*Config, error) {
 
 	return &configVar, nil
 }
+
+// loadRateLimits reads the default limit and the per group overrides.
+func loadRateLimits(log log.Log) (map[string]ratelimit.Limit, error) {
+	defaultLimit := env.Getenv("RATE_LIMIT_DEFAULT", "120/m:30")
+
+	limit, err := ratelimit.ParseLimit(defaultLimit)
+	if err != nil {
+		log.Error.Printf("Invalid RATE_LIMIT_DEFAULT [%s] env format: %s", defaultLimit, err.Error())
+		return nil, err
+	}
+
+	limits := map[string]ratelimit.Limit{"default": limit}
+
+	// failed authentications are limited per client IP on their own, with a
+	// stricter default
+	authLimit := env.Getenv("RATE_LIMIT_AUTH", "10/m:10")
+	limit, err = ratelimit.ParseLimit(authLimit)
+	if err != nil {
+		log.Error.Printf("Invalid RATE_LIMIT_AUTH [%s] env format: %s", authLimit, err.Error())
+		return nil, err
+	}
+	limits["auth"] = limit
+
+	for _, group := range RateLimitGroups {
+		key := "RATE_LIMIT_" + strings.ToUpper(group)
+
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			log.Error.Printf("Invalid %s [%s] env format: %s", key, value, err.Error())
+			return nil, err
+		}
+		limits[group] = limit
+	}
+
+	return limits, nil
+}
+
+// ParseRepositoryGroups parses named groups of repositories in the format
+// "group=owner/a,owner/b;other=owner/c".
+func ParseRepositoryGroups(value string) (map[string][]string, error) {
+	groups := make(map[string][]string)
+
+	for _, group := range strings.Split(value, ";") {
+		if strings.TrimSpace(group) == "" {
+			continue
+		}
+
+		name, repos, ok := strings.Cut(group, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("group %q must be in the format name=owner/repo,owner/repo", group)
+		}
+
+		for _, repo := range strings.Split(repos, ",") {
+			repo = strings.TrimSpace(repo)
+			if owner, repoName, ok := strings.Cut(repo, "/"); !ok || owner == "" || repoName == "" {
+				return nil, fmt.Errorf("repository %q of group %q must be in the format owner/repo", repo, name)
+			}
+			groups[name] = append(groups[name], repo)
+		}
+	}
+
+	return groups, nil
+}
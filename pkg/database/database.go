@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+
+	"gorm.io/gorm"
 )
 
 type Database interface {
@@ -9,5 +11,10 @@ type Database interface {
 	Migrate(ctx context.Context) error
 	PingDb(ctx context.Context) error
 	CloseDb(ctx context.Context) error
-	GetDB() interface{}
+	GetDB() *gorm.DB
 }
+
+var (
+	_ Database = (*PostgresDatabase)(nil)
+	_ Database = (*SQLiteDatabase)(nil)
+)
@@ -0,0 +1,327 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migrationLockKey is the Postgres advisory lock held while migrating, so only
+// one instance migrates at a time.
+const migrationLockKey = 7_356_046_138
+
+// migrationFile matches migration file names such as 0001_initial.up.sql.
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// sqliteAdoptedColumns are the columns of the tables AutoMigrate created since
+// the first release, the tables it created lack the columns added after them.
+// They are added before the initial migration, which only creates tables that
+// do not exist.
+var sqliteAdoptedColumns = []struct {
+	table   string
+	columns []string
+}{
+	{table: "author", columns: []string{
+		"name text", "email text", "commit_count integer", "created_at datetime", "updated_at datetime",
+	}},
+	{table: "repository", columns: []string{
+		"owner_name text", "name text", "description text", "language text", "url text",
+		"forks_count integer", "stars_count integer", "open_issues_count integer", "watchers_count integer",
+		"created_at datetime", "updated_at datetime", "since datetime", "last_page integer",
+		"last_fetched_commit text", `"index" numeric`,
+	}},
+	{table: "commit", columns: []string{
+		"commit_hash text", "author_id integer", "repository_id integer", "message text",
+		"additions integer", "deletions integer", "type text", "scope text", "breaking numeric",
+		"description text", "date datetime", "created_at datetime", "last_page integer", "files_fetched numeric",
+	}},
+}
+
+// Migration is a versioned change of the schema with the SQL that applies and
+// reverts it.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus is a migration and the time it was applied, AppliedAt is nil
+// for pending migrations.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// LoadMigrations reads the migrations in dir, each version needs an up and a
+// down file.
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s must be named {version}_{name}.{up|down}.sql", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+
+		sql, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(sql)
+		} else {
+			migration.Down = string(sql)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Migrator applies and reverts the migrations of a database in order. Each
+// migration runs in its own transaction and is recorded in the
+// schema_migrations table.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	// before runs in the transaction of the migration of its version, before
+	// its SQL
+	before map[int64]func(tx *gorm.DB) error
+}
+
+// NewMigrator returns a Migrator with the embedded migrations of the dialect of db.
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := LoadMigrations(migrationFiles, path.Join("migrations", db.Dialector.Name()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s migrations: %w", db.Dialector.Name(), err)
+	}
+
+	m := &Migrator{db: db, migrations: migrations}
+	if db.Dialector.Name() == "sqlite" {
+		m.before = map[int64]func(tx *gorm.DB) error{1: addSQLiteAdoptedColumns}
+	}
+	return m, nil
+}
+
+// addSQLiteAdoptedColumns adds the columns of sqliteAdoptedColumns that the
+// existing tables lack.
+func addSQLiteAdoptedColumns(tx *gorm.DB) error {
+	for _, t := range sqliteAdoptedColumns {
+		if !tx.Migrator().HasTable(t.table) {
+			continue
+		}
+		for _, column := range t.columns {
+			name := strings.Trim(strings.Fields(column)[0], `"`)
+			if tx.Migrator().HasColumn(t.table, name) {
+				continue
+			}
+			if err := tx.Exec(fmt.Sprintf(`ALTER TABLE "%s" ADD COLUMN %s`, t.table, column)).Error; err != nil {
+				return fmt.Errorf("failed to add %s.%s: %w", t.table, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Up applies the pending migrations and returns how many were applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	var applied int
+
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		done, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if before, ok := m.before[migration.Version]; ok {
+					if err := before(tx); err != nil {
+						return err
+					}
+				}
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+				return tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+					migration.Version, migration.Name, time.Now()).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations and returns how many were
+// reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	var reverted int
+
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		done, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		known := make(map[int64]Migration, len(m.migrations))
+		for _, migration := range m.migrations {
+			known[migration.Version] = migration
+		}
+
+		versions := make([]int64, 0, len(done))
+		for version := range done {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, version := range versions[:min(steps, len(versions))] {
+			migration, ok := known[version]
+			if !ok {
+				return fmt.Errorf("migration %d was applied by a newer version and cannot be reverted by this one", version)
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+				return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status returns every known migration with the time it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	db := m.db.WithContext(ctx)
+
+	done := make(map[int64]time.Time)
+	if db.Migrator().HasTable("schema_migrations") {
+		var err error
+		if done, err = appliedMigrations(db); err != nil {
+			return nil, err
+		}
+	}
+
+	status := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		s := MigrationStatus{Migration: migration}
+		if appliedAt, ok := done[migration.Version]; ok {
+			s.AppliedAt = &appliedAt
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+// Pending returns the number of migrations that are not applied.
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var pending int
+	for _, s := range status {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// locked runs fn on a single connection after creating the schema_migrations
+// table. On Postgres the connection holds an advisory lock, other instances
+// wait for it. SQLite databases are written by a single instance.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		appliedAtType := "timestamp"
+		if m.db.Dialector.Name() == "postgres" {
+			appliedAtType = "timestamptz"
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+				return fmt.Errorf("failed to take the migration lock: %w", err)
+			}
+			// the lock must be released even when ctx is done, or the pooled
+			// connection keeps it
+			defer conn.WithContext(context.Background()).Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+		}
+
+		err := conn.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (" +
+			"version bigint PRIMARY KEY, name text NOT NULL, applied_at " + appliedAtType + " NOT NULL)").Error
+		if err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+
+		return fn(conn)
+	})
+}
+
+// appliedMigrations returns the applied versions and when they were applied.
+func appliedMigrations(db *gorm.DB) (map[int64]time.Time, error) {
+	var rows []struct {
+		Version   int64
+		AppliedAt time.Time
+	}
+	if err := db.Table("schema_migrations").Select("version, applied_at").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	applied := make(map[int64]time.Time, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
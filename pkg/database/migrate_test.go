@@ -0,0 +1,308 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// models are the tables the migrations must create.
+func models() []interface{} {
+	return []interface{}{
+		&repository.Author{},
+		&repository.Repository{},
+		&repository.Commit{},
+		&repository.CommitFile{},
+		&repository.CommitLink{},
+		&repository.AuthorRepositoryStat{},
+		&repository.Tag{},
+		&repository.Release{},
+		&repository.PullRequest{},
+		&repository.PullRequestCommit{},
+		&repository.APIKey{},
+	}
+}
+
+// The author, repository and commit models of the first release, whose schema
+// AutoMigrate created before the migrations existed.
+type baselineAuthor struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"index"`
+	Email       string `gorm:"index"`
+	CommitCount int
+	Commits     []baselineCommit `gorm:"foreignKey:AuthorID"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (baselineAuthor) TableName() string { return "author" }
+
+type baselineRepository struct {
+	ID                uint   `gorm:"primaryKey"`
+	OwnerName         string `gorm:"index"`
+	Name              string `gorm:"uniqueIndex"`
+	Description       string
+	Language          string
+	URL               string
+	ForksCount        int
+	StarsCount        int
+	OpenIssuesCount   int
+	WatchersCount     int
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Commits           []baselineCommit `gorm:"foreignKey:RepositoryID"`
+	Since             time.Time
+	LastPage          int
+	LastFetchedCommit string
+	Index             bool
+}
+
+func (baselineRepository) TableName() string { return "repository" }
+
+type baselineCommit struct {
+	ID           uint   `gorm:"primaryKey"`
+	CommitHash   string `gorm:"uniqueIndex"`
+	AuthorID     uint
+	RepositoryID uint
+	Message      string
+	Date         time.Time
+	Author       baselineAuthor `gorm:"foreignKey:AuthorID"`
+	CreatedAt    time.Time
+	LastPage     int
+}
+
+func (baselineCommit) TableName() string { return "commit" }
+
+// newSQLiteDB returns a connected in memory database without tables
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db := NewSQLiteDatabase(":memory:")
+	require.NoError(t, db.ConnectDB(context.TODO()))
+	t.Cleanup(func() { db.CloseDb(context.TODO()) })
+
+	return db.GetDB()
+}
+
+// TestLoadMigrations tests that migrations are read in version order
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0010_second.up.sql":   {Data: []byte("CREATE TABLE b (id int);")},
+		"m/0010_second.down.sql": {Data: []byte("DROP TABLE b;")},
+		"m/0002_first.up.sql":    {Data: []byte("CREATE TABLE a (id int);")},
+		"m/0002_first.down.sql":  {Data: []byte("DROP TABLE a;")},
+	}
+
+	migrations, err := LoadMigrations(fsys, "m")
+
+	require.NoError(t, err)
+	assert.Equal(t, []Migration{
+		{Version: 2, Name: "first", Up: "CREATE TABLE a (id int);", Down: "DROP TABLE a;"},
+		{Version: 10, Name: "second", Up: "CREATE TABLE b (id int);", Down: "DROP TABLE b;"},
+	}, migrations)
+}
+
+// TestLoadMigrations_Invalid tests that badly named or incomplete migrations are rejected
+func TestLoadMigrations_Invalid(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"bad name": {
+			"m/initial.sql": {Data: []byte("SELECT 1;")},
+		},
+		"missing down": {
+			"m/0001_initial.up.sql": {Data: []byte("SELECT 1;")},
+		},
+		"two names": {
+			"m/0001_initial.up.sql": {Data: []byte("SELECT 1;")},
+			"m/0001_other.down.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadMigrations(fsys, "m")
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestMigrator_UpDown tests applying, reverting and reapplying the embedded migrations
+func TestMigrator_UpDown(t *testing.T) {
+	// Arrange
+	db := newSQLiteDB(t)
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	total := len(migrator.migrations)
+
+	pending, err := migrator.Pending(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, total, pending)
+
+	// Act
+	applied, err := migrator.Up(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, total, applied)
+
+	applied, err = migrator.Up(context.TODO())
+	require.NoError(t, err)
+	assert.Zero(t, applied)
+
+	status, err := migrator.Status(context.TODO())
+	require.NoError(t, err)
+	for _, s := range status {
+		assert.NotNil(t, s.AppliedAt, s.Name)
+	}
+
+	reverted, err := migrator.Down(context.TODO(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reverted)
+
+	pending, err = migrator.Pending(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, 1, pending)
+
+	reverted, err = migrator.Down(context.TODO(), total)
+	require.NoError(t, err)
+	assert.Equal(t, total-1, reverted)
+	assert.False(t, db.Migrator().HasTable(&repository.Commit{}))
+
+	applied, err = migrator.Up(context.TODO())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, total, applied)
+	assert.True(t, db.Migrator().HasTable(&repository.Commit{}))
+}
+
+// TestMigrator_MatchesModels tests that the migrated schema has the tables,
+// columns and indexes of the GORM models
+func TestMigrator_MatchesModels(t *testing.T) {
+	// Arrange
+	db := newSQLiteDB(t)
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+
+	// Act
+	_, err = migrator.Up(context.TODO())
+
+	// Assert
+	require.NoError(t, err)
+	for _, model := range models() {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(model))
+		table := stmt.Schema.Table
+
+		require.True(t, db.Migrator().HasTable(model), table)
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" {
+				assert.True(t, db.Migrator().HasColumn(model, field.DBName), "%s.%s", table, field.DBName)
+			}
+		}
+		for _, index := range stmt.Schema.ParseIndexes() {
+			assert.True(t, db.Migrator().HasIndex(model, index.Name), "%s %s", table, index.Name)
+		}
+	}
+}
+
+// TestMigrator_MergesDuplicateAuthors tests that authors stored twice are merged
+// with their commits and aggregates before their identity becomes unique
+func TestMigrator_MergesDuplicateAuthors(t *testing.T) {
+	// Arrange
+	db := newSQLiteDB(t)
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	all := migrator.migrations
+	for i, migration := range all {
+		if migration.Name == "author_identity" {
+			migrator.migrations = all[:i]
+		}
+	}
+	_, err = migrator.Up(context.TODO())
+	require.NoError(t, err)
+	migrator.migrations = all
+
+	require.NoError(t, db.Exec(`INSERT INTO repository (id, name) VALUES (1, 'org/repo')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO author (id, name, email, commit_count) VALUES
+		(1, 'Jane', 'jane@example.com', 1), (2, 'John', 'john@example.com', 1), (3, 'Jane', 'jane@example.com', 2)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO "commit" (id, commit_hash, author_id, repository_id, additions) VALUES
+		(1, 'a', 1, 1, 1), (2, 'b', 2, 1, 1), (3, 'c', 3, 1, 2), (4, 'd', 3, 1, 3)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO author_repository_stat (author_id, repository_id, commit_count, additions, deletions) VALUES
+		(1, 1, 1, 1, 0), (2, 1, 1, 1, 0), (3, 1, 2, 5, 0)`).Error)
+
+	// Act
+	_, err = migrator.Up(context.TODO())
+
+	// Assert
+	require.NoError(t, err)
+
+	var authors []repository.Author
+	require.NoError(t, db.Order("id").Find(&authors).Error)
+	require.Len(t, authors, 2)
+	assert.Equal(t, uint(1), authors[0].ID)
+	assert.Equal(t, 3, authors[0].CommitCount)
+
+	var authorIDs []uint
+	require.NoError(t, db.Model(&repository.Commit{}).Order("id").Pluck("author_id", &authorIDs).Error)
+	assert.Equal(t, []uint{1, 2, 1, 1}, authorIDs)
+
+	var stat repository.AuthorRepositoryStat
+	require.NoError(t, db.Where("author_id = 1 AND repository_id = 1").First(&stat).Error)
+	assert.Equal(t, 3, stat.CommitCount)
+	assert.Equal(t, 6, stat.Additions)
+
+	err = db.Exec(`INSERT INTO author (name, email) VALUES ('Jane', 'jane@example.com')`).Error
+	assert.Error(t, err)
+}
+
+// TestMigrator_AdoptsBaselineSchema tests that a database AutoMigrate created in
+// the first release gains the columns added since and keeps its data
+func TestMigrator_AdoptsBaselineSchema(t *testing.T) {
+	// Arrange
+	db := newSQLiteDB(t)
+	require.NoError(t, db.AutoMigrate(&baselineAuthor{}, &baselineRepository{}, &baselineCommit{}))
+
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := baselineRepository{Name: "org/repo", OwnerName: "org", LastPage: 3, LastFetchedCommit: "b"}
+	require.NoError(t, db.Create(&repo).Error)
+	author := baselineAuthor{Name: "Jane", Email: "jane@example.com", CommitCount: 2}
+	require.NoError(t, db.Create(&author).Error)
+	require.NoError(t, db.Create(&[]baselineCommit{
+		{CommitHash: "a", AuthorID: author.ID, RepositoryID: repo.ID, Message: "feat(api): one", Date: date},
+		{CommitHash: "b", AuthorID: author.ID, RepositoryID: repo.ID, Message: "fix: two", Date: date.Add(time.Hour)},
+	}).Error)
+
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+
+	// Act
+	applied, err := migrator.Up(context.TODO())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, len(migrator.migrations), applied)
+	for _, model := range models() {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(model))
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" {
+				assert.True(t, db.Migrator().HasColumn(model, field.DBName), "%s.%s", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+
+	var commits []repository.Commit
+	require.NoError(t, db.Order("id").Find(&commits).Error)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "a", commits[0].CommitHash)
+	assert.Equal(t, repo.ID, commits[1].RepositoryID)
+
+	var stored repository.Repository
+	require.NoError(t, db.First(&stored, repo.ID).Error)
+	assert.Equal(t, 3, stored.LastPage)
+	assert.Equal(t, "b", stored.LastFetchedCommit)
+}
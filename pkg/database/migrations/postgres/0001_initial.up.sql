@@ -0,0 +1,203 @@
+-- The schema as created by GORM AutoMigrate. Every statement is idempotent and
+-- the author, repository and commit tables gain the columns they lack, so
+-- databases created by AutoMigrate, from the first release on, adopt the
+-- migrations.
+
+CREATE TABLE IF NOT EXISTS author (
+    id bigserial PRIMARY KEY,
+    name text,
+    email text,
+    commit_count bigint,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+ALTER TABLE author
+    ADD COLUMN IF NOT EXISTS name text,
+    ADD COLUMN IF NOT EXISTS email text,
+    ADD COLUMN IF NOT EXISTS commit_count bigint,
+    ADD COLUMN IF NOT EXISTS created_at timestamptz,
+    ADD COLUMN IF NOT EXISTS updated_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_author_name ON author (name);
+CREATE INDEX IF NOT EXISTS idx_author_email ON author (email);
+
+CREATE TABLE IF NOT EXISTS repository (
+    id bigserial PRIMARY KEY,
+    owner_name text,
+    name text,
+    description text,
+    language text,
+    url text,
+    forks_count bigint,
+    stars_count bigint,
+    open_issues_count bigint,
+    watchers_count bigint,
+    created_at timestamptz,
+    updated_at timestamptz,
+    since timestamptz,
+    last_page bigint,
+    last_fetched_commit text,
+    "index" boolean
+);
+ALTER TABLE repository
+    ADD COLUMN IF NOT EXISTS owner_name text,
+    ADD COLUMN IF NOT EXISTS name text,
+    ADD COLUMN IF NOT EXISTS description text,
+    ADD COLUMN IF NOT EXISTS language text,
+    ADD COLUMN IF NOT EXISTS url text,
+    ADD COLUMN IF NOT EXISTS forks_count bigint,
+    ADD COLUMN IF NOT EXISTS stars_count bigint,
+    ADD COLUMN IF NOT EXISTS open_issues_count bigint,
+    ADD COLUMN IF NOT EXISTS watchers_count bigint,
+    ADD COLUMN IF NOT EXISTS created_at timestamptz,
+    ADD COLUMN IF NOT EXISTS updated_at timestamptz,
+    ADD COLUMN IF NOT EXISTS since timestamptz,
+    ADD COLUMN IF NOT EXISTS last_page bigint,
+    ADD COLUMN IF NOT EXISTS last_fetched_commit text,
+    ADD COLUMN IF NOT EXISTS "index" boolean;
+CREATE INDEX IF NOT EXISTS idx_repository_owner_name ON repository (owner_name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_repository_name ON repository (name);
+
+CREATE TABLE IF NOT EXISTS "commit" (
+    id bigserial PRIMARY KEY,
+    commit_hash text,
+    author_id bigint,
+    repository_id bigint,
+    message text,
+    additions bigint,
+    deletions bigint,
+    type text,
+    scope text,
+    breaking boolean,
+    description text,
+    date timestamptz,
+    created_at timestamptz,
+    last_page bigint,
+    files_fetched boolean,
+    CONSTRAINT fk_author_commits FOREIGN KEY (author_id) REFERENCES author (id),
+    CONSTRAINT fk_repository_commits FOREIGN KEY (repository_id) REFERENCES repository (id)
+);
+ALTER TABLE "commit"
+    ADD COLUMN IF NOT EXISTS commit_hash text,
+    ADD COLUMN IF NOT EXISTS author_id bigint,
+    ADD COLUMN IF NOT EXISTS repository_id bigint,
+    ADD COLUMN IF NOT EXISTS message text,
+    ADD COLUMN IF NOT EXISTS additions bigint,
+    ADD COLUMN IF NOT EXISTS deletions bigint,
+    ADD COLUMN IF NOT EXISTS type text,
+    ADD COLUMN IF NOT EXISTS scope text,
+    ADD COLUMN IF NOT EXISTS breaking boolean,
+    ADD COLUMN IF NOT EXISTS description text,
+    ADD COLUMN IF NOT EXISTS date timestamptz,
+    ADD COLUMN IF NOT EXISTS created_at timestamptz,
+    ADD COLUMN IF NOT EXISTS last_page bigint,
+    ADD COLUMN IF NOT EXISTS files_fetched boolean;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commit_commit_hash ON "commit" (commit_hash);
+CREATE INDEX IF NOT EXISTS idx_commit_repository_date ON "commit" (repository_id, date);
+CREATE INDEX IF NOT EXISTS idx_commit_type ON "commit" (type);
+
+CREATE TABLE IF NOT EXISTS commit_file (
+    id bigserial PRIMARY KEY,
+    commit_id bigint,
+    path text,
+    directory text,
+    additions bigint,
+    deletions bigint
+);
+CREATE INDEX IF NOT EXISTS idx_commit_file_commit_id ON commit_file (commit_id);
+CREATE INDEX IF NOT EXISTS idx_commit_file_path ON commit_file (path);
+CREATE INDEX IF NOT EXISTS idx_commit_file_directory ON commit_file (directory);
+
+CREATE TABLE IF NOT EXISTS commit_link (
+    commit_id bigint PRIMARY KEY,
+    repository_id bigint,
+    kind text,
+    target text,
+    target_id bigint,
+    date timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_commit_link_repository_date ON commit_link (repository_id, date);
+CREATE INDEX IF NOT EXISTS idx_commit_link_target_id ON commit_link (target_id);
+
+CREATE TABLE IF NOT EXISTS author_repository_stat (
+    author_id bigint,
+    repository_id bigint,
+    commit_count bigint,
+    additions bigint,
+    deletions bigint,
+    first_commit_at timestamptz,
+    last_commit_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (author_id, repository_id)
+);
+CREATE INDEX IF NOT EXISTS idx_author_repository_stat_repository_id ON author_repository_stat (repository_id);
+
+CREATE TABLE IF NOT EXISTS tag (
+    id bigserial PRIMARY KEY,
+    repository_id bigint,
+    name text,
+    commit_hash text,
+    date timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tag_repository_name ON tag (repository_id, name);
+CREATE INDEX IF NOT EXISTS idx_tag_commit_hash ON tag (commit_hash);
+CREATE INDEX IF NOT EXISTS idx_tag_date ON tag (date);
+
+CREATE TABLE IF NOT EXISTS "release" (
+    id bigserial PRIMARY KEY,
+    repository_id bigint,
+    tag_name text,
+    name text,
+    body text,
+    prerelease boolean,
+    published_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_release_repository_tag ON "release" (repository_id, tag_name);
+CREATE INDEX IF NOT EXISTS idx_release_published_at ON "release" (published_at);
+
+CREATE TABLE IF NOT EXISTS pull_request (
+    id bigserial PRIMARY KEY,
+    repository_id bigint,
+    number bigint,
+    title text,
+    author_login text,
+    state text,
+    base_branch text,
+    head_branch text,
+    merge_commit_hash text,
+    opened_at timestamptz,
+    merged_at timestamptz,
+    closed_at timestamptz,
+    remote_updated_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pull_request_repository_number ON pull_request (repository_id, number);
+CREATE INDEX IF NOT EXISTS idx_pull_request_author_login ON pull_request (author_login);
+CREATE INDEX IF NOT EXISTS idx_pull_request_state ON pull_request (state);
+CREATE INDEX IF NOT EXISTS idx_pull_request_merge_commit_hash ON pull_request (merge_commit_hash);
+CREATE INDEX IF NOT EXISTS idx_pull_request_opened_at ON pull_request (opened_at);
+
+CREATE TABLE IF NOT EXISTS pull_request_commit (
+    pull_request_id bigint,
+    commit_hash text,
+    commit_id bigint,
+    PRIMARY KEY (pull_request_id, commit_hash)
+);
+CREATE INDEX IF NOT EXISTS idx_pull_request_commit_commit_hash ON pull_request_commit (commit_hash);
+CREATE INDEX IF NOT EXISTS idx_pull_request_commit_commit_id ON pull_request_commit (commit_id);
+
+CREATE TABLE IF NOT EXISTS api_key (
+    id bigserial PRIMARY KEY,
+    name text,
+    prefix text,
+    key_hash text,
+    role text,
+    created_at timestamptz,
+    revoked_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_api_key_name ON api_key (name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_key_key_hash ON api_key (key_hash);
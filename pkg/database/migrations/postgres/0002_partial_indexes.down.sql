@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_commit_link_unresolved;
+DROP INDEX IF EXISTS idx_commit_files_pending;
+DROP INDEX IF EXISTS idx_commit_unparsed;
@@ -0,0 +1,6 @@
+-- Partial indexes for the backlog queries, which only look at the few rows
+-- still waiting to be parsed, fetched or linked.
+
+CREATE INDEX idx_commit_unparsed ON "commit" (id) WHERE type IS NULL;
+CREATE INDEX idx_commit_files_pending ON "commit" (repository_id, date) WHERE NOT files_fetched;
+CREATE INDEX idx_commit_link_unresolved ON commit_link (repository_id, commit_id) WHERE target_id IS NULL;
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS api_key;
+DROP TABLE IF EXISTS pull_request_commit;
+DROP TABLE IF EXISTS pull_request;
+DROP TABLE IF EXISTS "release";
+DROP TABLE IF EXISTS tag;
+DROP TABLE IF EXISTS author_repository_stat;
+DROP TABLE IF EXISTS commit_link;
+DROP TABLE IF EXISTS commit_file;
+DROP TABLE IF EXISTS "commit";
+DROP TABLE IF EXISTS repository;
+DROP TABLE IF EXISTS author;
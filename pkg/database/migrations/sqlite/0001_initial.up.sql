@@ -0,0 +1,166 @@
+-- The schema as created by GORM AutoMigrate. Every statement is idempotent and
+-- the Migrator first adds the columns the author, repository and commit tables
+-- lack, SQLite cannot add a column only if it is missing, so databases created
+-- by AutoMigrate, from the first release on, adopt the migrations.
+
+CREATE TABLE IF NOT EXISTS author (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text,
+    email text,
+    commit_count integer,
+    created_at datetime,
+    updated_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_author_name ON author (name);
+CREATE INDEX IF NOT EXISTS idx_author_email ON author (email);
+
+CREATE TABLE IF NOT EXISTS repository (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    owner_name text,
+    name text,
+    description text,
+    language text,
+    url text,
+    forks_count integer,
+    stars_count integer,
+    open_issues_count integer,
+    watchers_count integer,
+    created_at datetime,
+    updated_at datetime,
+    since datetime,
+    last_page integer,
+    last_fetched_commit text,
+    "index" numeric
+);
+CREATE INDEX IF NOT EXISTS idx_repository_owner_name ON repository (owner_name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_repository_name ON repository (name);
+
+CREATE TABLE IF NOT EXISTS "commit" (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    commit_hash text,
+    author_id integer,
+    repository_id integer,
+    message text,
+    additions integer,
+    deletions integer,
+    type text,
+    scope text,
+    breaking numeric,
+    description text,
+    date datetime,
+    created_at datetime,
+    last_page integer,
+    files_fetched numeric,
+    CONSTRAINT fk_author_commits FOREIGN KEY (author_id) REFERENCES author (id),
+    CONSTRAINT fk_repository_commits FOREIGN KEY (repository_id) REFERENCES repository (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commit_commit_hash ON "commit" (commit_hash);
+CREATE INDEX IF NOT EXISTS idx_commit_repository_date ON "commit" (repository_id, date);
+CREATE INDEX IF NOT EXISTS idx_commit_type ON "commit" (type);
+
+CREATE TABLE IF NOT EXISTS commit_file (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    commit_id integer,
+    path text,
+    directory text,
+    additions integer,
+    deletions integer
+);
+CREATE INDEX IF NOT EXISTS idx_commit_file_commit_id ON commit_file (commit_id);
+CREATE INDEX IF NOT EXISTS idx_commit_file_path ON commit_file (path);
+CREATE INDEX IF NOT EXISTS idx_commit_file_directory ON commit_file (directory);
+
+CREATE TABLE IF NOT EXISTS commit_link (
+    commit_id integer PRIMARY KEY,
+    repository_id integer,
+    kind text,
+    target text,
+    target_id integer,
+    date datetime
+);
+CREATE INDEX IF NOT EXISTS idx_commit_link_repository_date ON commit_link (repository_id, date);
+CREATE INDEX IF NOT EXISTS idx_commit_link_target_id ON commit_link (target_id);
+
+CREATE TABLE IF NOT EXISTS author_repository_stat (
+    author_id integer,
+    repository_id integer,
+    commit_count integer,
+    additions integer,
+    deletions integer,
+    first_commit_at datetime,
+    last_commit_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (author_id, repository_id)
+);
+CREATE INDEX IF NOT EXISTS idx_author_repository_stat_repository_id ON author_repository_stat (repository_id);
+
+CREATE TABLE IF NOT EXISTS tag (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    repository_id integer,
+    name text,
+    commit_hash text,
+    date datetime,
+    created_at datetime,
+    updated_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tag_repository_name ON tag (repository_id, name);
+CREATE INDEX IF NOT EXISTS idx_tag_commit_hash ON tag (commit_hash);
+CREATE INDEX IF NOT EXISTS idx_tag_date ON tag (date);
+
+CREATE TABLE IF NOT EXISTS "release" (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    repository_id integer,
+    tag_name text,
+    name text,
+    body text,
+    prerelease numeric,
+    published_at datetime,
+    created_at datetime,
+    updated_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_release_repository_tag ON "release" (repository_id, tag_name);
+CREATE INDEX IF NOT EXISTS idx_release_published_at ON "release" (published_at);
+
+CREATE TABLE IF NOT EXISTS pull_request (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    repository_id integer,
+    number integer,
+    title text,
+    author_login text,
+    state text,
+    base_branch text,
+    head_branch text,
+    merge_commit_hash text,
+    opened_at datetime,
+    merged_at datetime,
+    closed_at datetime,
+    remote_updated_at datetime,
+    created_at datetime,
+    updated_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pull_request_repository_number ON pull_request (repository_id, number);
+CREATE INDEX IF NOT EXISTS idx_pull_request_author_login ON pull_request (author_login);
+CREATE INDEX IF NOT EXISTS idx_pull_request_state ON pull_request (state);
+CREATE INDEX IF NOT EXISTS idx_pull_request_merge_commit_hash ON pull_request (merge_commit_hash);
+CREATE INDEX IF NOT EXISTS idx_pull_request_opened_at ON pull_request (opened_at);
+
+CREATE TABLE IF NOT EXISTS pull_request_commit (
+    pull_request_id integer,
+    commit_hash text,
+    commit_id integer,
+    PRIMARY KEY (pull_request_id, commit_hash)
+);
+CREATE INDEX IF NOT EXISTS idx_pull_request_commit_commit_hash ON pull_request_commit (commit_hash);
+CREATE INDEX IF NOT EXISTS idx_pull_request_commit_commit_id ON pull_request_commit (commit_id);
+
+CREATE TABLE IF NOT EXISTS api_key (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text,
+    prefix text,
+    key_hash text,
+    role text,
+    created_at datetime,
+    revoked_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_api_key_name ON api_key (name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_key_key_hash ON api_key (key_hash);
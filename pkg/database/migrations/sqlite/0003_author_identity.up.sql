@@ -0,0 +1,38 @@
+-- Authors are identified by their name and email. Concurrent saves could store
+-- an author twice, so duplicates are merged into the oldest row before the pair
+-- becomes unique and commits can upsert their authors.
+
+CREATE TEMPORARY TABLE author_merge AS
+SELECT a.id, k.keep
+FROM author a
+JOIN (SELECT name, email, MIN(id) AS keep FROM author GROUP BY name, email HAVING COUNT(*) > 1) k
+    ON a.name = k.name AND a.email = k.email
+WHERE a.id <> k.keep;
+
+UPDATE "commit" SET author_id = (SELECT keep FROM author_merge WHERE author_merge.id = "commit".author_id)
+WHERE author_id IN (SELECT id FROM author_merge);
+
+INSERT INTO author_repository_stat (author_id, repository_id, commit_count, additions, deletions, first_commit_at, last_commit_at, updated_at)
+SELECT m.keep, s.repository_id, SUM(s.commit_count), SUM(s.additions), SUM(s.deletions), MIN(s.first_commit_at), MAX(s.last_commit_at), CURRENT_TIMESTAMP
+FROM author_repository_stat s
+JOIN author_merge m ON s.author_id = m.id
+WHERE true
+GROUP BY m.keep, s.repository_id
+ON CONFLICT (author_id, repository_id) DO UPDATE SET
+    commit_count = author_repository_stat.commit_count + excluded.commit_count,
+    additions = author_repository_stat.additions + excluded.additions,
+    deletions = author_repository_stat.deletions + excluded.deletions,
+    first_commit_at = CASE WHEN excluded.first_commit_at < author_repository_stat.first_commit_at THEN excluded.first_commit_at ELSE author_repository_stat.first_commit_at END,
+    last_commit_at = CASE WHEN excluded.last_commit_at > author_repository_stat.last_commit_at THEN excluded.last_commit_at ELSE author_repository_stat.last_commit_at END,
+    updated_at = excluded.updated_at;
+
+DELETE FROM author_repository_stat WHERE author_id IN (SELECT id FROM author_merge);
+
+UPDATE author SET commit_count = (SELECT COALESCE(SUM(s.commit_count), 0) FROM author_repository_stat s WHERE s.author_id = author.id)
+WHERE id IN (SELECT keep FROM author_merge);
+
+DELETE FROM author WHERE id IN (SELECT id FROM author_merge);
+
+DROP TABLE author_merge;
+
+CREATE UNIQUE INDEX idx_author_identity ON author (name, email);
@@ -0,0 +1,2 @@
+ALTER TABLE repository DROP COLUMN retention_commits;
+ALTER TABLE repository DROP COLUMN retention_days;
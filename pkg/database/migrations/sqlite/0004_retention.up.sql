@@ -0,0 +1,4 @@
+-- Retention policy of a repository, zero keeps every commit.
+
+ALTER TABLE repository ADD COLUMN retention_days integer NOT NULL DEFAULT 0;
+ALTER TABLE repository ADD COLUMN retention_commits integer NOT NULL DEFAULT 0;
@@ -0,0 +1,236 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/just-nibble/git-service/pkg/log"
+	"gorm.io/gorm"
+)
+
+// partitionsAhead is the number of years after the current one that commit
+// partitions are created for.
+const partitionsAhead = 1
+
+// commitIndexes are the indexes of the partitioned commit table. The index on
+// the hash also holds the date, the partition key, hashes are unique in
+// commit_key.
+var commitIndexes = []string{
+	`CREATE UNIQUE INDEX idx_commit_commit_hash ON "commit" (commit_hash, date)`,
+	`CREATE INDEX idx_commit_repository_date ON "commit" (repository_id, date)`,
+	`CREATE INDEX idx_commit_type ON "commit" (type)`,
+	`CREATE INDEX idx_commit_unparsed ON "commit" (id) WHERE type IS NULL`,
+	`CREATE INDEX idx_commit_files_pending ON "commit" (repository_id, date) WHERE NOT files_fetched`,
+}
+
+// commitKeyStatements create commit_key, which holds the id and hash of every
+// commit. A partitioned table only enforces keys that include the partition key,
+// so commit_key enforces the uniqueness of hashes and is referenced by the
+// foreign keys to commits instead, the trigger keeps it in sync with the commits.
+var commitKeyStatements = []string{
+	`CREATE TABLE commit_key (id bigint PRIMARY KEY, commit_hash text UNIQUE)`,
+	`INSERT INTO commit_key (id, commit_hash) SELECT id, commit_hash FROM "commit"`,
+	`CREATE FUNCTION commit_key_sync() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		INSERT INTO commit_key (id, commit_hash) VALUES (NEW.id, NEW.commit_hash);
+	ELSIF TG_OP = 'UPDATE' THEN
+		UPDATE commit_key SET id = NEW.id, commit_hash = NEW.commit_hash WHERE id = OLD.id;
+	ELSE
+		DELETE FROM commit_key WHERE id = OLD.id;
+	END IF;
+	RETURN NULL;
+END
+$$`,
+	`CREATE TRIGGER commit_key_sync AFTER INSERT OR DELETE OR UPDATE OF id, commit_hash ON "commit"
+	FOR EACH ROW EXECUTE FUNCTION commit_key_sync()`,
+}
+
+// commitReference matches the referenced table of a foreign key to commits.
+var commitReference = regexp.MustCompile(`REFERENCES "?commit"?\(`)
+
+// commitKeyReference returns the definition of a foreign key to commits with
+// commit_key as the referenced table.
+func commitKeyReference(definition string) string {
+	return commitReference.ReplaceAllString(definition, "REFERENCES commit_key(")
+}
+
+// commitPartition returns the name and the range of the partition holding the
+// commits of a year.
+func commitPartition(year int) (name, from, to string) {
+	return fmt.Sprintf("commit_y%d", year), fmt.Sprintf("%d-01-01", year), fmt.Sprintf("%d-01-01", year+1)
+}
+
+// CommitsPartitioned reports whether the commit table is partitioned.
+func CommitsPartitioned(ctx context.Context, db *gorm.DB) (bool, error) {
+	if db.Dialector.Name() != "postgres" {
+		return false, nil
+	}
+
+	var partitioned bool
+	err := db.WithContext(ctx).
+		Raw(`SELECT EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = '"commit"'::regclass)`).
+		Scan(&partitioned).Error
+	return partitioned, err
+}
+
+// PartitionCommits converts the commit table of a Postgres database into a table
+// partitioned by year of the commit date. The commits are copied in a single
+// transaction that locks the table, so the service should be stopped while it
+// runs. A partitioned table cannot be referenced by id alone, so the foreign
+// keys referencing commits are recreated on commit_key, which also keeps hashes
+// unique. Commits without a date are rejected, commits dated outside of the
+// created years go to a default partition.
+func PartitionCommits(ctx context.Context, db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" {
+		return fmt.Errorf("partitioning is only supported on postgres")
+	}
+
+	partitioned, err := CommitsPartitioned(ctx, db)
+	if err != nil {
+		return err
+	}
+	if partitioned {
+		return fmt.Errorf("the commit table is already partitioned")
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`LOCK TABLE "commit" IN ACCESS EXCLUSIVE MODE`).Error; err != nil {
+			return err
+		}
+
+		var undated int64
+		if err := tx.Raw(`SELECT COUNT(*) FROM "commit" WHERE date IS NULL`).Scan(&undated).Error; err != nil {
+			return err
+		}
+		if undated > 0 {
+			return fmt.Errorf("%d commits have no date, the partition key, set one before partitioning", undated)
+		}
+
+		// the foreign keys are dropped with the old table and recreated on
+		// commit_key
+		var references []struct {
+			Table      string
+			Name       string
+			Definition string
+		}
+		err := tx.Raw(`SELECT conrelid::regclass::text AS "table", quote_ident(conname) AS name, pg_get_constraintdef(oid) AS definition
+			FROM pg_constraint WHERE contype = 'f' AND confrelid = '"commit"'::regclass`).Scan(&references).Error
+		if err != nil {
+			return err
+		}
+
+		var first *time.Time
+		if err := tx.Raw(`SELECT MIN(date) FROM "commit"`).Scan(&first).Error; err != nil {
+			return err
+		}
+		last := time.Now().Year() + partitionsAhead
+		from := time.Now().Year()
+		if first != nil && first.Year() < from {
+			from = first.Year()
+		}
+
+		statements := []string{
+			`CREATE TABLE commit_partitioned (LIKE "commit" INCLUDING DEFAULTS) PARTITION BY RANGE (date)`,
+			`ALTER TABLE commit_partitioned ADD CONSTRAINT commit_partitioned_pkey PRIMARY KEY (id, date)`,
+			`ALTER TABLE commit_partitioned ADD CONSTRAINT fk_author_commits FOREIGN KEY (author_id) REFERENCES author (id)`,
+			`ALTER TABLE commit_partitioned ADD CONSTRAINT fk_repository_commits FOREIGN KEY (repository_id) REFERENCES repository (id)`,
+		}
+		for year := from; year <= last; year++ {
+			name, start, end := commitPartition(year)
+			statements = append(statements, fmt.Sprintf(
+				`CREATE TABLE %s PARTITION OF commit_partitioned FOR VALUES FROM ('%s') TO ('%s')`, name, start, end))
+		}
+		statements = append(statements,
+			`CREATE TABLE commit_default PARTITION OF commit_partitioned DEFAULT`,
+			`INSERT INTO commit_partitioned SELECT * FROM "commit"`,
+			// the id sequence would be dropped with the old table
+			`ALTER SEQUENCE commit_id_seq OWNED BY NONE`,
+			// the primary key of a partitioned table includes the partition key,
+			// so the foreign keys to commit ids are dropped with the old table
+			`DROP TABLE "commit" CASCADE`,
+			`ALTER TABLE commit_partitioned RENAME TO "commit"`,
+			`ALTER TABLE "commit" RENAME CONSTRAINT commit_partitioned_pkey TO commit_pkey`,
+			`ALTER SEQUENCE commit_id_seq OWNED BY "commit".id`,
+		)
+		statements = append(statements, commitIndexes...)
+		statements = append(statements, commitKeyStatements...)
+		for _, r := range references {
+			statements = append(statements, fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %s %s`, r.Table, r.Name, commitKeyReference(r.Definition)))
+		}
+
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to partition commits: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// EnsureCommitPartitions creates the partitions of the current year and the
+// years ahead that are missing and returns how many were created. It does
+// nothing when the commit table is not partitioned.
+func EnsureCommitPartitions(ctx context.Context, db *gorm.DB, now time.Time) (int, error) {
+	partitioned, err := CommitsPartitioned(ctx, db)
+	if err != nil || !partitioned {
+		return 0, err
+	}
+
+	var created int
+	for year := now.Year(); year <= now.Year()+partitionsAhead; year++ {
+		name, start, end := commitPartition(year)
+
+		var exists bool
+		if err := db.WithContext(ctx).Raw(`SELECT to_regclass(?) IS NOT NULL`, name).Scan(&exists).Error; err != nil {
+			return created, err
+		}
+		if exists {
+			continue
+		}
+
+		// commits of the year that went to the default partition would make
+		// the new partition overlap with it
+		var stray bool
+		err := db.WithContext(ctx).
+			Raw(`SELECT EXISTS (SELECT 1 FROM commit_default WHERE date >= ? AND date < ?)`, start, end).
+			Scan(&stray).Error
+		if err != nil {
+			return created, err
+		}
+		if stray {
+			return created, fmt.Errorf("partition %s cannot be created, commit_default holds commits of %d", name, year)
+		}
+
+		err = db.WithContext(ctx).Exec(fmt.Sprintf(
+			`CREATE TABLE %s PARTITION OF "commit" FOR VALUES FROM ('%s') TO ('%s')`, name, start, end)).Error
+		if err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+// MaintainCommitPartitions creates upcoming commit partitions every interval
+// until ctx is done.
+func MaintainCommitPartitions(ctx context.Context, db *gorm.DB, interval time.Duration, logger log.Log) {
+	for ctx.Err() == nil {
+		created, err := EnsureCommitPartitions(ctx, db, time.Now())
+		if err != nil && ctx.Err() == nil {
+			logger.Error.Printf("Error creating commit partitions: %s", err.Error())
+		}
+		if created > 0 {
+			logger.Info.Printf("Created %d commit partitions", created)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCommitPartition tests that a partition holds a whole year
+func TestCommitPartition(t *testing.T) {
+	// Act
+	name, from, to := commitPartition(2024)
+
+	// Assert
+	assert.Equal(t, "commit_y2024", name)
+	assert.Equal(t, "2024-01-01", from)
+	assert.Equal(t, "2025-01-01", to)
+}
+
+// TestCommitKeyReference tests that foreign keys to commits are moved to
+// commit_key with their actions
+func TestCommitKeyReference(t *testing.T) {
+	// Act
+	quoted := commitKeyReference(`FOREIGN KEY (commit_id) REFERENCES "commit"(id) ON DELETE CASCADE`)
+	unquoted := commitKeyReference(`FOREIGN KEY (target_id) REFERENCES commit(id)`)
+	other := commitKeyReference(`FOREIGN KEY (commit_id) REFERENCES commit_file(id)`)
+
+	// Assert
+	assert.Equal(t, `FOREIGN KEY (commit_id) REFERENCES commit_key(id) ON DELETE CASCADE`, quoted)
+	assert.Equal(t, `FOREIGN KEY (target_id) REFERENCES commit_key(id)`, unquoted)
+	assert.Equal(t, `FOREIGN KEY (commit_id) REFERENCES commit_file(id)`, other)
+}
+
+// TestEnsureCommitPartitions_SQLite tests that partitions are not created on
+// databases that do not support them
+func TestEnsureCommitPartitions_SQLite(t *testing.T) {
+	// Arrange
+	db := newSQLiteDB(t)
+
+	// Act
+	created, err := EnsureCommitPartitions(context.TODO(), db, time.Now())
+	partitionErr := PartitionCommits(context.TODO(), db)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Zero(t, created)
+	assert.Error(t, partitionErr)
+}
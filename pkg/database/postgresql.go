@@ -6,7 +6,6 @@ import (
 	"log"
 	"time"
 
-	"github.com/just-nibble/git-service/internal/repository"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -62,18 +61,23 @@ func (p *PostgresDatabase) GetDB() *gorm.DB {
 	return p.db
 }
 
-// Migrate handles schema migrations for Postgres.
+// Migrate applies the pending SQL migrations for Postgres.
 func (p *PostgresDatabase) Migrate(ctx context.Context) error {
 	if p.db == nil {
 		return fmt.Errorf("database not connected")
 	}
 
-	// Assuming models like User, Product, etc.
-	if err := p.db.AutoMigrate(&repository.Author{}, &repository.Repository{}, &repository.Commit{}); err != nil {
+	migrator, err := NewMigrator(p.db)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
 		return fmt.Errorf("failed to migrate postgres: %w", err)
 	}
 
-	log.Println("Postgres migrations applied successfully")
+	log.Printf("Postgres migrations applied successfully (%d new)", applied)
 	return nil
 }
 
@@ -0,0 +1,220 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"time"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// SQLiteDatabase stores everything in a single file, or in memory when Path is
+// ":memory:". It needs no server, which suits single node deployments and tests.
+type SQLiteDatabase struct {
+	Path string
+	db   *gorm.DB
+}
+
+func NewSQLiteDatabase(path string) *SQLiteDatabase {
+	return &SQLiteDatabase{Path: path}
+}
+
+// utcDialector binds times in UTC. SQLite stores times as text, which only
+// compares in time order when every time is written with the same offset.
+type utcDialector struct {
+	*sqlite.Dialector
+}
+
+func (d utcDialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v interface{}) {
+	switch t := v.(type) {
+	case time.Time:
+		stmt.Vars[len(stmt.Vars)-1] = t.UTC()
+	case *time.Time:
+		if t != nil {
+			utc := t.UTC()
+			stmt.Vars[len(stmt.Vars)-1] = &utc
+		}
+	}
+	d.Dialector.BindVarTo(writer, stmt, v)
+}
+
+// timeDriverName is the sqlite driver that reads computed times as times.
+const timeDriverName = "sqlite-time"
+
+// timeFormat is the format the sqlite driver writes times in.
+const timeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+func init() {
+	sql.Register(timeDriverName, timeDriver{&gosqlite.Driver{}})
+}
+
+// timeDriver wraps the sqlite driver, which only returns times for columns
+// declared as times. Expressions such as MIN(date) or COALESCE(tag.date,
+// release.published_at) have no declared type and are read as text, which
+// cannot be scanned into a time.Time.
+type timeDriver struct {
+	driver.Driver
+}
+
+// sqliteConn is the part of a sqlite connection the sql package uses.
+type sqliteConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+}
+
+// sqliteRows are the rows of a sqlite query.
+type sqliteRows interface {
+	driver.Rows
+	driver.RowsColumnTypeDatabaseTypeName
+	driver.RowsColumnTypeLength
+	driver.RowsColumnTypeNullable
+	driver.RowsColumnTypePrecisionScale
+	driver.RowsColumnTypeScanType
+}
+
+func (d timeDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return timeConn{conn.(sqliteConn)}, nil
+}
+
+type timeConn struct {
+	sqliteConn
+}
+
+func (c timeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.sqliteConn.QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return timeRows{rows.(sqliteRows)}, nil
+}
+
+type timeRows struct {
+	sqliteRows
+}
+
+// Next reads the text of columns without a declared type as a time when it is
+// in the format times are written in.
+func (r timeRows) Next(dest []driver.Value) error {
+	if err := r.sqliteRows.Next(dest); err != nil {
+		return err
+	}
+
+	for i, v := range dest {
+		text, ok := v.(string)
+		if !ok || r.ColumnTypeDatabaseTypeName(i) != "" {
+			continue
+		}
+		if t, err := time.Parse(timeFormat, text); err == nil {
+			dest[i] = t
+		}
+	}
+	return nil
+}
+
+// ConnectDB opens the SQLite database. Writers are serialized on a single
+// connection, SQLite locks the whole database for writes anyway.
+func (s *SQLiteDatabase) ConnectDB(ctx context.Context) error {
+	dsn := s.Path + "?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)"
+	if s.Path != ":memory:" {
+		dsn += "&_pragma=journal_mode(WAL)"
+	}
+
+	dialector := &sqlite.Dialector{DriverName: timeDriverName, DSN: dsn}
+	db, err := gorm.Open(utcDialector{dialector}, &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Warn),
+		NowFunc: func() time.Time { return time.Now().UTC() },
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to sqlite: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+
+	// an in memory database lives as long as its connection
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetMaxIdleConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+
+	s.db = db
+
+	log.Println("SQLite database connected successfully")
+	return nil
+}
+
+// GetDB returns the underlying *gorm.DB instance.
+func (s *SQLiteDatabase) GetDB() *gorm.DB {
+	return s.db
+}
+
+// Migrate applies the pending SQL migrations.
+func (s *SQLiteDatabase) Migrate(ctx context.Context) error {
+	if s.db == nil {
+		return fmt.Errorf("database not connected")
+	}
+
+	migrator, err := NewMigrator(s.db)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to migrate sqlite: %w", err)
+	}
+
+	log.Printf("SQLite migrations applied successfully (%d new)", applied)
+	return nil
+}
+
+// PingDb checks if the SQLite database can be queried.
+func (s *SQLiteDatabase) PingDb(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("sqlite ping failed: %w", err)
+	}
+	return nil
+}
+
+// CloseDb closes the SQLite database.
+func (s *SQLiteDatabase) CloseDb(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close sqlite database: %w", err)
+	}
+
+	log.Println("SQLite database closed successfully")
+	return nil
+}
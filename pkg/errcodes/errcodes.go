@@ -2,12 +2,94 @@ package errcodes
 
 import "errors"
 
+// Code is a machine-readable error code returned to API clients.
+type Code string
+
+const (
+	CodeInvalidArgument  Code = "invalid_argument"
+	CodeUnauthenticated  Code = "unauthenticated"
+	CodePermissionDenied Code = "permission_denied"
+	CodeNotFound         Code = "not_found"
+	CodeAlreadyExists    Code = "already_exists"
+	CodeRateLimited      Code = "rate_limited"
+	CodeCancelled        Code = "cancelled"
+	CodeInternal         Code = "internal"
+)
+
+// Error is an error with a code. The sentinel errors below are *Error values,
+// so they can still be compared with == and errors.Is.
+type Error struct {
+	Code    Code
+	Message string
+	Details map[string]interface{}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// New creates an error with a code.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// WithDetails returns a copy of the error with extra details for the client.
+func (e *Error) WithDetails(details map[string]interface{}) *Error {
+	return &Error{Code: e.Code, Message: e.Message, Details: details}
+}
+
+// CodeOf returns the code of err, errors without one are internal errors.
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeInternal
+}
+
 var (
 	// General Errors
-	ErrNoRecordFound    = errors.New("no record found")
-	ErrContextCancelled = errors.New("operation cancelled by context")
+	ErrNoRecordFound    = New(CodeNotFound, "no record found")
+	ErrContextCancelled = New(CodeCancelled, "operation cancelled by context")
+	ErrInvalidSort      = New(CodeInvalidArgument, "invalid sort, expected one of: created_at, date, commit_hash")
+	ErrInvalidDirection = New(CodeInvalidArgument, "invalid direction, expected one of: asc, desc")
 
 	// Repository Errors
-	ErrRepoAlreadyAdded      = errors.New("repository has already been added")
-	ErrInvalidRepositoryName = errors.New("invalid repository name, expected format: {owner/repositoryName}")
+	ErrRepoAlreadyAdded      = New(CodeAlreadyExists, "repository has already been added")
+	ErrCommitAlreadyAdded    = New(CodeAlreadyExists, "commit has already been added")
+	ErrInvalidRepositoryName = New(CodeInvalidArgument, "invalid repository name, expected format: {owner/repositoryName}")
+	ErrUnknownRef            = New(CodeNotFound, "no commit, tag or branch with this name")
+	ErrInvalidRefRange       = New(CodeInvalidArgument, "invalid range, from must be older than to")
+	ErrChangelogTooLarge     = New(CodeInvalidArgument, "range has too many commits for a changelog, narrow it with from and to")
+	ErrInvalidPullState      = New(CodeInvalidArgument, "invalid state, expected one of: open, closed, merged, all")
+	ErrInvalidRetention      = New(CodeInvalidArgument, "invalid retention, days and commits must not be negative")
+	ErrInvalidArchive        = New(CodeInvalidArgument, "invalid archive")
+
+	// Stats Errors
+	ErrInvalidInterval  = New(CodeInvalidArgument, "invalid interval, expected one of: day, week, month")
+	ErrInvalidTimezone  = New(CodeInvalidArgument, "invalid timezone, expected an IANA name such as Europe/Berlin")
+	ErrInvalidDate      = New(CodeInvalidArgument, "invalid date, expected RFC 3339 or YYYY-MM-DD")
+	ErrInvalidTimeRange = New(CodeInvalidArgument, "invalid time range, since must be before until")
+	ErrTooManyIntervals = New(CodeInvalidArgument, "time range spans more than 1000 intervals")
+	ErrInvalidRanking   = New(CodeInvalidArgument, "invalid rank_by, expected one of: commits, lines")
+	ErrUnknownGroup     = New(CodeNotFound, "repository group is not configured")
+
+	// Delivery Errors
+	ErrInvalidDeploymentSource = New(CodeInvalidArgument, "invalid source, expected one of: tags, releases, branch")
+
+	// GraphQL Errors
+	ErrInvalidCursor   = New(CodeInvalidArgument, "invalid cursor, expected the cursor of an edge")
+	ErrInvalidFirst    = New(CodeInvalidArgument, "invalid first, expected a positive number")
+	ErrQueryTooDeep    = New(CodeInvalidArgument, "query is nested too deeply")
+	ErrQueryTooComplex = New(CodeInvalidArgument, "query is too complex, ask for fewer items or fields")
+	ErrQueryFansOut    = New(CodeInvalidArgument, "query reads too many nested lists, ask for fewer repositories")
+
+	// gRPC Errors
+	ErrInvalidPaging = New(CodeInvalidArgument, "invalid page or limit, expected a positive number")
+
+	// Auth Errors
+	ErrUnauthorized = New(CodeUnauthenticated, "missing or invalid API key")
+	ErrForbidden    = New(CodePermissionDenied, "API key does not have the required role")
+	ErrInvalidRole  = New(CodeInvalidArgument, "invalid role, expected one of: reader, editor, admin")
+	ErrRateLimited  = New(CodeRateLimited, "rate limit exceeded")
 )
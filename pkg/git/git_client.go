@@ -10,4 +10,17 @@ import (
 type GitClient interface {
 	FetchRepoMetadata(ctx context.Context, repositoryName string) (*domain.RepositoryMeta, error)
 	FetchCommits(ctx context.Context, repo domain.RepositoryMeta, since time.Time, until time.Time, lastFetchedCommit string, page, perPage int) ([]domain.Commit, bool, error)
+	// ResolveRef returns the commit a SHA, tag or branch points to
+	ResolveRef(ctx context.Context, repositoryName string, ref string) (*domain.Commit, error)
+	// FetchCommitFiles returns a commit with only its hash, line counts and files set
+	FetchCommitFiles(ctx context.Context, repositoryName string, hash string) (*domain.Commit, error)
+	// FetchTags returns a page of tags, their dates are not set
+	FetchTags(ctx context.Context, repositoryName string, page, perPage int) ([]domain.Tag, bool, error)
+	// FetchReleases returns a page of published releases
+	FetchReleases(ctx context.Context, repositoryName string, page, perPage int) ([]domain.Release, bool, error)
+	// FetchPullRequests returns a page of pull requests in every state, most
+	// recently updated first, their commits are not set
+	FetchPullRequests(ctx context.Context, repositoryName string, page, perPage int) ([]domain.PullRequest, bool, error)
+	// FetchPullRequestCommits returns a page of the commit hashes of a pull request
+	FetchPullRequestCommits(ctx context.Context, repositoryName string, number, page, perPage int) ([]string, bool, error)
 }
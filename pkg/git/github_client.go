@@ -13,6 +13,7 @@ import (
 
 	"github.com/just-nibble/git-service/internal/domain"
 	"github.com/just-nibble/git-service/pkg/api"
+	"github.com/just-nibble/git-service/pkg/errcodes"
 	"github.com/just-nibble/git-service/pkg/log"
 )
 
@@ -37,6 +38,16 @@ type GitHubCommitResponse struct {
 	Commit  Commit `json:"commit"`
 	Author  Author `json:"author"`
 	HtmlURL string `json:"html_url"`
+	// Stats and Files are only returned for single commits
+	Stats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+	Files []struct {
+		Filename  string `json:"filename"`
+		Additions int    `json:"additions"`
+		Deletions int    `json:"deletions"`
+	} `json:"files"`
 }
 
 type Commit struct {
@@ -51,6 +62,42 @@ type Author struct {
 	Date  time.Time `json:"date"`
 }
 
+type GitHubTagResponse struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+type GitHubReleaseResponse struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+type GitHubPullRequestResponse struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	User   struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	Head struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	MergeCommitSHA string     `json:"merge_commit_sha"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	MergedAt       *time.Time `json:"merged_at"`
+	ClosedAt       *time.Time `json:"closed_at"`
+}
+
 type GitHubMetaResponse struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
@@ -114,10 +161,13 @@ func (g *GitHubClient) FetchRepoMetadata(ctx context.Context, repositoryName str
 		return nil, errors.New("failed to parse repository metadata response")
 	}
 
+	owner, _, _ := strings.Cut(gitHubRepo.FullName, "/")
+
 	return &domain.RepositoryMeta{
+		OwnerName:       owner,
 		Name:            gitHubRepo.FullName,
 		Description:     gitHubRepo.Description,
-		URL:             gitHubRepo.URL,
+		URL:             gitHubRepo.HtmlURL,
 		Language:        gitHubRepo.Language,
 		ForksCount:      gitHubRepo.ForksCount,
 		StarsCount:      gitHubRepo.StargazersCount,
@@ -169,6 +219,198 @@ func (g *GitHubClient) FetchCommits(ctx context.Context, repo domain.RepositoryM
 	return commits, morePages, nil
 }
 
+// ResolveRef fetches the commit a SHA, tag or branch points to.
+func (g *GitHubClient) ResolveRef(ctx context.Context, repositoryName string, ref string) (*domain.Commit, error) {
+	endpoint := fmt.Sprintf("https://%s/repos/%s/commits/%s", g.baseURL, repositoryName, url.PathEscape(ref))
+
+	resp, err := g.client.GetWithContext(ctx, endpoint, nil, g.getHeaders())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve ref: %w", err)
+	}
+
+	g.updateRateLimit(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusUnprocessableEntity:
+		return nil, errcodes.ErrUnknownRef
+	default:
+		return nil, fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+	}
+
+	var commitRes GitHubCommitResponse
+	if err := json.Unmarshal([]byte(resp.Body), &commitRes); err != nil {
+		return nil, errors.New("failed to parse commit response")
+	}
+
+	commits := g.parseCommits([]GitHubCommitResponse{commitRes}, repositoryName)
+	return &commits[0], nil
+}
+
+// FetchCommitFiles fetches the line counts and changed files of a commit from
+// GitHub, which lists at most 300 files per commit.
+func (g *GitHubClient) FetchCommitFiles(ctx context.Context, repositoryName string, hash string) (*domain.Commit, error) {
+	endpoint := fmt.Sprintf("https://%s/repos/%s/commits/%s", g.baseURL, repositoryName, url.PathEscape(hash))
+
+	resp, err := g.client.Get(endpoint, nil, g.getHeaders())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commit files: %w", err)
+	}
+
+	g.updateRateLimit(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusUnprocessableEntity:
+		return nil, errcodes.ErrUnknownRef
+	default:
+		return nil, fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+	}
+
+	var commitRes GitHubCommitResponse
+	if err := json.Unmarshal([]byte(resp.Body), &commitRes); err != nil {
+		return nil, errors.New("failed to parse commit response")
+	}
+
+	commit := &domain.Commit{
+		Hash:      commitRes.SHA,
+		Additions: commitRes.Stats.Additions,
+		Deletions: commitRes.Stats.Deletions,
+		Files:     make([]domain.CommitFile, 0, len(commitRes.Files)),
+	}
+	for _, f := range commitRes.Files {
+		commit.Files = append(commit.Files, domain.CommitFile{Path: f.Filename, Additions: f.Additions, Deletions: f.Deletions})
+	}
+	return commit, nil
+}
+
+// FetchTags fetches a page of the tags of a repository from GitHub.
+func (g *GitHubClient) FetchTags(ctx context.Context, repositoryName string, page, perPage int) ([]domain.Tag, bool, error) {
+	var tagRes []GitHubTagResponse
+	hasMore, err := g.fetchPage(fmt.Sprintf("https://%s/repos/%s/tags", g.baseURL, repositoryName), page, perPage, &tagRes)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch tags: %w", err)
+	}
+
+	tags := make([]domain.Tag, 0, len(tagRes))
+	for _, t := range tagRes {
+		tags = append(tags, domain.Tag{Name: t.Name, Hash: t.Commit.SHA})
+	}
+	return tags, hasMore, nil
+}
+
+// FetchReleases fetches a page of the published releases of a repository from
+// GitHub, drafts are left out.
+func (g *GitHubClient) FetchReleases(ctx context.Context, repositoryName string, page, perPage int) ([]domain.Release, bool, error) {
+	var releaseRes []GitHubReleaseResponse
+	hasMore, err := g.fetchPage(fmt.Sprintf("https://%s/repos/%s/releases", g.baseURL, repositoryName), page, perPage, &releaseRes)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+
+	releases := make([]domain.Release, 0, len(releaseRes))
+	for _, r := range releaseRes {
+		if r.Draft {
+			continue
+		}
+		releases = append(releases, domain.Release{
+			TagName:     r.TagName,
+			Name:        r.Name,
+			Body:        r.Body,
+			Prerelease:  r.Prerelease,
+			PublishedAt: r.PublishedAt,
+		})
+	}
+	return releases, hasMore, nil
+}
+
+// FetchPullRequests fetches a page of the pull requests of a repository from
+// GitHub, most recently updated first. The merge commit of unmerged pull
+// requests is a test merge and left out.
+func (g *GitHubClient) FetchPullRequests(ctx context.Context, repositoryName string, page, perPage int) ([]domain.PullRequest, bool, error) {
+	var prRes []GitHubPullRequestResponse
+	endpoint := fmt.Sprintf("https://%s/repos/%s/pulls?state=all&sort=updated&direction=desc", g.baseURL, repositoryName)
+	hasMore, err := g.fetchPage(endpoint, page, perPage, &prRes)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch pull requests: %w", err)
+	}
+
+	prs := make([]domain.PullRequest, 0, len(prRes))
+	for _, p := range prRes {
+		pr := domain.PullRequest{
+			Number:     p.Number,
+			Title:      p.Title,
+			Author:     p.User.Login,
+			State:      domain.PullRequestOpen,
+			BaseBranch: p.Base.Ref,
+			HeadBranch: p.Head.Ref,
+			CreatedAt:  p.CreatedAt,
+			UpdatedAt:  p.UpdatedAt,
+			MergedAt:   p.MergedAt,
+			ClosedAt:   p.ClosedAt,
+		}
+		switch {
+		case p.MergedAt != nil:
+			pr.State = domain.PullRequestMerged
+			pr.MergeCommitHash = p.MergeCommitSHA
+		case p.State == "closed":
+			pr.State = domain.PullRequestClosed
+		}
+		prs = append(prs, pr)
+	}
+	return prs, hasMore, nil
+}
+
+// FetchPullRequestCommits fetches a page of the commits of a pull request from
+// GitHub, which lists at most 250 commits per pull request.
+func (g *GitHubClient) FetchPullRequestCommits(ctx context.Context, repositoryName string, number, page, perPage int) ([]string, bool, error) {
+	var commitRes []GitHubCommitResponse
+	hasMore, err := g.fetchPage(fmt.Sprintf("https://%s/repos/%s/pulls/%d/commits", g.baseURL, repositoryName, number), page, perPage, &commitRes)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch pull request commits: %w", err)
+	}
+
+	hashes := make([]string, 0, len(commitRes))
+	for _, c := range commitRes {
+		hashes = append(hashes, c.SHA)
+	}
+	return hashes, hasMore, nil
+}
+
+// fetchPage decodes a page of a GitHub list endpoint into v and reports whether
+// there are more pages.
+func (g *GitHubClient) fetchPage(endpoint string, page, perPage int, v interface{}) (bool, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("per_page", strconv.Itoa(perPage))
+	q.Set("page", strconv.Itoa(page))
+	u.RawQuery = q.Encode()
+
+	resp, err := g.client.Get(u.String(), nil, g.getHeaders())
+	if err != nil {
+		return false, err
+	}
+
+	g.updateRateLimit(resp)
+
+	if resp.StatusCode == http.StatusForbidden {
+		return false, fmt.Errorf("rate limit exceeded")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal([]byte(resp.Body), v); err != nil {
+		return false, errors.New("failed to parse response")
+	}
+
+	return g.hasNextPage(resp.Headers["Link"]), nil
+}
+
 func (g *GitHubClient) buildCommitEndpoint(repoName string, since, until time.Time, lastFetchedCommit string, page, perPage int) (string, error) {
 	u, err := url.Parse(fmt.Sprintf("https://%s/repos/%s/commits", g.baseURL, repoName))
 	if err != nil {
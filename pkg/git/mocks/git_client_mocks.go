@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/just-nibble/git-service/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// GitClient mock
+type GitClient struct {
+	mock.Mock
+}
+
+func (m *GitClient) FetchRepoMetadata(ctx context.Context, repositoryName string) (*domain.RepositoryMeta, error) {
+	args := m.Called(ctx, repositoryName)
+	return args.Get(0).(*domain.RepositoryMeta), args.Error(1)
+}
+
+func (m *GitClient) FetchCommits(ctx context.Context, repo domain.RepositoryMeta, since time.Time, until time.Time, lastFetchedCommit string, page, perPage int) ([]domain.Commit, bool, error) {
+	args := m.Called(ctx, repo, since, until, lastFetchedCommit, page, perPage)
+	return args.Get(0).([]domain.Commit), args.Bool(1), args.Error(2)
+}
+
+func (m *GitClient) ResolveRef(ctx context.Context, repositoryName string, ref string) (*domain.Commit, error) {
+	args := m.Called(ctx, repositoryName, ref)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Commit), args.Error(1)
+}
+
+func (m *GitClient) FetchCommitFiles(ctx context.Context, repositoryName string, hash string) (*domain.Commit, error) {
+	args := m.Called(ctx, repositoryName, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Commit), args.Error(1)
+}
+
+func (m *GitClient) FetchTags(ctx context.Context, repositoryName string, page, perPage int) ([]domain.Tag, bool, error) {
+	args := m.Called(ctx, repositoryName, page, perPage)
+	return args.Get(0).([]domain.Tag), args.Bool(1), args.Error(2)
+}
+
+func (m *GitClient) FetchReleases(ctx context.Context, repositoryName string, page, perPage int) ([]domain.Release, bool, error) {
+	args := m.Called(ctx, repositoryName, page, perPage)
+	return args.Get(0).([]domain.Release), args.Bool(1), args.Error(2)
+}
+
+func (m *GitClient) FetchPullRequests(ctx context.Context, repositoryName string, page, perPage int) ([]domain.PullRequest, bool, error) {
+	args := m.Called(ctx, repositoryName, page, perPage)
+	return args.Get(0).([]domain.PullRequest), args.Bool(1), args.Error(2)
+}
+
+func (m *GitClient) FetchPullRequestCommits(ctx context.Context, repositoryName string, number, page, perPage int) ([]string, bool, error) {
+	args := m.Called(ctx, repositoryName, number, page, perPage)
+	return args.Get(0).([]string), args.Bool(1), args.Error(2)
+}
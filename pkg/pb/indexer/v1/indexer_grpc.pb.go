@@ -0,0 +1,712 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: indexer/v1/indexer.proto
+
+package indexerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RepositoryService_AddRepository_FullMethodName     = "/indexer.v1.RepositoryService/AddRepository"
+	RepositoryService_GetRepository_FullMethodName     = "/indexer.v1.RepositoryService/GetRepository"
+	RepositoryService_ListRepositories_FullMethodName  = "/indexer.v1.RepositoryService/ListRepositories"
+	RepositoryService_RemoveRepository_FullMethodName  = "/indexer.v1.RepositoryService/RemoveRepository"
+	RepositoryService_ReindexRepository_FullMethodName = "/indexer.v1.RepositoryService/ReindexRepository"
+)
+
+// RepositoryServiceClient is the client API for RepositoryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RepositoryService adds, reads and removes the tracked repositories.
+type RepositoryServiceClient interface {
+	// AddRepository starts indexing a GitHub repository, requires the editor role
+	AddRepository(ctx context.Context, in *AddRepositoryRequest, opts ...grpc.CallOption) (*Repository, error)
+	GetRepository(ctx context.Context, in *GetRepositoryRequest, opts ...grpc.CallOption) (*Repository, error)
+	ListRepositories(ctx context.Context, in *ListRepositoriesRequest, opts ...grpc.CallOption) (*ListRepositoriesResponse, error)
+	// RemoveRepository stops indexing a repository and deletes its commits,
+	// requires the editor role
+	RemoveRepository(ctx context.Context, in *RemoveRepositoryRequest, opts ...grpc.CallOption) (*RemoveRepositoryResponse, error)
+	// ReindexRepository deletes the commits of a repository and indexes it again,
+	// requires the editor role
+	ReindexRepository(ctx context.Context, in *ReindexRepositoryRequest, opts ...grpc.CallOption) (*Repository, error)
+}
+
+type repositoryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRepositoryServiceClient(cc grpc.ClientConnInterface) RepositoryServiceClient {
+	return &repositoryServiceClient{cc}
+}
+
+func (c *repositoryServiceClient) AddRepository(ctx context.Context, in *AddRepositoryRequest, opts ...grpc.CallOption) (*Repository, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Repository)
+	err := c.cc.Invoke(ctx, RepositoryService_AddRepository_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repositoryServiceClient) GetRepository(ctx context.Context, in *GetRepositoryRequest, opts ...grpc.CallOption) (*Repository, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Repository)
+	err := c.cc.Invoke(ctx, RepositoryService_GetRepository_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repositoryServiceClient) ListRepositories(ctx context.Context, in *ListRepositoriesRequest, opts ...grpc.CallOption) (*ListRepositoriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRepositoriesResponse)
+	err := c.cc.Invoke(ctx, RepositoryService_ListRepositories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repositoryServiceClient) RemoveRepository(ctx context.Context, in *RemoveRepositoryRequest, opts ...grpc.CallOption) (*RemoveRepositoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveRepositoryResponse)
+	err := c.cc.Invoke(ctx, RepositoryService_RemoveRepository_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repositoryServiceClient) ReindexRepository(ctx context.Context, in *ReindexRepositoryRequest, opts ...grpc.CallOption) (*Repository, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Repository)
+	err := c.cc.Invoke(ctx, RepositoryService_ReindexRepository_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RepositoryServiceServer is the server API for RepositoryService service.
+// All implementations must embed UnimplementedRepositoryServiceServer
+// for forward compatibility.
+//
+// RepositoryService adds, reads and removes the tracked repositories.
+type RepositoryServiceServer interface {
+	// AddRepository starts indexing a GitHub repository, requires the editor role
+	AddRepository(context.Context, *AddRepositoryRequest) (*Repository, error)
+	GetRepository(context.Context, *GetRepositoryRequest) (*Repository, error)
+	ListRepositories(context.Context, *ListRepositoriesRequest) (*ListRepositoriesResponse, error)
+	// RemoveRepository stops indexing a repository and deletes its commits,
+	// requires the editor role
+	RemoveRepository(context.Context, *RemoveRepositoryRequest) (*RemoveRepositoryResponse, error)
+	// ReindexRepository deletes the commits of a repository and indexes it again,
+	// requires the editor role
+	ReindexRepository(context.Context, *ReindexRepositoryRequest) (*Repository, error)
+	mustEmbedUnimplementedRepositoryServiceServer()
+}
+
+// UnimplementedRepositoryServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRepositoryServiceServer struct{}
+
+func (UnimplementedRepositoryServiceServer) AddRepository(context.Context, *AddRepositoryRequest) (*Repository, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRepository not implemented")
+}
+func (UnimplementedRepositoryServiceServer) GetRepository(context.Context, *GetRepositoryRequest) (*Repository, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRepository not implemented")
+}
+func (UnimplementedRepositoryServiceServer) ListRepositories(context.Context, *ListRepositoriesRequest) (*ListRepositoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRepositories not implemented")
+}
+func (UnimplementedRepositoryServiceServer) RemoveRepository(context.Context, *RemoveRepositoryRequest) (*RemoveRepositoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRepository not implemented")
+}
+func (UnimplementedRepositoryServiceServer) ReindexRepository(context.Context, *ReindexRepositoryRequest) (*Repository, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReindexRepository not implemented")
+}
+func (UnimplementedRepositoryServiceServer) mustEmbedUnimplementedRepositoryServiceServer() {}
+func (UnimplementedRepositoryServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeRepositoryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RepositoryServiceServer will
+// result in compilation errors.
+type UnsafeRepositoryServiceServer interface {
+	mustEmbedUnimplementedRepositoryServiceServer()
+}
+
+func RegisterRepositoryServiceServer(s grpc.ServiceRegistrar, srv RepositoryServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRepositoryServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RepositoryService_ServiceDesc, srv)
+}
+
+func _RepositoryService_AddRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRepositoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).AddRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RepositoryService_AddRepository_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).AddRepository(ctx, req.(*AddRepositoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepositoryService_GetRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRepositoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).GetRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RepositoryService_GetRepository_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).GetRepository(ctx, req.(*GetRepositoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepositoryService_ListRepositories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRepositoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).ListRepositories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RepositoryService_ListRepositories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).ListRepositories(ctx, req.(*ListRepositoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepositoryService_RemoveRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRepositoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).RemoveRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RepositoryService_RemoveRepository_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).RemoveRepository(ctx, req.(*RemoveRepositoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepositoryService_ReindexRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReindexRepositoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).ReindexRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RepositoryService_ReindexRepository_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).ReindexRepository(ctx, req.(*ReindexRepositoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RepositoryService_ServiceDesc is the grpc.ServiceDesc for RepositoryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RepositoryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "indexer.v1.RepositoryService",
+	HandlerType: (*RepositoryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddRepository",
+			Handler:    _RepositoryService_AddRepository_Handler,
+		},
+		{
+			MethodName: "GetRepository",
+			Handler:    _RepositoryService_GetRepository_Handler,
+		},
+		{
+			MethodName: "ListRepositories",
+			Handler:    _RepositoryService_ListRepositories_Handler,
+		},
+		{
+			MethodName: "RemoveRepository",
+			Handler:    _RepositoryService_RemoveRepository_Handler,
+		},
+		{
+			MethodName: "ReindexRepository",
+			Handler:    _RepositoryService_ReindexRepository_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "indexer/v1/indexer.proto",
+}
+
+const (
+	CommitService_ListCommits_FullMethodName      = "/indexer.v1.CommitService/ListCommits"
+	CommitService_StreamCommits_FullMethodName    = "/indexer.v1.CommitService/StreamCommits"
+	CommitService_SubscribeCommits_FullMethodName = "/indexer.v1.CommitService/SubscribeCommits"
+)
+
+// CommitServiceClient is the client API for CommitService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CommitService reads the indexed commits of a repository.
+type CommitServiceClient interface {
+	// ListCommits returns a page of commits
+	ListCommits(ctx context.Context, in *ListCommitsRequest, opts ...grpc.CallOption) (*ListCommitsResponse, error)
+	// StreamCommits sends every matching commit, without paging
+	StreamCommits(ctx context.Context, in *StreamCommitsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Commit], error)
+	// SubscribeCommits sends the commits indexed after the call, in the order
+	// they are indexed, until the client cancels it or the server stops
+	SubscribeCommits(ctx context.Context, in *SubscribeCommitsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Commit], error)
+}
+
+type commitServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCommitServiceClient(cc grpc.ClientConnInterface) CommitServiceClient {
+	return &commitServiceClient{cc}
+}
+
+func (c *commitServiceClient) ListCommits(ctx context.Context, in *ListCommitsRequest, opts ...grpc.CallOption) (*ListCommitsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCommitsResponse)
+	err := c.cc.Invoke(ctx, CommitService_ListCommits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commitServiceClient) StreamCommits(ctx context.Context, in *StreamCommitsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Commit], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CommitService_ServiceDesc.Streams[0], CommitService_StreamCommits_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamCommitsRequest, Commit]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CommitService_StreamCommitsClient = grpc.ServerStreamingClient[Commit]
+
+func (c *commitServiceClient) SubscribeCommits(ctx context.Context, in *SubscribeCommitsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Commit], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CommitService_ServiceDesc.Streams[1], CommitService_SubscribeCommits_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeCommitsRequest, Commit]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CommitService_SubscribeCommitsClient = grpc.ServerStreamingClient[Commit]
+
+// CommitServiceServer is the server API for CommitService service.
+// All implementations must embed UnimplementedCommitServiceServer
+// for forward compatibility.
+//
+// CommitService reads the indexed commits of a repository.
+type CommitServiceServer interface {
+	// ListCommits returns a page of commits
+	ListCommits(context.Context, *ListCommitsRequest) (*ListCommitsResponse, error)
+	// StreamCommits sends every matching commit, without paging
+	StreamCommits(*StreamCommitsRequest, grpc.ServerStreamingServer[Commit]) error
+	// SubscribeCommits sends the commits indexed after the call, in the order
+	// they are indexed, until the client cancels it or the server stops
+	SubscribeCommits(*SubscribeCommitsRequest, grpc.ServerStreamingServer[Commit]) error
+	mustEmbedUnimplementedCommitServiceServer()
+}
+
+// UnimplementedCommitServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCommitServiceServer struct{}
+
+func (UnimplementedCommitServiceServer) ListCommits(context.Context, *ListCommitsRequest) (*ListCommitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCommits not implemented")
+}
+func (UnimplementedCommitServiceServer) StreamCommits(*StreamCommitsRequest, grpc.ServerStreamingServer[Commit]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCommits not implemented")
+}
+func (UnimplementedCommitServiceServer) SubscribeCommits(*SubscribeCommitsRequest, grpc.ServerStreamingServer[Commit]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeCommits not implemented")
+}
+func (UnimplementedCommitServiceServer) mustEmbedUnimplementedCommitServiceServer() {}
+func (UnimplementedCommitServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeCommitServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CommitServiceServer will
+// result in compilation errors.
+type UnsafeCommitServiceServer interface {
+	mustEmbedUnimplementedCommitServiceServer()
+}
+
+func RegisterCommitServiceServer(s grpc.ServiceRegistrar, srv CommitServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCommitServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CommitService_ServiceDesc, srv)
+}
+
+func _CommitService_ListCommits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommitServiceServer).ListCommits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommitService_ListCommits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommitServiceServer).ListCommits(ctx, req.(*ListCommitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommitService_StreamCommits_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamCommitsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CommitServiceServer).StreamCommits(m, &grpc.GenericServerStream[StreamCommitsRequest, Commit]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CommitService_StreamCommitsServer = grpc.ServerStreamingServer[Commit]
+
+func _CommitService_SubscribeCommits_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeCommitsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CommitServiceServer).SubscribeCommits(m, &grpc.GenericServerStream[SubscribeCommitsRequest, Commit]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CommitService_SubscribeCommitsServer = grpc.ServerStreamingServer[Commit]
+
+// CommitService_ServiceDesc is the grpc.ServiceDesc for CommitService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CommitService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "indexer.v1.CommitService",
+	HandlerType: (*CommitServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListCommits",
+			Handler:    _CommitService_ListCommits_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamCommits",
+			Handler:       _CommitService_StreamCommits_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeCommits",
+			Handler:       _CommitService_SubscribeCommits_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "indexer/v1/indexer.proto",
+}
+
+const (
+	AuthorService_GetTopAuthors_FullMethodName   = "/indexer.v1.AuthorService/GetTopAuthors"
+	AuthorService_GetLeaderboard_FullMethodName  = "/indexer.v1.AuthorService/GetLeaderboard"
+	AuthorService_GetAuthor_FullMethodName       = "/indexer.v1.AuthorService/GetAuthor"
+	AuthorService_BatchGetAuthors_FullMethodName = "/indexer.v1.AuthorService/BatchGetAuthors"
+)
+
+// AuthorServiceClient is the client API for AuthorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AuthorService ranks and describes the authors of the indexed commits.
+type AuthorServiceClient interface {
+	// GetTopAuthors ranks the authors of a repository
+	GetTopAuthors(ctx context.Context, in *GetTopAuthorsRequest, opts ...grpc.CallOption) (*GetTopAuthorsResponse, error)
+	// GetLeaderboard ranks the authors across every repository or a configured
+	// group of repositories
+	GetLeaderboard(ctx context.Context, in *GetLeaderboardRequest, opts ...grpc.CallOption) (*GetLeaderboardResponse, error)
+	GetAuthor(ctx context.Context, in *GetAuthorRequest, opts ...grpc.CallOption) (*AuthorProfile, error)
+	// BatchGetAuthors returns the profiles of the known authors among ids
+	BatchGetAuthors(ctx context.Context, in *BatchGetAuthorsRequest, opts ...grpc.CallOption) (*BatchGetAuthorsResponse, error)
+}
+
+type authorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuthorServiceClient(cc grpc.ClientConnInterface) AuthorServiceClient {
+	return &authorServiceClient{cc}
+}
+
+func (c *authorServiceClient) GetTopAuthors(ctx context.Context, in *GetTopAuthorsRequest, opts ...grpc.CallOption) (*GetTopAuthorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTopAuthorsResponse)
+	err := c.cc.Invoke(ctx, AuthorService_GetTopAuthors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorServiceClient) GetLeaderboard(ctx context.Context, in *GetLeaderboardRequest, opts ...grpc.CallOption) (*GetLeaderboardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLeaderboardResponse)
+	err := c.cc.Invoke(ctx, AuthorService_GetLeaderboard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorServiceClient) GetAuthor(ctx context.Context, in *GetAuthorRequest, opts ...grpc.CallOption) (*AuthorProfile, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorProfile)
+	err := c.cc.Invoke(ctx, AuthorService_GetAuthor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorServiceClient) BatchGetAuthors(ctx context.Context, in *BatchGetAuthorsRequest, opts ...grpc.CallOption) (*BatchGetAuthorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetAuthorsResponse)
+	err := c.cc.Invoke(ctx, AuthorService_BatchGetAuthors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthorServiceServer is the server API for AuthorService service.
+// All implementations must embed UnimplementedAuthorServiceServer
+// for forward compatibility.
+//
+// AuthorService ranks and describes the authors of the indexed commits.
+type AuthorServiceServer interface {
+	// GetTopAuthors ranks the authors of a repository
+	GetTopAuthors(context.Context, *GetTopAuthorsRequest) (*GetTopAuthorsResponse, error)
+	// GetLeaderboard ranks the authors across every repository or a configured
+	// group of repositories
+	GetLeaderboard(context.Context, *GetLeaderboardRequest) (*GetLeaderboardResponse, error)
+	GetAuthor(context.Context, *GetAuthorRequest) (*AuthorProfile, error)
+	// BatchGetAuthors returns the profiles of the known authors among ids
+	BatchGetAuthors(context.Context, *BatchGetAuthorsRequest) (*BatchGetAuthorsResponse, error)
+	mustEmbedUnimplementedAuthorServiceServer()
+}
+
+// UnimplementedAuthorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAuthorServiceServer struct{}
+
+func (UnimplementedAuthorServiceServer) GetTopAuthors(context.Context, *GetTopAuthorsRequest) (*GetTopAuthorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopAuthors not implemented")
+}
+func (UnimplementedAuthorServiceServer) GetLeaderboard(context.Context, *GetLeaderboardRequest) (*GetLeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeaderboard not implemented")
+}
+func (UnimplementedAuthorServiceServer) GetAuthor(context.Context, *GetAuthorRequest) (*AuthorProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuthor not implemented")
+}
+func (UnimplementedAuthorServiceServer) BatchGetAuthors(context.Context, *BatchGetAuthorsRequest) (*BatchGetAuthorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetAuthors not implemented")
+}
+func (UnimplementedAuthorServiceServer) mustEmbedUnimplementedAuthorServiceServer() {}
+func (UnimplementedAuthorServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeAuthorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuthorServiceServer will
+// result in compilation errors.
+type UnsafeAuthorServiceServer interface {
+	mustEmbedUnimplementedAuthorServiceServer()
+}
+
+func RegisterAuthorServiceServer(s grpc.ServiceRegistrar, srv AuthorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAuthorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AuthorService_ServiceDesc, srv)
+}
+
+func _AuthorService_GetTopAuthors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopAuthorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).GetTopAuthors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_GetTopAuthors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).GetTopAuthors(ctx, req.(*GetTopAuthorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorService_GetLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).GetLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_GetLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).GetLeaderboard(ctx, req.(*GetLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorService_GetAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).GetAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_GetAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).GetAuthor(ctx, req.(*GetAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorService_BatchGetAuthors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetAuthorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).BatchGetAuthors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_BatchGetAuthors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).BatchGetAuthors(ctx, req.(*BatchGetAuthorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AuthorService_ServiceDesc is the grpc.ServiceDesc for AuthorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuthorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "indexer.v1.AuthorService",
+	HandlerType: (*AuthorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTopAuthors",
+			Handler:    _AuthorService_GetTopAuthors_Handler,
+		},
+		{
+			MethodName: "GetLeaderboard",
+			Handler:    _AuthorService_GetLeaderboard_Handler,
+		},
+		{
+			MethodName: "GetAuthor",
+			Handler:    _AuthorService_GetAuthor_Handler,
+		},
+		{
+			MethodName: "BatchGetAuthors",
+			Handler:    _AuthorService_BatchGetAuthors_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "indexer/v1/indexer.proto",
+}
@@ -0,0 +1,156 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalidLimit = errors.New("invalid rate limit, expected format: {requests}/{s|m|h}[:{burst}]")
+
+// Limit describes a token bucket: Rate tokens are added per second up to Burst.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// ParseLimit parses limits such as "10/s", "600/m" or "600/m:50". Without an
+// explicit burst the bucket holds one period worth of requests.
+func ParseLimit(s string) (Limit, error) {
+	spec, burstStr, hasBurst := strings.Cut(strings.TrimSpace(s), ":")
+
+	countStr, unit, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Limit{}, ErrInvalidLimit
+	}
+
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count <= 0 {
+		return Limit{}, ErrInvalidLimit
+	}
+
+	var period time.Duration
+	switch unit {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		return Limit{}, ErrInvalidLimit
+	}
+
+	burst := count
+	if hasBurst {
+		burst, err = strconv.Atoi(burstStr)
+		if err != nil || burst <= 0 {
+			return Limit{}, ErrInvalidLimit
+		}
+	}
+
+	return Limit{Rate: float64(count) / period.Seconds(), Burst: burst}, nil
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%g/s:%d", l.Rate, l.Burst)
+}
+
+// Result is the outcome of a single Allow call.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long to wait until a request would be allowed, zero if Allowed
+	RetryAfter time.Duration
+	// Reset is when the bucket is full again
+	Reset time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter keeps a token bucket per key, e.g. per API key or client IP.
+type Limiter struct {
+	limit     Limit
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// sweepInterval is how often buckets that refilled completely are dropped.
+const sweepInterval = time.Minute
+
+func New(limit Limit) *Limiter {
+	return &Limiter{
+		limit:   limit,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of key if one is available.
+func (l *Limiter) Allow(key string) Result {
+	return l.take(key, 1)
+}
+
+// Peek reports whether the bucket of key has a token left, without taking it.
+func (l *Limiter) Peek(key string) Result {
+	return l.take(key, 0)
+}
+
+// take takes n tokens from the bucket of key if it holds at least one.
+func (l *Limiter) take(key string, n float64) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(l.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*l.limit.Rate)
+	b.last = now
+
+	result := Result{Limit: l.limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens -= n
+		result.Allowed = true
+	} else {
+		result.RetryAfter = l.durationFor(1 - b.tokens)
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = now.Add(l.durationFor(float64(l.limit.Burst) - b.tokens))
+	return result
+}
+
+func (l *Limiter) durationFor(tokens float64) time.Duration {
+	return time.Duration(tokens / l.limit.Rate * float64(time.Second))
+}
+
+// sweep drops buckets that have been idle long enough to be full again, they
+// behave exactly like a new bucket.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.limit.Rate >= float64(l.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
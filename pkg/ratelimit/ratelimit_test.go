@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("120/m:10")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Rate: 2, Burst: 10}, limit)
+
+	limit, err = ParseLimit("5/s")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Rate: 5, Burst: 5}, limit)
+
+	for _, invalid := range []string{"", "10", "10/d", "0/s", "10/s:0", "x/s"} {
+		_, err := ParseLimit(invalid)
+		assert.Equal(t, ErrInvalidLimit, err, invalid)
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := New(Limit{Rate: 1, Burst: 2})
+	limiter.now = func() time.Time { return now }
+
+	// The burst is available immediately
+	assert.True(t, limiter.Allow("a").Allowed)
+	res := limiter.Allow("a")
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	// The bucket is empty, the next token arrives in a second
+	res = limiter.Allow("a")
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+	assert.Equal(t, now.Add(2*time.Second), res.Reset)
+
+	// Other keys have their own bucket
+	assert.True(t, limiter.Allow("b").Allowed)
+
+	now = now.Add(time.Second)
+	assert.True(t, limiter.Allow("a").Allowed)
+	assert.False(t, limiter.Allow("a").Allowed)
+}
+
+func TestLimiter_Peek(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := New(Limit{Rate: 1, Burst: 1})
+	limiter.now = func() time.Time { return now }
+
+	// Peeking does not take the token
+	assert.True(t, limiter.Peek("a").Allowed)
+	assert.True(t, limiter.Peek("a").Allowed)
+	assert.True(t, limiter.Allow("a").Allowed)
+
+	res := limiter.Peek("a")
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+}
+
+func TestLimiter_SweepsIdleBuckets(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := New(Limit{Rate: 1, Burst: 1})
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("a")
+	now = now.Add(2 * sweepInterval)
+	limiter.Allow("b")
+
+	assert.Len(t, limiter.buckets, 1)
+}
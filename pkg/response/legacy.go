@@ -0,0 +1,45 @@
+package response
+
+import (
+	"net/http"
+)
+
+// legacyWriter marks responses of the deprecated unversioned routes, which are
+// written without the envelope.
+type legacyWriter struct {
+	http.ResponseWriter
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *legacyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// isLegacy reports whether w is, or wraps, a legacyWriter.
+func isLegacy(w http.ResponseWriter) bool {
+	for {
+		if _, ok := w.(*legacyWriter); ok {
+			return true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = u.Unwrap()
+	}
+}
+
+// Legacy serves next in the response format used before the API was
+// versioned, and flags the responses as deprecated. successor is the prefix of
+// the replacing routes, sunset an optional HTTP date after which the legacy
+// routes are removed.
+func Legacy(successor, sunset string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+r.URL.Path+`>; rel="successor-version"`)
+		if sunset != "" {
+			w.Header().Set("Sunset", sunset)
+		}
+		next.ServeHTTP(&legacyWriter{ResponseWriter: w}, r)
+	})
+}
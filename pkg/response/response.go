@@ -2,19 +2,124 @@ package response
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/just-nibble/git-service/pkg/log"
 )
 
+// internalMessage replaces the message of internal errors, which may hold
+// queries or other details clients must not see
+const internalMessage = "internal server error"
+
+// errorLog records the internal errors hidden from clients
+var errorLog = log.NewLogger().Error
+
+// Envelope is the body of every API response.
+type Envelope struct {
+	Data  interface{} `json:"data"`
+	Meta  interface{} `json:"meta,omitempty"`
+	Error *ErrorBody  `json:"error,omitempty"`
+}
+
+type ErrorBody struct {
+	Code    errcodes.Code          `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+var codeStatuses = map[errcodes.Code]int{
+	errcodes.CodeInvalidArgument:  http.StatusBadRequest,
+	errcodes.CodeUnauthenticated:  http.StatusUnauthorized,
+	errcodes.CodePermissionDenied: http.StatusForbidden,
+	errcodes.CodeNotFound:         http.StatusNotFound,
+	errcodes.CodeAlreadyExists:    http.StatusConflict,
+	errcodes.CodeRateLimited:      http.StatusTooManyRequests,
+	errcodes.CodeCancelled:        499,
+	errcodes.CodeInternal:         http.StatusInternalServerError,
+}
+
+// StatusOf returns the HTTP status for an error code.
+func StatusOf(code errcodes.Code) int {
+	if status, ok := codeStatuses[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// CodeOfStatus returns the code of errors reported by status only.
+func CodeOfStatus(status int) errcodes.Code {
+	for code, s := range codeStatuses {
+		if s == status {
+			return code
+		}
+	}
+	if status >= 400 && status < 500 {
+		return errcodes.CodeInvalidArgument
+	}
+	return errcodes.CodeInternal
+}
+
 // SuccessResponse writes a success response with a given status code and data
 func SuccessResponse(w http.ResponseWriter, status int, data interface{}) {
+	PagedResponse(w, status, data, nil)
+}
+
+// PagedResponse writes a success response with metadata such as paging info
+func PagedResponse(w http.ResponseWriter, status int, data interface{}, meta interface{}) {
+	if isLegacy(w) {
+		writeJSON(w, status, data)
+		return
+	}
+	writeJSON(w, status, Envelope{Data: data, Meta: meta})
+}
+
+// Content writes a success response that is not JSON, such as a rendered
+// document, without the envelope
+func Content(w http.ResponseWriter, status int, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+	w.Write(body)
+}
+
+// NoContent writes an empty success response
+func NoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // ErrorResponse writes an error response with a given status code and message
 func ErrorResponse(w http.ResponseWriter, status int, message string) {
-	w.WriteHeader(status)
+	writeError(w, status, ErrorBody{Code: CodeOfStatus(status), Message: message})
+}
+
+// Error writes err with the status of its code. Errors without a code are
+// reported as internal errors, their message is logged and not sent.
+func Error(w http.ResponseWriter, err error) {
+	body := ErrorBody{Code: errcodes.CodeOf(err), Message: err.Error()}
+	if body.Code == errcodes.CodeInternal {
+		errorLog.Output(2, "Internal error: "+err.Error())
+		body.Message = internalMessage
+	}
+
+	var e *errcodes.Error
+	if errors.As(err, &e) {
+		body.Details = e.Details
+	}
+
+	writeError(w, StatusOf(body.Code), body)
+}
+
+func writeError(w http.ResponseWriter, status int, body ErrorBody) {
+	if isLegacy(w) {
+		writeJSON(w, status, map[string]string{"error": body.Message})
+		return
+	}
+	writeJSON(w, status, Envelope{Error: &body})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
 }
@@ -0,0 +1,139 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/just-nibble/git-service/pkg/errcodes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuccessResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	PagedResponse(rec, http.StatusOK, []string{"a"}, map[string]int{"page": 1})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"data":["a"],"meta":{"page":1}}`, rec.Body.String())
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		body   string
+	}{
+		{
+			err:    errcodes.ErrNoRecordFound,
+			status: http.StatusNotFound,
+			body:   `{"data":null,"error":{"code":"not_found","message":"no record found"}}`,
+		},
+		{
+			err:    fmt.Errorf("saving: %w", errcodes.ErrRepoAlreadyAdded),
+			status: http.StatusConflict,
+			body:   `{"data":null,"error":{"code":"already_exists","message":"saving: repository has already been added"}}`,
+		},
+		{
+			err:    errcodes.ErrRateLimited.WithDetails(map[string]interface{}{"retry_after": 3}),
+			status: http.StatusTooManyRequests,
+			body:   `{"data":null,"error":{"code":"rate_limited","message":"rate limit exceeded","details":{"retry_after":3}}}`,
+		},
+		{
+			err:    fmt.Errorf(`ERROR: relation "commit" does not exist (SQLSTATE 42P01)`),
+			status: http.StatusInternalServerError,
+			body:   `{"data":null,"error":{"code":"internal","message":"internal server error"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+
+		Error(rec, tt.err)
+
+		assert.Equal(t, tt.status, rec.Code)
+		assert.JSONEq(t, tt.body, rec.Body.String())
+	}
+}
+
+func TestLegacy(t *testing.T) {
+	handler := Legacy("/v1", "Sat, 01 May 2027 00:00:00 GMT", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("fail") {
+			Error(w, errcodes.ErrNoRecordFound)
+			return
+		}
+		SuccessResponse(w, http.StatusOK, []string{"a"})
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/commits/a/b", nil))
+
+	assert.JSONEq(t, `["a"]`, rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+	assert.Equal(t, `</v1/commits/a/b>; rel="successor-version"`, rec.Header().Get("Link"))
+	assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", rec.Header().Get("Sunset"))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/commits/a/b?fail", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"error":"no record found"}`, rec.Body.String())
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]StreamFormat{
+		"":                                    "",
+		"application/json":                    "",
+		"text/csv":                            CSV,
+		"application/x-ndjson":                NDJSON,
+		"text/csv; q=0, application/x-ndjson": NDJSON,
+		"application/json, text/csv":          "",
+		"text/html, text/csv;q=0.9":           CSV,
+		"*/*":                                 "",
+		"application/json;q=0.1, text/csv":    CSV,
+		"text/csv;q=0.5, application/json":    "",
+		"text/csv;q=0.5, application/x-ndjson;q=0.8": NDJSON,
+		"application/json;q=0, text/csv;q=0.2":       CSV,
+		"text/csv;q=0":                               "",
+		"text/csv;q=abc, application/x-ndjson;q=0.1": NDJSON,
+	}
+
+	for accept, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", accept)
+
+		format, ok := Negotiate(req)
+
+		assert.Equal(t, want, format, accept)
+		assert.Equal(t, want != "", ok, accept)
+	}
+}
+
+func TestStream(t *testing.T) {
+	rec := httptest.NewRecorder()
+	stream := NewStream(rec, CSV, []string{"hash", "message"})
+
+	assert.NoError(t, stream.Write(nil, []string{"a1", "feat: one"}))
+	assert.NoError(t, stream.Write(nil, []string{"a2", "two\n\nbody, with a comma"}))
+	assert.NoError(t, stream.Close())
+
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "hash,message\na1,feat: one\na2,\"two\n\nbody, with a comma\"\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	stream = NewStream(rec, NDJSON, nil)
+
+	assert.False(t, stream.Started())
+	assert.NoError(t, stream.Write(map[string]string{"hash": "a1"}, nil))
+	assert.True(t, stream.Started())
+	assert.NoError(t, stream.Close())
+
+	assert.Equal(t, "application/x-ndjson; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "{\"hash\":\"a1\"}\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	assert.NoError(t, NewStream(rec, CSV, []string{"hash"}).Close())
+	assert.Equal(t, "hash\n", rec.Body.String())
+}
@@ -0,0 +1,130 @@
+package response
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StreamFormat is a row format a list can be streamed in instead of a JSON
+// envelope.
+type StreamFormat string
+
+const (
+	CSV    StreamFormat = "text/csv"
+	NDJSON StreamFormat = "application/x-ndjson"
+)
+
+// Negotiate returns the stream format asked for by the Accept header of r. Media
+// ranges are tried by decreasing quality, q=0 refuses a type, it returns false
+// when the client prefers JSON or names no stream format.
+func Negotiate(r *http.Request) (StreamFormat, bool) {
+	type mediaRange struct {
+		mediaType string
+		quality   float64
+	}
+
+	var ranges []mediaRange
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil || quality < 0 || quality > 1 {
+				continue
+			}
+		}
+		if quality > 0 {
+			ranges = append(ranges, mediaRange{mediaType, quality})
+		}
+	}
+	// ranges of the same quality keep the order of the header
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+
+	for _, accepted := range ranges {
+		switch StreamFormat(accepted.mediaType) {
+		case CSV, NDJSON:
+			return StreamFormat(accepted.mediaType), true
+		}
+		if accepted.mediaType == "application/json" || accepted.mediaType == "*/*" {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// Stream writes the rows of a list one at a time. Nothing is sent before the
+// first row, so an error found before it can still be written as JSON.
+type Stream struct {
+	w       http.ResponseWriter
+	format  StreamFormat
+	header  []string
+	csv     *csv.Writer
+	json    *json.Encoder
+	started bool
+}
+
+// NewStream returns a stream of rows in format, header names the CSV columns.
+func NewStream(w http.ResponseWriter, format StreamFormat, header []string) *Stream {
+	return &Stream{w: w, format: format, header: header}
+}
+
+// Started reports whether the response was sent, errors can then no longer be
+// reported to the client.
+func (s *Stream) Started() bool {
+	return s.started
+}
+
+func (s *Stream) start() error {
+	s.started = true
+	s.w.Header().Set("Content-Type", string(s.format)+"; charset=utf-8")
+	s.w.WriteHeader(http.StatusOK)
+
+	if s.format == CSV {
+		s.csv = csv.NewWriter(s.w)
+		return s.csv.Write(s.header)
+	}
+	s.json = json.NewEncoder(s.w)
+	return nil
+}
+
+// Write writes a row, v as a line of JSON or record as a CSV record.
+func (s *Stream) Write(v interface{}, record []string) error {
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	if s.format == CSV {
+		if err := s.csv.Write(record); err != nil {
+			return err
+		}
+		// rows are buffered by the csv writer, the error of a gone client
+		// shows once they are flushed
+		return s.csv.Error()
+	}
+	return s.json.Encode(v)
+}
+
+// Close sends the rows still buffered, or an empty list if no row was written.
+func (s *Stream) Close() error {
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	if s.csv != nil {
+		s.csv.Flush()
+		return s.csv.Error()
+	}
+	return nil
+}